@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -12,9 +14,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dsyorkd/pi-controller/internal/agent"
+	"github.com/dsyorkd/pi-controller/internal/agent/deviceplugin"
 	"github.com/dsyorkd/pi-controller/internal/config"
 	"github.com/dsyorkd/pi-controller/internal/grpc/client"
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/pkg/discovery"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
 
@@ -133,6 +138,7 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		"mac_address": nodeInfo.MACAddress,
 		"architecture": nodeInfo.Architecture,
 		"model":       nodeInfo.Model,
+		"serial_number": nodeInfo.SerialNumber,
 		"cpu_cores":   nodeInfo.CPUCores,
 	}).Info("Node information collected")
 
@@ -144,23 +150,54 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Enrolled agents connect with mutual TLS using their certificate; a
+	// bootstrap token enrolls the agent on first start
+	creds, err := pki.LoadNodeCredentials(cfg.GRPCClient.CredentialsDir)
+	if err != nil && !errors.Is(err, pki.ErrNoCredentials) {
+		return fmt.Errorf("failed to load node credentials: %w", err)
+	}
+	var registeredNode *pb.Node
+	switch {
+	case creds != nil:
+		grpcClient.SetCredentials(creds)
+	case cfg.GRPCClient.BootstrapToken != "":
+		registeredNode, err = grpcClient.Enroll(ctx, cfg.GRPCClient.BootstrapToken, cfg.GRPCClient.CACertHash, nodeInfo, cfg.GRPCClient.CredentialsDir)
+		if err != nil {
+			structuredLogger.WithError(err).Error("Failed to enroll with controller")
+			return fmt.Errorf("failed to enroll node: %w", err)
+		}
+		creds = grpcClient.Credentials()
+	}
+
 	// Start gRPC client (this will connect and begin heartbeat)
 	if err := grpcClient.Start(ctx); err != nil {
 		structuredLogger.WithError(err).Error("Failed to start gRPC client")
 		return fmt.Errorf("failed to start gRPC client: %w", err)
 	}
 
-	// Register node with controller
-	registeredNode, err := grpcClient.RegisterNode(ctx, nodeInfo)
-	if err != nil {
-		structuredLogger.WithError(err).Error("Failed to register node")
-		return fmt.Errorf("failed to register node: %w", err)
+	switch {
+	case registeredNode != nil:
+	case creds != nil:
+		// The certificate names the node this agent enrolled as
+		registeredNode, err = grpcClient.GetNode(ctx, uint32(creds.NodeID()))
+		if err != nil {
+			structuredLogger.WithError(err).Error("Failed to get enrolled node")
+			return fmt.Errorf("failed to get enrolled node: %w", err)
+		}
+	default:
+		// Register node with controller
+		registeredNode, err = grpcClient.RegisterNode(ctx, nodeInfo)
+		if err != nil {
+			structuredLogger.WithError(err).Error("Failed to register node")
+			return fmt.Errorf("failed to register node: %w", err)
+		}
 	}
 
 	structuredLogger.WithFields(map[string]interface{}{
 		"registered_node_id":   registeredNode.Id,
 		"registered_node_name": registeredNode.Name,
 		"node_status":          registeredNode.Status,
+		"enrolled":             creds != nil,
 	}).Info("Node registered successfully with controller")
 
 	// Start GPIO gRPC server if enabled
@@ -170,6 +207,26 @@ func runAgent(cmd *cobra.Command, args []string) error {
 			Address: cfg.AgentServer.Address,
 			Port:    cfg.AgentServer.Port,
 		}
+		if creds != nil {
+			// Only the controller may call an enrolled agent
+			agentConfig.TLSConfig = creds.ServerTLSConfig()
+		} else if cfg.AgentServer.TLSCertFile != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.AgentServer.TLSCertFile, cfg.AgentServer.TLSKeyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load agent server certificate: %w", err)
+			}
+			agentConfig.TLSConfig = &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{cert},
+			}
+		}
+		if cfg.AgentServer.TokenPublicKeyFile != "" {
+			// Also accept tokens issued by the controller
+			agentConfig.TokenVerifier, err = pki.LoadAgentTokenVerifier(cfg.AgentServer.TokenPublicKeyFile)
+			if err != nil {
+				return err
+			}
+		}
 		
 		agentServer, err = agent.NewServer(agentConfig, structuredLogger)
 		if err != nil {
@@ -190,6 +247,57 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		structuredLogger.WithField("address", agentServer.GetAddress()).Info("Agent GPIO gRPC server started")
 	}
 
+	// Advertise the agent over mDNS so the controller's discovery finds it
+	var advertiser *discovery.Advertiser
+	if cfg.AgentServer.Advertise {
+		advertiser, err = discovery.NewAdvertiser(cfg.Discovery.ServiceType, cfg.Discovery.Interface, discovery.Advertisement{
+			Instance:     nodeInfo.Name,
+			IPAddress:    nodeInfo.IPAddress,
+			Port:         cfg.AgentServer.Port,
+			Version:      version,
+			Model:        nodeInfo.Model,
+			SerialNumber: nodeInfo.SerialNumber,
+			MACAddress:   nodeInfo.MACAddress,
+			Capabilities: agentCapabilities(cfg),
+		}, setupLogger())
+		if err == nil {
+			err = advertiser.Start(ctx)
+		}
+		if err != nil {
+			// Discovery can still find the node through static configuration
+			structuredLogger.WithError(err).Warn("Failed to advertise agent over mDNS")
+			advertiser = nil
+		} else {
+			structuredLogger.WithField("service_type", cfg.Discovery.ServiceType).Info("Agent advertised over mDNS")
+		}
+	}
+
+	// Advertise GPIO devices to the kubelet if enabled
+	pluginDone := make(chan struct{})
+	if cfg.AgentServer.DevicePlugin.Enabled {
+		refreshInterval, err := time.ParseDuration(cfg.AgentServer.DevicePlugin.RefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid device plugin refresh interval: %w", err)
+		}
+
+		plugin := deviceplugin.New(deviceplugin.Config{
+			PluginDir:          cfg.AgentServer.DevicePlugin.PluginDir,
+			PodResourcesSocket: cfg.AgentServer.DevicePlugin.PodResourcesSocket,
+			RefreshInterval:    refreshInterval,
+		}, grpcClient, registeredNode.Id, structuredLogger)
+
+		go func() {
+			defer close(pluginDone)
+			if err := plugin.Run(ctx); err != nil {
+				structuredLogger.WithError(err).Error("GPIO device plugin stopped")
+			}
+		}()
+
+		structuredLogger.Info("GPIO device plugin started")
+	} else {
+		close(pluginDone)
+	}
+
 	structuredLogger.Info("Pi Agent started successfully")
 
 	// Main agent loop
@@ -206,6 +314,13 @@ func runAgent(cmd *cobra.Command, args []string) error {
 			if err := grpcClient.UpdateNodeStatus(ctx, registeredNode.Id, registeredNode.Status); err != nil {
 				structuredLogger.WithError(err).Warn("Failed to update node status")
 			}
+
+			// Rotate the node certificate well before it expires
+			if creds != nil && creds.NeedsRenewal(time.Now()) {
+				if err := grpcClient.RenewCertificate(ctx); err != nil {
+					structuredLogger.WithError(err).Warn("Failed to renew node certificate")
+				}
+			}
 			
 			// TODO: Future enhancements:
 			// - Collect and report system metrics
@@ -222,6 +337,13 @@ func runAgent(cmd *cobra.Command, args []string) error {
 				structuredLogger.WithError(err).Warn("Failed to update node status to maintenance")
 			}
 			
+			// Withdraw the mDNS advertisement
+			if advertiser != nil {
+				if err := advertiser.Stop(); err != nil {
+					structuredLogger.WithError(err).Warn("Error stopping mDNS advertiser")
+				}
+			}
+
 			// Stop the agent server if it's running
 			if agentServer != nil {
 				if err := agentServer.Stop(); err != nil {
@@ -237,12 +359,26 @@ func runAgent(cmd *cobra.Command, args []string) error {
 			cancel()
 			
 		case <-ctx.Done():
+			<-pluginDone
 			structuredLogger.Info("Pi Agent shutdown complete")
 			return nil
 		}
 	}
 }
 
+// agentCapabilities lists the services the agent offers, published in its
+// mDNS TXT record
+func agentCapabilities(cfg *config.Config) []string {
+	var capabilities []string
+	if cfg.AgentServer.EnableGPIO {
+		capabilities = append(capabilities, "gpio", "pwm", "interrupt")
+	}
+	if cfg.AgentServer.DevicePlugin.Enabled {
+		capabilities = append(capabilities, "device-plugin")
+	}
+	return capabilities
+}
+
 // setupStructuredLogger creates a structured logger compatible with our logger interface
 func setupStructuredLogger() (logger.Interface, error) {
 	loggerConfig := logger.Config{
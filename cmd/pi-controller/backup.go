@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsyorkd/pi-controller/internal/backup"
+	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/grpc/agentpool"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Cluster backup commands",
+	Long: `Snapshot, list, restore and delete backups of each cluster's datastore. Commands
+run in the foreground against the controller's database and backup storage.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Back up a cluster now",
+	Long:  `Snapshot a cluster's datastore through its master node's agent and store it`,
+	Args:  cobra.NoArgs,
+	RunE:  runBackupCreate,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List a cluster's backups",
+	Long:  `List a cluster's backups, newest first`,
+	Args:  cobra.NoArgs,
+	RunE:  runBackupList,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <backup-id>",
+	Short: "Restore a cluster from a backup (DANGEROUS)",
+	Long: `Replace a cluster's datastore with a backup. k3s is stopped on the master node while
+the snapshot is restored. WARNING: Changes made since the backup are lost!`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+var backupDeleteCmd = &cobra.Command{
+	Use:   "delete <backup-id>",
+	Short: "Delete a backup",
+	Long:  `Delete a backup and its snapshot`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBackupDelete,
+}
+
+var backupClusterID uint
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupDeleteCmd)
+
+	backupCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file path")
+	backupCmd.PersistentFlags().UintVar(&backupClusterID, "cluster", 0, "cluster ID")
+	backupCmd.MarkPersistentFlagRequired("cluster")
+
+	// Add confirmation flag for restore command
+	backupRestoreCmd.Flags().Bool("confirm", false, "Confirm destructive restore operation")
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	backups, db, err := setupBackupEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	record, err := backups.Backup(context.Background(), backupClusterID, models.BackupTriggerManual)
+	if err != nil {
+		return errors.Wrapf(err, "failed to back up cluster %d", backupClusterID)
+	}
+
+	fmt.Printf("Backup %d of cluster %d succeeded: %s, %d bytes, sha256 %s\n",
+		record.ID, record.ClusterID, record.Datastore, record.SizeBytes, record.Checksum)
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	backups, db, err := setupBackupEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	records, _, err := backups.List(backupClusterID, services.BackupListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list backups")
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No backups found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tTRIGGER\tSTATUS\tDATASTORE\tSIZE\tSTORAGE")
+	for _, record := range records {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			record.ID, record.StartedAt.Format("2006-01-02 15:04:05"), record.Trigger, record.Status,
+			record.Datastore, record.SizeBytes, record.Storage)
+	}
+	return w.Flush()
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	if !confirm {
+		return fmt.Errorf("restore operation requires --confirm flag due to destructive nature")
+	}
+
+	backupID, err := parseBackupID(args[0])
+	if err != nil {
+		return err
+	}
+
+	backups, db, err := setupBackupEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	job, err := backups.Restore(context.Background(), backupClusterID, backupID)
+	if err != nil {
+		if job != nil {
+			return errors.Wrapf(err, "restore job %d failed", job.ID)
+		}
+		return errors.Wrapf(err, "failed to restore backup %d", backupID)
+	}
+
+	fmt.Printf("Cluster %d restored from backup %d\n", backupClusterID, backupID)
+	return nil
+}
+
+func runBackupDelete(cmd *cobra.Command, args []string) error {
+	backupID, err := parseBackupID(args[0])
+	if err != nil {
+		return err
+	}
+
+	backups, db, err := setupBackupEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := backups.Delete(context.Background(), backupClusterID, backupID); err != nil {
+		return errors.Wrapf(err, "failed to delete backup %d", backupID)
+	}
+
+	fmt.Printf("Backup %d deleted\n", backupID)
+	return nil
+}
+
+func parseBackupID(arg string) (uint, error) {
+	id, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup ID %q", arg)
+	}
+	return uint(id), nil
+}
+
+func setupBackupEnvironment() (*services.BackupService, *storage.Database, error) {
+	// Setup logger
+	log, err := setupLogger()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to setup logger")
+	}
+
+	// Load configuration
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load config")
+	}
+
+	db, err := storage.New(&cfg.Database, log)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to initialize database")
+	}
+
+	backups, err := newBackupService(cfg, db, log)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	poolConfig, err := agentpool.ConfigFromYAML(cfg.AgentPool)
+	if err != nil {
+		db.Close()
+		return nil, nil, errors.Wrapf(err, "invalid agent pool config")
+	}
+	enrollment, err := newEnrollmentService(cfg, db, services.NewNodeService(db, log), log)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	if enrollment != nil {
+		poolConfig.TLSConfig = enrollment.ClientTLSConfig()
+	}
+	agentPool := agentpool.New(poolConfig, log)
+	tokenIssuer, err := newAgentTokenIssuer(cfg)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	if tokenIssuer != nil {
+		agentPool.SetTokenIssuer(tokenIssuer)
+	}
+	backups.SetAgentConnector(agentPool)
+	backups.SetJobService(services.NewJobService(db, log))
+
+	return backups, db, nil
+}
+
+// newBackupService creates the backup service with the configured storage
+func newBackupService(cfg *config.Config, db *storage.Database, log logger.Interface) (*services.BackupService, error) {
+	backupConfig, err := backup.ConfigFromYAML(cfg.Backup)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid backup config")
+	}
+	store, err := backup.NewStore(backupConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open backup storage")
+	}
+	return services.NewBackupService(db, store, backupConfig, log), nil
+}
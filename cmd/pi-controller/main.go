@@ -9,16 +9,23 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/dsyorkd/pi-controller/internal/api"
 	"github.com/dsyorkd/pi-controller/internal/config"
 	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/grpc/agentpool"
 	grpcserver "github.com/dsyorkd/pi-controller/internal/grpc/server"
 	"github.com/dsyorkd/pi-controller/internal/logger"
 	"github.com/dsyorkd/pi-controller/internal/migrations"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/provisioning"
+	"github.com/dsyorkd/pi-controller/internal/services"
 	"github.com/dsyorkd/pi-controller/internal/storage"
 	"github.com/dsyorkd/pi-controller/internal/websocket"
+	"github.com/dsyorkd/pi-controller/pkg/discovery"
 )
 
 var (
@@ -142,6 +149,161 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	log.Info("Database initialized successfully")
 
+	// Initialize services shared by all servers, publishing state changes to one event bus
+	eventBus := events.NewBus()
+	// Clients for managed clusters are shared and dropped when a cluster changes
+	kubeClients := services.NewKubeClientRegistry(log)
+	clusterService := services.NewClusterService(db, log)
+	clusterService.SetEventBus(eventBus)
+	clusterService.SetKubeClientRegistry(kubeClients)
+	nodeService := services.NewNodeService(db, log)
+	nodeService.SetEventBus(eventBus)
+	nodeService.SetKubeClientProvider(kubeClients)
+	gpioService := services.NewGPIOService(db, log)
+	gpioService.SetEventBus(eventBus)
+	jobService := services.NewJobService(db, log)
+	jobService.SetEventBus(eventBus)
+	nodeService.SetJobService(jobService)
+	deploymentService := services.NewDeploymentService(db, kubeClients, log)
+
+	// Issue agents certificates from the controller's CA; with it enabled all
+	// controller<->agent gRPC requires mutual TLS
+	enrollmentService, err := newEnrollmentService(cfg, db, nodeService, log)
+	if err != nil {
+		return err
+	}
+
+	// Initialize agent connection pool
+	poolConfig, err := agentpool.ConfigFromYAML(cfg.AgentPool)
+	if err != nil {
+		return errors.Wrapf(err, "invalid agent pool config")
+	}
+	if enrollmentService != nil {
+		poolConfig.TLSConfig = enrollmentService.ClientTLSConfig()
+		log.WithField("ca_cert_hash", enrollmentService.CA().Hash()).Info("Agent enrollment enabled")
+	}
+	agentPool := agentpool.New(poolConfig, log)
+	tokenIssuer, err := newAgentTokenIssuer(cfg)
+	if err != nil {
+		return err
+	}
+	if tokenIssuer != nil {
+		// Agents without mutual TLS authenticate the controller by its tokens
+		agentPool.SetTokenIssuer(tokenIssuer)
+	}
+	if enrollmentService != nil {
+		// Drop connections to agents whose certificates are revoked
+		enrollmentService.AddRevocationHandler(agentPool.Remove)
+	}
+	agentPool.OnStateChange(func(nodeID uint, oldState, newState agentpool.ConnectionState) {
+		if newState != agentpool.StateReady && newState != agentpool.StateUnreachable {
+			return
+		}
+		if err := nodeService.HandleAgentStateChange(nodeID, newState == agentpool.StateReady); err != nil {
+			log.WithError(err).WithField("node_id", nodeID).Warn("Failed to record agent state change")
+		}
+	})
+	gpioService.SetAgentConnector(agentPool)
+	nodeService.SetAgentStateProvider(agentPool)
+
+	poolCtx, poolCancel := context.WithCancel(context.Background())
+	defer poolCancel()
+	agentPool.Start(poolCtx, func() ([]models.Node, error) {
+		nodes, _, err := nodeService.List(services.NodeListOptions{})
+		return nodes, err
+	})
+	defer agentPool.Stop()
+	gpioService.StartEdgeWatch(poolCtx)
+
+	// Add nodes found by discovery to the inventory, directly or through the
+	// approval queue
+	registration := cfg.Discovery.Registration
+	registrationPolicy, err := services.NewRegistrationPolicy(registration.Approval, registration.Allow, registration.Deny)
+	if err != nil {
+		return errors.Wrapf(err, "invalid discovery registration config")
+	}
+	registrationService := services.NewRegistrationService(db, nodeService, registrationPolicy, log)
+	discoveryService, err := discovery.NewService(&discovery.Config{
+		Enabled:          cfg.Discovery.Enabled,
+		Method:           cfg.Discovery.Method,
+		Interface:        cfg.Discovery.Interface,
+		Port:             cfg.Discovery.Port,
+		Interval:         cfg.Discovery.Interval,
+		Timeout:          cfg.Discovery.Timeout,
+		StaticNodes:      cfg.Discovery.StaticNodes,
+		ServiceName:      cfg.Discovery.ServiceName,
+		ServiceType:      cfg.Discovery.ServiceType,
+		Methods:          cfg.Discovery.Methods,
+		LeaseFiles:       cfg.Discovery.LeaseFiles,
+		ARPTable:         cfg.Discovery.ARPTable,
+		OUIs:             cfg.Discovery.OUIs,
+		PhoneHomeAddress: cfg.Discovery.PhoneHomeAddress,
+	}, logrus.New())
+	if err != nil {
+		return errors.Wrapf(err, "invalid discovery config")
+	}
+	discoveryService.AddEventHandler(registrationService.HandleEvent)
+	if err := discoveryService.Start(poolCtx); err != nil {
+		return errors.Wrapf(err, "failed to start discovery")
+	}
+	defer discoveryService.Stop()
+
+	// Derive cluster status from each cluster's Kubernetes API
+	resyncInterval, err := time.ParseDuration(cfg.Kubernetes.ResyncInterval)
+	if err != nil {
+		return errors.Wrapf(err, "invalid kubernetes resync_interval")
+	}
+	if resyncInterval <= 0 {
+		return fmt.Errorf("kubernetes resync_interval must be positive, got %s", resyncInterval)
+	}
+	clusterService.StartStatusReconciler(poolCtx, resyncInterval)
+
+	// Back up each cluster's datastore through its master node's agent
+	backupService, err := newBackupService(cfg, db, log)
+	if err != nil {
+		return err
+	}
+	backupService.SetAgentConnector(agentPool)
+	backupService.SetJobService(jobService)
+	if err := backupService.FailInterrupted(); err != nil {
+		log.WithError(err).Error("Failed to record interrupted backups")
+	}
+	backupService.StartScheduler(poolCtx)
+
+	// Upgrade clusters node by node; the new version is installed over SSH
+	upgradeService := services.NewUpgradeService(db, kubeClients, log)
+	upgradeService.SetJobService(jobService)
+	upgradeCtx, upgradeCancel := context.WithCancel(context.Background())
+	defer func() {
+		upgradeCancel()
+		upgradeService.Wait()
+	}()
+	if err := upgradeService.Start(upgradeCtx); err != nil {
+		log.WithError(err).Error("Failed to record interrupted upgrades")
+	}
+
+	// Initialize node provisioning over SSH; without an SSH key nodes are only
+	// assigned to clusters and cannot be upgraded
+	provisioningConfig, err := provisioning.ConfigFromYAML(cfg.Provisioning)
+	if err != nil {
+		return errors.Wrapf(err, "invalid provisioning config")
+	}
+	if dialer, err := provisioning.NewSSHDialer(provisioningConfig); err != nil {
+		log.WithError(err).Warn("Node provisioning over SSH is disabled")
+	} else {
+		provisioner := provisioning.New(provisioningConfig, dialer, nodeService, clusterService, jobService, log)
+		provisionCtx, provisionCancel := context.WithCancel(context.Background())
+		defer func() {
+			provisionCancel()
+			provisioner.Wait()
+		}()
+		if err := provisioner.Start(provisionCtx); err != nil {
+			log.WithError(err).Error("Failed to resume node provisioning")
+		}
+		nodeService.SetProvisioner(provisioner)
+		upgradeService.SetNodeUpgrader(provisioner)
+	}
+
 	// Setup graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -151,7 +313,17 @@ func runServer(cmd *cobra.Command, args []string) error {
 	serverErrors := make(chan error, 3)
 
 	// Start REST API server
-	apiServer := api.New(&cfg.API, log, db)
+	apiServer := api.NewWithServices(&cfg.API, log, db, api.Services{
+		Cluster:      clusterService,
+		Node:         nodeService,
+		GPIO:         gpioService,
+		Job:          jobService,
+		Deployment:   deploymentService,
+		Backup:       backupService,
+		Upgrade:      upgradeService,
+		Registration: registrationService,
+		Enrollment:   enrollmentService,
+	})
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -162,7 +334,7 @@ func runServer(cmd *cobra.Command, args []string) error {
 	}()
 
 	// Start gRPC server
-	grpcServer, err := grpcserver.New(&cfg.GRPC, log, db)
+	grpcServer, err := grpcserver.NewWithServices(&cfg.GRPC, log, db, apiServer.AuthManager(), clusterService, nodeService, gpioService, enrollmentService)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create gRPC server")
 	}
@@ -178,6 +350,9 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	// Start WebSocket server
 	wsServer := websocket.New(&cfg.WebSocket, log, db)
+	wsServer.SetAuthManager(apiServer.AuthManager())
+	wsServer.SetGPIOService(gpioService)
+	wsServer.ConsumeEvents(eventBus)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
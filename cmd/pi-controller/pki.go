@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Agent bootstrap token commands",
+	Long: `Create and list the one-time bootstrap tokens agents present to enroll for a
+certificate. Requires pki.enabled in the controller's configuration.`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bootstrap token",
+	Long: `Create a bootstrap token and print it with the CA hash agents pin. The token is
+only shown once.`,
+	Args: cobra.NoArgs,
+	RunE: runTokenCreate,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bootstrap tokens",
+	Long:  `List bootstrap tokens, newest first`,
+	Args:  cobra.NoArgs,
+	RunE:  runTokenList,
+}
+
+var (
+	tokenDescription string
+	tokenTTL         string
+	tokenNodeID      uint
+)
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+
+	tokenCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file path")
+	tokenCreateCmd.Flags().StringVar(&tokenDescription, "description", "", "what the token is for")
+	tokenCreateCmd.Flags().StringVar(&tokenTTL, "ttl", "", "how long the token is valid (default pki.bootstrap_token_ttl)")
+	tokenCreateCmd.Flags().UintVar(&tokenNodeID, "node", 0, "enroll the agent as this existing node")
+}
+
+func runTokenCreate(cmd *cobra.Command, args []string) error {
+	enrollment, db, err := setupEnrollmentEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	req := services.CreateBootstrapTokenRequest{
+		Description: tokenDescription,
+		TTL:         tokenTTL,
+	}
+	if tokenNodeID != 0 {
+		req.NodeID = &tokenNodeID
+	}
+	record, token, err := enrollment.CreateBootstrapToken(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create bootstrap token")
+	}
+
+	fmt.Printf("Token:        %s\n", token)
+	fmt.Printf("CA cert hash: %s\n", enrollment.CA().Hash())
+	fmt.Printf("Expires:      %s\n", record.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+func runTokenList(cmd *cobra.Command, args []string) error {
+	enrollment, db, err := setupEnrollmentEnvironment()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tokens, err := enrollment.ListBootstrapTokens()
+	if err != nil {
+		return errors.Wrapf(err, "failed to list bootstrap tokens")
+	}
+
+	if len(tokens) == 0 {
+		fmt.Println("No bootstrap tokens found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTOKEN ID\tEXPIRES\tUSED\tNODE\tDESCRIPTION")
+	for _, token := range tokens {
+		used, node := "-", "-"
+		if token.UsedAt != nil {
+			used = token.UsedAt.Format("2006-01-02 15:04:05")
+		}
+		if token.NodeID != nil {
+			node = fmt.Sprint(*token.NodeID)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			token.ID, token.TokenID, token.ExpiresAt.Format("2006-01-02 15:04:05"), used, node, token.Description)
+	}
+	return w.Flush()
+}
+
+func setupEnrollmentEnvironment() (*services.EnrollmentService, *storage.Database, error) {
+	// Setup logger
+	log, err := setupLogger()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to setup logger")
+	}
+
+	// Load configuration
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load config")
+	}
+	if !cfg.PKI.Enabled {
+		return nil, nil, fmt.Errorf("agent enrollment requires pki.enabled")
+	}
+
+	db, err := storage.New(&cfg.Database, log)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to initialize database")
+	}
+
+	enrollment, err := newEnrollmentService(cfg, db, services.NewNodeService(db, log), log)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return enrollment, db, nil
+}
+
+// newEnrollmentService loads or creates the controller's CA and creates the
+// enrollment service, or returns nil if the PKI is disabled
+func newEnrollmentService(cfg *config.Config, db *storage.Database, nodeService *services.NodeService, log logger.Interface) (*services.EnrollmentService, error) {
+	if !cfg.PKI.Enabled {
+		return nil, nil
+	}
+
+	certTTL, err := time.ParseDuration(cfg.PKI.CertificateTTL)
+	if err != nil || certTTL <= 0 {
+		return nil, fmt.Errorf("pki certificate_ttl must be a positive duration, got %q", cfg.PKI.CertificateTTL)
+	}
+	tokenTTL, err := time.ParseDuration(cfg.PKI.BootstrapTokenTTL)
+	if err != nil || tokenTTL <= 0 {
+		return nil, fmt.Errorf("pki bootstrap_token_ttl must be a positive duration, got %q", cfg.PKI.BootstrapTokenTTL)
+	}
+
+	ca, err := pki.LoadOrCreateCA(cfg.PKI.Dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load certificate authority")
+	}
+	enrollment, err := services.NewEnrollmentService(db, nodeService, ca, certTTL, tokenTTL, log)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to initialize agent enrollment")
+	}
+	return enrollment, nil
+}
+
+// newAgentTokenIssuer loads or creates the key the controller signs agent
+// tokens with, or returns nil if agents are not configured to accept tokens
+func newAgentTokenIssuer(cfg *config.Config) (*pki.AgentTokenIssuer, error) {
+	if cfg.AgentPool.TokenKeyFile == "" {
+		return nil, nil
+	}
+	issuer, err := pki.LoadOrCreateAgentTokenIssuer(cfg.AgentPool.TokenKeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load agent token key")
+	}
+	return issuer, nil
+}
@@ -8,12 +8,13 @@ import (
 )
 
 // AgentService implements the complete PiAgent gRPC service
-// It combines GPIO and metrics functionality
+// It combines GPIO, metrics and datastore functionality
 type AgentService struct {
 	pb.UnimplementedPiAgentServiceServer
-	gpio    *GPIOService
-	metrics *MetricsService
-	logger  logger.Interface
+	gpio      *GPIOService
+	metrics   *MetricsService
+	datastore *DatastoreService
+	logger    logger.Interface
 }
 
 // NewAgentService creates a new complete agent service
@@ -24,11 +25,13 @@ func NewAgentService(logger logger.Interface) (*AgentService, error) {
 	}
 
 	metrics := NewMetricsService(logger)
+	datastore := NewDatastoreService(DatastoreConfig{}, logger)
 
 	return &AgentService{
-		gpio:    gpio,
-		metrics: metrics,
-		logger:  logger.WithField("component", "agent-service"),
+		gpio:      gpio,
+		metrics:   metrics,
+		datastore: datastore,
+		logger:    logger.WithField("component", "agent-service"),
 	}, nil
 }
 
@@ -69,6 +72,10 @@ func (s *AgentService) ListConfiguredPins(ctx context.Context, req *pb.ListConfi
 	return s.gpio.ListConfiguredPins(ctx, req)
 }
 
+func (s *AgentService) WatchGPIOPin(req *pb.WatchGPIOPinRequest, stream pb.PiAgentService_WatchGPIOPinServer) error {
+	return s.gpio.WatchGPIOPin(req, stream)
+}
+
 func (s *AgentService) AgentHealth(ctx context.Context, req *pb.AgentHealthRequest) (*pb.AgentHealthResponse, error) {
 	return s.gpio.AgentHealth(ctx, req)
 }
@@ -85,4 +92,14 @@ func (s *AgentService) GetSystemMetrics(ctx context.Context, req *pb.GetSystemMe
 
 func (s *AgentService) StreamSystemMetrics(req *pb.StreamSystemMetricsRequest, stream pb.PiAgentService_StreamSystemMetricsServer) error {
 	return s.metrics.StreamSystemMetrics(req, stream)
-}
\ No newline at end of file
+}
+
+// Datastore-related methods - delegate to datastore service
+
+func (s *AgentService) SnapshotDatastore(req *pb.SnapshotDatastoreRequest, stream pb.PiAgentService_SnapshotDatastoreServer) error {
+	return s.datastore.SnapshotDatastore(req, stream)
+}
+
+func (s *AgentService) RestoreDatastore(stream pb.PiAgentService_RestoreDatastoreServer) error {
+	return s.datastore.RestoreDatastore(stream)
+}
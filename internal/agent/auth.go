@@ -0,0 +1,159 @@
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// systemUserID identifies operations the agent performs on its own behalf,
+// rather than for an authenticated caller
+const systemUserID = "agent"
+
+// methodRoles is the role each agent RPC requires. Methods not listed
+// require the admin role.
+var methodRoles = map[string]string{
+	pb.PiAgentService_AgentHealth_FullMethodName:         middleware.RoleViewer,
+	pb.PiAgentService_GetSystemInfo_FullMethodName:       middleware.RoleViewer,
+	pb.PiAgentService_GetSystemMetrics_FullMethodName:    middleware.RoleViewer,
+	pb.PiAgentService_StreamSystemMetrics_FullMethodName: middleware.RoleViewer,
+	pb.PiAgentService_ListConfiguredPins_FullMethodName:  middleware.RoleViewer,
+	pb.PiAgentService_ReadGPIOPin_FullMethodName:         middleware.RoleViewer,
+	pb.PiAgentService_WatchGPIOPin_FullMethodName:        middleware.RoleViewer,
+	pb.PiAgentService_ConfigureGPIOPin_FullMethodName:    middleware.RoleOperator,
+	pb.PiAgentService_WriteGPIOPin_FullMethodName:        middleware.RoleOperator,
+	pb.PiAgentService_SetGPIOPWM_FullMethodName:          middleware.RoleOperator,
+	pb.PiAgentService_SnapshotDatastore_FullMethodName:   middleware.RoleAdmin,
+	pb.PiAgentService_RestoreDatastore_FullMethodName:    middleware.RoleAdmin,
+}
+
+// roleRanks orders roles so a role grants the roles below it
+var roleRanks = map[string]int{
+	middleware.RoleViewer:   1,
+	middleware.RoleOperator: 2,
+	middleware.RoleAdmin:    3,
+}
+
+// userIDKey is the context key of the authenticated caller's user ID
+type userIDKey struct{}
+
+// UserIDFromContext returns the user ID of the caller authenticated by the
+// agent server's interceptors. Calls made without them, such as from within
+// the agent, act as the agent itself.
+func UserIDFromContext(ctx context.Context) string {
+	if userID, ok := ctx.Value(userIDKey{}).(string); ok {
+		return userID
+	}
+	return systemUserID
+}
+
+// authenticator identifies callers of the agent server
+type authenticator struct {
+	tokens *pki.AgentTokenVerifier
+	logger logger.Interface
+}
+
+// authenticate identifies the caller by the controller's client certificate
+// or a token issued by the controller for agents, checks it may call the
+// method and returns the context carrying its user ID
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	userID, role, err := a.identify(ctx)
+	if err != nil {
+		a.logger.WithFields(map[string]interface{}{
+			"method": method,
+			"error":  err,
+		}).Warn("Rejected unauthenticated agent gRPC call")
+		return nil, err
+	}
+
+	requiredRole, ok := methodRoles[method]
+	if !ok {
+		requiredRole = middleware.RoleAdmin
+	}
+	if roleRanks[role] < roleRanks[requiredRole] {
+		a.logger.WithFields(map[string]interface{}{
+			"method":  method,
+			"user_id": userID,
+			"role":    role,
+		}).Warn("Rejected unauthorized agent gRPC call")
+		return nil, status.Errorf(codes.PermissionDenied, "%s role required", requiredRole)
+	}
+
+	return context.WithValue(ctx, userIDKey{}, userID), nil
+}
+
+// identify returns the caller's user ID and role
+func (a *authenticator) identify(ctx context.Context) (string, string, error) {
+	// The controller's certificate grants full access to its agents
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			if name := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; name == pki.ControllerName {
+				return name, middleware.RoleAdmin, nil
+			}
+		}
+	}
+
+	if a.tokens == nil {
+		return "", "", status.Error(codes.Unauthenticated, "Controller client certificate required")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 {
+		return "", "", status.Error(codes.Unauthenticated, "Missing authorization header")
+	}
+	const bearerPrefix = "Bearer "
+	if !strings.HasPrefix(authHeaders[0], bearerPrefix) {
+		return "", "", status.Error(codes.Unauthenticated, "Invalid authorization header format")
+	}
+
+	claims, err := a.tokens.Verify(strings.TrimPrefix(authHeaders[0], bearerPrefix))
+	if err != nil {
+		return "", "", status.Error(codes.Unauthenticated, "Invalid or expired token")
+	}
+	return claims.Subject, claims.Role, nil
+}
+
+// authInterceptor rejects unauthenticated unary RPCs
+func authInterceptor(auth *authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := auth.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor rejects unauthenticated streaming RPCs
+func streamAuthInterceptor(auth *authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := auth.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream carries the caller's user ID in the stream's context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the caller's user ID
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
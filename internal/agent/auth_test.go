@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/pkg/gpio"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// startAuthenticatedAgent serves a mock GPIO service behind the agent
+// server's interceptors and returns a dialer for it along with the GPIO
+// controller's log
+func startAuthenticatedAgent(t *testing.T, tokens *pki.AgentTokenVerifier, serverTLS *tls.Config) (func(...grpc.DialOption) pb.PiAgentServiceClient, *logrustest.Hook) {
+	config := gpio.DefaultConfig()
+	config.MockMode = true
+	gpioLogger, hook := logrustest.NewNullLogger()
+
+	service := &GPIOService{
+		controller: gpio.NewController(config, agentSecurityConfig(), gpioLogger),
+		logger:     logger.Default(),
+	}
+	require.NoError(t, service.Initialize(context.Background()))
+	t.Cleanup(func() { service.Close() })
+
+	auth := &authenticator{tokens: tokens, logger: logger.Default()}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(loggingInterceptor(logger.Default()), authInterceptor(auth)),
+		grpc.ChainStreamInterceptor(streamLoggingInterceptor(logger.Default()), streamAuthInterceptor(auth)),
+	}
+	if serverTLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(serverTLS)))
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(opts...)
+	pb.RegisterPiAgentServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	dial := func(opts ...grpc.DialOption) pb.PiAgentServiceClient {
+		opts = append([]grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		}, opts...)
+		conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return pb.NewPiAgentServiceClient(conn)
+	}
+	return dial, hook
+}
+
+// newTestTokenIssuer creates an agent token issuer and a verifier for its
+// tokens
+func newTestTokenIssuer(t *testing.T) (*pki.AgentTokenIssuer, *pki.AgentTokenVerifier) {
+	issuer, err := pki.LoadOrCreateAgentTokenIssuer(filepath.Join(t.TempDir(), "agent-token.key"))
+	require.NoError(t, err)
+	pubPEM, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+	verifier, err := pki.NewAgentTokenVerifier(pubPEM)
+	require.NoError(t, err)
+	return issuer, verifier
+}
+
+func withToken(t *testing.T, issuer *pki.AgentTokenIssuer, userID, role string) context.Context {
+	token, _, err := issuer.Issue(userID, role)
+	require.NoError(t, err)
+	return withBearer(token)
+}
+
+func withBearer(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// auditedUsers returns the users in the GPIO controller's audit log for an
+// event type
+func auditedUsers(hook *logrustest.Hook, eventType string) []string {
+	var users []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Data["event_type"] == eventType {
+			users = append(users, entry.Data["user_id"].(string))
+		}
+	}
+	return users
+}
+
+func TestAgentAuth_Token(t *testing.T) {
+	issuer, verifier := newTestTokenIssuer(t)
+	dial, hook := startAuthenticatedAgent(t, verifier, nil)
+	client := dial(grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	write := &pb.WriteGPIOPinRequest{Pin: 17, Value: 1}
+
+	_, err := client.WriteGPIOPin(context.Background(), write)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "calls without credentials are rejected")
+
+	_, err = client.WriteGPIOPin(withBearer("not-a-token"), write)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	otherIssuer, _ := newTestTokenIssuer(t)
+	_, err = client.WriteGPIOPin(withToken(t, otherIssuer, "mallory", middleware.RoleAdmin), write)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "tokens must be signed with the controller's key")
+
+	authManager, err := middleware.NewAuthManager(middleware.DefaultAuthConfig(), logger.Default())
+	require.NoError(t, err)
+	apiToken, err := authManager.GenerateToken("mallory", middleware.RoleAdmin, middleware.TokenTypeAccess)
+	require.NoError(t, err)
+	_, err = client.WriteGPIOPin(withBearer(apiToken), write)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "the controller API's tokens are not accepted")
+
+	viewer := withToken(t, issuer, "vera", middleware.RoleViewer)
+	_, err = client.AgentHealth(viewer, &pb.AgentHealthRequest{})
+	assert.NoError(t, err)
+	_, err = client.WriteGPIOPin(viewer, write)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	operator := withToken(t, issuer, "otto", middleware.RoleOperator)
+	configured, err := client.ConfigureGPIOPin(operator, &pb.ConfigureGPIOPinRequest{
+		Pin:       17,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+	})
+	require.NoError(t, err)
+	require.True(t, configured.Success, configured.Message)
+	_, err = client.WriteGPIOPin(operator, write)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"otto"}, auditedUsers(hook, "pin_write"), "GPIO access is attributed to the token's user")
+
+	restore, err := client.RestoreDatastore(operator)
+	require.NoError(t, err)
+	_, err = restore.CloseAndRecv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "restoring a datastore requires the admin role")
+}
+
+func TestAgentAuth_Streams(t *testing.T) {
+	issuer, verifier := newTestTokenIssuer(t)
+	dial, hook := startAuthenticatedAgent(t, verifier, nil)
+	client := dial(grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{Pin: 17})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	operator := withToken(t, issuer, "otto", middleware.RoleOperator)
+	_, err = client.ConfigureGPIOPin(operator, &pb.ConfigureGPIOPinRequest{
+		Pin:       17,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+	})
+	require.NoError(t, err)
+
+	watchCtx, stopWatching := context.WithCancel(withToken(t, issuer, "vera", middleware.RoleViewer))
+	stream, err = client.WatchGPIOPin(watchCtx, &pb.WatchGPIOPinRequest{Pin: 17})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(auditedUsers(hook, "interrupt_enabled")) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"vera"}, auditedUsers(hook, "interrupt_enabled"))
+	stopWatching()
+}
+
+func TestAgentAuth_MutualTLS(t *testing.T) {
+	ca, err := pki.LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	key, csr, err := pki.NewCSR()
+	require.NoError(t, err)
+	_, certPEM, err := ca.SignNodeCertificate(csr, 7, time.Hour)
+	require.NoError(t, err)
+	creds, err := pki.SaveNodeCredentials(t.TempDir(), key, certPEM, ca.CertificatePEM())
+	require.NoError(t, err)
+
+	// No tokens are accepted, only the controller's certificate
+	dial, hook := startAuthenticatedAgent(t, nil, creds.ServerTLSConfig())
+
+	controller, err := ca.IssueControllerCertificate(time.Hour)
+	require.NoError(t, err)
+	client := dial(grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{controller},
+		RootCAs:      ca.Pool(),
+		ServerName:   pki.NodeName(7),
+	})))
+
+	configured, err := client.ConfigureGPIOPin(context.Background(), &pb.ConfigureGPIOPinRequest{
+		Pin:       17,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+	})
+	require.NoError(t, err)
+	require.True(t, configured.Success, configured.Message)
+	_, err = client.WriteGPIOPin(context.Background(), &pb.WriteGPIOPinRequest{Pin: 17, Value: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{pki.ControllerName}, auditedUsers(hook, "pin_write"))
+
+	// Without a controller certificate the handshake fails, and a token
+	// cannot stand in for it
+	issuer, _ := newTestTokenIssuer(t)
+	anonymous := dial(grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		RootCAs:    ca.Pool(),
+		ServerName: pki.NodeName(7),
+	})))
+	ctx, cancel := context.WithTimeout(withToken(t, issuer, "otto", middleware.RoleAdmin), 5*time.Second)
+	defer cancel()
+	_, err = anonymous.WriteGPIOPin(ctx, &pb.WriteGPIOPinRequest{Pin: 17, Value: 0})
+	assert.Error(t, err)
+}
+
+func TestNewServer_TokensRequireTLS(t *testing.T) {
+	_, verifier := newTestTokenIssuer(t)
+	config := DefaultConfig()
+	config.TokenVerifier = verifier
+
+	_, err := NewServer(config, logger.Default())
+	assert.ErrorContains(t, err, "requires TLS")
+}
+
+func TestUserIDFromContext(t *testing.T) {
+	assert.Equal(t, systemUserID, UserIDFromContext(context.Background()), "calls from within the agent act as the agent")
+}
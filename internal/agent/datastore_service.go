@@ -0,0 +1,372 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+const (
+	// datastoreChunkSize is the size of each snapshot chunk streamed
+	datastoreChunkSize = 64 * 1024
+
+	// defaultSnapshotName names snapshots when the request does not
+	defaultSnapshotName = "pi-controller"
+
+	// serviceStartTimeout bounds starting k3s again after a restore
+	serviceStartTimeout = 2 * time.Minute
+)
+
+// snapshotNamePattern restricts snapshot names to safe file names
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// commandRunner runs a command and returns its combined output
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand runs a command on the host
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// DatastoreConfig contains settings for the k3s datastore of a server node
+type DatastoreConfig struct {
+	// DataDir is the k3s server data directory
+	DataDir string
+
+	// Service is the systemd unit running k3s
+	Service string
+
+	// TempDir holds snapshots while they are streamed
+	TempDir string
+}
+
+// DatastoreService snapshots and restores the k3s datastore of a server
+// node, either embedded etcd or the default SQLite database
+type DatastoreService struct {
+	pb.UnimplementedPiAgentServiceServer
+	config DatastoreConfig
+	run    commandRunner
+	logger logger.Interface
+}
+
+// NewDatastoreService creates a new datastore service instance
+func NewDatastoreService(config DatastoreConfig, logger logger.Interface) *DatastoreService {
+	if config.DataDir == "" {
+		config.DataDir = "/var/lib/rancher/k3s/server"
+	}
+	if config.Service == "" {
+		config.Service = "k3s"
+	}
+	if config.TempDir == "" {
+		config.TempDir = os.TempDir()
+	}
+
+	return &DatastoreService{
+		config: config,
+		run:    runCommand,
+		logger: logger.WithField("component", "datastore-service"),
+	}
+}
+
+// sqlitePath is the k3s SQLite database
+func (s *DatastoreService) sqlitePath() string {
+	return filepath.Join(s.config.DataDir, "db", "state.db")
+}
+
+// detect returns which datastore the node's k3s server uses
+func (s *DatastoreService) detect() (pb.AgentDatastore, error) {
+	if info, err := os.Stat(filepath.Join(s.config.DataDir, "db", "etcd")); err == nil && info.IsDir() {
+		return pb.AgentDatastore_AGENT_DATASTORE_ETCD, nil
+	}
+	if _, err := os.Stat(s.sqlitePath()); err == nil {
+		return pb.AgentDatastore_AGENT_DATASTORE_SQLITE, nil
+	}
+	return pb.AgentDatastore_AGENT_DATASTORE_UNSPECIFIED,
+		status.Errorf(codes.FailedPrecondition, "no k3s datastore found in %s", s.config.DataDir)
+}
+
+// SnapshotDatastore takes a consistent snapshot of the node's datastore and
+// streams it back
+func (s *DatastoreService) SnapshotDatastore(req *pb.SnapshotDatastoreRequest, stream pb.PiAgentService_SnapshotDatastoreServer) error {
+	name := req.Name
+	if name == "" {
+		name = defaultSnapshotName
+	}
+	if !snapshotNamePattern.MatchString(name) {
+		return status.Errorf(codes.InvalidArgument, "invalid snapshot name %q", name)
+	}
+
+	datastore, err := s.detect()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp(s.config.TempDir, "datastore-snapshot")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create snapshot directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var path string
+	switch datastore {
+	case pb.AgentDatastore_AGENT_DATASTORE_ETCD:
+		path, err = s.snapshotEtcd(stream.Context(), dir, name)
+	default:
+		path, err = s.snapshotSQLite(stream.Context(), dir)
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to snapshot datastore")
+		return status.Errorf(codes.Internal, "failed to snapshot datastore: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open snapshot: %v", err)
+	}
+	defer file.Close()
+
+	var size int64
+	buf := make([]byte, datastoreChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.DatastoreChunk{Datastore: datastore, Data: buf[:n]}); err != nil {
+				return err
+			}
+			size += int64(n)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read snapshot: %v", err)
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"datastore":  datastore.String(),
+		"name":       name,
+		"size_bytes": size,
+	}).Info("Datastore snapshot streamed")
+	return nil
+}
+
+// snapshotEtcd saves an etcd snapshot with k3s into dir and returns its path
+func (s *DatastoreService) snapshotEtcd(ctx context.Context, dir, name string) (string, error) {
+	if output, err := s.run(ctx, "k3s", "etcd-snapshot", "save", "--dir", dir, "--name", name); err != nil {
+		return "", fmt.Errorf("k3s etcd-snapshot save: %w: %s", err, output)
+	}
+
+	// k3s appends the node name and a timestamp to the snapshot name
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			return filepath.Join(dir, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("k3s etcd-snapshot save wrote no snapshot")
+}
+
+// snapshotSQLite copies the SQLite database into dir while k3s keeps running
+func (s *DatastoreService) snapshotSQLite(ctx context.Context, dir string) (string, error) {
+	db, err := sql.Open("sqlite3", "file:"+s.sqlitePath()+"?mode=ro")
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	path := filepath.Join(dir, "state.db")
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return "", fmt.Errorf("failed to copy database: %w", err)
+	}
+	return path, nil
+}
+
+// RestoreDatastore replaces the node's datastore with a streamed snapshot,
+// stopping k3s while it does and starting it again afterwards
+func (s *DatastoreService) RestoreDatastore(stream pb.PiAgentService_RestoreDatastoreServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	datastore, err := s.detect()
+	if err != nil {
+		return err
+	}
+	if first.Datastore != datastore {
+		return status.Errorf(codes.FailedPrecondition, "node uses a %s datastore, the snapshot is %s", datastore, first.Datastore)
+	}
+
+	file, err := os.CreateTemp(s.config.TempDir, "datastore-restore")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create restore file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	var size int64
+	msg := first
+	for {
+		n, err := file.Write(msg.Data)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to write restore file: %v", err)
+		}
+		size += int64(n)
+
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to write restore file: %v", err)
+	}
+	if size == 0 {
+		return status.Error(codes.InvalidArgument, "snapshot is empty")
+	}
+
+	ctx := stream.Context()
+	if err := s.restore(ctx, datastore, file.Name()); err != nil {
+		s.logger.WithError(err).Error("Failed to restore datastore")
+		return status.Errorf(codes.Internal, "failed to restore datastore: %v", err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"datastore":  datastore.String(),
+		"size_bytes": size,
+	}).Info("Datastore restored")
+
+	return stream.SendAndClose(&pb.RestoreDatastoreResponse{
+		Message:   fmt.Sprintf("restored %s datastore and restarted %s", datastore, s.config.Service),
+		SizeBytes: size,
+	})
+}
+
+// restore stops k3s, replaces the datastore with the snapshot at path and
+// starts k3s again, even if the restore failed
+func (s *DatastoreService) restore(ctx context.Context, datastore pb.AgentDatastore, path string) (err error) {
+	if output, err := s.run(ctx, "systemctl", "stop", s.config.Service); err != nil {
+		return fmt.Errorf("failed to stop %s: %w: %s", s.config.Service, err, output)
+	}
+	defer func() {
+		// Start k3s again even if the caller went away during the restore
+		startCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serviceStartTimeout)
+		defer cancel()
+		if output, startErr := s.run(startCtx, "systemctl", "start", s.config.Service); startErr != nil && err == nil {
+			err = fmt.Errorf("failed to start %s: %w: %s", s.config.Service, startErr, output)
+		}
+	}()
+
+	if datastore == pb.AgentDatastore_AGENT_DATASTORE_ETCD {
+		output, err := s.run(ctx, "k3s", "server", "--cluster-reset", "--cluster-reset-restore-path="+path)
+		if err != nil {
+			return fmt.Errorf("k3s cluster reset: %w: %s", err, output)
+		}
+		return nil
+	}
+
+	// Stage the snapshot next to the database so it can be renamed into place
+	current := s.sqlitePath()
+	staged, err := stageFile(path, filepath.Dir(current))
+	if err != nil {
+		return fmt.Errorf("failed to stage database: %w", err)
+	}
+	defer os.Remove(staged)
+	if err := checkSQLite(ctx, staged); err != nil {
+		return fmt.Errorf("snapshot is not a usable database: %w", err)
+	}
+
+	// Keep the replaced database and its journal next to the restored one, and
+	// put them back if the restored one cannot be moved into place
+	aside := fmt.Sprintf("%s.pre-restore-%d", current, time.Now().Unix())
+	var moved []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(moved) - 1; i >= 0; i-- {
+			if rollbackErr := os.Rename(aside+moved[i], current+moved[i]); rollbackErr != nil {
+				s.logger.WithError(rollbackErr).Error("Failed to put back replaced database")
+			}
+		}
+	}()
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Rename(current+suffix, aside+suffix); err != nil {
+			if os.IsNotExist(err) && suffix != "" {
+				continue
+			}
+			return fmt.Errorf("failed to move aside %s: %w", current+suffix, err)
+		}
+		moved = append(moved, suffix)
+	}
+
+	if err := os.Rename(staged, current); err != nil {
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+	return nil
+}
+
+// checkSQLite runs SQLite's integrity check on the database at path
+func checkSQLite(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&immutable=1")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+	return nil
+}
+
+// stageFile copies src to a new file in dir, synced to disk, and returns its
+// path
+func stageFile(src, dir string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(dir, ".restore-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
@@ -0,0 +1,295 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// startDatastoreAgent serves a datastore service over a k3s data directory,
+// recording the commands it runs instead of running them
+func startDatastoreAgent(t *testing.T, dataDir string, run commandRunner) pb.PiAgentServiceClient {
+	service := NewDatastoreService(DatastoreConfig{DataDir: dataDir, TempDir: t.TempDir()}, logger.Default())
+	service.run = run
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewPiAgentServiceClient(conn)
+}
+
+// commandLog records the commands a datastore service runs
+type commandLog struct {
+	commands []string
+	onRun    func(args []string)
+}
+
+func (l *commandLog) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	l.commands = append(l.commands, name+" "+strings.Join(args, " "))
+	if l.onRun != nil {
+		l.onRun(append([]string{name}, args...))
+	}
+	return nil, nil
+}
+
+func snapshot(t *testing.T, client pb.PiAgentServiceClient, name string) (pb.AgentDatastore, []byte, error) {
+	stream, err := client.SnapshotDatastore(context.Background(), &pb.SnapshotDatastoreRequest{Name: name})
+	require.NoError(t, err)
+
+	var data bytes.Buffer
+	datastore := pb.AgentDatastore_AGENT_DATASTORE_UNSPECIFIED
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return datastore, data.Bytes(), nil
+		}
+		if err != nil {
+			return datastore, nil, err
+		}
+		datastore = chunk.Datastore
+		data.Write(chunk.Data)
+	}
+}
+
+func restore(t *testing.T, client pb.PiAgentServiceClient, datastore pb.AgentDatastore, data []byte) (*pb.RestoreDatastoreResponse, error) {
+	stream, err := client.RestoreDatastore(context.Background())
+	require.NoError(t, err)
+
+	for len(data) > 0 {
+		n := min(len(data), 1000)
+		require.NoError(t, stream.Send(&pb.RestoreDatastoreRequest{Datastore: datastore, Data: data[:n]}))
+		data = data[n:]
+	}
+	return stream.CloseAndRecv()
+}
+
+// kineDatabase creates a k3s SQLite database at path holding the given keys
+func kineDatabase(t *testing.T, path string, names ...string) {
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE kine (name TEXT)")
+	require.NoError(t, err)
+	for _, name := range names {
+		_, err = db.Exec("INSERT INTO kine VALUES (?)", name)
+		require.NoError(t, err)
+	}
+}
+
+// kineRows returns the keys in a k3s SQLite database
+func kineRows(t *testing.T, path string) []string {
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT name FROM kine ORDER BY name")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestDatastoreService_SQLite(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "db"), 0o755))
+	statePath := filepath.Join(dataDir, "db", "state.db")
+
+	db, err := sql.Open("sqlite3", statePath)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE kine (name TEXT); INSERT INTO kine VALUES ('/registry/pods/default/blinker')")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	commands := &commandLog{}
+	client := startDatastoreAgent(t, dataDir, commands.run)
+
+	datastore, data, err := snapshot(t, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, pb.AgentDatastore_AGENT_DATASTORE_SQLITE, datastore)
+	assert.Empty(t, commands.commands, "SQLite is copied while k3s runs")
+
+	// Change the database after the snapshot, then restore it
+	db, err = sql.Open("sqlite3", statePath)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO kine VALUES ('/registry/pods/default/later')")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	resp, err := restore(t, client, datastore, data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), resp.SizeBytes)
+	assert.Equal(t, []string{"systemctl stop k3s", "systemctl start k3s"}, commands.commands)
+	assert.Equal(t, []string{"/registry/pods/default/blinker"}, kineRows(t, statePath))
+
+	replaced, err := filepath.Glob(statePath + ".pre-restore-*")
+	require.NoError(t, err)
+	assert.Len(t, replaced, 1, "the replaced database is kept")
+
+	_, err = restore(t, client, pb.AgentDatastore_AGENT_DATASTORE_ETCD, data)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "an etcd snapshot cannot restore SQLite")
+}
+
+func TestDatastoreService_SQLiteRollback(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "db"), 0o755))
+	statePath := filepath.Join(dataDir, "db", "state.db")
+
+	db, err := sql.Open("sqlite3", statePath)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE kine (name TEXT); INSERT INTO kine VALUES ('/registry/pods/default/blinker')")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	require.NoError(t, os.WriteFile(statePath+"-wal", nil, 0o600))
+
+	// Occupy where the journal would be moved aside once k3s is stopped, so
+	// the restore fails after the database was moved
+	commands := &commandLog{onRun: func(args []string) {
+		if args[1] != "stop" {
+			return
+		}
+		now := time.Now().Unix()
+		for _, at := range []int64{now, now + 1} {
+			occupied := fmt.Sprintf("%s.pre-restore-%d-wal", statePath, at)
+			require.NoError(t, os.MkdirAll(filepath.Join(occupied, "occupied"), 0o755))
+		}
+	}}
+	client := startDatastoreAgent(t, dataDir, commands.run)
+
+	snapshotPath := filepath.Join(t.TempDir(), "state.db")
+	kineDatabase(t, snapshotPath, "/registry/pods/default/restored")
+	data, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+
+	_, err = restore(t, client, pb.AgentDatastore_AGENT_DATASTORE_SQLITE, data)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, []string{"systemctl stop k3s", "systemctl start k3s"}, commands.commands)
+	assert.Equal(t, []string{"/registry/pods/default/blinker"}, kineRows(t, statePath), "the replaced database is put back")
+	assert.FileExists(t, statePath+"-wal")
+
+	staged, err := filepath.Glob(filepath.Join(dataDir, "db", ".restore-*"))
+	require.NoError(t, err)
+	assert.Empty(t, staged, "the staged snapshot is removed")
+}
+
+func TestDatastoreService_SQLiteCorrupt(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "db"), 0o755))
+	statePath := filepath.Join(dataDir, "db", "state.db")
+	kineDatabase(t, statePath, "/registry/pods/default/blinker")
+
+	commands := &commandLog{}
+	client := startDatastoreAgent(t, dataDir, commands.run)
+
+	_, err := restore(t, client, pb.AgentDatastore_AGENT_DATASTORE_SQLITE, []byte("not the database"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "not a usable database")
+	assert.Equal(t, []string{"systemctl stop k3s", "systemctl start k3s"}, commands.commands)
+	assert.Equal(t, []string{"/registry/pods/default/blinker"}, kineRows(t, statePath))
+
+	replaced, err := filepath.Glob(statePath + ".pre-restore-*")
+	require.NoError(t, err)
+	assert.Empty(t, replaced, "the database is left in place")
+}
+
+func TestDatastoreService_RestartAfterCancel(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "db", "etcd"), 0o755))
+	service := NewDatastoreService(DatastoreConfig{DataDir: dataDir, TempDir: t.TempDir()}, logger.Default())
+
+	// The caller goes away while k3s is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var startErr error
+	var started bool
+	service.run = func(runCtx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case name == "systemctl" && args[0] == "stop":
+			cancel()
+		case name == "systemctl" && args[0] == "start":
+			started = true
+			startErr = runCtx.Err()
+			_, hasDeadline := runCtx.Deadline()
+			assert.True(t, hasDeadline, "starting k3s is bounded")
+		}
+		return nil, nil
+	}
+
+	require.NoError(t, service.restore(ctx, pb.AgentDatastore_AGENT_DATASTORE_ETCD, "snapshot"))
+	assert.True(t, started)
+	assert.NoError(t, startErr, "k3s is started with a live context")
+}
+
+func TestDatastoreService_Etcd(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "db", "etcd"), 0o755))
+
+	commands := &commandLog{}
+	commands.onRun = func(args []string) {
+		// Write the snapshot where k3s etcd-snapshot save --dir would
+		if len(args) > 4 && args[1] == "etcd-snapshot" {
+			assert.NoError(t, os.WriteFile(filepath.Join(args[4], args[6]+"-pi-1-1760600000"), []byte("etcd snapshot"), 0o600))
+		}
+	}
+	client := startDatastoreAgent(t, dataDir, commands.run)
+
+	datastore, data, err := snapshot(t, client, "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, pb.AgentDatastore_AGENT_DATASTORE_ETCD, datastore)
+	assert.Equal(t, "etcd snapshot", string(data))
+
+	_, _, err = snapshot(t, client, "../escape")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	commands.commands = nil
+	_, err = restore(t, client, datastore, data)
+	require.NoError(t, err)
+	require.Len(t, commands.commands, 3)
+	assert.Equal(t, "systemctl stop k3s", commands.commands[0])
+	assert.True(t, strings.HasPrefix(commands.commands[1], "k3s server --cluster-reset --cluster-reset-restore-path="))
+	assert.Equal(t, "systemctl start k3s", commands.commands[2])
+}
+
+func TestDatastoreService_NotAServer(t *testing.T) {
+	client := startDatastoreAgent(t, t.TempDir(), (&commandLog{}).run)
+
+	_, _, err := snapshot(t, client, "")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
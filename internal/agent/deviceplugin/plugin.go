@@ -0,0 +1,437 @@
+// Package deviceplugin advertises a node's GPIO devices to the kubelet as
+// pi-controller.io/gpio resources, so pods can request pins, and reports which
+// pods hold them back to the controller.
+package deviceplugin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+const (
+	// socketName is the plugin's socket in the kubelet's device plugin directory
+	socketName = "pi-controller-gpio.sock"
+
+	// PinsEnv lists the pins allocated to a container, comma separated
+	PinsEnv = "PI_CONTROLLER_GPIO_PINS"
+
+	// DefaultPodResourcesSocket is the kubelet's pod resources API socket
+	DefaultPodResourcesSocket = "/var/lib/kubelet/pod-resources/kubelet.sock"
+
+	// DefaultRefreshInterval is how often devices and allocations are re-read
+	DefaultRefreshInterval = 30 * time.Second
+
+	// kubeletTimeout bounds each call to the kubelet and the controller
+	kubeletTimeout = 10 * time.Second
+
+	// allocationSettle is how long after an Allocate allocations are synced,
+	// giving the kubelet time to record it
+	allocationSettle = 2 * time.Second
+)
+
+// ControllerClient is the part of the controller's gRPC API the plugin uses
+type ControllerClient interface {
+	ListGPIODevices(ctx context.Context, nodeID uint32) ([]*pb.GPIODevice, error)
+	ReportGPIOAllocations(ctx context.Context, nodeID uint32, allocations map[int]string) error
+}
+
+// Config contains device plugin settings
+type Config struct {
+	// PluginDir is the kubelet's device plugin directory, holding its
+	// registration socket and the plugin's own socket
+	PluginDir string
+
+	// PodResourcesSocket is the kubelet's pod resources API socket
+	PodResourcesSocket string
+
+	// DevDir is where GPIO character devices are found
+	DevDir string
+
+	// RefreshInterval is how often devices and allocations are re-read
+	RefreshInterval time.Duration
+}
+
+// withDefaults fills in unset settings
+func (c Config) withDefaults() Config {
+	if c.PluginDir == "" {
+		c.PluginDir = pluginapi.DevicePluginPath
+	}
+	if c.PodResourcesSocket == "" {
+		c.PodResourcesSocket = DefaultPodResourcesSocket
+	}
+	if c.DevDir == "" {
+		c.DevDir = "/dev"
+	}
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = DefaultRefreshInterval
+	}
+	return c
+}
+
+// Plugin is a kubelet device plugin serving one device per active GPIO device
+// configured on the node. Devices are named gpio-<pin>.
+type Plugin struct {
+	pluginapi.UnimplementedDevicePluginServer
+
+	config Config
+	client ControllerClient
+	nodeID uint32
+	logger logger.Interface
+
+	mu      sync.Mutex
+	pins    []int
+	changed chan struct{}
+
+	// reported is the last allocation set the controller accepted
+	reported map[int]string
+
+	// allocated asks the run loop to sync allocations after an Allocate
+	allocated chan struct{}
+
+	server *grpc.Server
+	stop   chan struct{}
+}
+
+// New creates a device plugin for the GPIO devices of a node
+func New(config Config, client ControllerClient, nodeID uint32, logger logger.Interface) *Plugin {
+	return &Plugin{
+		config:    config.withDefaults(),
+		client:    client,
+		nodeID:    nodeID,
+		logger:    logger.WithField("component", "device-plugin"),
+		changed:   make(chan struct{}),
+		allocated: make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Run serves the plugin and registers it with the kubelet, re-registering
+// whenever the kubelet restarts, until ctx is cancelled
+func (p *Plugin) Run(ctx context.Context) error {
+	p.refreshPins(ctx)
+
+	if err := p.serve(); err != nil {
+		return err
+	}
+	defer p.shutdown()
+
+	if err := p.register(ctx); err != nil {
+		return err
+	}
+	p.syncAllocations(ctx)
+
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.allocated:
+			settled = time.After(allocationSettle)
+		case <-settled:
+			settled = nil
+			p.syncAllocations(ctx)
+		case <-ticker.C:
+			// The kubelet removes every plugin socket when it restarts
+			if _, err := os.Stat(p.socketPath()); err != nil {
+				p.logger.Info("Plugin socket removed, re-registering with the kubelet")
+				p.server.Stop()
+				if err := p.serve(); err != nil {
+					return err
+				}
+				if err := p.register(ctx); err != nil {
+					p.logger.WithError(err).Warn("Failed to re-register with the kubelet")
+				}
+			}
+			p.refreshPins(ctx)
+			p.syncAllocations(ctx)
+		}
+	}
+}
+
+// socketPath is where the plugin serves
+func (p *Plugin) socketPath() string {
+	return filepath.Join(p.config.PluginDir, socketName)
+}
+
+// serve starts the plugin's gRPC server on its socket
+func (p *Plugin) serve() error {
+	socket := p.socketPath()
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale plugin socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+
+	server := grpc.NewServer()
+	pluginapi.RegisterDevicePluginServer(server, p)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			p.logger.WithError(err).Error("Device plugin server stopped")
+		}
+	}()
+	p.server = server
+	return nil
+}
+
+// shutdown stops the server, ends ListAndWatch streams and removes the socket
+func (p *Plugin) shutdown() {
+	close(p.stop)
+	p.server.Stop()
+	if err := os.Remove(p.socketPath()); err != nil && !os.IsNotExist(err) {
+		p.logger.WithError(err).Warn("Failed to remove plugin socket")
+	}
+}
+
+// dial connects to one of the kubelet's unix sockets
+func dial(socket string) (*grpc.ClientConn, error) {
+	return grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// register announces the plugin to the kubelet
+func (p *Plugin) register(ctx context.Context) error {
+	conn, err := dial(filepath.Join(p.config.PluginDir, filepath.Base(pluginapi.KubeletSocket)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to the kubelet: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, kubeletTimeout)
+	defer cancel()
+
+	_, err = pluginapi.NewRegistrationClient(conn).Register(ctx, &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     socketName,
+		ResourceName: models.GPIOResourceName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register with the kubelet: %w", err)
+	}
+
+	p.logger.WithField("resource", models.GPIOResourceName).Info("Registered with the kubelet")
+	return nil
+}
+
+// refreshPins reloads the node's active GPIO devices from the controller and
+// wakes ListAndWatch streams when they change
+func (p *Plugin) refreshPins(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, kubeletTimeout)
+	defer cancel()
+
+	devices, err := p.client.ListGPIODevices(ctx, p.nodeID)
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to list GPIO devices")
+		return
+	}
+
+	var pins []int
+	for _, device := range devices {
+		if device.Status == pb.GPIOStatus_GPIO_STATUS_ACTIVE {
+			pins = append(pins, int(device.PinNumber))
+		}
+	}
+	slices.Sort(pins)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if slices.Equal(pins, p.pins) {
+		return
+	}
+	p.pins = pins
+	close(p.changed)
+	p.changed = make(chan struct{})
+
+	p.logger.WithField("pins", pins).Info("GPIO devices changed")
+}
+
+// snapshot returns the current pins and a channel closed when they change
+func (p *Plugin) snapshot() ([]int, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pins, p.changed
+}
+
+// syncAllocations reads which containers hold GPIO devices from the kubelet's
+// pod resources API and reports them to the controller when they change
+func (p *Plugin) syncAllocations(ctx context.Context) {
+	allocations, err := p.listAllocations(ctx)
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to list GPIO allocations")
+		return
+	}
+	if p.reported != nil && maps.Equal(allocations, p.reported) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kubeletTimeout)
+	defer cancel()
+	if err := p.client.ReportGPIOAllocations(ctx, p.nodeID, allocations); err != nil {
+		p.logger.WithError(err).Warn("Failed to report GPIO allocations")
+		return
+	}
+	p.reported = allocations
+
+	p.logger.WithField("allocations", len(allocations)).Info("Reported GPIO allocations")
+}
+
+// listAllocations maps allocated pins to the namespace/pod/container holding them
+func (p *Plugin) listAllocations(ctx context.Context) (map[int]string, error) {
+	conn, err := dial(p.config.PodResourcesSocket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the pod resources API: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, kubeletTimeout)
+	defer cancel()
+
+	resp, err := podresourcesapi.NewPodResourcesListerClient(conn).List(ctx, &podresourcesapi.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod resources: %w", err)
+	}
+
+	allocations := make(map[int]string)
+	for _, pod := range resp.PodResources {
+		for _, container := range pod.Containers {
+			for _, devices := range container.Devices {
+				if devices.ResourceName != models.GPIOResourceName {
+					continue
+				}
+				for _, id := range devices.DeviceIds {
+					pin, err := parseDeviceID(id)
+					if err != nil {
+						p.logger.WithError(err).Warn("Ignoring unknown GPIO device")
+						continue
+					}
+					allocations[pin] = pod.Namespace + "/" + pod.Name + "/" + container.Name
+				}
+			}
+		}
+	}
+	return allocations, nil
+}
+
+// parseDeviceID returns the pin of a gpio-<pin> device ID
+func parseDeviceID(id string) (int, error) {
+	pin, err := strconv.Atoi(strings.TrimPrefix(id, "gpio-"))
+	if err != nil || !strings.HasPrefix(id, "gpio-") {
+		return 0, fmt.Errorf("invalid GPIO device ID %q", id)
+	}
+	return pin, nil
+}
+
+// GetDevicePluginOptions reports that the plugin needs no optional calls
+func (p *Plugin) GetDevicePluginOptions(context.Context, *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	return &pluginapi.DevicePluginOptions{}, nil
+}
+
+// ListAndWatch streams the node's GPIO devices, resending them on every change
+func (p *Plugin) ListAndWatch(_ *pluginapi.Empty, stream pluginapi.DevicePlugin_ListAndWatchServer) error {
+	for {
+		pins, changed := p.snapshot()
+
+		devices := make([]*pluginapi.Device, 0, len(pins))
+		for _, pin := range pins {
+			devices = append(devices, &pluginapi.Device{
+				ID:     (&models.GPIODevice{PinNumber: pin}).PluginDeviceID(),
+				Health: pluginapi.Healthy,
+			})
+		}
+		if err := stream.Send(&pluginapi.ListAndWatchResponse{Devices: devices}); err != nil {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		case <-p.stop:
+			return nil
+		}
+	}
+}
+
+// Allocate gives each container its pins in PinsEnv along with the GPIO
+// character devices of the node. A character device covers every line of its
+// chip, so the kernel cannot hold a container to its allocated pins; it only
+// refuses lines another process, such as the agent, has already requested.
+// /dev/gpiomem is not exposed because it maps the GPIO registers directly,
+// bypassing even that.
+func (p *Plugin) Allocate(_ context.Context, req *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	pins, _ := p.snapshot()
+	deviceSpecs := p.gpioDeviceSpecs()
+
+	resp := &pluginapi.AllocateResponse{}
+	for _, container := range req.ContainerRequests {
+		allocated := make([]string, 0, len(container.DevicesIDs))
+		for _, id := range container.DevicesIDs {
+			pin, err := parseDeviceID(id)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			if !slices.Contains(pins, pin) {
+				return nil, status.Errorf(codes.InvalidArgument, "GPIO pin %d is not configured on this node", pin)
+			}
+			allocated = append(allocated, strconv.Itoa(pin))
+		}
+
+		resp.ContainerResponses = append(resp.ContainerResponses, &pluginapi.ContainerAllocateResponse{
+			Envs:    map[string]string{PinsEnv: strings.Join(allocated, ",")},
+			Devices: deviceSpecs,
+		})
+
+		p.logger.WithField("pins", allocated).Info("Allocated GPIO pins")
+	}
+
+	select {
+	case p.allocated <- struct{}{}:
+	default:
+	}
+
+	return resp, nil
+}
+
+// gpioDeviceSpecs lists the GPIO character devices to expose to containers
+func (p *Plugin) gpioDeviceSpecs() []*pluginapi.DeviceSpec {
+	chips, _ := filepath.Glob(filepath.Join(p.config.DevDir, "gpiochip*"))
+
+	specs := make([]*pluginapi.DeviceSpec, 0, len(chips))
+	for _, path := range chips {
+		specs = append(specs, &pluginapi.DeviceSpec{
+			HostPath:      path,
+			ContainerPath: "/dev/" + filepath.Base(path),
+			Permissions:   "rw",
+		})
+	}
+	return specs
+}
+
+// PreStartContainer is not used
+func (p *Plugin) PreStartContainer(context.Context, *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	return &pluginapi.PreStartContainerResponse{}, nil
+}
@@ -0,0 +1,239 @@
+package deviceplugin
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// fakeController serves a node's GPIO devices and records reported allocations
+type fakeController struct {
+	mu      sync.Mutex
+	devices []*pb.GPIODevice
+	reports chan map[int]string
+}
+
+func (c *fakeController) setDevices(devices ...*pb.GPIODevice) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.devices = devices
+}
+
+func (c *fakeController) ListGPIODevices(_ context.Context, _ uint32) ([]*pb.GPIODevice, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.devices, nil
+}
+
+func (c *fakeController) ReportGPIOAllocations(_ context.Context, _ uint32, allocations map[int]string) error {
+	c.reports <- allocations
+	return nil
+}
+
+// fakeKubelet accepts registrations and lists pod resources
+type fakeKubelet struct {
+	pluginapi.UnimplementedRegistrationServer
+	podresourcesapi.UnimplementedPodResourcesListerServer
+
+	registrations chan *pluginapi.RegisterRequest
+
+	mu   sync.Mutex
+	pods []*podresourcesapi.PodResources
+}
+
+func (k *fakeKubelet) Register(_ context.Context, req *pluginapi.RegisterRequest) (*pluginapi.Empty, error) {
+	k.registrations <- req
+	return &pluginapi.Empty{}, nil
+}
+
+func (k *fakeKubelet) List(context.Context, *podresourcesapi.ListPodResourcesRequest) (*podresourcesapi.ListPodResourcesResponse, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return &podresourcesapi.ListPodResourcesResponse{PodResources: k.pods}, nil
+}
+
+func (k *fakeKubelet) setPods(pods ...*podresourcesapi.PodResources) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.pods = pods
+}
+
+// serveUnix serves a gRPC server on a unix socket for the test's duration
+func serveUnix(t *testing.T, socket string, register func(*grpc.Server)) {
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+}
+
+func gpioDevice(pin int32, status pb.GPIOStatus) *pb.GPIODevice {
+	return &pb.GPIODevice{PinNumber: pin, Status: status}
+}
+
+func deviceIDs(resp *pluginapi.ListAndWatchResponse) []string {
+	ids := make([]string, 0, len(resp.Devices))
+	for _, device := range resp.Devices {
+		ids = append(ids, device.ID)
+	}
+	return ids
+}
+
+func TestPlugin(t *testing.T) {
+	// Unix socket paths are limited in length, so avoid t.TempDir
+	dir, err := os.MkdirTemp("", "gpio-plugin")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	devDir := filepath.Join(dir, "dev")
+	require.NoError(t, os.Mkdir(devDir, 0o755))
+	for _, name := range []string{"gpiochip0", "gpiomem", "null"} {
+		require.NoError(t, os.WriteFile(filepath.Join(devDir, name), nil, 0o600))
+	}
+
+	kubelet := &fakeKubelet{registrations: make(chan *pluginapi.RegisterRequest, 4)}
+	serveUnix(t, filepath.Join(dir, "kubelet.sock"), func(s *grpc.Server) {
+		pluginapi.RegisterRegistrationServer(s, kubelet)
+	})
+	serveUnix(t, filepath.Join(dir, "pod-resources.sock"), func(s *grpc.Server) {
+		podresourcesapi.RegisterPodResourcesListerServer(s, kubelet)
+	})
+
+	controller := &fakeController{reports: make(chan map[int]string, 16)}
+	controller.setDevices(
+		gpioDevice(18, pb.GPIOStatus_GPIO_STATUS_ACTIVE),
+		gpioDevice(4, pb.GPIOStatus_GPIO_STATUS_INACTIVE),
+	)
+
+	plugin := New(Config{
+		PluginDir:          dir,
+		PodResourcesSocket: filepath.Join(dir, "pod-resources.sock"),
+		DevDir:             devDir,
+		RefreshInterval:    20 * time.Millisecond,
+	}, controller, 1, logger.Default())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- plugin.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+		assert.NoFileExists(t, filepath.Join(dir, socketName), "the plugin removes its socket")
+	})
+
+	t.Run("registers with the kubelet", func(t *testing.T) {
+		select {
+		case req := <-kubelet.registrations:
+			assert.Equal(t, pluginapi.Version, req.Version)
+			assert.Equal(t, socketName, req.Endpoint)
+			assert.Equal(t, models.GPIOResourceName, req.ResourceName)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the plugin did not register")
+		}
+
+		select {
+		case allocations := <-controller.reports:
+			assert.Empty(t, allocations, "nothing is allocated yet")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the plugin did not report allocations")
+		}
+	})
+
+	conn, err := dial(filepath.Join(dir, socketName))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pluginapi.NewDevicePluginClient(conn)
+
+	t.Run("lists active devices and resends them on change", func(t *testing.T) {
+		stream, err := client.ListAndWatch(ctx, &pluginapi.Empty{})
+		require.NoError(t, err)
+
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gpio-18"}, deviceIDs(resp))
+		assert.Equal(t, pluginapi.Healthy, resp.Devices[0].Health)
+
+		controller.setDevices(
+			gpioDevice(18, pb.GPIOStatus_GPIO_STATUS_ACTIVE),
+			gpioDevice(23, pb.GPIOStatus_GPIO_STATUS_ACTIVE),
+		)
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gpio-18", "gpio-23"}, deviceIDs(resp))
+	})
+
+	t.Run("allocates pins with the GPIO character devices", func(t *testing.T) {
+		resp, err := client.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIDs: []string{"gpio-18", "gpio-23"}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.ContainerResponses, 1)
+
+		container := resp.ContainerResponses[0]
+		assert.Equal(t, "18,23", container.Envs[PinsEnv])
+		require.Len(t, container.Devices, 1, "gpiomem is not exposed")
+		assert.Equal(t, filepath.Join(devDir, "gpiochip0"), container.Devices[0].HostPath)
+		assert.Equal(t, "/dev/gpiochip0", container.Devices[0].ContainerPath)
+
+		_, err = client.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIDs: []string{"gpio-4"}}},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "pin 4 is inactive")
+	})
+
+	t.Run("reports pods holding pins", func(t *testing.T) {
+		kubelet.setPods(&podresourcesapi.PodResources{
+			Name:      "blinker",
+			Namespace: "default",
+			Containers: []*podresourcesapi.ContainerResources{{
+				Name: "led",
+				Devices: []*podresourcesapi.ContainerDevices{
+					{ResourceName: models.GPIOResourceName, DeviceIds: []string{"gpio-18"}},
+					{ResourceName: "example.com/other", DeviceIds: []string{"gpio-23"}},
+				},
+			}},
+		})
+
+		want := map[int]string{18: "default/blinker/led"}
+		assert.Eventually(t, func() bool {
+			select {
+			case allocations := <-controller.reports:
+				return assert.ObjectsAreEqual(want, allocations)
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("re-registers when the kubelet removes its socket", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, socketName)))
+
+		select {
+		case <-kubelet.registrations:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the plugin did not re-register")
+		}
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(dir, socketName))
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
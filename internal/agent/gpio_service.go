@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"github.com/sirupsen/logrus"
@@ -17,21 +18,20 @@ type GPIOService struct {
 	pb.UnimplementedPiAgentServiceServer
 	controller *gpio.Controller
 	logger     logger.Interface
+
+	// Edge watchers per pin, see WatchGPIOPin
+	watchMu  sync.Mutex
+	watchers map[int]map[*pinWatcher]struct{}
 }
 
 // NewGPIOService creates a new GPIO service instance
 func NewGPIOService(logger logger.Interface) (*GPIOService, error) {
 	// Create GPIO configuration with secure defaults
 	gpioConfig := gpio.DefaultConfig()
-	securityConfig := gpio.DefaultSecurityConfig()
-	
-	// For agent mode, we can be less restrictive since it's running on the node
-	securityConfig.Level = gpio.SecurityLevelPermissive
-	securityConfig.RequireUserContext = false // Agent operations are system-level
 
 	// Initialize GPIO controller
 	logrusLogger := logrus.New()
-	controller := gpio.NewController(gpioConfig, securityConfig, logrusLogger)
+	controller := gpio.NewController(gpioConfig, agentSecurityConfig(), logrusLogger)
 	
 	return &GPIOService{
 		controller: controller,
@@ -39,6 +39,16 @@ func NewGPIOService(logger logger.Interface) (*GPIOService, error) {
 	}, nil
 }
 
+// agentSecurityConfig returns the GPIO security settings for agent mode, which
+// can be less restrictive since it's running on the node
+func agentSecurityConfig() *gpio.SecurityConfig {
+	securityConfig := gpio.DefaultSecurityConfig()
+	securityConfig.Level = gpio.SecurityLevelPermissive
+	securityConfig.RequireUserContext = false // Agent operations are system-level
+	securityConfig.AllowedOperations = append(securityConfig.AllowedOperations, "pwm", "interrupt")
+	return securityConfig
+}
+
 // Initialize initializes the GPIO service
 func (s *GPIOService) Initialize(ctx context.Context) error {
 	s.logger.Info("Initializing GPIO service")
@@ -86,14 +96,38 @@ func (s *GPIOService) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPI
 		config.PWMDutyCycle = int(req.PwmDutyCycle)
 	}
 
-	// Configure the pin (using empty userID for system operations)
-	if err := s.controller.ConfigurePin(config, "agent"); err != nil {
+	// Watched pins keep their direction, and reconfiguring one stops its
+	// edge detection, so it is enabled again for the watchers
+	userID := UserIDFromContext(ctx)
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	watched := len(s.watchers[config.Pin]) > 0
+	if watched {
+		if current, err := s.controller.GetPinState(config.Pin, userID); err == nil && current.Direction != config.Direction {
+			return &pb.ConfigureGPIOPinResponse{
+				Success: false,
+				Message: fmt.Sprintf("Pin %d is being watched for edges and must stay an %s", req.Pin, current.Direction),
+			}, nil
+		}
+	}
+
+	// Configure the pin on behalf of the authenticated caller
+	if err := s.controller.ConfigurePin(config, userID); err != nil {
 		s.logger.WithError(err).WithField("pin", req.Pin).Error("Failed to configure GPIO pin")
 		return &pb.ConfigureGPIOPinResponse{
 			Success: false,
 			Message: fmt.Sprintf("Failed to configure pin: %v", err),
 		}, nil
 	}
+	if watched {
+		if err := s.enableInterrupt(config.Pin, userID); err != nil {
+			s.logger.WithError(err).WithField("pin", req.Pin).Error("Failed to re-enable GPIO interrupt")
+			return &pb.ConfigureGPIOPinResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to re-enable edge detection: %v", err),
+			}, nil
+		}
+	}
 
 	return &pb.ConfigureGPIOPinResponse{
 		Success:      true,
@@ -106,7 +140,7 @@ func (s *GPIOService) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPI
 func (s *GPIOService) ReadGPIOPin(ctx context.Context, req *pb.ReadGPIOPinRequest) (*pb.ReadGPIOPinResponse, error) {
 	s.logger.WithField("pin", req.Pin).Debug("Reading GPIO pin")
 
-	value, err := s.controller.ReadPin(int(req.Pin), "agent")
+	value, err := s.controller.ReadPin(int(req.Pin), UserIDFromContext(ctx))
 	if err != nil {
 		s.logger.WithError(err).WithField("pin", req.Pin).Error("Failed to read GPIO pin")
 		return nil, fmt.Errorf("failed to read pin %d: %w", req.Pin, err)
@@ -131,7 +165,7 @@ func (s *GPIOService) WriteGPIOPin(ctx context.Context, req *pb.WriteGPIOPinRequ
 		value = gpio.High
 	}
 
-	if err := s.controller.WritePin(int(req.Pin), value, "agent"); err != nil {
+	if err := s.controller.WritePin(int(req.Pin), value, UserIDFromContext(ctx)); err != nil {
 		s.logger.WithError(err).WithFields(map[string]interface{}{
 			"pin":   req.Pin,
 			"value": req.Value,
@@ -154,7 +188,7 @@ func (s *GPIOService) SetGPIOPWM(ctx context.Context, req *pb.SetGPIOPWMRequest)
 		"duty_cycle": req.DutyCycle,
 	}).Info("Setting GPIO PWM")
 
-	if err := s.controller.SetPWM(int(req.Pin), int(req.Frequency), int(req.DutyCycle), "agent"); err != nil {
+	if err := s.controller.SetPWM(int(req.Pin), int(req.Frequency), int(req.DutyCycle), UserIDFromContext(ctx)); err != nil {
 		s.logger.WithError(err).WithFields(map[string]interface{}{
 			"pin":        req.Pin,
 			"frequency":  req.Frequency,
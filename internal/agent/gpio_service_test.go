@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/pkg/gpio"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
 
@@ -302,4 +303,36 @@ func TestInvalidOperations(t *testing.T) {
 
 	_, err = service.WriteGPIOPin(ctx, writeReq)
 	assert.Error(t, err) // Should fail for unconfigured pin
-}
\ No newline at end of file
+}
+func TestConfigureGPIOPin_Unchanged(t *testing.T) {
+	service, _ := startMockAgent(t)
+	ctx := context.Background()
+
+	output := &pb.ConfigureGPIOPinRequest{
+		Pin:       18,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+		PullMode:  pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_NONE,
+	}
+	resp, err := service.ConfigureGPIOPin(ctx, output)
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Message)
+	_, err = service.WriteGPIOPin(ctx, &pb.WriteGPIOPinRequest{Pin: 18, Value: 1})
+	require.NoError(t, err)
+
+	// The controller configures pins before every operation, which must not
+	// drive outputs low
+	resp, err = service.ConfigureGPIOPin(ctx, output)
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Message)
+	state, err := service.controller.GetPinState(18, systemUserID)
+	require.NoError(t, err)
+	assert.Equal(t, gpio.High, state.Value)
+
+	output.PullMode = pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_DOWN
+	resp, err = service.ConfigureGPIOPin(ctx, output)
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Message)
+	state, err = service.controller.GetPinState(18, systemUserID)
+	require.NoError(t, err)
+	assert.Equal(t, gpio.Low, state.Value, "a changed configuration is applied")
+}
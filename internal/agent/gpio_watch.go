@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/pkg/gpio"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+const (
+	// maxDebounce caps the debounce window a watcher may request
+	maxDebounce = 10 * time.Second
+
+	// watchBufferSize is how many edges a slow stream may fall behind before
+	// the oldest are dropped
+	watchBufferSize = 64
+)
+
+// pinWatcher is one WatchGPIOPin stream's view of a pin's edges
+type pinWatcher struct {
+	edge     pb.AgentGPIOEdge
+	debounce time.Duration
+	events   chan *pb.GPIOPinEvent
+	last     time.Time
+}
+
+// accept applies the watcher's edge filter and debounce window to an event
+func (w *pinWatcher) accept(edge pb.AgentGPIOEdge, at time.Time) bool {
+	if w.edge != pb.AgentGPIOEdge_AGENT_GPIO_EDGE_BOTH && w.edge != edge {
+		return false
+	}
+	if !w.last.IsZero() && at.Sub(w.last) < w.debounce {
+		return false
+	}
+	w.last = at
+	return true
+}
+
+// WatchGPIOPin streams edge interrupts on a configured pin. Interrupts are
+// enabled for both edges while any stream watches the pin and each stream
+// filters and debounces independently.
+func (s *GPIOService) WatchGPIOPin(req *pb.WatchGPIOPinRequest, stream pb.PiAgentService_WatchGPIOPinServer) error {
+	debounce := time.Duration(req.DebounceMs) * time.Millisecond
+	if req.DebounceMs < 0 || debounce > maxDebounce {
+		return status.Errorf(codes.InvalidArgument, "debounce_ms must be between 0 and %d", maxDebounce.Milliseconds())
+	}
+
+	edge := req.Edge
+	if edge == pb.AgentGPIOEdge_AGENT_GPIO_EDGE_UNSPECIFIED {
+		edge = pb.AgentGPIOEdge_AGENT_GPIO_EDGE_BOTH
+	}
+
+	pin := int(req.Pin)
+	watcher := &pinWatcher{
+		edge:     edge,
+		debounce: debounce,
+		events:   make(chan *pb.GPIOPinEvent, watchBufferSize),
+	}
+	userID := UserIDFromContext(stream.Context())
+	if err := s.addWatcher(pin, watcher, userID); err != nil {
+		s.logger.WithError(err).WithField("pin", pin).Error("Failed to watch GPIO pin")
+		return status.Errorf(codes.FailedPrecondition, "failed to watch pin %d: %v", pin, err)
+	}
+	defer s.removeWatcher(pin, watcher, userID)
+
+	s.logger.WithFields(map[string]interface{}{
+		"pin":         pin,
+		"edge":        edge.String(),
+		"debounce_ms": req.DebounceMs,
+	}).Info("Watching GPIO pin")
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-watcher.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addWatcher registers a watcher, enabling the pin's interrupt for the first one
+func (s *GPIOService) addWatcher(pin int, watcher *pinWatcher, userID string) error {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	if len(s.watchers[pin]) == 0 {
+		if err := s.enableInterrupt(pin, userID); err != nil {
+			return err
+		}
+	}
+
+	if s.watchers == nil {
+		s.watchers = make(map[int]map[*pinWatcher]struct{})
+	}
+	if s.watchers[pin] == nil {
+		s.watchers[pin] = make(map[*pinWatcher]struct{})
+	}
+	s.watchers[pin][watcher] = struct{}{}
+	return nil
+}
+
+// enableInterrupt enables the pin's interrupt for both edges, dispatching
+// them to its watchers. The caller holds watchMu.
+func (s *GPIOService) enableInterrupt(pin int, userID string) error {
+	handler := func(event gpio.Event) { s.dispatchEdge(pin, event) }
+	return s.controller.EnableInterrupt(pin, gpio.EventBothEdges, handler, userID)
+}
+
+// removeWatcher unregisters a watcher, disabling the interrupt after the last one
+func (s *GPIOService) removeWatcher(pin int, watcher *pinWatcher, userID string) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	delete(s.watchers[pin], watcher)
+	if len(s.watchers[pin]) > 0 {
+		return
+	}
+	delete(s.watchers, pin)
+
+	if err := s.controller.DisableInterrupt(pin, userID); err != nil {
+		s.logger.WithError(err).WithField("pin", pin).Warn("Failed to disable GPIO interrupt")
+	}
+}
+
+// dispatchEdge delivers an interrupt to every watcher of the pin without
+// blocking the interrupt handler
+func (s *GPIOService) dispatchEdge(pin int, event gpio.Event) {
+	edge := pb.AgentGPIOEdge_AGENT_GPIO_EDGE_FALLING
+	if event.Value == gpio.High {
+		edge = pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING
+	}
+	msg := &pb.GPIOPinEvent{
+		Pin:       int32(pin),
+		Edge:      edge,
+		Value:     int32(event.Value),
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	for watcher := range s.watchers[pin] {
+		if !watcher.accept(edge, event.Timestamp) {
+			continue
+		}
+		select {
+		case watcher.events <- msg:
+		default:
+			// Drop the oldest edge so the stream stays current
+			select {
+			case <-watcher.events:
+			default:
+			}
+			watcher.events <- msg
+		}
+	}
+}
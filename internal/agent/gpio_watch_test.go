@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/pkg/gpio"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// startMockAgent serves a GPIO service backed by the mock GPIO implementation
+func startMockAgent(t *testing.T) (*GPIOService, pb.PiAgentServiceClient) {
+	config := gpio.DefaultConfig()
+	config.MockMode = true
+
+	service := &GPIOService{
+		controller: gpio.NewController(config, agentSecurityConfig(), logrus.New()),
+		logger:     logger.Default(),
+	}
+	require.NoError(t, service.Initialize(context.Background()))
+	t.Cleanup(func() { service.Close() })
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return service, pb.NewPiAgentServiceClient(conn)
+}
+
+// recvEvents forwards stream events to a channel so tests can wait with a timeout
+func recvEvents(stream pb.PiAgentService_WatchGPIOPinClient) <-chan *pb.GPIOPinEvent {
+	events := make(chan *pb.GPIOPinEvent, 16)
+	go func() {
+		defer close(events)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			events <- event
+		}
+	}()
+	return events
+}
+
+func nextEdge(t *testing.T, events <-chan *pb.GPIOPinEvent) *pb.GPIOPinEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		require.NotNil(t, event)
+		return event
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for edge event")
+		return nil
+	}
+}
+
+func TestWatchGPIOPin(t *testing.T) {
+	service, client := startMockAgent(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The mock raises interrupts when a pin's value changes through a write
+	_, err := client.ConfigureGPIOPin(ctx, &pb.ConfigureGPIOPinRequest{
+		Pin:       17,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+	})
+	require.NoError(t, err)
+
+	both, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{Pin: 17})
+	require.NoError(t, err)
+	rising, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{
+		Pin:        17,
+		Edge:       pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING,
+		DebounceMs: int32(maxDebounce.Milliseconds()),
+	})
+	require.NoError(t, err)
+	bothEvents, risingEvents := recvEvents(both), recvEvents(rising)
+
+	require.Eventually(t, func() bool {
+		service.watchMu.Lock()
+		defer service.watchMu.Unlock()
+		return len(service.watchers[17]) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	write := func(value int32) {
+		_, err := client.WriteGPIOPin(ctx, &pb.WriteGPIOPinRequest{Pin: 17, Value: value})
+		require.NoError(t, err)
+	}
+
+	write(1)
+	event := nextEdge(t, bothEvents)
+	assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING, event.Edge)
+	assert.Equal(t, int32(1), event.Value)
+	assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING, nextEdge(t, risingEvents).Edge)
+
+	write(0)
+	assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_FALLING, nextEdge(t, bothEvents).Edge)
+	write(1)
+	assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING, nextEdge(t, bothEvents).Edge)
+
+	// The second rising edge falls inside the debounce window and the falling
+	// edge is filtered out
+	select {
+	case event := <-risingEvents:
+		t.Fatalf("unexpected event on debounced stream: %v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Closing every stream disables the interrupt
+	cancel()
+	require.Eventually(t, func() bool {
+		service.watchMu.Lock()
+		defer service.watchMu.Unlock()
+		return len(service.watchers) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWatchGPIOPin_Reconfigure(t *testing.T) {
+	service, client := startMockAgent(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configure := &pb.ConfigureGPIOPinRequest{
+		Pin:       17,
+		Direction: pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT,
+	}
+	_, err := client.ConfigureGPIOPin(ctx, configure)
+	require.NoError(t, err)
+
+	stream, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{Pin: 17})
+	require.NoError(t, err)
+	events := recvEvents(stream)
+	require.Eventually(t, func() bool {
+		service.watchMu.Lock()
+		defer service.watchMu.Unlock()
+		return len(service.watchers[17]) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Reconfiguring the pin stops its edge detection, which must come back
+	// for the open stream
+	configure.PullMode = pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_DOWN
+	resp, err := client.ConfigureGPIOPin(ctx, configure)
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Message)
+	_, err = client.WriteGPIOPin(ctx, &pb.WriteGPIOPinRequest{Pin: 17, Value: 1})
+	require.NoError(t, err)
+	assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING, nextEdge(t, events).Edge)
+
+	configure.Direction = pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_INPUT
+	resp, err = client.ConfigureGPIOPin(ctx, configure)
+	require.NoError(t, err)
+	assert.False(t, resp.Success, "a watched pin keeps its direction")
+}
+
+func TestWatchGPIOPin_Errors(t *testing.T) {
+	_, client := startMockAgent(t)
+	ctx := context.Background()
+
+	stream, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{Pin: 22})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "pin is not configured")
+
+	stream, err = client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{Pin: 22, DebounceMs: -1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
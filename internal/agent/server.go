@@ -2,13 +2,16 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
 
@@ -24,6 +27,15 @@ type Server struct {
 type Config struct {
 	Address string `yaml:"address" mapstructure:"address"`
 	Port    int    `yaml:"port" mapstructure:"port"`
+
+	// TLSConfig serves TLS, e.g. the enrolled node's credentials accepting
+	// only the controller. Without it the server is insecure.
+	TLSConfig *tls.Config `yaml:"-" mapstructure:"-"`
+
+	// TokenVerifier verifies tokens issued by the controller, so callers
+	// without the controller's client certificate can authenticate with a
+	// token. Tokens are bearer credentials, so they require TLSConfig.
+	TokenVerifier *pki.AgentTokenVerifier `yaml:"-" mapstructure:"-"`
 }
 
 // DefaultConfig returns default server configuration
@@ -44,6 +56,9 @@ func NewServer(config *Config, logger logger.Interface) (*Server, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	if config.TokenVerifier != nil && config.TLSConfig == nil {
+		return nil, fmt.Errorf("token authentication requires TLS, so tokens cannot be sniffed and replayed")
+	}
 
 	// Create agent service (includes GPIO and metrics)
 	agentService, err := NewAgentService(logger)
@@ -51,11 +66,25 @@ func NewServer(config *Config, logger logger.Interface) (*Server, error) {
 		return nil, fmt.Errorf("failed to create agent service: %w", err)
 	}
 
-	// Create gRPC server with logging interceptors
+	creds := insecure.NewCredentials()
+	if config.TLSConfig != nil {
+		creds = credentials.NewTLS(config.TLSConfig)
+	}
+
+	// Every call must come from the controller or carry one of its tokens
+	if config.TLSConfig == nil {
+		logger.Warn("Agent server has neither TLS nor token authentication configured; all calls will be rejected")
+	}
+	auth := &authenticator{
+		tokens: config.TokenVerifier,
+		logger: logger.WithField("component", "agent-auth"),
+	}
+
+	// Create gRPC server with logging and authentication interceptors
 	grpcServer := grpc.NewServer(
-		grpc.Creds(insecure.NewCredentials()),
-		grpc.UnaryInterceptor(loggingInterceptor(logger)),
-		grpc.StreamInterceptor(streamLoggingInterceptor(logger)),
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(loggingInterceptor(logger), authInterceptor(auth)),
+		grpc.ChainStreamInterceptor(streamLoggingInterceptor(logger), streamAuthInterceptor(auth)),
 	)
 
 	// Register the agent service
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+const (
+	// maxManifestBytes bounds the size of the manifests accepted by Apply
+	maxManifestBytes = 4 << 20
+	// applyRequestTimeout bounds applying and pruning every object of a request
+	applyRequestTimeout = 2 * time.Minute
+)
+
+// Apply server-side applies the multi-document YAML or JSON manifests in the
+// request body to a cluster. The dry_run and force query parameters are
+// booleans; prune is a label selector whose matching objects are deleted if
+// they are missing from the manifests.
+func (h *DeploymentHandler) Apply(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	opts := k8s.ApplyOptions{PruneSelector: c.Query("prune")}
+	for param, value := range map[string]*bool{"dry_run": &opts.DryRun, "force": &opts.Force} {
+		parsed, err := strconv.ParseBool(c.DefaultQuery(param, "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid " + param + " parameter",
+			})
+			return
+		}
+		*value = parsed
+	}
+
+	manifests, err := io.ReadAll(io.LimitReader(c.Request.Body, maxManifestBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if len(manifests) > maxManifestBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Request Entity Too Large",
+			"message": "Manifests must not exceed 4 MiB",
+		})
+		return
+	}
+
+	// Applying and pruning can outlast the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), applyRequestTimeout)
+	defer cancel()
+
+	results, err := h.service.ApplyManifests(ctx, clusterID, manifests, opts)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to apply manifests")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Action == k8s.ApplyFailed {
+			failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"count":   len(results),
+		"failed":  failed,
+		"dry_run": opts.DryRun,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+func TestDeploymentHandler_Apply(t *testing.T) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	cluster, err := services.NewClusterService(db, logger.Default()).Create(services.CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	clients := fakeClusterClients{
+		clientset: fake.NewSimpleClientset(),
+		dynamic:   dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		mapper:    mapper,
+	}
+	service := services.NewDeploymentService(db, clients, logger.Default())
+
+	router := gin.New()
+	router.POST("/clusters/:id/apply", NewDeploymentHandler(service, logger.Default()).Apply)
+	path := fmt.Sprintf("/clusters/%d/apply", cluster.ID)
+
+	do := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/yaml")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("reports the result of each object", func(t *testing.T) {
+		w := do(path+"?dry_run=true", "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: gear\n")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response struct {
+			Results []k8s.ApplyResult `json:"results"`
+			Failed  int               `json:"failed"`
+			DryRun  bool              `json:"dry_run"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Results, 1)
+		assert.Equal(t, k8s.ApplyFailed, response.Results[0].Action, "the cluster does not serve widgets")
+		assert.Equal(t, 1, response.Failed)
+		assert.True(t, response.DryRun)
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"
+
+		assert.Equal(t, http.StatusBadRequest, do(path, "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(path, "kind: ConfigMap\n").Code)
+		assert.Equal(t, http.StatusBadRequest, do(path+"?dry_run=maybe", configMap).Code)
+		assert.Equal(t, http.StatusBadRequest, do(path+"?prune=app+in+(", configMap).Code)
+	})
+
+	t.Run("returns not found for unknown clusters", func(t *testing.T) {
+		w := do(fmt.Sprintf("/clusters/%d/apply", cluster.ID+1), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
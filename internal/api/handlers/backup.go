@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// BackupHandler handles cluster backup API operations
+type BackupHandler struct {
+	service *services.BackupService
+	logger  logger.Interface
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(service *services.BackupService, logger logger.Interface) *BackupHandler {
+	return &BackupHandler{
+		service: service,
+		logger:  logger.WithField("handler", "backup"),
+	}
+}
+
+// List returns a cluster's backups, newest first
+func (h *BackupHandler) List(c *gin.Context) {
+	clusterID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid cluster ID",
+		})
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	backups, total, err := h.service.List(clusterID, services.BackupListOptions{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list backups")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"backups": backups,
+		"count":   len(backups),
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// Get returns one of a cluster's backups
+func (h *BackupHandler) Get(c *gin.Context) {
+	clusterID, backupID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	record, err := h.service.Get(clusterID, backupID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get backup")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// Create starts an on-demand backup of a cluster. The backup runs in the
+// background; poll it until its status is no longer running.
+func (h *BackupHandler) Create(c *gin.Context) {
+	clusterID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid cluster ID",
+		})
+		return
+	}
+
+	record, err := h.service.StartBackup(clusterID, models.BackupTriggerManual)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to start backup")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, record)
+}
+
+// Restore starts restoring a cluster's datastore from one of its backups and
+// responds with the restore job of the master node
+func (h *BackupHandler) Restore(c *gin.Context) {
+	clusterID, backupID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.StartRestore(clusterID, backupID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to start restore")
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"backup_id":  backupID,
+	}).Info("Started restore")
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Delete removes a backup and its snapshot
+func (h *BackupHandler) Delete(c *gin.Context) {
+	clusterID, backupID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), clusterID, backupID); err != nil {
+		h.handleServiceError(c, err, "Failed to delete backup")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// parseIDs reads the cluster and backup IDs from the path, responding with an
+// error if either is invalid
+func (h *BackupHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	clusterID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid cluster ID",
+		})
+		return 0, 0, false
+	}
+
+	backupID, err := parseIDParam(c, "backup_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid backup ID",
+		})
+		return 0, 0, false
+	}
+
+	return clusterID, backupID, true
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *BackupHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsConflict(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsAgentUnreachable(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/backup"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+// setupBackupHandler creates a cluster with one succeeded and one failed
+// backup and no ready master
+func setupBackupHandler(t *testing.T) (*gin.Engine, backup.Store, *models.Cluster, []models.ClusterBackup) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	cluster, err := services.NewClusterService(db, logger.Default()).Create(services.CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+	master, err := services.NewNodeService(db, logger.Default()).Create(services.CreateNodeRequest{
+		Name:       "master",
+		IPAddress:  "192.168.1.2",
+		MACAddress: "aa:bb:cc:dd:ee:02",
+		Role:       models.NodeRoleMaster,
+		ClusterID:  &cluster.ID,
+		CPUCores:   4,
+		Memory:     4096,
+	})
+	require.NoError(t, err)
+
+	store, err := backup.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now()
+	backups := []models.ClusterBackup{
+		{ClusterID: cluster.ID, NodeID: master.ID, Trigger: models.BackupTriggerScheduled, Status: models.BackupStatusSucceeded,
+			Datastore: "etcd", Storage: backup.StorageLocal, Key: "clusters/1/a.snapshot", SizeBytes: 4, StartedAt: now.Add(-time.Hour), FinishedAt: &now},
+		{ClusterID: cluster.ID, NodeID: master.ID, Trigger: models.BackupTriggerManual, Status: models.BackupStatusFailed,
+			Storage: backup.StorageLocal, Key: "clusters/1/b.snapshot", Error: "agent went away", StartedAt: now, FinishedAt: &now},
+	}
+	for i := range backups {
+		require.NoError(t, gormDB.Create(&backups[i]).Error)
+	}
+	require.NoError(t, store.Put(context.Background(), backups[0].Key, bytes.NewReader([]byte("etcd"))))
+
+	handler := NewBackupHandler(services.NewBackupService(db, store, backup.Config{Timeout: time.Minute}, logger.Default()), logger.Default())
+	router := gin.New()
+	router.GET("/clusters/:id/backups", handler.List)
+	router.GET("/clusters/:id/backups/:backup_id", handler.Get)
+	router.POST("/clusters/:id/backups", handler.Create)
+	router.POST("/clusters/:id/backups/:backup_id/restore", handler.Restore)
+	router.DELETE("/clusters/:id/backups/:backup_id", handler.Delete)
+	return router, store, cluster, backups
+}
+
+func TestBackupHandler_List(t *testing.T) {
+	router, _, cluster, backups := setupBackupHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/backups", cluster.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Backups []models.ClusterBackup `json:"backups"`
+		Total   int64                  `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response.Total)
+	require.Len(t, response.Backups, 2)
+	assert.Equal(t, backups[1].ID, response.Backups[0].ID, "newest first")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/clusters/999/backups", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBackupHandler_Get(t *testing.T) {
+	router, _, cluster, backups := setupBackupHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/backups/%d", cluster.ID, backups[0].ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var record models.ClusterBackup
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record))
+	assert.Equal(t, "etcd", record.Datastore)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/backups/999", cluster.ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/backups/latest", cluster.ID), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBackupHandler_Create(t *testing.T) {
+	router, _, cluster, _ := setupBackupHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/clusters/%d/backups", cluster.ID), nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "the cluster has no ready master")
+}
+
+func TestBackupHandler_Restore(t *testing.T) {
+	router, _, cluster, backups := setupBackupHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/clusters/%d/backups/%d/restore", cluster.ID, backups[1].ID), nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "failed backups cannot be restored")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/clusters/%d/backups/%d/restore", cluster.ID, backups[0].ID), nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "the cluster has no ready master")
+}
+
+func TestBackupHandler_Delete(t *testing.T) {
+	router, store, cluster, backups := setupBackupHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", fmt.Sprintf("/clusters/%d/backups/%d", cluster.ID, backups[0].ID), nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, err := store.Get(context.Background(), backups[0].Key)
+	assert.ErrorIs(t, err, backup.ErrNotFound)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/backups/%d", cluster.ID, backups[0].ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -167,7 +167,7 @@ func (h *ClusterHandler) ListNodes(c *gin.Context) {
 	})
 }
 
-// Status returns the status of a cluster
+// Status returns the status of a cluster and the progress of its latest upgrade
 func (h *ClusterHandler) Status(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -178,13 +178,13 @@ func (h *ClusterHandler) Status(c *gin.Context) {
 		return
 	}
 
-	status, err := h.service.GetStatus(uint(id))
+	report, err := h.service.StatusReport(uint(id))
 	if err != nil {
 		h.handleServiceError(c, err, "Failed to get cluster status")
 		return
 	}
 
-	c.JSON(http.StatusOK, status)
+	c.JSON(http.StatusOK, report)
 }
 
 // handleServiceError handles service layer errors and maps them to appropriate HTTP responses
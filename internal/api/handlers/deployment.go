@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// deploymentRequestTimeout bounds the Kubernetes API calls made for a request
+const deploymentRequestTimeout = 30 * time.Second
+
+// DeploymentHandler handles API operations on the Deployments of a cluster
+type DeploymentHandler struct {
+	service *services.DeploymentService
+	logger  logger.Interface
+}
+
+// NewDeploymentHandler creates a new deployment handler
+func NewDeploymentHandler(service *services.DeploymentService, logger logger.Interface) *DeploymentHandler {
+	return &DeploymentHandler{
+		service: service,
+		logger:  logger.WithField("handler", "deployment"),
+	}
+}
+
+// RollbackDeploymentRequest selects the revision to roll back to
+type RollbackDeploymentRequest struct {
+	// Revision to roll back to; 0 or omitted selects the previous revision
+	Revision int64 `json:"revision"`
+}
+
+// List returns a cluster's deployments, optionally filtered by the namespace
+// query parameter
+func (h *DeploymentHandler) List(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	deployments, err := h.service.ListDeployments(ctx, clusterID, c.Query("namespace"))
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list deployments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deployments": deployments,
+		"count":       len(deployments),
+	})
+}
+
+// Create creates an apps/v1 Deployment from the request body
+func (h *DeploymentHandler) Create(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	var deployment appsv1.Deployment
+	if err := c.ShouldBindJSON(&deployment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	created, err := h.service.CreateDeployment(ctx, clusterID, &deployment)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to create deployment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// Get returns a deployment
+func (h *DeploymentHandler) Get(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	deployment, err := h.service.GetDeployment(ctx, clusterID, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get deployment")
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment)
+}
+
+// Update replaces a deployment with the request body. The body's namespace
+// and name default to those in the path and must match them if set.
+func (h *DeploymentHandler) Update(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	var deployment appsv1.Deployment
+	if err := c.ShouldBindJSON(&deployment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	if deployment.Namespace == "" {
+		deployment.Namespace = namespace
+	}
+	if deployment.Name == "" {
+		deployment.Name = name
+	}
+	if deployment.Namespace != namespace || deployment.Name != name {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Deployment namespace and name must match the path",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	updated, err := h.service.UpdateDeployment(ctx, clusterID, &deployment)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to update deployment")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete deletes a deployment along with its pods
+func (h *DeploymentHandler) Delete(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	if err := h.service.DeleteDeployment(ctx, clusterID, c.Param("namespace"), c.Param("name")); err != nil {
+		h.handleServiceError(c, err, "Failed to delete deployment")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RolloutStatus reports the progress of a deployment's latest rollout
+func (h *DeploymentHandler) RolloutStatus(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	status, err := h.service.GetRolloutStatus(ctx, clusterID, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get rollout status")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Rollback rolls a deployment back to an earlier revision. The body is
+// optional; without one the deployment returns to its previous revision.
+func (h *DeploymentHandler) Rollback(c *gin.Context) {
+	clusterID, ok := h.parseClusterID(c)
+	if !ok {
+		return
+	}
+
+	var req RollbackDeploymentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deploymentRequestTimeout)
+	defer cancel()
+
+	deployment, err := h.service.RollbackDeployment(ctx, clusterID, c.Param("namespace"), c.Param("name"), req.Revision)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to roll back deployment")
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment)
+}
+
+func (h *DeploymentHandler) parseClusterID(c *gin.Context) (uint, bool) {
+	clusterID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid cluster ID",
+		})
+		return 0, false
+	}
+	return clusterID, true
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *DeploymentHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	switch {
+	case services.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+	case services.IsAlreadyExists(err), services.IsConflict(err):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+	case services.IsValidationFailed(err):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation Failed",
+			"message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// fakeClusterClients serves every cluster from one fake clientset, and from
+// a fake dynamic client if one is set
+type fakeClusterClients struct {
+	clientset *fake.Clientset
+	dynamic   dynamic.Interface
+	mapper    meta.RESTMapper
+}
+
+func (f fakeClusterClients) ClientFor(cluster *models.Cluster) (k8s.K8sClient, error) {
+	client := k8s.NewClientForClientset(f.clientset, logrus.New())
+	if f.dynamic != nil {
+		client.SetDynamicClient(f.dynamic, f.mapper)
+	}
+	return client, nil
+}
+
+func setupDeploymentHandler(t *testing.T) (*gin.Engine, *fake.Clientset, *models.Cluster) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	cluster, err := services.NewClusterService(db, logger.Default()).Create(services.CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+
+	clientset := fake.NewSimpleClientset()
+	service := services.NewDeploymentService(db, fakeClusterClients{clientset: clientset}, logger.Default())
+
+	handler := NewDeploymentHandler(service, logger.Default())
+	router := gin.New()
+	router.GET("/clusters/:id/deployments", handler.List)
+	router.POST("/clusters/:id/deployments", handler.Create)
+	router.GET("/clusters/:id/deployments/:namespace/:name", handler.Get)
+	router.PUT("/clusters/:id/deployments/:namespace/:name", handler.Update)
+	router.DELETE("/clusters/:id/deployments/:namespace/:name", handler.Delete)
+	router.GET("/clusters/:id/deployments/:namespace/:name/rollout", handler.RolloutStatus)
+	router.POST("/clusters/:id/deployments/:namespace/:name/rollback", handler.Rollback)
+	return router, clientset, cluster
+}
+
+const webDeployment = `{
+	"metadata": {"name": "web"},
+	"spec": {
+		"replicas": 2,
+		"selector": {"matchLabels": {"app": "web"}},
+		"template": {
+			"metadata": {"labels": {"app": "web"}},
+			"spec": {"containers": [{"name": "web", "image": "nginx:1.27"}]}
+		}
+	}
+}`
+
+func TestDeploymentHandler(t *testing.T) {
+	router, clientset, cluster := setupDeploymentHandler(t)
+	base := fmt.Sprintf("/clusters/%d/deployments", cluster.ID)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("creates a deployment in the default namespace", func(t *testing.T) {
+		w := do(http.MethodPost, base, webDeployment)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		deployment, err := clientset.AppsV1().Deployments("default").Get(t.Context(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	})
+
+	t.Run("rejects duplicates", func(t *testing.T) {
+		w := do(http.MethodPost, base, webDeployment)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("lists and gets deployments", func(t *testing.T) {
+		w := do(http.MethodGet, base+"?namespace=default", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var list struct {
+			Deployments []appsv1.Deployment `json:"deployments"`
+			Count       int                 `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, 1, list.Count)
+
+		w = do(http.MethodGet, base+"/default/web", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = do(http.MethodGet, base+"/default/missing", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("updates a deployment", func(t *testing.T) {
+		body := strings.Replace(webDeployment, `"replicas": 2`, `"replicas": 3`, 1)
+		w := do(http.MethodPut, base+"/default/web", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		body = strings.Replace(body, `{"name": "web"}`, `{"name": "web", "namespace": "default"}`, 1)
+		w = do(http.MethodPut, base+"/other/web", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "namespace must match the path")
+	})
+
+	t.Run("reports rollout status", func(t *testing.T) {
+		w := do(http.MethodGet, base+"/default/web/rollout", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var status k8s.RolloutStatus
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		assert.Equal(t, k8s.RolloutProgressing, status.State, "no replicas have been updated by the fake clientset")
+		assert.Equal(t, int32(3), status.Replicas)
+	})
+
+	t.Run("refuses to roll back without history", func(t *testing.T) {
+		w := do(http.MethodPost, base+"/default/web/rollback", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = do(http.MethodPost, base+"/default/web/rollback", `{"revision": "two"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("deletes a deployment", func(t *testing.T) {
+		w := do(http.MethodDelete, base+"/default/web", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodDelete, base+"/default/web", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns not found for unknown clusters", func(t *testing.T) {
+		w := do(http.MethodGet, fmt.Sprintf("/clusters/%d/deployments", cluster.ID+1), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
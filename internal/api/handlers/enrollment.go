@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// EnrollmentHandler handles bootstrap tokens and the certificates issued to
+// enrolled agents
+type EnrollmentHandler struct {
+	service *services.EnrollmentService
+	logger  logger.Interface
+}
+
+// NewEnrollmentHandler creates a new enrollment handler
+func NewEnrollmentHandler(service *services.EnrollmentService, logger logger.Interface) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		service: service,
+		logger:  logger.WithField("handler", "enrollment"),
+	}
+}
+
+// ListTokens returns bootstrap tokens, newest first
+func (h *EnrollmentHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.service.ListBootstrapTokens()
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list bootstrap tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bootstrap_tokens": tokens,
+		"count":            len(tokens),
+	})
+}
+
+// CreateToken creates a bootstrap token. The response is the only time the
+// token is shown.
+func (h *EnrollmentHandler) CreateToken(c *gin.Context) {
+	var req services.CreateBootstrapTokenRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	record, token, err := h.service.CreateBootstrapToken(req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to create bootstrap token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":           token,
+		"bootstrap_token": record,
+		"ca_cert_hash":    h.service.CA().Hash(),
+	})
+}
+
+// DeleteToken deletes a bootstrap token so it can no longer be used
+func (h *EnrollmentHandler) DeleteToken(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid bootstrap token ID",
+		})
+		return
+	}
+
+	if err := h.service.DeleteBootstrapToken(id); err != nil {
+		h.handleServiceError(c, err, "Failed to delete bootstrap token")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListCertificates returns the certificates issued to a node, newest first
+func (h *EnrollmentHandler) ListCertificates(c *gin.Context) {
+	nodeID, ok := h.parseNodeID(c)
+	if !ok {
+		return
+	}
+
+	certs, err := h.service.ListCertificates(nodeID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list node certificates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certs,
+		"count":        len(certs),
+	})
+}
+
+// RevokeCertificates revokes every certificate issued to a node. Its agent
+// must enroll again with a new bootstrap token.
+func (h *EnrollmentHandler) RevokeCertificates(c *gin.Context) {
+	nodeID, ok := h.parseNodeID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.service.RevokeNodeCertificates(nodeID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to revoke node certificates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"node_id": nodeID,
+		"revoked": count,
+	})
+}
+
+// parseNodeID reads the node ID from the path, responding with an error if
+// it is invalid
+func (h *EnrollmentHandler) parseNodeID(c *gin.Context) (uint, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *EnrollmentHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsValidationFailed(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
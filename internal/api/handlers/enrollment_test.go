@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+func setupEnrollmentHandler(t *testing.T) (*gin.Engine, *services.EnrollmentService) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	ca, err := pki.LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	enrollment, err := services.NewEnrollmentService(db, services.NewNodeService(db, logger.Default()), ca, time.Hour, time.Hour, logger.Default())
+	require.NoError(t, err)
+
+	handler := NewEnrollmentHandler(enrollment, logger.Default())
+	router := gin.New()
+	router.GET("/bootstrap-tokens", handler.ListTokens)
+	router.POST("/bootstrap-tokens", handler.CreateToken)
+	router.DELETE("/bootstrap-tokens/:id", handler.DeleteToken)
+	router.GET("/nodes/:id/certificates", handler.ListCertificates)
+	router.POST("/nodes/:id/certificates/revoke", handler.RevokeCertificates)
+	return router, enrollment
+}
+
+func TestEnrollmentHandler_Tokens(t *testing.T) {
+	router, enrollment := setupEnrollmentHandler(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/bootstrap-tokens", strings.NewReader(`{"description":"kitchen","ttl":"2h"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct {
+		Token          string                `json:"token"`
+		BootstrapToken models.BootstrapToken `json:"bootstrap_token"`
+		CACertHash     string                `json:"ca_cert_hash"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.True(t, strings.HasPrefix(created.Token, created.BootstrapToken.TokenID+"."))
+	assert.Equal(t, enrollment.CA().Hash(), created.CACertHash)
+	assert.NotContains(t, w.Body.String(), "secret_hash")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/bootstrap-tokens", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Token, "tokens are only shown when created")
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/bootstrap-tokens", strings.NewReader(`{"ttl":"forever"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", fmt.Sprintf("/bootstrap-tokens/%d", created.BootstrapToken.ID), nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", fmt.Sprintf("/bootstrap-tokens/%d", created.BootstrapToken.ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestEnrollmentHandler_Certificates(t *testing.T) {
+	router, enrollment := setupEnrollmentHandler(t)
+
+	_, token, err := enrollment.CreateBootstrapToken(services.CreateBootstrapTokenRequest{})
+	require.NoError(t, err)
+	_, csr, err := pki.NewCSR()
+	require.NoError(t, err)
+	node, _, err := enrollment.Enroll(services.EnrollRequest{
+		BootstrapToken: token,
+		CSR:            csr,
+		Name:           "pi-kitchen",
+		IPAddress:      "192.168.1.10",
+		MACAddress:     "dc:a6:32:00:00:01",
+		CPUCores:       4,
+		Memory:         4 << 30,
+	})
+	require.NoError(t, err)
+
+	var listed struct {
+		Certificates []models.NodeCertificate `json:"certificates"`
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/certificates", node.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Certificates, 1)
+	assert.Nil(t, listed.Certificates[0].RevokedAt)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/nodes/%d/certificates/revoke", node.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"node_id":%d,"revoked":1}`, node.ID), w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/certificates", node.ID), nil))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Certificates, 1)
+	assert.NotNil(t, listed.Certificates[0].RevokedAt)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/nodes/999/certificates/revoke", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/nodes/first/certificates", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
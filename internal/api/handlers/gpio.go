@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/internal/services"
 )
 
@@ -17,4 +22,339 @@ func NewGPIOHandler(service *services.GPIOService, logger logger.Interface) *GPI
 		service: service,
 		logger:  logger.WithField("handler", "gpio"),
 	}
-}
\ No newline at end of file
+}
+
+// WriteGPIORequest represents the request to write a value to a GPIO device
+type WriteGPIORequest struct {
+	Value *int `json:"value" binding:"required"`
+}
+
+// List returns all GPIO devices
+func (h *GPIOHandler) List(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	nodeID, err := parseUintQuery(c, "node_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	opts := services.GPIOListOptions{
+		NodeID: nodeID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if deviceType := c.Query("device_type"); deviceType != "" {
+		gpioDeviceType := models.GPIODeviceType(deviceType)
+		opts.DeviceType = &gpioDeviceType
+	}
+	if direction := c.Query("direction"); direction != "" {
+		gpioDirection := models.GPIODirection(direction)
+		opts.Direction = &gpioDirection
+	}
+	if status := c.Query("status"); status != "" {
+		gpioStatus := models.GPIOStatus(status)
+		opts.Status = &gpioStatus
+	}
+
+	devices, total, err := h.service.List(opts)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list GPIO devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+		"count":   len(devices),
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// Create creates a new GPIO device
+func (h *GPIOHandler) Create(c *gin.Context) {
+	var req services.CreateGPIODeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	device, err := h.service.Create(req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to create GPIO device")
+		return
+	}
+
+	h.logger.WithField("device_id", device.ID).Info("Created new GPIO device")
+	c.JSON(http.StatusCreated, device)
+}
+
+// Get returns a specific GPIO device by ID
+func (h *GPIOHandler) Get(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	device, err := h.service.GetByID(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get GPIO device")
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// Update updates a GPIO device
+func (h *GPIOHandler) Update(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	var req services.UpdateGPIODeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	device, err := h.service.Update(id, req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to update GPIO device")
+		return
+	}
+
+	h.logger.WithField("device_id", device.ID).Info("Updated GPIO device")
+	c.JSON(http.StatusOK, device)
+}
+
+// Delete deletes a GPIO device
+func (h *GPIOHandler) Delete(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		h.handleServiceError(c, err, "Failed to delete GPIO device")
+		return
+	}
+
+	h.logger.WithField("device_id", id).Info("Deleted GPIO device")
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Read reads the current value of a GPIO device
+func (h *GPIOHandler) Read(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	device, err := h.service.Read(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to read GPIO device")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"device_id": device.ID,
+		"pin":       device.PinNumber,
+		"value":     device.Value,
+		"timestamp": device.UpdatedAt,
+	})
+}
+
+// Write writes a value to a GPIO device
+func (h *GPIOHandler) Write(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	var req WriteGPIORequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Write(id, *req.Value); err != nil {
+		h.handleServiceError(c, err, "Failed to write GPIO device")
+		return
+	}
+
+	device, err := h.service.GetByID(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get GPIO device")
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"device_id": id,
+		"value":     device.Value,
+	}).Info("Wrote GPIO device")
+	c.JSON(http.StatusOK, gin.H{
+		"device_id": device.ID,
+		"pin":       device.PinNumber,
+		"value":     device.Value,
+		"timestamp": device.UpdatedAt,
+	})
+}
+
+// GetReadings returns GPIO readings for a device
+func (h *GPIOHandler) GetReadings(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid GPIO device ID",
+		})
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter := services.GPIOReadingFilter{
+		DeviceID: id,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if filter.StartTime, err = parseTimeQuery(c, "start_time"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if filter.EndTime, err = parseTimeQuery(c, "end_time"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Readings for a missing device would otherwise come back as an empty list
+	if _, err := h.service.GetByID(id); err != nil {
+		h.handleServiceError(c, err, "Failed to get GPIO device")
+		return
+	}
+
+	readings, total, err := h.service.GetReadings(filter)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get GPIO readings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"readings":  readings,
+		"count":     len(readings),
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+		"device_id": id,
+	})
+}
+
+// handleServiceError handles service layer errors and maps them to appropriate HTTP responses
+func (h *GPIOHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsNotFound(err) {
+		notFound := "GPIO device not found"
+		if err != services.ErrNotFound {
+			// Wrapped errors name the missing resource, e.g. an unknown node
+			notFound = err.Error()
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": notFound,
+		})
+		return
+	}
+
+	if services.IsAlreadyExists(err) || services.IsConflict(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsValidationFailed(err) || services.IsInvalidInput(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation Failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsAgentUnreachable(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsGPIOError(err) {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Bad Gateway",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
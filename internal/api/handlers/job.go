@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// logKeepAliveInterval is how often an idle log stream sends a comment so
+// proxies do not close it
+const logKeepAliveInterval = 15 * time.Second
+
+// JobHandler handles provisioning job API operations
+type JobHandler struct {
+	service *services.JobService
+	nodes   *services.NodeService
+	logger  logger.Interface
+}
+
+// NewJobHandler creates a new provisioning job handler
+func NewJobHandler(service *services.JobService, nodes *services.NodeService, logger logger.Interface) *JobHandler {
+	return &JobHandler{
+		service: service,
+		nodes:   nodes,
+		logger:  logger.WithField("handler", "job"),
+	}
+}
+
+// List returns a node's provisioning jobs, newest first
+func (h *JobHandler) List(c *gin.Context) {
+	nodeID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if _, err := h.nodes.GetByID(nodeID, false); err != nil {
+		h.handleServiceError(c, err, "Failed to get node")
+		return
+	}
+
+	jobs, total, err := h.service.List(services.JobListOptions{
+		NodeID: nodeID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list provisioning jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":   jobs,
+		"count":  len(jobs),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Get returns a provisioning job with its steps and their output excerpts
+func (h *JobHandler) Get(c *gin.Context) {
+	nodeID, jobID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Get(nodeID, jobID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get provisioning job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Logs streams a provisioning job's output as server-sent events. The output
+// recorded for finished steps is sent first, followed by live output until the
+// job finishes. Each line is a "log" event and the stream ends with a "done"
+// event carrying the job.
+func (h *JobHandler) Logs(c *gin.Context) {
+	nodeID, jobID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	// Subscribe before loading the job so no output falls between the two
+	sub := h.service.Follow(jobID)
+	defer sub.Close()
+
+	job, err := h.service.Get(nodeID, jobID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get provisioning job")
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// Output of steps finished before the job was loaded is sent from their
+	// excerpts, so their live lines still queued on the subscription are skipped
+	replayed := make(map[string]bool)
+	for _, step := range job.Steps {
+		if step.FinishedAt == nil {
+			continue
+		}
+		replayed[step.Name] = true
+		h.sendExcerpt(c, job, step, "stdout", step.Stdout)
+		h.sendExcerpt(c, job, step, "stderr", step.Stderr)
+	}
+	c.Writer.Flush()
+
+	if job.IsFinished() {
+		c.SSEvent("done", job)
+		c.Writer.Flush()
+		return
+	}
+
+	keepAlive := time.NewTicker(logKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := c.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-sub.C():
+			if !ok {
+				return
+			}
+			switch payload := event.Payload.(type) {
+			case events.ProvisioningLog:
+				if replayed[payload.Step] {
+					continue
+				}
+				c.SSEvent("log", payload)
+				c.Writer.Flush()
+			case events.ProvisioningJob:
+				if payload.Status == string(models.JobStatusRunning) {
+					continue
+				}
+				if finished, err := h.service.Get(nodeID, jobID); err == nil {
+					job = finished
+				}
+				c.SSEvent("done", job)
+				c.Writer.Flush()
+				return
+			}
+		}
+	}
+}
+
+// sendExcerpt sends the recorded output of a finished step as log events
+func (h *JobHandler) sendExcerpt(c *gin.Context, job *models.ProvisioningJob, step models.ProvisioningStep, stream, output string) {
+	if output == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		c.SSEvent("log", events.ProvisioningLog{
+			JobID:     job.ID,
+			NodeID:    job.NodeID,
+			Step:      step.Name,
+			Stream:    stream,
+			Line:      line,
+			Timestamp: *step.FinishedAt,
+		})
+	}
+}
+
+// parseIDs reads the node and job IDs from the path, responding with an error
+// if either is invalid
+func (h *JobHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	nodeID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return 0, 0, false
+	}
+
+	jobID, err := parseIDParam(c, "job_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid job ID",
+		})
+		return 0, 0, false
+	}
+
+	return nodeID, jobID, true
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *JobHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsNotFound(err) {
+		notFound := "Node not found"
+		if err != services.ErrNotFound {
+			notFound = err.Error()
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": notFound,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+func setupJobHandler(t *testing.T) (*gin.Engine, *services.JobService, *models.Node) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	nodes := services.NewNodeService(db, logger.Default())
+	jobs := services.NewJobService(db, logger.Default())
+	jobs.SetEventBus(events.NewBus())
+
+	node, err := nodes.Create(services.CreateNodeRequest{
+		Name:       "pi-1",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+
+	handler := NewJobHandler(jobs, nodes, logger.Default())
+	router := gin.New()
+	router.GET("/nodes/:id/jobs", handler.List)
+	router.GET("/nodes/:id/jobs/:job_id", handler.Get)
+	router.GET("/nodes/:id/jobs/:job_id/logs", handler.Logs)
+	return router, jobs, node
+}
+
+// createJob records a job whose first step has finished with output
+func createJob(t *testing.T, jobs *services.JobService, nodeID uint) *models.ProvisioningJob {
+	job, err := jobs.Create(nodeID, nil, models.JobTypeProvision, []models.ProvisioningStep{
+		{Position: 0, Name: "preflight", Status: models.StepStatusPending},
+		{Position: 1, Name: "install-agent", Status: models.StepStatusPending},
+	})
+	require.NoError(t, err)
+	require.NoError(t, jobs.StartStep(job, &job.Steps[0]))
+	require.NoError(t, jobs.FinishStep(&job.Steps[0], models.StepStatusSucceeded, "", "$ uname -m\naarch64\n", ""))
+	return job
+}
+
+func TestJobHandler_List(t *testing.T) {
+	router, jobs, node := setupJobHandler(t)
+	job := createJob(t, jobs, node.ID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/jobs", node.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Jobs  []models.ProvisioningJob `json:"jobs"`
+		Total int64                    `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(1), response.Total)
+	require.Len(t, response.Jobs, 1)
+	assert.Equal(t, job.ID, response.Jobs[0].ID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/jobs/%d", node.ID, job.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var loaded models.ProvisioningJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &loaded))
+	require.Len(t, loaded.Steps, 2)
+	assert.Equal(t, "$ uname -m\naarch64\n", loaded.Steps[0].Stdout)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/jobs", node.ID+1), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/jobs/%d/logs", node.ID+1, job.ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJobHandler_LogsOfFinishedJob(t *testing.T) {
+	router, jobs, node := setupJobHandler(t)
+	job := createJob(t, jobs, node.ID)
+	require.NoError(t, jobs.Finish(job, models.JobStatusFailed, "step install-agent failed"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/nodes/%d/jobs/%d/logs", node.ID, job.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+
+	body := w.Body.String()
+	assert.Contains(t, body, `"line":"$ uname -m"`)
+	assert.Contains(t, body, `"line":"aarch64"`)
+	assert.Contains(t, body, "event:done")
+	assert.Contains(t, body, `"error":"step install-agent failed"`)
+}
+
+func TestJobHandler_StreamsLiveLogs(t *testing.T) {
+	router, jobs, node := setupJobHandler(t)
+	job := createJob(t, jobs, node.ID)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/nodes/%d/jobs/%d/logs", server.URL, node.ID, job.ID))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Output of the finished step arrives before anything live
+	buf := make([]byte, 4096)
+	n, err := resp.Body.Read(buf)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), `"line":"aarch64"`)
+
+	require.NoError(t, jobs.StartStep(job, &job.Steps[1]))
+	jobs.PublishLog(job, "preflight", "stdout", "late output of a replayed step")
+	jobs.PublishLog(job, "install-agent", "stdout", "[INFO] systemd: Starting k3s-agent")
+	require.NoError(t, jobs.Finish(job, models.JobStatusSucceeded, ""))
+
+	done := make(chan string, 1)
+	go func() {
+		rest, _ := io.ReadAll(resp.Body)
+		done <- string(rest)
+	}()
+
+	select {
+	case rest := <-done:
+		assert.Contains(t, rest, `"line":"[INFO] systemd: Starting k3s-agent"`)
+		assert.NotContains(t, rest, "late output of a replayed step")
+		assert.True(t, strings.Contains(rest, "event:done"), "the stream ends once the job finishes")
+	case <-time.After(5 * time.Second):
+		t.Fatal("log stream did not end after the job finished")
+	}
+}
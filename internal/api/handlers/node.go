@@ -1,7 +1,14 @@
 package handlers
 
 import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/internal/services"
 )
 
@@ -17,4 +24,332 @@ func NewNodeHandler(service *services.NodeService, logger logger.Interface) *Nod
 		service: service,
 		logger:  logger.WithField("handler", "node"),
 	}
-}
\ No newline at end of file
+}
+
+// ProvisionNodeRequest represents the request to provision a node into a cluster
+type ProvisionNodeRequest struct {
+	ClusterID uint `json:"cluster_id" binding:"required"`
+}
+
+// DeprovisionNodeRequest represents the optional body of a request to remove
+// a node from its cluster
+type DeprovisionNodeRequest struct {
+	Force        bool   `json:"force"`
+	DrainTimeout string `json:"drain_timeout"` // e.g. "10m"; defaults to 5m
+}
+
+// List returns all nodes
+func (h *NodeHandler) List(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	clusterID, err := parseUintQuery(c, "cluster_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	opts := services.NodeListOptions{
+		ClusterID: clusterID,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	if status := c.Query("status"); status != "" {
+		nodeStatus := models.NodeStatus(status)
+		opts.Status = &nodeStatus
+	}
+	if role := c.Query("role"); role != "" {
+		nodeRole := models.NodeRole(role)
+		opts.Role = &nodeRole
+	}
+	opts.IncludeGPIO, _ = strconv.ParseBool(c.DefaultQuery("include_gpio", "false"))
+
+	nodes, total, err := h.service.List(opts)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list nodes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nodes":  nodes,
+		"count":  len(nodes),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Create creates a new node
+func (h *NodeHandler) Create(c *gin.Context) {
+	var req services.CreateNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	node, err := h.service.Create(req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to create node")
+		return
+	}
+
+	h.logger.WithField("node_id", node.ID).Info("Created new node")
+	c.JSON(http.StatusCreated, node)
+}
+
+// Get returns a specific node by ID
+func (h *NodeHandler) Get(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	includeGPIO, _ := strconv.ParseBool(c.DefaultQuery("include_gpio", "false"))
+
+	node, err := h.service.GetByID(id, includeGPIO)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get node")
+		return
+	}
+
+	c.JSON(http.StatusOK, node)
+}
+
+// Update updates a node
+func (h *NodeHandler) Update(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	var req services.UpdateNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	node, err := h.service.Update(id, req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to update node")
+		return
+	}
+
+	h.logger.WithField("node_id", node.ID).Info("Updated node")
+	c.JSON(http.StatusOK, node)
+}
+
+// Delete deletes a node
+func (h *NodeHandler) Delete(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		h.handleServiceError(c, err, "Failed to delete node")
+		return
+	}
+
+	h.logger.WithField("node_id", id).Info("Deleted node")
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListGPIO returns all GPIO devices for a node
+func (h *NodeHandler) ListGPIO(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	devices, err := h.service.GetGPIODevices(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list node GPIO devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+		"count":   len(devices),
+		"node_id": id,
+	})
+}
+
+// Provision provisions a node to a cluster
+func (h *NodeHandler) Provision(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	var req ProvisionNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Provision(id, req.ClusterID); err != nil {
+		h.handleServiceError(c, err, "Failed to provision node")
+		return
+	}
+
+	node, err := h.service.GetByID(id, false)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get node")
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"node_id":    id,
+		"cluster_id": req.ClusterID,
+	}).Info("Started node provisioning")
+	c.JSON(http.StatusAccepted, node)
+}
+
+// Deprovision removes a node from its cluster
+func (h *NodeHandler) Deprovision(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid node ID",
+		})
+		return
+	}
+
+	var req DeprovisionNodeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	opts := services.DeprovisionOptions{Force: req.Force}
+	if req.DrainTimeout != "" {
+		opts.DrainTimeout, err = time.ParseDuration(req.DrainTimeout)
+		if err != nil || opts.DrainTimeout <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "drain_timeout must be a positive duration such as 10m",
+			})
+			return
+		}
+	}
+
+	// Draining can outlast the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	if err := h.service.Deprovision(c.Request.Context(), id, opts); err != nil {
+		h.handleServiceError(c, err, "Failed to deprovision node")
+		return
+	}
+
+	node, err := h.service.GetByID(id, false)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get node")
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"node_id": id,
+		"force":   req.Force,
+	}).Info("Deprovisioned node")
+	c.JSON(http.StatusOK, node)
+}
+
+// handleServiceError handles service layer errors and maps them to appropriate HTTP responses
+func (h *NodeHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsNotFound(err) {
+		notFound := "Node not found"
+		if err != services.ErrNotFound {
+			// Wrapped errors name the missing resource, e.g. an unknown cluster
+			notFound = err.Error()
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": notFound,
+		})
+		return
+	}
+
+	if services.IsAlreadyExists(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": "Node with that name or IP address already exists",
+		})
+		return
+	}
+
+	if services.IsConflict(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err == services.ErrHasAssociatedResources {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": "Cannot delete node with associated GPIO devices",
+		})
+		return
+	}
+
+	if services.IsValidationFailed(err) || services.IsInvalidInput(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation Failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 1000
+)
+
+// parsePagination reads the limit and offset query parameters shared by all list endpoints
+func parsePagination(c *gin.Context) (int, int, error) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultListLimit)))
+	if err != nil || limit < 1 || limit > maxListLimit {
+		return 0, 0, fmt.Errorf("limit must be a number between 1 and %d", maxListLimit)
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("offset must be a non-negative number")
+	}
+
+	return limit, offset, nil
+}
+
+// parseIDParam reads a numeric ID from the named path parameter
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// parseUintQuery reads an optional numeric ID from the named query parameter
+func parseUintQuery(c *gin.Context, name string) (*uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter: must be a number", name)
+	}
+	id := uint(value)
+	return &id, nil
+}
+
+// parseTimeQuery reads an optional RFC 3339 timestamp from the named query parameter
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter: must be an RFC 3339 timestamp", name)
+	}
+	return &parsed, nil
+}
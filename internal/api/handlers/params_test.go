@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		query  string
+		limit  int
+		offset int
+		valid  bool
+	}{
+		{"", defaultListLimit, 0, true},
+		{"?limit=1&offset=20", 1, 20, true},
+		{"?limit=1000", maxListLimit, 0, true},
+		{"?limit=0", 0, 0, false},
+		{"?limit=-1", 0, 0, false},
+		{"?limit=1001", 0, 0, false},
+		{"?limit=ten", 0, 0, false},
+		{"?offset=-5", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/nodes"+tt.query, nil)
+
+			limit, offset, err := parsePagination(c)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.limit, limit)
+			assert.Equal(t, tt.offset, offset)
+		})
+	}
+}
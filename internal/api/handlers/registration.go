@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// RegistrationHandler handles the approval queue of discovered nodes
+type RegistrationHandler struct {
+	service *services.RegistrationService
+	logger  logger.Interface
+}
+
+// NewRegistrationHandler creates a new registration handler
+func NewRegistrationHandler(service *services.RegistrationService, logger logger.Interface) *RegistrationHandler {
+	return &RegistrationHandler{
+		service: service,
+		logger:  logger.WithField("handler", "registration"),
+	}
+}
+
+// List returns registrations of discovered nodes, pending ones first
+func (h *RegistrationHandler) List(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	opts := services.RegistrationListOptions{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if status := c.Query("status"); status != "" {
+		registrationStatus := models.RegistrationStatus(status)
+		opts.Status = &registrationStatus
+	}
+
+	registrations, total, err := h.service.List(opts)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to list registrations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registrations": registrations,
+		"count":         len(registrations),
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+// Get returns a registration
+func (h *RegistrationHandler) Get(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	registration, err := h.service.Get(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get registration")
+		return
+	}
+
+	c.JSON(http.StatusOK, registration)
+}
+
+// Approve adds a pending registration's node to the inventory and responds
+// with the node
+func (h *RegistrationHandler) Approve(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req services.ApproveRegistrationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	node, err := h.service.Approve(id, req)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to approve registration")
+		return
+	}
+
+	c.JSON(http.StatusCreated, node)
+}
+
+// Reject keeps a pending registration's node out of the inventory
+func (h *RegistrationHandler) Reject(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	registration, err := h.service.Reject(id)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to reject registration")
+		return
+	}
+
+	c.JSON(http.StatusOK, registration)
+}
+
+// Delete forgets a registration so its node is queued again when discovered
+func (h *RegistrationHandler) Delete(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		h.handleServiceError(c, err, "Failed to delete registration")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// parseID reads the registration ID from the path, responding with an error
+// if it is invalid
+func (h *RegistrationHandler) parseID(c *gin.Context) (uint, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid registration ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *RegistrationHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsValidationFailed(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsConflict(err) || services.IsAlreadyExists(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/discovery"
+)
+
+// setupRegistrationHandler queues two discovered nodes for approval
+func setupRegistrationHandler(t *testing.T) (*gin.Engine, []models.NodeRegistration) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	registrations := services.NewRegistrationService(db, services.NewNodeService(db, logger.Default()), nil, logger.Default())
+	for i, name := range []string{"raspberrypi", "laptop"} {
+		registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovery.Node{
+			Name:        name,
+			IPAddress:   fmt.Sprintf("192.168.1.%d", 10+i),
+			ServiceType: "_pi-controller._tcp",
+			MACAddress:  fmt.Sprintf("dc:a6:32:00:00:%02x", i),
+		}})
+	}
+	queued, _, err := registrations.List(services.RegistrationListOptions{})
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+
+	handler := NewRegistrationHandler(registrations, logger.Default())
+	router := gin.New()
+	router.GET("/registrations", handler.List)
+	router.GET("/registrations/:id", handler.Get)
+	router.POST("/registrations/:id/approve", handler.Approve)
+	router.POST("/registrations/:id/reject", handler.Reject)
+	router.DELETE("/registrations/:id", handler.Delete)
+	return router, queued
+}
+
+func TestRegistrationHandler_Approve(t *testing.T) {
+	router, queued := setupRegistrationHandler(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", fmt.Sprintf("/registrations/%d/approve", queued[0].ID), strings.NewReader(`{"name":"pi-1","role":"master"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var node models.Node
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &node))
+	assert.Equal(t, "pi-1", node.Name)
+	assert.Equal(t, models.NodeRoleMaster, node.Role)
+	assert.Equal(t, queued[0].MACAddress, node.MACAddress)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/registrations/%d/approve", queued[0].ID), nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "already approved")
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", fmt.Sprintf("/registrations/%d/approve", queued[1].ID), strings.NewReader(`{"name":"pi-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code, "the name is taken")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/registrations/999/approve", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRegistrationHandler_ListRejectDelete(t *testing.T) {
+	router, queued := setupRegistrationHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", fmt.Sprintf("/registrations/%d/reject", queued[1].ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Registrations []models.NodeRegistration `json:"registrations"`
+		Total         int64                     `json:"total"`
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/registrations?status=pending", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(1), response.Total)
+	require.Len(t, response.Registrations, 1)
+	assert.Equal(t, queued[0].ID, response.Registrations[0].ID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", fmt.Sprintf("/registrations/%d", queued[1].ID), nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/registrations/%d", queued[1].ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/registrations/first", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
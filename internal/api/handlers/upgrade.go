@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// UpgradeHandler handles cluster upgrade API operations
+type UpgradeHandler struct {
+	service *services.UpgradeService
+	logger  logger.Interface
+}
+
+// NewUpgradeHandler creates a new upgrade handler
+func NewUpgradeHandler(service *services.UpgradeService, logger logger.Interface) *UpgradeHandler {
+	return &UpgradeHandler{
+		service: service,
+		logger:  logger.WithField("handler", "upgrade"),
+	}
+}
+
+// UpgradeClusterRequest represents a request to upgrade a cluster's
+// Kubernetes version
+type UpgradeClusterRequest struct {
+	Version      string `json:"version" binding:"required"` // k3s release, e.g. "v1.30.2+k3s1"
+	BatchSize    int    `json:"batch_size"`                 // workers upgraded at once; defaults to 1
+	DrainTimeout string `json:"drain_timeout"`              // e.g. "10m"; defaults to 5m
+}
+
+// Create starts a rolling upgrade of a cluster. The upgrade runs in the
+// background; its progress is reported by the cluster status endpoint.
+func (h *UpgradeHandler) Create(c *gin.Context) {
+	clusterID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid cluster ID",
+		})
+		return
+	}
+
+	var req UpgradeClusterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	upgradeReq := services.UpgradeRequest{Version: req.Version, BatchSize: req.BatchSize}
+	if req.DrainTimeout != "" {
+		upgradeReq.DrainTimeout, err = time.ParseDuration(req.DrainTimeout)
+		if err != nil || upgradeReq.DrainTimeout <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "drain_timeout must be a positive duration such as 10m",
+			})
+			return
+		}
+	}
+
+	upgrade, err := h.service.Upgrade(clusterID, upgradeReq)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to start upgrade")
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"upgrade_id": upgrade.ID,
+		"version":    req.Version,
+	}).Info("Started cluster upgrade")
+
+	c.JSON(http.StatusAccepted, upgrade)
+}
+
+// handleServiceError maps service errors to HTTP responses
+func (h *UpgradeHandler) handleServiceError(c *gin.Context, err error, message string) {
+	h.logger.WithError(err).Error(message)
+
+	if services.IsValidationFailed(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if services.IsConflict(err) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal Server Error",
+		"message": message,
+	})
+}
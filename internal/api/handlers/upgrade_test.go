@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+// noopUpgrader stands in for upgrading nodes over SSH
+type noopUpgrader struct{}
+
+func (noopUpgrader) UpgradeNode(ctx context.Context, job *models.ProvisioningJob, step string, node *models.Node, version string) (string, string, error) {
+	return "", "", nil
+}
+
+// setupUpgradeHandler creates a cluster with no nodes joined to Kubernetes
+func setupUpgradeHandler(t *testing.T) (*gin.Engine, *storage.Database, *models.Cluster) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	clusters := services.NewClusterService(db, logger.Default())
+	cluster, err := clusters.Create(services.CreateClusterRequest{Name: "home", Version: "v1.30.2+k3s1"})
+	require.NoError(t, err)
+
+	upgrades := services.NewUpgradeService(db, fakeClusterClients{clientset: fake.NewSimpleClientset()}, logger.Default())
+	upgrades.SetNodeUpgrader(noopUpgrader{})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		upgrades.Wait()
+	})
+	require.NoError(t, upgrades.Start(ctx))
+
+	upgradeHandler := NewUpgradeHandler(upgrades, logger.Default())
+	clusterHandler := NewClusterHandler(clusters, logger.Default())
+	router := gin.New()
+	router.POST("/clusters/:id/upgrade", upgradeHandler.Create)
+	router.GET("/clusters/:id/status", clusterHandler.Status)
+	return router, db, cluster
+}
+
+func TestUpgradeHandler_Create(t *testing.T) {
+	router, _, cluster := setupUpgradeHandler(t)
+
+	tests := []struct {
+		name      string
+		clusterID string
+		body      string
+		status    int
+	}{
+		{"missing version", fmt.Sprint(cluster.ID), `{}`, http.StatusBadRequest},
+		{"invalid version", fmt.Sprint(cluster.ID), `{"version":"1.30"}`, http.StatusBadRequest},
+		{"invalid drain timeout", fmt.Sprint(cluster.ID), `{"version":"v1.30.4+k3s1","drain_timeout":"soon"}`, http.StatusBadRequest},
+		{"invalid cluster ID", "home", `{"version":"v1.30.4+k3s1"}`, http.StatusBadRequest},
+		{"unknown cluster", "999", `{"version":"v1.30.4+k3s1"}`, http.StatusNotFound},
+		{"no joined nodes", fmt.Sprint(cluster.ID), `{"version":"v1.30.4+k3s1","batch_size":2}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/clusters/"+tt.clusterID+"/upgrade", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestClusterHandler_StatusReportsUpgrade(t *testing.T) {
+	router, db, cluster := setupUpgradeHandler(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/status", cluster.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"upgrade"`, "a cluster never upgraded has no upgrade")
+
+	upgrade := &models.ClusterUpgrade{
+		ClusterID:     cluster.ID,
+		FromVersion:   "v1.30.2+k3s1",
+		ToVersion:     "v1.30.4+k3s1",
+		BatchSize:     2,
+		Status:        models.UpgradeStatusRunning,
+		Phase:         models.UpgradePhaseWorkers,
+		CurrentNodes:  "pi-worker-1,pi-worker-2",
+		NodesTotal:    5,
+		NodesUpgraded: 1,
+		StartedAt:     time.Now(),
+	}
+	require.NoError(t, db.DB().Create(upgrade).Error)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/clusters/%d/status", cluster.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report services.ClusterStatusReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, cluster.ID, report.ClusterID)
+	assert.Equal(t, "v1.30.2+k3s1", report.Version)
+	require.NotNil(t, report.Upgrade)
+	assert.Equal(t, models.UpgradeStatusRunning, report.Upgrade.Status)
+	assert.Equal(t, "pi-worker-1,pi-worker-2", report.Upgrade.CurrentNodes)
+	assert.Equal(t, 1, report.Upgrade.NodesUpgraded)
+}
@@ -44,8 +44,9 @@ func DefaultValidationConfig() *ValidationConfig {
 
 // Validator provides request validation middleware
 type Validator struct {
-	config *ValidationConfig
-	logger logger.Interface
+	config       *ValidationConfig
+	logger       logger.Interface
+	contentTypes map[string][]string
 }
 
 // NewValidator creates a new request validator
@@ -55,11 +56,19 @@ func NewValidator(config *ValidationConfig, logger logger.Interface) *Validator
 	}
 
 	return &Validator{
-		config: config,
-		logger: logger.WithField("component", "validator"),
+		config:       config,
+		logger:       logger.WithField("component", "validator"),
+		contentTypes: make(map[string][]string),
 	}
 }
 
+// AllowContentTypes accepts request bodies of the given content types on a
+// route, identified by its full path such as /api/v1/clusters/:id/apply, in
+// addition to JSON and multipart forms. Call it before serving requests.
+func (v *Validator) AllowContentTypes(route string, contentTypes ...string) {
+	v.contentTypes[route] = append(v.contentTypes[route], contentTypes...)
+}
+
 // ValidateRequest provides comprehensive request validation
 func (v *Validator) ValidateRequest() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -201,7 +210,7 @@ func (v *Validator) validateHeaders(c *gin.Context) error {
 	// Check Content-Type for POST/PUT requests
 	if c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "PATCH" {
 		contentType := c.GetHeader("Content-Type")
-		if contentType != "" && !strings.HasPrefix(contentType, "application/json") && !strings.HasPrefix(contentType, "multipart/form-data") {
+		if contentType != "" && !v.isAllowedContentType(c.FullPath(), contentType) {
 			return fmt.Errorf("unsupported content type: %s", contentType)
 		}
 	}
@@ -253,6 +262,20 @@ func (v *Validator) validateQueryParams(c *gin.Context) error {
 	return nil
 }
 
+// isAllowedContentType checks if a request body's content type is allowed on
+// a route
+func (v *Validator) isAllowedContentType(route, contentType string) bool {
+	if strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "multipart/form-data") {
+		return true
+	}
+	for _, allowed := range v.contentTypes[route] {
+		if strings.HasPrefix(contentType, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
 // isAllowedMethod checks if HTTP method is allowed
 func (v *Validator) isAllowedMethod(method string) bool {
 	for _, allowed := range v.config.AllowedMethods {
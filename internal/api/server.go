@@ -18,37 +18,77 @@ import (
 
 // Server represents the REST API server
 type Server struct {
-	config         *config.APIConfig
-	logger         logger.Interface
-	database       *storage.Database
-	clusterService *services.ClusterService
-	nodeService    *services.NodeService
-	gpioService    *services.GPIOService
-	authManager    *middleware.AuthManager
-	validator      *middleware.Validator
-	rateLimiter    *middleware.RateLimiter
-	router         *gin.Engine
-	server         *http.Server
+	config              *config.APIConfig
+	logger              logger.Interface
+	database            *storage.Database
+	clusterService      *services.ClusterService
+	nodeService         *services.NodeService
+	gpioService         *services.GPIOService
+	jobService          *services.JobService
+	deploymentService   *services.DeploymentService
+	backupService       *services.BackupService
+	upgradeService      *services.UpgradeService
+	registrationService *services.RegistrationService
+	enrollmentService   *services.EnrollmentService
+	authManager         *middleware.AuthManager
+	validator           *middleware.Validator
+	rateLimiter         *middleware.RateLimiter
+	router              *gin.Engine
+	server              *http.Server
 }
 
-// New creates a new API server instance
+// New creates a new API server instance with its own services
 func New(cfg *config.APIConfig, log logger.Interface, db *storage.Database) *Server {
-	// Set Gin mode based on environment  
-	gin.SetMode(gin.ReleaseMode) // Default to release mode for structured logging
-
-	router := gin.New()
-
 	// Initialize services
 	clusterService := services.NewClusterService(db, log)
 	nodeService := services.NewNodeService(db, log)
 	gpioService := services.NewGPIOService(db, log)
+	jobService := services.NewJobService(db, log)
+	kubeClients := services.NewKubeClientRegistry(log)
+	clusterService.SetKubeClientRegistry(kubeClients)
+	deploymentService := services.NewDeploymentService(db, kubeClients, log)
+	registrationService := services.NewRegistrationService(db, nodeService, nil, log)
+
+	// Backups need a configured store, upgrades need SSH access to nodes and
+	// enrollment needs the controller's CA, so they are only served by the
+	// controller
+	return NewWithServices(cfg, log, db, Services{
+		Cluster:      clusterService,
+		Node:         nodeService,
+		GPIO:         gpioService,
+		Job:          jobService,
+		Deployment:   deploymentService,
+		Registration: registrationService,
+	})
+}
+
+// Services are the services the API server exposes. Backup, Upgrade and
+// Enrollment may be nil, in which case their endpoints are not served.
+type Services struct {
+	Cluster      *services.ClusterService
+	Node         *services.NodeService
+	GPIO         *services.GPIOService
+	Job          *services.JobService
+	Deployment   *services.DeploymentService
+	Backup       *services.BackupService
+	Upgrade      *services.UpgradeService
+	Registration *services.RegistrationService
+	Enrollment   *services.EnrollmentService
+}
+
+// NewWithServices creates a new API server instance using services shared with
+// the rest of the controller
+func NewWithServices(cfg *config.APIConfig, log logger.Interface, db *storage.Database, svc Services) *Server {
+	// Set Gin mode based on environment  
+	gin.SetMode(gin.ReleaseMode) // Default to release mode for structured logging
+
+	router := gin.New()
 
 	// Initialize authentication manager if auth is enabled
 	var authManager *middleware.AuthManager
 	if cfg.AuthEnabled {
-		authConfig := middleware.DefaultAuthConfig()
 		var err error
-		authManager, err = middleware.NewAuthManager(authConfig, log)
+		authManager, err = NewAuthManager(cfg, log)
 		if err != nil {
 			log.WithError(err).Fatalf("Failed to initialize authentication manager")
 		}
@@ -62,16 +102,22 @@ func New(cfg *config.APIConfig, log logger.Interface, db *storage.Database) *Ser
 	rateLimiter := middleware.NewRateLimiter(middleware.DefaultRateLimitConfig(), logrusLogger)
 
 	s := &Server{
-		config:         cfg,
-		logger:         log,
-		database:       db,
-		clusterService: clusterService,
-		nodeService:    nodeService,
-		gpioService:    gpioService,
-		authManager:    authManager,
-		validator:      validator,
-		rateLimiter:    rateLimiter,
-		router:         router,
+		config:              cfg,
+		logger:              log,
+		database:            db,
+		clusterService:      svc.Cluster,
+		nodeService:         svc.Node,
+		gpioService:         svc.GPIO,
+		jobService:          svc.Job,
+		deploymentService:   svc.Deployment,
+		backupService:       svc.Backup,
+		upgradeService:      svc.Upgrade,
+		registrationService: svc.Registration,
+		enrollmentService:   svc.Enrollment,
+		authManager:         authManager,
+		validator:           validator,
+		rateLimiter:         rateLimiter,
+		router:              router,
 	}
 
 	s.setupRoutes()
@@ -105,6 +151,7 @@ func (s *Server) setupRoutes() {
 
 		// Cluster management
 		clusterHandler := handlers.NewClusterHandler(s.clusterService, s.logger)
+		deploymentHandler := handlers.NewDeploymentHandler(s.deploymentService, s.logger)
 		clusters := v1.Group("/clusters")
 		{
 			// Read operations - require viewer role
@@ -112,23 +159,55 @@ func (s *Server) setupRoutes() {
 			clusters.GET("/:id", s.requireRole("viewer"), clusterHandler.Get)
 			clusters.GET("/:id/nodes", s.requireRole("viewer"), clusterHandler.ListNodes)
 			clusters.GET("/:id/status", s.requireRole("viewer"), clusterHandler.Status)
+			clusters.GET("/:id/deployments", s.requireRole("viewer"), deploymentHandler.List)
+			clusters.GET("/:id/deployments/:namespace/:name", s.requireRole("viewer"), deploymentHandler.Get)
+			clusters.GET("/:id/deployments/:namespace/:name/rollout", s.requireRole("viewer"), deploymentHandler.RolloutStatus)
 			
 			// Write operations - require operator role
 			clusters.POST("", s.requireRole("operator"), clusterHandler.Create)
 			clusters.PUT("/:id", s.requireRole("operator"), clusterHandler.Update)
+			clusters.POST("/:id/deployments", s.requireRole("operator"), deploymentHandler.Create)
+			clusters.PUT("/:id/deployments/:namespace/:name", s.requireRole("operator"), deploymentHandler.Update)
+			clusters.POST("/:id/deployments/:namespace/:name/rollback", s.requireRole("operator"), deploymentHandler.Rollback)
 			
 			// Delete operations - require admin role
 			clusters.DELETE("/:id", s.requireRole("admin"), clusterHandler.Delete)
+			clusters.DELETE("/:id/deployments/:namespace/:name", s.requireRole("admin"), deploymentHandler.Delete)
+
+			// Apply accepts any kind, including RBAC, and can prune or take over
+			// fields, so it requires admin role
+			clusters.POST("/:id/apply", s.requireRole("admin"), deploymentHandler.Apply)
+			s.validator.AllowContentTypes(clusters.BasePath()+"/:id/apply", "application/yaml", "application/x-yaml", "text/yaml")
+
+			// Backups - restoring replaces the cluster's state, so it requires admin role
+			if s.backupService != nil {
+				backupHandler := handlers.NewBackupHandler(s.backupService, s.logger)
+				clusters.GET("/:id/backups", s.requireRole("viewer"), backupHandler.List)
+				clusters.GET("/:id/backups/:backup_id", s.requireRole("viewer"), backupHandler.Get)
+				clusters.POST("/:id/backups", s.requireRole("operator"), backupHandler.Create)
+				clusters.POST("/:id/backups/:backup_id/restore", s.requireRole("admin"), backupHandler.Restore)
+				clusters.DELETE("/:id/backups/:backup_id", s.requireRole("admin"), backupHandler.Delete)
+			}
+
+			// Upgrades - progress is reported by the status endpoint
+			if s.upgradeService != nil {
+				upgradeHandler := handlers.NewUpgradeHandler(s.upgradeService, s.logger)
+				clusters.POST("/:id/upgrade", s.requireRole("operator"), upgradeHandler.Create)
+			}
 		}
 
 		// Node management
 		nodeHandler := handlers.NewNodeHandler(s.nodeService, s.logger)
+		jobHandler := handlers.NewJobHandler(s.jobService, s.nodeService, s.logger)
 		nodes := v1.Group("/nodes")
 		{
 			// Read operations - require viewer role
 			nodes.GET("", s.requireRole("viewer"), nodeHandler.List)
 			nodes.GET("/:id", s.requireRole("viewer"), nodeHandler.Get)
 			nodes.GET("/:id/gpio", s.requireRole("viewer"), nodeHandler.ListGPIO)
+			nodes.GET("/:id/jobs", s.requireRole("viewer"), jobHandler.List)
+			nodes.GET("/:id/jobs/:job_id", s.requireRole("viewer"), jobHandler.Get)
+			nodes.GET("/:id/jobs/:job_id/logs", s.requireRole("viewer"), jobHandler.Logs)
 			
 			// Write operations - require operator role
 			nodes.POST("", s.requireRole("operator"), nodeHandler.Create)
@@ -138,6 +217,35 @@ func (s *Server) setupRoutes() {
 			
 			// Delete operations - require admin role
 			nodes.DELETE("/:id", s.requireRole("admin"), nodeHandler.Delete)
+
+			// Agent certificates - revoking cuts the agent off, so it requires admin role
+			if s.enrollmentService != nil {
+				enrollmentHandler := handlers.NewEnrollmentHandler(s.enrollmentService, s.logger)
+				nodes.GET("/:id/certificates", s.requireRole("viewer"), enrollmentHandler.ListCertificates)
+				nodes.POST("/:id/certificates/revoke", s.requireRole("admin"), enrollmentHandler.RevokeCertificates)
+			}
+		}
+
+		// Bootstrap tokens let agents enroll, so managing them requires admin role
+		if s.enrollmentService != nil {
+			enrollmentHandler := handlers.NewEnrollmentHandler(s.enrollmentService, s.logger)
+			tokens := v1.Group("/bootstrap-tokens")
+			{
+				tokens.GET("", s.requireRole("admin"), enrollmentHandler.ListTokens)
+				tokens.POST("", s.requireRole("admin"), enrollmentHandler.CreateToken)
+				tokens.DELETE("/:id", s.requireRole("admin"), enrollmentHandler.DeleteToken)
+			}
+		}
+
+		// Discovered nodes awaiting approval into the inventory
+		registrationHandler := handlers.NewRegistrationHandler(s.registrationService, s.logger)
+		registrations := v1.Group("/registrations")
+		{
+			registrations.GET("", s.requireRole("viewer"), registrationHandler.List)
+			registrations.GET("/:id", s.requireRole("viewer"), registrationHandler.Get)
+			registrations.POST("/:id/approve", s.requireRole("operator"), registrationHandler.Approve)
+			registrations.POST("/:id/reject", s.requireRole("operator"), registrationHandler.Reject)
+			registrations.DELETE("/:id", s.requireRole("admin"), registrationHandler.Delete)
 		}
 
 		// GPIO management
@@ -203,6 +311,19 @@ func (s *Server) Stop(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
 
+// NewAuthManager creates the authentication manager issuing and verifying
+// the API's tokens
+func NewAuthManager(cfg *config.APIConfig, log logger.Interface) (*middleware.AuthManager, error) {
+	authConfig := middleware.DefaultAuthConfig()
+	authConfig.JWTSecretFromFile = cfg.JWTSecretFile
+	return middleware.NewAuthManager(authConfig, log)
+}
+
+// AuthManager returns the server's authentication manager, or nil when auth is disabled
+func (s *Server) AuthManager() *middleware.AuthManager {
+	return s.authManager
+}
+
 // Router returns the underlying Gin router for testing
 func (s *Server) Router() *gin.Engine {
 	return s.router
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+func TestServer_ContentTypes(t *testing.T) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	server := New(&config.APIConfig{ReadTimeout: "30s", WriteTimeout: "30s"}, logger.Default(), db)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		rejected    bool
+	}{
+		{"manifests as YAML", "/api/v1/clusters/999/apply", "application/yaml", false},
+		{"manifests as legacy YAML", "/api/v1/clusters/999/apply", "application/x-yaml", false},
+		{"manifests as text YAML", "/api/v1/clusters/999/apply", "text/yaml; charset=utf-8", false},
+		{"manifests as JSON", "/api/v1/clusters/999/apply", "application/json", false},
+		{"manifests as plain text", "/api/v1/clusters/999/apply", "text/plain", true},
+		{"clusters as YAML", "/api/v1/clusters", "application/yaml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader("kind: ConfigMap\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+			server.Router().ServeHTTP(w, req)
+
+			if tt.rejected {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), "unsupported content type")
+			} else {
+				assert.Equal(t, http.StatusNotFound, w.Code, "reaches the handler: %s", w.Body.String())
+			}
+		})
+	}
+}
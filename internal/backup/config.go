@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+)
+
+// Storage kinds
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// Config contains cluster backup configuration
+type Config struct {
+	// Interval between scheduled backups of each cluster; zero disables them
+	Schedule time.Duration
+
+	// Retention
+	KeepLast int
+	MaxAge   time.Duration
+
+	// Time allowed for one backup or restore
+	Timeout time.Duration
+
+	// Storage
+	Storage string
+	Dir     string
+	S3      S3Config
+}
+
+// S3Config contains settings for an S3-compatible bucket
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	Prefix    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool
+}
+
+// applyDefaults sets default values for unset configuration fields
+func applyDefaults(config Config) Config {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Minute
+	}
+	if config.Storage == "" {
+		config.Storage = StorageLocal
+	}
+	return config
+}
+
+// ConfigFromYAML converts the YAML-based config to the backup Config
+func ConfigFromYAML(yamlConfig config.BackupConfig) (Config, error) {
+	backupConfig := Config{
+		KeepLast: yamlConfig.KeepLast,
+		Storage:  yamlConfig.Storage,
+		Dir:      yamlConfig.Dir,
+		S3: S3Config{
+			Endpoint:  yamlConfig.S3.Endpoint,
+			Bucket:    yamlConfig.S3.Bucket,
+			Prefix:    yamlConfig.S3.Prefix,
+			Region:    yamlConfig.S3.Region,
+			AccessKey: yamlConfig.S3.AccessKey,
+			SecretKey: yamlConfig.S3.SecretKey,
+			Insecure:  yamlConfig.S3.Insecure,
+		},
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"schedule", yamlConfig.Schedule, &backupConfig.Schedule},
+		{"max_age", yamlConfig.MaxAge, &backupConfig.MaxAge},
+		{"timeout", yamlConfig.Timeout, &backupConfig.Timeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return backupConfig, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		if parsed < 0 {
+			return backupConfig, fmt.Errorf("%s must not be negative, got %s", d.name, parsed)
+		}
+		*d.dest = parsed
+	}
+	if backupConfig.KeepLast < 0 {
+		return backupConfig, fmt.Errorf("keep_last must not be negative, got %d", backupConfig.KeepLast)
+	}
+
+	return applyDefaults(backupConfig), nil
+}
@@ -0,0 +1,134 @@
+// Package backup stores snapshots of the datastores of managed clusters
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when a snapshot is not in the store
+var ErrNotFound = errors.New("snapshot not found")
+
+// Store keeps snapshots under slash-separated keys
+type Store interface {
+	// Kind returns the kind of storage, StorageLocal or StorageS3
+	Kind() string
+
+	// Put writes a snapshot, replacing any with the same key
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens a snapshot for reading
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes a snapshot; deleting a missing snapshot is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore creates the store selected by the configuration
+func NewStore(config Config) (Store, error) {
+	switch config.Storage {
+	case StorageLocal:
+		return NewLocalStore(config.Dir)
+	case StorageS3:
+		return NewS3Store(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown backup storage %q", config.Storage)
+	}
+}
+
+// validKey reports whether a key is a clean relative path
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "/") && path.Clean(key) == key && !strings.HasPrefix(key, "../") && key != ".."
+}
+
+// LocalStore keeps snapshots in a directory
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store in dir, creating the directory if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("backup directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Kind returns StorageLocal
+func (s *LocalStore) Kind() string {
+	return StorageLocal
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid snapshot key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes a snapshot to a temporary file and renames it into place, so a
+// failed write never leaves a partial snapshot under the key
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	dest, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".partial-")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return nil
+}
+
+// Get opens a snapshot file
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	return file, nil
+}
+
+// Delete removes a snapshot file
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+)
+
+// fakeS3 is an in-memory stand-in for MinIO serving the object and
+// multipart upload calls the store makes. Signatures are not checked.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	next    int
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	s3 := &fakeS3{objects: make(map[string][]byte), uploads: make(map[string]map[int][]byte)}
+	server := httptest.NewServer(s3)
+	t.Cleanup(server.Close)
+	return s3, server
+}
+
+func (s *fakeS3) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// body reads a request body, decoding aws-chunked streaming uploads
+func body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	uploadID := query.Get("uploadId")
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.next++
+		id := strconv.Itoa(s.next)
+		s.uploads[id] = make(map[int][]byte)
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			UploadID string   `xml:"UploadId"`
+		}{UploadID: id})
+
+	case r.Method == http.MethodPut && uploadID != "":
+		data, err := body(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, _ := strconv.Atoi(query.Get("partNumber"))
+		s.uploads[uploadID][part] = data
+		w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, part))
+
+	case r.Method == http.MethodPost && uploadID != "":
+		var data []byte
+		for part := 1; part <= len(s.uploads[uploadID]); part++ {
+			data = append(data, s.uploads[uploadID][part]...)
+		}
+		delete(s.uploads, uploadID)
+		s.objects[name] = data
+		bucket, key, _ := strings.Cut(name, "/")
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string   `xml:"Bucket"`
+			Key     string   `xml:"Key"`
+			ETag    string   `xml:"ETag"`
+		}{Bucket: bucket, Key: key, ETag: `"object"`})
+
+	case r.Method == http.MethodDelete && uploadID != "":
+		delete(s.uploads, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := s.objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				writeXML(w, struct {
+					XMLName xml.Name `xml:"Error"`
+					Code    string   `xml:"Code"`
+				}{Code: "NoSuchKey"})
+			}
+			return
+		}
+		w.Header().Set("ETag", `"object"`)
+		http.ServeContent(w, r, name, time.Unix(1760600000, 0), bytes.NewReader(data))
+
+	case r.Method == http.MethodDelete:
+		delete(s.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "unsupported request", http.StatusNotImplemented)
+	}
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(v)
+}
+
+// testStore puts, gets and deletes a snapshot
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	key := "clusters/1/20261016T100000Z-manual.snapshot"
+	data := bytes.Repeat([]byte("snapshot "), 10000)
+
+	require.NoError(t, store.Put(ctx, key, bytes.NewReader(data)))
+
+	reader, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data, got)
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.True(t, errors.Is(err, ErrNotFound), "got %v", err)
+	assert.NoError(t, store.Delete(ctx, key), "deleting twice is not an error")
+
+	assert.Error(t, store.Put(ctx, "../outside", bytes.NewReader(data)))
+	assert.Error(t, store.Put(ctx, "/absolute", bytes.NewReader(data)))
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+	assert.Equal(t, StorageLocal, store.Kind())
+
+	testStore(t, store)
+
+	t.Run("failed writes leave nothing behind", func(t *testing.T) {
+		err := store.Put(context.Background(), "clusters/1/broken", io.MultiReader(
+			strings.NewReader("partial"),
+			errorReader{errors.New("agent went away")},
+		))
+		assert.Error(t, err)
+
+		_, err = store.Get(context.Background(), "clusters/1/broken")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+type errorReader struct{ err error }
+
+func (r errorReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestS3Store(t *testing.T) {
+	s3, server := newFakeS3(t)
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint.Host,
+		Bucket:    "backups",
+		Prefix:    "/pi-controller/",
+		Region:    "us-east-1",
+		AccessKey: "minio",
+		SecretKey: "minio123",
+		Insecure:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StorageS3, store.Kind())
+
+	testStore(t, store)
+
+	require.NoError(t, store.Put(context.Background(), "clusters/2/latest", strings.NewReader("etcd")))
+	assert.Equal(t, []string{"backups/pi-controller/clusters/2/latest"}, s3.keys(), "objects are stored under the prefix")
+}
+
+func TestConfigFromYAML(t *testing.T) {
+	backupConfig, err := ConfigFromYAML(config.BackupConfig{Schedule: "24h", KeepLast: 7})
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, backupConfig.Schedule)
+	assert.Equal(t, 7, backupConfig.KeepLast)
+	assert.Equal(t, 30*time.Minute, backupConfig.Timeout)
+	assert.Equal(t, StorageLocal, backupConfig.Storage)
+
+	_, err = ConfigFromYAML(config.BackupConfig{Schedule: "daily"})
+	assert.Error(t, err)
+	_, err = ConfigFromYAML(config.BackupConfig{MaxAge: "-1h"})
+	assert.Error(t, err)
+	_, err = ConfigFromYAML(config.BackupConfig{KeepLast: -1})
+	assert.Error(t, err)
+}
@@ -45,6 +45,18 @@ type Config struct {
 	
 	// Pi Agent gRPC server configuration
 	AgentServer AgentServerConfig `yaml:"agent_server"`
+	
+	// Controller-side agent connection pool configuration
+	AgentPool AgentPoolConfig `yaml:"agent_pool"`
+
+	// Node provisioning over SSH
+	Provisioning ProvisioningConfig `yaml:"provisioning"`
+
+	// Cluster datastore backups
+	Backup BackupConfig `yaml:"backup"`
+
+	// Certificate authority for controller<->agent mTLS
+	PKI PKIConfig `yaml:"pki"`
 }
 
 // AppConfig contains general application settings
@@ -66,6 +78,10 @@ type APIConfig struct {
 	TLSKeyFile   string `yaml:"tls_key_file"`
 	CORSEnabled  bool   `yaml:"cors_enabled"`
 	AuthEnabled  bool   `yaml:"auth_enabled"`
+
+	// JWTSecretFile holds the secret signing tokens, so they survive
+	// restarts. Without it a random secret is generated on each start.
+	JWTSecretFile string `yaml:"jwt_secret_file"`
 }
 
 // GRPCConfig contains gRPC server settings
@@ -78,12 +94,14 @@ type GRPCConfig struct {
 
 // WebSocketConfig contains WebSocket server settings
 type WebSocketConfig struct {
-	Host            string `yaml:"host"`
-	Port            int    `yaml:"port"`
-	Path            string `yaml:"path"`
-	ReadBufferSize  int    `yaml:"read_buffer_size"`
-	WriteBufferSize int    `yaml:"write_buffer_size"`
-	CheckOrigin     bool   `yaml:"check_origin"`
+	Host             string            `yaml:"host"`
+	Port             int               `yaml:"port"`
+	Path             string            `yaml:"path"`
+	ReadBufferSize   int               `yaml:"read_buffer_size"`
+	WriteBufferSize  int               `yaml:"write_buffer_size"`
+	CheckOrigin      bool              `yaml:"check_origin"`
+	ReplayBufferSize int               `yaml:"replay_buffer_size"` // recent topic messages kept for since_seq resume
+	TopicRoles       map[string]string `yaml:"topic_roles"`        // topic pattern -> minimum role, overriding the defaults
 }
 
 // LogConfig contains logging configuration
@@ -129,6 +147,26 @@ type DiscoveryConfig struct {
 	StaticNodes     []string `yaml:"static_nodes"`
 	ServiceName     string   `yaml:"service_name"`
 	ServiceType     string   `yaml:"service_type"`
+
+	// Methods runs several backends together (mdns, scan, static, dhcp,
+	// arp, phone-home) and merges their results by node identity
+	Methods          []string `yaml:"methods"`
+	LeaseFiles       []string `yaml:"lease_files"`        // dnsmasq or ISC dhcpd leases
+	ARPTable         string   `yaml:"arp_table"`          // /proc/net/arp
+	OUIs             []string `yaml:"ouis"`               // MAC prefixes kept from leases and ARP, Raspberry Pi's by default
+	PhoneHomeAddress string   `yaml:"phone_home_address"` // where freshly imaged Pis POST their identity
+
+	// Registration decides how discovered nodes enter the inventory
+	Registration RegistrationConfig `yaml:"registration"`
+}
+
+// RegistrationConfig contains the policy for adding discovered nodes to the
+// inventory. Allow and deny entries are CIDRs (192.168.1.0/24), MAC address
+// prefixes (dc:a6:32) or hostname patterns (pi-*).
+type RegistrationConfig struct {
+	Approval string   `yaml:"approval"` // auto or manual
+	Allow    []string `yaml:"allow"`    // approved automatically
+	Deny     []string `yaml:"deny"`     // never registered
 }
 
 // GRPCClientConfig contains gRPC client settings for Pi Agent
@@ -160,6 +198,17 @@ type GRPCClientConfig struct {
 	Insecure bool   `yaml:"insecure"`
 	TLSCert  string `yaml:"tls_cert"`
 	TLSKey   string `yaml:"tls_key"`
+
+	// Enrollment with the controller's CA. The bootstrap token is used once
+	// to obtain a client certificate, which is kept in the credentials
+	// directory and renewed before it expires. The CA certificate hash
+	// ("sha256:<hex>") pins the controller on first contact and is required
+	// unless InsecureSkipCAVerification trusts whichever CA the controller
+	// presents.
+	BootstrapToken             string `yaml:"bootstrap_token"`
+	CACertHash                 string `yaml:"ca_cert_hash"`
+	InsecureSkipCAVerification bool   `yaml:"insecure_skip_ca_verification"`
+	CredentialsDir             string `yaml:"credentials_dir"`
 	
 	// Node information
 	NodeID   string `yaml:"node_id"`
@@ -174,10 +223,120 @@ type AgentServerConfig struct {
 	
 	// Service settings
 	EnableGPIO bool `yaml:"enable_gpio"`
+
+	// Publish the agent over mDNS, using the discovery service type and
+	// interface, so the controller finds it without static configuration
+	Advertise bool `yaml:"advertise"`
 	
 	// Security
 	TLSCertFile string `yaml:"tls_cert_file"`
 	TLSKeyFile  string `yaml:"tls_key_file"`
+
+	// TokenPublicKeyFile is the public key of the controller's
+	// agent_pool.token_key_file, letting it authenticate with tokens instead
+	// of its client certificate. Tokens require the server to use TLS, from
+	// an enrolled certificate or TLSCertFile and TLSKeyFile.
+	TokenPublicKeyFile string `yaml:"token_public_key_file"`
+
+	// Kubernetes device plugin advertising the node's GPIO devices
+	DevicePlugin DevicePluginConfig `yaml:"device_plugin"`
+}
+
+// DevicePluginConfig contains settings for the agent's GPIO device plugin
+type DevicePluginConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	PluginDir          string `yaml:"plugin_dir"`
+	PodResourcesSocket string `yaml:"pod_resources_socket"`
+	RefreshInterval    string `yaml:"refresh_interval"`
+}
+
+// AgentPoolConfig contains settings for the controller's connections to Pi Agents
+type AgentPoolConfig struct {
+	// Agent gRPC port on each node
+	Port int `yaml:"port"`
+	
+	// Connection settings
+	MaxMessageSize   int    `yaml:"max_message_size"`
+	KeepAliveTime    string `yaml:"keepalive_time"`
+	KeepAliveTimeout string `yaml:"keepalive_timeout"`
+	
+	// Health probing
+	HealthInterval   string `yaml:"health_interval"`
+	HealthTimeout    string `yaml:"health_timeout"`
+	FailureThreshold int    `yaml:"failure_threshold"`
+	
+	// Security
+	Insecure bool `yaml:"insecure"`
+
+	// TLSCAFile verifies agents serving their own certificates
+	// (agent_server.tls_cert_file) when PKI is disabled
+	TLSCAFile string `yaml:"tls_ca_file"`
+
+	// TokenKeyFile holds the key signing the tokens the controller presents
+	// to agents, created if missing. Agents verify them with the public key
+	// written next to it as <file>.pub (agent_server.token_public_key_file).
+	// Tokens are only sent over TLS.
+	TokenKeyFile string `yaml:"token_key_file"`
+}
+
+// ProvisioningConfig contains settings for installing k3s on nodes over SSH
+type ProvisioningConfig struct {
+	// SSH connection
+	SSHUser               string `yaml:"ssh_user"`
+	SSHPort               int    `yaml:"ssh_port"`
+	SSHKeyFile            string `yaml:"ssh_key_file"`
+	KnownHostsFile        string `yaml:"known_hosts_file"`
+	InsecureIgnoreHostKey bool   `yaml:"insecure_ignore_host_key"`
+
+	// k3s installation
+	K3sVersion       string `yaml:"k3s_version"` // empty installs the latest stable release
+	InstallScriptURL string `yaml:"install_script_url"`
+	ReleaseURL       string `yaml:"k3s_release_url"` // k3s binaries used for upgrades are downloaded from <url>/<version>/<asset> and checked against <url>/<version>/sha256sum-<arch>.txt
+
+	// Timeouts
+	CommandTimeout      string `yaml:"command_timeout"`
+	RegistrationTimeout string `yaml:"registration_timeout"`
+	PollInterval        string `yaml:"poll_interval"`
+}
+
+// BackupConfig contains settings for snapshots of each cluster's datastore
+type BackupConfig struct {
+	// Interval between scheduled backups of each cluster; empty disables them
+	Schedule string `yaml:"schedule"`
+
+	// Retention
+	KeepLast int    `yaml:"keep_last"` // backups kept per cluster; 0 keeps all
+	MaxAge   string `yaml:"max_age"`   // empty keeps backups regardless of age
+
+	// Time allowed for one backup or restore
+	Timeout string `yaml:"timeout"`
+
+	// Where snapshots are stored: "local" or "s3"
+	Storage string         `yaml:"storage"`
+	Dir     string         `yaml:"dir"` // local storage directory, relative to the data directory
+	S3      BackupS3Config `yaml:"s3"`
+}
+
+// BackupS3Config contains settings for an S3-compatible backup bucket
+type BackupS3Config struct {
+	Endpoint  string `yaml:"endpoint"` // host[:port], e.g. s3.amazonaws.com or minio.local:9000
+	Bucket    string `yaml:"bucket"`
+	Prefix    string `yaml:"prefix"`
+	Region    string `yaml:"region"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	Insecure  bool   `yaml:"insecure"` // use plain HTTP
+}
+
+// PKIConfig contains settings for the controller's certificate authority.
+// When enabled, agents enroll with a bootstrap token for a client
+// certificate bound to their node, and all controller<->agent gRPC uses
+// mutual TLS with certificates the CA issues.
+type PKIConfig struct {
+	Enabled           bool   `yaml:"enabled"`
+	Dir               string `yaml:"dir"`                 // CA certificate and key, relative to the data directory
+	CertificateTTL    string `yaml:"certificate_ttl"`     // lifetime of issued certificates
+	BootstrapTokenTTL string `yaml:"bootstrap_token_ttl"` // default lifetime of bootstrap tokens
 }
 
 // Load loads configuration from YAML file with defaults
@@ -241,6 +400,12 @@ func (c *Config) validate() error {
 		if !filepath.IsAbs(c.Database.Path) {
 			c.Database.Path = filepath.Join(c.App.DataDir, c.Database.Path)
 		}
+		if c.Backup.Dir != "" && !filepath.IsAbs(c.Backup.Dir) {
+			c.Backup.Dir = filepath.Join(c.App.DataDir, c.Backup.Dir)
+		}
+		if c.PKI.Dir != "" && !filepath.IsAbs(c.PKI.Dir) {
+			c.PKI.Dir = filepath.Join(c.App.DataDir, c.PKI.Dir)
+		}
 	}
 	
 	// Validate log level
@@ -285,6 +450,9 @@ func getDevelopmentDefaults() Config {
 	config.API.TLSKeyFile = ""
 	config.GRPC.TLSCertFile = ""
 	config.GRPC.TLSKeyFile = ""
+
+	// Accept any SSH host key so new Pis can be provisioned without a known_hosts entry
+	config.Provisioning.InsecureIgnoreHostKey = true
 	
 	return config
 }
@@ -323,12 +491,13 @@ func getProductionDefaults() Config {
 			TLSKeyFile:  "/etc/pi-controller/tls/server.key", // Default TLS key path for production
 		},
 		WebSocket: WebSocketConfig{
-			Host:            "0.0.0.0",
-			Port:            8081,
-			Path:            "/ws",
-			ReadBufferSize:  1024,
-			WriteBufferSize: 1024,
-			CheckOrigin:     false,
+			Host:             "0.0.0.0",
+			Port:             8081,
+			Path:             "/ws",
+			ReadBufferSize:   1024,
+			WriteBufferSize:  1024,
+			CheckOrigin:      false,
+			ReplayBufferSize: 1024,
 		},
 		Log: LogConfig{
 			Level:      "info",
@@ -355,13 +524,18 @@ func getProductionDefaults() Config {
 			DefaultPullMode:  "none",
 		},
 		Discovery: DiscoveryConfig{
-			Enabled:     true,
-			Method:      "mdns",
-			Port:        9091,
-			Interval:    "30s",
-			Timeout:     "5s",
-			ServiceName: "pi-controller",
-			ServiceType: "_pi-controller._tcp",
+			Enabled:          true,
+			Method:           "mdns",
+			Port:             9091,
+			Interval:         "30s",
+			Timeout:          "5s",
+			ServiceName:      "pi-controller",
+			ServiceType:      "_pi-controller._tcp",
+			ARPTable:         "/proc/net/arp",
+			PhoneHomeAddress: ":8082",
+			Registration: RegistrationConfig{
+				Approval: "manual",
+			},
 		},
 		GRPCClient: GRPCClientConfig{
 			ServerAddress:     "localhost",
@@ -378,6 +552,7 @@ func getProductionDefaults() Config {
 			KeepAliveTime:     "30s",
 			KeepAliveTimeout:  "5s",
 			Insecure:          true,
+			CredentialsDir:    "/var/lib/pi-agent/pki",
 			NodeID:            "",
 			NodeName:          "",
 		},
@@ -385,6 +560,47 @@ func getProductionDefaults() Config {
 			Address:    "0.0.0.0",
 			Port:       9091,
 			EnableGPIO: true,
+			Advertise:  true,
+			DevicePlugin: DevicePluginConfig{
+				Enabled:            false,
+				PluginDir:          "/var/lib/kubelet/device-plugins",
+				PodResourcesSocket: "/var/lib/kubelet/pod-resources/kubelet.sock",
+				RefreshInterval:    "30s",
+			},
+		},
+		AgentPool: AgentPoolConfig{
+			Port:             9091,
+			MaxMessageSize:   4 * 1024 * 1024, // 4MB
+			KeepAliveTime:    "30s",
+			KeepAliveTimeout: "5s",
+			HealthInterval:   "15s",
+			HealthTimeout:    "3s",
+			FailureThreshold: 3,
+			Insecure:         true,
+		},
+		Provisioning: ProvisioningConfig{
+			SSHUser:             "pi",
+			SSHPort:             22,
+			SSHKeyFile:          "/etc/pi-controller/ssh/id_ed25519",
+			KnownHostsFile:      "/etc/pi-controller/ssh/known_hosts",
+			InstallScriptURL:    "https://get.k3s.io",
+			ReleaseURL:          "https://github.com/k3s-io/k3s/releases/download",
+			CommandTimeout:      "10m",
+			RegistrationTimeout: "5m",
+			PollInterval:        "5s",
+		},
+		Backup: BackupConfig{
+			Schedule: "24h",
+			KeepLast: 7,
+			Timeout:  "30m",
+			Storage:  "local",
+			Dir:      "backups",
+		},
+		PKI: PKIConfig{
+			Enabled:           false,
+			Dir:               "pki",
+			CertificateTTL:    "720h",
+			BootstrapTokenTTL: "24h",
 		},
 	}
 }
@@ -409,6 +625,12 @@ func applyEnvOverrides(config *Config) {
 	if env := os.Getenv("PI_CONTROLLER_DATA_DIR"); env != "" {
 		config.App.DataDir = env
 	}
+	if env := os.Getenv("PI_CONTROLLER_BACKUP_S3_ACCESS_KEY"); env != "" {
+		config.Backup.S3.AccessKey = env
+	}
+	if env := os.Getenv("PI_CONTROLLER_BACKUP_S3_SECRET_KEY"); env != "" {
+		config.Backup.S3.SecretKey = env
+	}
 }
 
 // parseIntEnv safely parses an integer from environment variable
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBufferSize is the number of events a subscription holds before it
+// starts dropping the oldest ones
+const DefaultBufferSize = 256
+
+// Filter selects the events delivered to a subscription
+type Filter func(Event) bool
+
+// OfType returns a filter matching events of any of the given types
+func OfType(types ...Type) Filter {
+	return func(e Event) bool {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Bus fans published events out to subscribers. Publishing never blocks: a
+// subscriber that falls behind loses its oldest queued events instead of
+// stalling the publisher or other subscribers.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// NewBus creates an event bus
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish delivers a payload to every matching subscriber
+func (b *Bus) Publish(payload Payload) {
+	event := Event{
+		Type:      payload.EventType(),
+		Timestamp: time.Now(),
+		Payload:   payload,
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if sub.filter == nil || sub.filter(event) {
+			sub.deliver(event)
+		}
+	}
+}
+
+// Subscribe registers for events published after this call. A nil filter
+// matches every event and a buffer of zero uses DefaultBufferSize. Callers
+// must Close the subscription.
+func (b *Bus) Subscribe(filter Filter, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBufferSize
+	}
+	sub := &Subscription{
+		ch:     make(chan Event, buffer),
+		filter: filter,
+		bus:    b,
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subs, sub)
+	b.mu.Unlock()
+	close(sub.ch)
+}
+
+// Subscription receives events from a Bus
+type Subscription struct {
+	ch      chan Event
+	filter  Filter
+	bus     *Bus
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// C returns the channel events are delivered on. It is closed by Close.
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events discarded because the consumer fell behind
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the delivery channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.remove(s)
+	})
+}
+
+// deliver queues an event, discarding the oldest queued event when full
+func (s *Subscription) deliver(event Event) {
+	for {
+		select {
+		case s.ch <- event:
+			return
+		default:
+		}
+
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_DropsOldestWhenFull(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(nil, 2)
+	defer sub.Close()
+
+	for i := uint(1); i <= 3; i++ {
+		bus.Publish(GPIOReading{ReadingID: i})
+	}
+
+	assert.Equal(t, uint64(1), sub.Dropped())
+	assert.Equal(t, uint(2), (<-sub.C()).Payload.(GPIOReading).ReadingID)
+	assert.Equal(t, uint(3), (<-sub.C()).Payload.(GPIOReading).ReadingID)
+}
+
+func TestBus_Filters(t *testing.T) {
+	bus := NewBus()
+	nodes := bus.Subscribe(OfType(TypeNode), 4)
+	defer nodes.Close()
+	all := bus.Subscribe(nil, 4)
+	defer all.Close()
+
+	bus.Publish(Node{Action: ActionCreated, NodeID: 1})
+	bus.Publish(Cluster{Action: ActionUpdated, ClusterID: 2})
+
+	require.Len(t, nodes.C(), 1)
+	event := <-nodes.C()
+	assert.Equal(t, TypeNode, event.Type)
+	assert.False(t, event.Timestamp.IsZero())
+	assert.Equal(t, uint(1), event.Payload.(Node).NodeID)
+
+	assert.Len(t, all.C(), 2)
+}
+
+func TestSubscription_Close(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(nil, 4)
+
+	bus.Publish(Node{NodeID: 1})
+	sub.Close()
+	sub.Close() // closing twice is safe
+
+	// Events queued before Close are still delivered, then the channel closes
+	assert.Equal(t, uint(1), (<-sub.C()).Payload.(Node).NodeID)
+	_, ok := <-sub.C()
+	assert.False(t, ok)
+
+	// Publishing after Close must not panic
+	bus.Publish(Node{NodeID: 2})
+}
+
+func TestBus_ConcurrentPublishers(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(nil, 8)
+	defer sub.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish(GPIOReading{ReadingID: uint(j)})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Nobody drained the subscription, so all but the buffered events were dropped
+	assert.Len(t, sub.C(), 8)
+	assert.Equal(t, uint64(400-8), sub.Dropped())
+}
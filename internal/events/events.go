@@ -0,0 +1,126 @@
+// Package events provides the in-process event bus that services publish state
+// changes to and that the WebSocket hub, gRPC streams and webhooks consume.
+package events
+
+import (
+	"time"
+)
+
+// Type identifies the kind of an event
+type Type string
+
+const (
+	TypeGPIOReading Type = "gpio.reading"
+	TypeGPIODevice  Type = "gpio.device"
+	TypeNode        Type = "node"
+	TypeCluster     Type = "cluster"
+
+	TypeProvisioningJob Type = "provisioning.job"
+	TypeProvisioningLog Type = "provisioning.log"
+)
+
+// Action describes what happened to the resource an event refers to
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+	ActionDeleted Action = "deleted"
+)
+
+// Payload is implemented by every event body. Each payload type belongs to
+// exactly one event Type.
+type Payload interface {
+	EventType() Type
+}
+
+// Event is a payload stamped with its type and publish time
+type Event struct {
+	Type      Type      `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Payload   Payload   `json:"payload"`
+}
+
+// GPIOReading is published whenever a GPIO reading is recorded
+type GPIOReading struct {
+	ReadingID uint      `json:"reading_id"`
+	DeviceID  uint      `json:"device_id"`
+	NodeID    uint      `json:"node_id"`
+	Pin       int       `json:"pin"`
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EventType implements Payload
+func (GPIOReading) EventType() Type { return TypeGPIOReading }
+
+// GPIODevice is published when a GPIO device is created, updated or deleted
+type GPIODevice struct {
+	Action     Action `json:"action"`
+	DeviceID   uint   `json:"device_id"`
+	NodeID     uint   `json:"node_id"`
+	Name       string `json:"name"`
+	Pin        int    `json:"pin"`
+	Direction  string `json:"direction"`
+	DeviceType string `json:"device_type"`
+	Status     string `json:"status"`
+}
+
+// EventType implements Payload
+func (GPIODevice) EventType() Type { return TypeGPIODevice }
+
+// Node is published when a node is created, updated or deleted, including
+// status changes driven by provisioning and agent reachability
+type Node struct {
+	Action    Action `json:"action"`
+	NodeID    uint   `json:"node_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	IPAddress string `json:"ip_address"`
+	ClusterID *uint  `json:"cluster_id,omitempty"`
+}
+
+// EventType implements Payload
+func (Node) EventType() Type { return TypeNode }
+
+// Cluster is published when a cluster is created, updated or deleted
+type Cluster struct {
+	Action     Action `json:"action"`
+	ClusterID  uint   `json:"cluster_id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	NodesReady int    `json:"nodes_ready"`
+	NodesTotal int    `json:"nodes_total"`
+}
+
+// EventType implements Payload
+func (Cluster) EventType() Type { return TypeCluster }
+
+// ProvisioningJob is published when a provisioning job starts, moves to a new
+// step or finishes
+type ProvisioningJob struct {
+	Action Action `json:"action"`
+	JobID  uint   `json:"job_id"`
+	NodeID uint   `json:"node_id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Step   string `json:"step,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// EventType implements Payload
+func (ProvisioningJob) EventType() Type { return TypeProvisioningJob }
+
+// ProvisioningLog is published for each line of output a provisioning step
+// produces. Stream is "stdout", "stderr" or "command" for the command being run.
+type ProvisioningLog struct {
+	JobID     uint      `json:"job_id"`
+	NodeID    uint      `json:"node_id"`
+	Step      string    `json:"step"`
+	Stream    string    `json:"stream"`
+	Line      string    `json:"line"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EventType implements Payload
+func (ProvisioningLog) EventType() Type { return TypeProvisioningLog }
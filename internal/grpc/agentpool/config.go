@@ -0,0 +1,116 @@
+package agentpool
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+)
+
+// Config contains agent pool configuration
+type Config struct {
+	// Agent gRPC port on each node
+	Port int
+
+	// Connection settings
+	MaxMessageSize   int
+	KeepAliveTime    time.Duration
+	KeepAliveTimeout time.Duration
+
+	// Health probing
+	HealthInterval   time.Duration
+	HealthTimeout    time.Duration
+	FailureThreshold int
+
+	// Security
+	Insecure bool
+
+	// TLSConfig dials agents with mutual TLS, verifying each agent's
+	// certificate against its node. It takes precedence over RootCAs and
+	// Insecure.
+	TLSConfig *tls.Config
+
+	// RootCAs dials agents serving their own certificates with TLS,
+	// verifying them against the address dialed. It takes precedence over
+	// Insecure.
+	RootCAs *x509.CertPool
+
+	// Additional dial options, e.g. transport credentials or a test dialer
+	DialOptions []grpc.DialOption
+}
+
+// applyDefaults sets default values for unset configuration fields
+func applyDefaults(config Config) Config {
+	if config.Port == 0 {
+		config.Port = 9091
+	}
+	if config.MaxMessageSize == 0 {
+		config.MaxMessageSize = 4 * 1024 * 1024 // 4MB
+	}
+	if config.KeepAliveTime == 0 {
+		config.KeepAliveTime = 30 * time.Second
+	}
+	if config.KeepAliveTimeout == 0 {
+		config.KeepAliveTimeout = 5 * time.Second
+	}
+	if config.HealthInterval == 0 {
+		config.HealthInterval = 15 * time.Second
+	}
+	if config.HealthTimeout == 0 {
+		config.HealthTimeout = 3 * time.Second
+	}
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 3
+	}
+
+	return config
+}
+
+// ConfigFromYAML converts the YAML-based config to the agent pool Config
+func ConfigFromYAML(yamlConfig config.AgentPoolConfig) (Config, error) {
+	poolConfig := Config{
+		Port:             yamlConfig.Port,
+		MaxMessageSize:   yamlConfig.MaxMessageSize,
+		FailureThreshold: yamlConfig.FailureThreshold,
+		Insecure:         yamlConfig.Insecure,
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"health_interval", yamlConfig.HealthInterval, &poolConfig.HealthInterval},
+		{"health_timeout", yamlConfig.HealthTimeout, &poolConfig.HealthTimeout},
+		{"keepalive_time", yamlConfig.KeepAliveTime, &poolConfig.KeepAliveTime},
+		{"keepalive_timeout", yamlConfig.KeepAliveTimeout, &poolConfig.KeepAliveTimeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return poolConfig, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.dest = parsed
+	}
+
+	if yamlConfig.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(yamlConfig.TLSCAFile)
+		if err != nil {
+			return poolConfig, fmt.Errorf("failed to read tls_ca_file: %w", err)
+		}
+		poolConfig.RootCAs = x509.NewCertPool()
+		if !poolConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return poolConfig, fmt.Errorf("tls_ca_file contains no PEM certificates")
+		}
+	}
+
+	return poolConfig, nil
+}
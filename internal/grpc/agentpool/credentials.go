@@ -0,0 +1,57 @@
+package agentpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+)
+
+// tokenCredentials attaches a token issued for agents to every call to an
+// agent, so agents without mutual TLS can authenticate the controller.
+// Without an issuer calls carry no token.
+type tokenCredentials struct {
+	mu      sync.Mutex
+	issuer  *pki.AgentTokenIssuer
+	token   string
+	renewAt time.Time
+}
+
+// setIssuer sets the issuer of the tokens
+func (c *tokenCredentials) setIssuer(issuer *pki.AgentTokenIssuer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.issuer = issuer
+	c.token = ""
+}
+
+// GetRequestMetadata returns the authorization header, issuing a new token
+// once half of the current one's lifetime has passed
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.issuer == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if c.token == "" || !now.Before(c.renewAt) {
+		token, expiresAt, err := c.issuer.Issue(pki.ControllerName, middleware.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		c.token = token
+		c.renewAt = now.Add(expiresAt.Sub(now) / 2)
+	}
+
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity returns true so tokens are never sent where they
+// could be sniffed and replayed
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
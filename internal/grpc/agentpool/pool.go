@@ -0,0 +1,419 @@
+// Package agentpool manages the controller's gRPC connections to the pi-agent
+// running on each node
+package agentpool
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// ConnectionState describes the reachability of a node's agent
+type ConnectionState string
+
+const (
+	StateUnknown     ConnectionState = "unknown"
+	StateConnecting  ConnectionState = "connecting"
+	StateReady       ConnectionState = "ready"
+	StateUnreachable ConnectionState = "unreachable"
+)
+
+// NodeSource returns the nodes whose agents the pool should track
+type NodeSource func() ([]models.Node, error)
+
+// StateChangeFunc is called when the connection state of a node's agent changes
+type StateChangeFunc func(nodeID uint, oldState, newState ConnectionState)
+
+// Connection is a snapshot of the pool's view of a single node's agent
+type Connection struct {
+	NodeID              uint            `json:"node_id"`
+	Address             string          `json:"address"`
+	State               ConnectionState `json:"state"`
+	ConsecutiveFailures int             `json:"consecutive_failures"`
+	LastCheck           time.Time       `json:"last_check"`
+	LastHealthy         time.Time       `json:"last_healthy"`
+	LastError           string          `json:"last_error,omitempty"`
+}
+
+// Pool caches one gRPC connection per node and tracks agent health in the background
+type Pool struct {
+	config Config
+	logger logger.Interface
+
+	mu        sync.RWMutex
+	conns     map[uint]*agentConn
+	listeners []StateChangeFunc
+	tokens    *tokenCredentials
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// agentConn holds the connection and health state for a single node
+type agentConn struct {
+	Connection
+	conn   *grpc.ClientConn
+	client pb.PiAgentServiceClient
+}
+
+// New creates a new agent connection pool
+func New(config Config, logger logger.Interface) *Pool {
+	return &Pool{
+		config: applyDefaults(config),
+		logger: logger.WithField("component", "agent-pool"),
+		conns:  make(map[uint]*agentConn),
+		tokens: &tokenCredentials{},
+	}
+}
+
+// SetTokenIssuer makes the pool authenticate to agents with tokens from the
+// issuer. Tokens are only sent over TLS. It applies to calls on existing
+// connections too.
+func (p *Pool) SetTokenIssuer(issuer *pki.AgentTokenIssuer) {
+	if p.config.TLSConfig == nil && p.config.RootCAs == nil {
+		p.logger.Warn("Agent tokens are only sent over TLS; set agent_pool.tls_ca_file or enable pki")
+	}
+	p.tokens.setIssuer(issuer)
+}
+
+// OnStateChange registers a function to be called on agent state transitions
+func (p *Pool) OnStateChange(fn StateChangeFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, fn)
+}
+
+// Client returns a PiAgentService client for the given node, opening a new
+// connection if none is cached or the node's address has changed
+func (p *Pool) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	if node.IPAddress == "" {
+		return nil, fmt.Errorf("node %d has no IP address", node.ID)
+	}
+	address := p.address(node)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.conns[node.ID]; ok && c.Address == address {
+		// Kick an idle or failed connection so the call does not wait for backoff
+		if state := c.conn.GetState(); state == connectivity.Idle || state == connectivity.TransientFailure {
+			c.conn.Connect()
+		}
+		return c.client, nil
+	}
+
+	c, err := p.dialLocked(node.ID, address)
+	if err != nil {
+		return nil, err
+	}
+	return c.client, nil
+}
+
+// State returns the connection state of a node's agent
+func (p *Pool) State(nodeID uint) ConnectionState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if c, ok := p.conns[nodeID]; ok {
+		return c.State
+	}
+	return StateUnknown
+}
+
+// AgentState returns the connection state of a node's agent as a string
+func (p *Pool) AgentState(nodeID uint) string {
+	return string(p.State(nodeID))
+}
+
+// Connection returns a snapshot of the pool's view of a node's agent
+func (p *Pool) Connection(nodeID uint) (Connection, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	c, ok := p.conns[nodeID]
+	if !ok {
+		return Connection{}, false
+	}
+	return c.Connection, true
+}
+
+// Connections returns a snapshot of all tracked agent connections
+func (p *Pool) Connections() []Connection {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	connections := make([]Connection, 0, len(p.conns))
+	for _, c := range p.conns {
+		connections = append(connections, c.Connection)
+	}
+	return connections
+}
+
+// Remove closes and forgets the connection to a node's agent
+func (p *Pool) Remove(nodeID uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(nodeID)
+}
+
+// Start begins background health probing of the agents on nodes returned by source
+func (p *Pool) Start(ctx context.Context, source NodeSource) {
+	ctx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.healthLoop(ctx, source)
+	}()
+
+	p.logger.WithField("interval", p.config.HealthInterval.String()).Info("Agent pool started")
+}
+
+// Stop stops health probing and closes all agent connections
+func (p *Pool) Stop() error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for nodeID, c := range p.conns {
+		if err := c.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.conns, nodeID)
+	}
+
+	p.logger.Info("Agent pool stopped")
+	return firstErr
+}
+
+// healthLoop probes all tracked agents every HealthInterval
+func (p *Pool) healthLoop(ctx context.Context, source NodeSource) {
+	ticker := time.NewTicker(p.config.HealthInterval)
+	defer ticker.Stop()
+
+	p.CheckAll(ctx, source)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckAll(ctx, source)
+		}
+	}
+}
+
+// CheckAll syncs the pool with the nodes returned by source and probes every agent once
+func (p *Pool) CheckAll(ctx context.Context, source NodeSource) {
+	nodes, err := source()
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to load nodes for agent health checks")
+		return
+	}
+
+	p.sync(nodes)
+
+	var wg sync.WaitGroup
+	for i := range nodes {
+		if nodes[i].IPAddress == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(nodeID uint) {
+			defer wg.Done()
+			p.check(ctx, nodeID)
+		}(nodes[i].ID)
+	}
+	wg.Wait()
+}
+
+// sync opens connections for new nodes and drops connections for removed ones
+func (p *Pool) sync(nodes []models.Node) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seen := make(map[uint]bool, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		seen[node.ID] = true
+		if node.IPAddress == "" {
+			continue
+		}
+
+		address := p.address(node)
+		if c, ok := p.conns[node.ID]; ok && c.Address == address {
+			continue
+		}
+		if _, err := p.dialLocked(node.ID, address); err != nil {
+			p.logger.WithError(err).WithField("node_id", node.ID).Warn("Failed to open agent connection")
+		}
+	}
+
+	for nodeID := range p.conns {
+		if !seen[nodeID] {
+			p.removeLocked(nodeID)
+		}
+	}
+}
+
+// check runs a single AgentHealth probe against a node's agent and records the result
+func (p *Pool) check(ctx context.Context, nodeID uint) {
+	p.mu.RLock()
+	c, ok := p.conns[nodeID]
+	p.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, p.config.HealthTimeout)
+	defer cancel()
+
+	resp, err := c.client.AgentHealth(probeCtx, &pb.AgentHealthRequest{})
+	if err == nil && resp.Status != "ok" {
+		err = fmt.Errorf("agent reported status %q", resp.Status)
+	}
+	if ctx.Err() != nil {
+		// Pool is shutting down, the probe result is meaningless
+		return
+	}
+
+	p.record(nodeID, c, err)
+}
+
+// record updates a connection's health state and notifies listeners on transitions
+func (p *Pool) record(nodeID uint, c *agentConn, err error) {
+	p.mu.Lock()
+	if current, ok := p.conns[nodeID]; !ok || current != c {
+		// Connection was replaced or removed while the probe was in flight
+		p.mu.Unlock()
+		return
+	}
+
+	oldState := c.State
+	c.LastCheck = time.Now()
+	if err == nil {
+		c.State = StateReady
+		c.ConsecutiveFailures = 0
+		c.LastHealthy = c.LastCheck
+		c.LastError = ""
+	} else {
+		c.ConsecutiveFailures++
+		c.LastError = err.Error()
+		if c.ConsecutiveFailures >= p.config.FailureThreshold {
+			c.State = StateUnreachable
+			// Force the channel to retry instead of waiting out its backoff
+			c.conn.ResetConnectBackoff()
+		}
+	}
+	newState := c.State
+	listeners := append([]StateChangeFunc(nil), p.listeners...)
+	p.mu.Unlock()
+
+	if oldState == newState {
+		return
+	}
+
+	p.logger.WithFields(map[string]interface{}{
+		"node_id":   nodeID,
+		"old_state": oldState,
+		"new_state": newState,
+		"error":     err,
+	}).Info("Agent connection state changed")
+
+	for _, fn := range listeners {
+		fn(nodeID, oldState, newState)
+	}
+}
+
+// dialLocked replaces any existing connection for a node. Callers must hold p.mu.
+func (p *Pool) dialLocked(nodeID uint, address string) (*agentConn, error) {
+	p.removeLocked(nodeID)
+
+	opts := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(p.config.MaxMessageSize),
+			grpc.MaxCallSendMsgSize(p.config.MaxMessageSize),
+		),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                p.config.KeepAliveTime,
+			Timeout:             p.config.KeepAliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	}
+	if p.config.TLSConfig != nil {
+		tlsConfig := p.config.TLSConfig.Clone()
+		tlsConfig.ServerName = pki.NodeName(nodeID)
+		opts = append(opts,
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+			grpc.WithPerRPCCredentials(p.tokens),
+		)
+	} else if p.config.RootCAs != nil {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: p.config.RootCAs}
+		opts = append(opts,
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+			grpc.WithPerRPCCredentials(p.tokens),
+		)
+	} else if p.config.Insecure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	opts = append(opts, p.config.DialOptions...)
+
+	conn, err := grpc.NewClient(address, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent connection to %s: %w", address, err)
+	}
+	conn.Connect()
+
+	c := &agentConn{
+		Connection: Connection{
+			NodeID:  nodeID,
+			Address: address,
+			State:   StateConnecting,
+		},
+		conn:   conn,
+		client: pb.NewPiAgentServiceClient(conn),
+	}
+	p.conns[nodeID] = c
+
+	p.logger.WithFields(map[string]interface{}{
+		"node_id": nodeID,
+		"address": address,
+	}).Debug("Opened agent connection")
+
+	return c, nil
+}
+
+// removeLocked closes and forgets a node's connection. Callers must hold p.mu.
+func (p *Pool) removeLocked(nodeID uint) {
+	c, ok := p.conns[nodeID]
+	if !ok {
+		return
+	}
+	if err := c.conn.Close(); err != nil {
+		p.logger.WithError(err).WithField("node_id", nodeID).Warn("Failed to close agent connection")
+	}
+	delete(p.conns, nodeID)
+}
+
+// address returns the agent address for a node
+func (p *Pool) address(node *models.Node) string {
+	return fmt.Sprintf("%s:%d", node.IPAddress, p.config.Port)
+}
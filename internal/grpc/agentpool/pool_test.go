@@ -0,0 +1,209 @@
+package agentpool
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// healthAgent is a fake agent whose health can be toggled by tests
+type healthAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu      sync.Mutex
+	healthy bool
+	probes  int
+}
+
+func (a *healthAgent) AgentHealth(ctx context.Context, req *pb.AgentHealthRequest) (*pb.AgentHealthResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.probes++
+	if !a.healthy {
+		return nil, status.Error(codes.Unavailable, "agent down")
+	}
+	return &pb.AgentHealthResponse{Status: "ok", Timestamp: timestamppb.Now(), GpioAvailable: true}, nil
+}
+
+func (a *healthAgent) setHealthy(healthy bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.healthy = healthy
+}
+
+func newTestPool(t *testing.T, agent *healthAgent) *Pool {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(server, agent)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	pool := New(Config{
+		Insecure:         true,
+		HealthInterval:   time.Hour,
+		HealthTimeout:    time.Second,
+		FailureThreshold: 2,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		},
+	}, logger.Default())
+	t.Cleanup(func() { pool.Stop() })
+
+	return pool
+}
+
+func staticNodes(nodes ...models.Node) NodeSource {
+	return func() ([]models.Node, error) {
+		return nodes, nil
+	}
+}
+
+func TestPool_Client(t *testing.T) {
+	agent := &healthAgent{healthy: true}
+	pool := newTestPool(t, agent)
+	node := &models.Node{ID: 1, IPAddress: "10.0.0.1"}
+
+	client, err := pool.Client(context.Background(), node)
+	require.NoError(t, err)
+
+	resp, err := client.AgentHealth(context.Background(), &pb.AgentHealthRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status)
+
+	conn, ok := pool.Connection(1)
+	require.True(t, ok)
+	assert.Equal(t, "10.0.0.1:9091", conn.Address)
+
+	// Cached connection is reused while the address is unchanged
+	_, err = pool.Client(context.Background(), node)
+	require.NoError(t, err)
+	assert.Len(t, pool.Connections(), 1)
+
+	// Address change replaces the connection
+	node.IPAddress = "10.0.0.2"
+	_, err = pool.Client(context.Background(), node)
+	require.NoError(t, err)
+	conn, _ = pool.Connection(1)
+	assert.Equal(t, "10.0.0.2:9091", conn.Address)
+
+	_, err = pool.Client(context.Background(), &models.Node{ID: 2})
+	assert.Error(t, err)
+}
+
+func TestPool_HealthTracking(t *testing.T) {
+	agent := &healthAgent{healthy: true}
+	pool := newTestPool(t, agent)
+	source := staticNodes(models.Node{ID: 1, IPAddress: "10.0.0.1"})
+
+	var mu sync.Mutex
+	var transitions []ConnectionState
+	pool.OnStateChange(func(nodeID uint, oldState, newState ConnectionState) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, newState)
+	})
+
+	assert.Equal(t, StateUnknown, pool.State(1))
+
+	pool.CheckAll(context.Background(), source)
+	assert.Equal(t, StateReady, pool.State(1))
+	assert.Equal(t, "ready", pool.AgentState(1))
+
+	// A single failure stays below the threshold
+	agent.setHealthy(false)
+	pool.CheckAll(context.Background(), source)
+	assert.Equal(t, StateReady, pool.State(1))
+
+	pool.CheckAll(context.Background(), source)
+	assert.Equal(t, StateUnreachable, pool.State(1))
+
+	conn, _ := pool.Connection(1)
+	assert.Equal(t, 2, conn.ConsecutiveFailures)
+	assert.NotEmpty(t, conn.LastError)
+
+	agent.setHealthy(true)
+	pool.CheckAll(context.Background(), source)
+	assert.Equal(t, StateReady, pool.State(1))
+
+	mu.Lock()
+	assert.Equal(t, []ConnectionState{StateReady, StateUnreachable, StateReady}, transitions)
+	mu.Unlock()
+}
+
+func TestPool_SyncRemovesDeletedNodes(t *testing.T) {
+	agent := &healthAgent{healthy: true}
+	pool := newTestPool(t, agent)
+
+	pool.CheckAll(context.Background(), staticNodes(
+		models.Node{ID: 1, IPAddress: "10.0.0.1"},
+		models.Node{ID: 2, IPAddress: "10.0.0.2"},
+	))
+	assert.Len(t, pool.Connections(), 2)
+
+	pool.CheckAll(context.Background(), staticNodes(models.Node{ID: 2, IPAddress: "10.0.0.2"}))
+	assert.Len(t, pool.Connections(), 1)
+	assert.Equal(t, StateUnknown, pool.State(1))
+	assert.Equal(t, StateReady, pool.State(2))
+}
+
+func TestPool_StartProbesInBackground(t *testing.T) {
+	agent := &healthAgent{healthy: true}
+	pool := newTestPool(t, agent)
+
+	pool.Start(context.Background(), staticNodes(models.Node{ID: 1, IPAddress: "10.0.0.1"}))
+
+	assert.Eventually(t, func() bool {
+		return pool.State(1) == StateReady
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, pool.Stop())
+	assert.Empty(t, pool.Connections())
+}
+
+func TestTokenCredentials(t *testing.T) {
+	creds := &tokenCredentials{}
+	assert.True(t, creds.RequireTransportSecurity(), "tokens are only sent over TLS")
+	md, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, md, "calls carry no token without an issuer")
+
+	issuer, err := pki.LoadOrCreateAgentTokenIssuer(filepath.Join(t.TempDir(), "agent-token.key"))
+	require.NoError(t, err)
+	creds.setIssuer(issuer)
+
+	md, err = creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(md["authorization"], "Bearer "))
+	pubPEM, err := issuer.PublicKeyPEM()
+	require.NoError(t, err)
+	verifier, err := pki.NewAgentTokenVerifier(pubPEM)
+	require.NoError(t, err)
+	claims, err := verifier.Verify(strings.TrimPrefix(md["authorization"], "Bearer "))
+	require.NoError(t, err)
+	assert.Equal(t, pki.ControllerName, claims.Subject)
+	assert.Equal(t, middleware.RoleAdmin, claims.Role)
+
+	again, err := creds.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, md, again, "the token is reused until half its lifetime has passed")
+}
@@ -8,10 +8,12 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
 
@@ -34,6 +36,9 @@ type Client struct {
 	
 	// Node information
 	nodeInfo *NodeInfo
+
+	// Enrolled credentials for mutual TLS with the controller
+	credentials *pki.NodeCredentials
 	
 	// Lifecycle management
 	ctx    context.Context
@@ -69,6 +74,10 @@ type Config struct {
 	Insecure bool   `yaml:"insecure"`
 	TLSCert  string `yaml:"tls_cert"`
 	TLSKey   string `yaml:"tls_key"`
+
+	// InsecureSkipCAVerification lets Enroll trust whichever CA the
+	// controller presents when no CA hash is pinned
+	InsecureSkipCAVerification bool `yaml:"insecure_skip_ca_verification"`
 }
 
 // NodeInfo contains information about the current node
@@ -210,10 +219,11 @@ func (c *Client) Connect(ctx context.Context) error {
 	}
 	
 	// Add security options
-	if c.config.Insecure {
+	if c.credentials != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(c.credentials.ClientTLSConfig())))
+	} else if c.config.Insecure {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
-	// TODO: Add TLS credentials when TLS is configured
 	
 	// Establish connection
 	conn, err := grpc.DialContext(ctx, c.getServerAddress(), opts...)
@@ -527,4 +527,16 @@ func parsePort(portStr string) int {
 		}
 		return 9090 // default
 	}
-}
\ No newline at end of file
+}
+func TestEnrollRequiresCAHash(t *testing.T) {
+	client, err := NewClient(Config{ServerAddress: "localhost", ServerPort: 9090}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	nodeInfo := &NodeInfo{Name: "pi-kitchen"}
+	_, err = client.Enroll(context.Background(), "abcdef.0123456789abcdef", "", nodeInfo, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "insecure_skip_ca_verification") {
+		t.Errorf("expected enrolling without a CA hash to be refused, got %v", err)
+	}
+}
@@ -18,6 +18,8 @@ func ConfigFromYAML(yamlConfig config.GRPCClientConfig) (Config, error) {
 		Insecure:        yamlConfig.Insecure,
 		TLSCert:         yamlConfig.TLSCert,
 		TLSKey:          yamlConfig.TLSKey,
+
+		InsecureSkipCAVerification: yamlConfig.InsecureSkipCAVerification,
 	}
 
 	// Parse duration strings
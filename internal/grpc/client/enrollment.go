@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// SetCredentials makes the client connect with mutual TLS using the node's
+// enrolled credentials. It takes effect on the next connection.
+func (c *Client) SetCredentials(creds *pki.NodeCredentials) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.credentials = creds
+}
+
+// Credentials returns the node's enrolled credentials, or nil
+func (c *Client) Credentials() *pki.NodeCredentials {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.credentials
+}
+
+// Enroll exchanges a bootstrap token for a certificate bound to the node,
+// saves the credentials in dir and uses them from then on. The controller
+// is verified against the pinned CA hash, which is required unless the client
+// is configured to skip CA verification.
+func (c *Client) Enroll(ctx context.Context, token, caHash string, nodeInfo *NodeInfo, dir string) (*pb.Node, error) {
+	if nodeInfo == nil {
+		return nil, fmt.Errorf("nodeInfo is required")
+	}
+	if caHash == "" {
+		if !c.config.InsecureSkipCAVerification {
+			return nil, fmt.Errorf("a CA certificate hash is required to enroll, or insecure_skip_ca_verification to trust any controller")
+		}
+		c.logger.Warn("ENROLLING WITHOUT VERIFYING THE CONTROLLER: no CA certificate hash is pinned, so the bootstrap token is sent to whichever server answers and its CA is trusted from now on",
+			"address", c.getServerAddress())
+	}
+
+	key, csr, err := pki.NewCSR()
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Enrolling with controller",
+		"address", c.getServerAddress(),
+		"node_name", nodeInfo.Name)
+
+	// The agent has no CA yet; a separate connection pins the controller's
+	// CA by its hash
+	dialCtx, cancel := context.WithTimeout(ctx, c.config.ConnectionTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, c.getServerAddress(),
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials.NewTLS(pki.PinnedTLSConfig(caHash))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer conn.Close()
+
+	callCtx, cancel := c.createCallContext(ctx)
+	defer cancel()
+	resp, err := pb.NewPiControllerServiceClient(conn).EnrollAgent(callCtx, &pb.EnrollAgentRequest{
+		BootstrapToken: token,
+		Csr:            csr,
+		Name:           nodeInfo.Name,
+		IpAddress:      nodeInfo.IPAddress,
+		MacAddress:     nodeInfo.MACAddress,
+		Architecture:   nodeInfo.Architecture,
+		Model:          nodeInfo.Model,
+		SerialNumber:   nodeInfo.SerialNumber,
+		CpuCores:       nodeInfo.CPUCores,
+		Memory:         nodeInfo.Memory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enroll: %w", err)
+	}
+	if !pki.MatchesHash(resp.CaCertificate, caHash) {
+		return nil, fmt.Errorf("controller returned a CA certificate that does not match %s", caHash)
+	}
+
+	creds, err := pki.SaveNodeCredentials(dir, key, resp.Certificate, resp.CaCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if creds.NodeID() != uint(resp.Node.Id) {
+		return nil, fmt.Errorf("certificate was issued to node %d, not node %d", creds.NodeID(), resp.Node.Id)
+	}
+
+	// Reconnect with the new credentials
+	c.Disconnect()
+	c.SetCredentials(creds)
+	c.SetNodeInfo(nodeInfo)
+
+	c.logger.Info("Enrolled with controller",
+		"node_id", resp.Node.Id,
+		"expires", creds.Leaf().NotAfter)
+	return resp.Node, nil
+}
+
+// RenewCertificate replaces the node's certificate with one for a new key and
+// reconnects, presenting the new certificate.
+func (c *Client) RenewCertificate(ctx context.Context) error {
+	creds := c.Credentials()
+	if creds == nil {
+		return fmt.Errorf("client has no enrolled credentials")
+	}
+
+	if err := c.ensureConnected(ctx); err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+
+	key, csr, err := pki.NewCSR()
+	if err != nil {
+		return err
+	}
+
+	callCtx, cancel := c.createCallContext(ctx)
+	defer cancel()
+	resp, err := c.client.RenewAgentCertificate(callCtx, &pb.RenewAgentCertificateRequest{Csr: csr})
+	if err != nil {
+		return fmt.Errorf("failed to renew certificate: %w", err)
+	}
+	if err := creds.Update(key, resp.Certificate); err != nil {
+		return fmt.Errorf("failed to save renewed certificate: %w", err)
+	}
+
+	// The controller revoked the old certificate, so reconnect with the new one
+	c.Disconnect()
+
+	c.logger.Info("Renewed node certificate",
+		"node_id", creds.NodeID(),
+		"expires", creds.Leaf().NotAfter)
+	return nil
+}
+
+// GetNode retrieves a node, such as the enrolled node itself
+func (c *Client) GetNode(ctx context.Context, nodeID uint32) (*pb.Node, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+
+	callCtx, cancel := c.createCallContext(ctx)
+	defer cancel()
+
+	node, err := c.client.GetNode(callCtx, &pb.GetNodeRequest{Id: nodeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return node, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"runtime"
 	"strings"
 	"time"
@@ -256,6 +257,62 @@ func (c *Client) WriteGPIO(ctx context.Context, deviceID uint32, value int32) (*
 	return resp, nil
 }
 
+// ListGPIODevices lists the GPIO devices configured on a node
+func (c *Client) ListGPIODevices(ctx context.Context, nodeID uint32) ([]*pb.GPIODevice, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+
+	var devices []*pb.GPIODevice
+	for page := int32(1); ; page++ {
+		callCtx, cancel := c.createCallContext(ctx)
+		resp, err := c.client.ListGPIODevices(callCtx, &pb.ListGPIODevicesRequest{
+			NodeId:   &nodeID,
+			Page:     page,
+			PageSize: 100,
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GPIO devices: %w", err)
+		}
+
+		devices = append(devices, resp.GpioDevices...)
+		if len(resp.GpioDevices) == 0 || int32(len(devices)) >= resp.TotalCount {
+			return devices, nil
+		}
+	}
+}
+
+// ReportGPIOAllocations reports which pods hold a node's GPIO pins through
+// its Kubernetes device plugin. Pins missing from allocations are released.
+func (c *Client) ReportGPIOAllocations(ctx context.Context, nodeID uint32, allocations map[int]string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+
+	callCtx, cancel := c.createCallContext(ctx)
+	defer cancel()
+
+	req := &pb.ReportGPIOAllocationsRequest{NodeId: nodeID}
+	for pin, holder := range allocations {
+		req.Allocations = append(req.Allocations, &pb.GPIOAllocation{
+			PinNumber:   int32(pin),
+			AllocatedTo: holder,
+		})
+	}
+
+	resp, err := c.client.ReportGPIOAllocations(callCtx, req)
+	if err != nil {
+		return fmt.Errorf("failed to report GPIO allocations: %w", err)
+	}
+
+	c.logger.Debug("GPIO allocations reported",
+		"node_id", nodeID,
+		"allocations", len(allocations),
+		"updated", resp.Updated)
+	return nil
+}
+
 // heartbeatLoop runs the periodic heartbeat in a separate goroutine
 func (c *Client) heartbeatLoop(ctx context.Context) {
 	c.logger.Debug("Starting heartbeat loop", 
@@ -329,6 +386,7 @@ func CollectNodeInfo(nodeID, nodeName string) (*NodeInfo, error) {
 		Architecture: runtime.GOARCH,
 		OSVersion:    runtime.GOOS,
 		CPUCores:     int32(runtime.NumCPU()),
+		SerialNumber: detectSerialNumber(),
 	}
 	
 	// Try to detect Raspberry Pi model
@@ -358,6 +416,28 @@ func detectRaspberryPiModel() string {
 	return "raspberry-pi"
 }
 
+// detectSerialNumber reads the board serial number from the device tree, or
+// from /proc/cpuinfo on older kernels. It returns "" when neither has one.
+func detectSerialNumber() string {
+	if data, err := os.ReadFile("/sys/firmware/devicetree/base/serial-number"); err == nil {
+		if serial := strings.TrimSpace(strings.Trim(string(data), "\x00")); serial != "" {
+			return serial
+		}
+	}
+
+	data, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if found && strings.TrimSpace(key) == "Serial" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
 // GetSystemInfo returns system information for the GetSystemInfo gRPC call
 func (c *Client) GetSystemInfo(ctx context.Context) (*pb.SystemInfoResponse, error) {
 	if err := c.ensureConnected(ctx); err != nil {
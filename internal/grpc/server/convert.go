@@ -0,0 +1,262 @@
+package server
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// Helper functions for model conversion
+
+func clusterToProto(cluster *models.Cluster) *pb.Cluster {
+	pbCluster := &pb.Cluster{
+		Id:             uint32(cluster.ID),
+		Name:           cluster.Name,
+		Description:    cluster.Description,
+		Status:         clusterStatusToProto(cluster.Status),
+		Version:        cluster.Version,
+		MasterEndpoint: cluster.MasterEndpoint,
+		CreatedAt:      timestamppb.New(cluster.CreatedAt),
+		UpdatedAt:      timestamppb.New(cluster.UpdatedAt),
+	}
+
+	// Convert nodes if loaded
+	if cluster.Nodes != nil {
+		pbCluster.Nodes = make([]*pb.Node, len(cluster.Nodes))
+		for i := range cluster.Nodes {
+			pbCluster.Nodes[i] = nodeToProto(&cluster.Nodes[i])
+		}
+	}
+
+	return pbCluster
+}
+
+func nodeToProto(node *models.Node) *pb.Node {
+	pbNode := &pb.Node{
+		Id:            uint32(node.ID),
+		Name:          node.Name,
+		IpAddress:     node.IPAddress,
+		MacAddress:    node.MACAddress,
+		Status:        nodeStatusToProto(node.Status),
+		Role:          nodeRoleToProto(node.Role),
+		Architecture:  node.Architecture,
+		Model:         node.Model,
+		SerialNumber:  node.SerialNumber,
+		CpuCores:      int32(node.CPUCores),
+		Memory:        node.Memory,
+		KubeVersion:   node.KubeVersion,
+		NodeName:      node.NodeName,
+		OsVersion:     node.OSVersion,
+		KernelVersion: node.KernelVersion,
+		LastSeen:      timestamppb.New(node.LastSeen),
+		CreatedAt:     timestamppb.New(node.CreatedAt),
+		UpdatedAt:     timestamppb.New(node.UpdatedAt),
+	}
+
+	if node.ClusterID != nil {
+		clusterID := uint32(*node.ClusterID)
+		pbNode.ClusterId = &clusterID
+	}
+
+	// Convert GPIO devices if loaded
+	if node.GPIODevices != nil {
+		pbNode.GpioDevices = make([]*pb.GPIODevice, len(node.GPIODevices))
+		for i := range node.GPIODevices {
+			pbNode.GpioDevices[i] = gpioDeviceToProto(&node.GPIODevices[i])
+		}
+	}
+
+	return pbNode
+}
+
+func gpioDeviceToProto(device *models.GPIODevice) *pb.GPIODevice {
+	pbDevice := &pb.GPIODevice{
+		Id:          uint32(device.ID),
+		Name:        device.Name,
+		Description: device.Description,
+		PinNumber:   int32(device.PinNumber),
+		Direction:   gpioDirectionToProto(device.Direction),
+		PullMode:    gpioPullModeToProto(device.PullMode),
+		Value:       int32(device.Value),
+		DeviceType:  gpioDeviceTypeToProto(device.DeviceType),
+		Status:      gpioStatusToProto(device.Status),
+		NodeId:      uint32(device.NodeID),
+		Config:      gpioConfigToProto(device.Config),
+		CreatedAt:   timestamppb.New(device.CreatedAt),
+		UpdatedAt:   timestamppb.New(device.UpdatedAt),
+		AllocatedTo: device.AllocatedTo,
+	}
+	if device.AllocatedAt != nil {
+		pbDevice.AllocatedAt = timestamppb.New(*device.AllocatedAt)
+	}
+	return pbDevice
+}
+
+func readingToProto(reading *models.GPIOReading) *pb.GPIOReading {
+	return &pb.GPIOReading{
+		Id:        uint32(reading.ID),
+		DeviceId:  uint32(reading.DeviceID),
+		Value:     reading.Value,
+		Timestamp: timestamppb.New(reading.Timestamp),
+	}
+}
+
+func readingEventToProto(reading events.GPIOReading) *pb.GPIOReading {
+	return &pb.GPIOReading{
+		Id:        uint32(reading.ReadingID),
+		DeviceId:  uint32(reading.DeviceID),
+		Value:     reading.Value,
+		Timestamp: timestamppb.New(reading.Timestamp),
+	}
+}
+
+func gpioConfigToProto(config models.GPIOConfig) *pb.GPIOConfig {
+	return &pb.GPIOConfig{
+		Frequency:  int32(config.Frequency),
+		DutyCycle:  int32(config.DutyCycle),
+		SpiMode:    int32(config.SPIMode),
+		SpiBits:    int32(config.SPIBits),
+		SpiSpeed:   int32(config.SPISpeed),
+		SpiChannel: int32(config.SPIChannel),
+		I2CAddress: int32(config.I2CAddress),
+		I2CBus:     int32(config.I2CBus),
+		SampleRate: int32(config.SampleRate),
+	}
+}
+
+func gpioConfigFromProto(config *pb.GPIOConfig) models.GPIOConfig {
+	if config == nil {
+		return models.GPIOConfig{}
+	}
+	return models.GPIOConfig{
+		Frequency:  int(config.Frequency),
+		DutyCycle:  int(config.DutyCycle),
+		SPIMode:    int(config.SpiMode),
+		SPIBits:    int(config.SpiBits),
+		SPISpeed:   int(config.SpiSpeed),
+		SPIChannel: int(config.SpiChannel),
+		I2CAddress: int(config.I2CAddress),
+		I2CBus:     int(config.I2CBus),
+		SampleRate: int(config.SampleRate),
+	}
+}
+
+// Enum conversions. Unspecified proto values map to the empty model value so
+// that service validation rejects them.
+
+var clusterStatuses = map[models.ClusterStatus]pb.ClusterStatus{
+	models.ClusterStatusPending:      pb.ClusterStatus_CLUSTER_STATUS_PENDING,
+	models.ClusterStatusProvisioning: pb.ClusterStatus_CLUSTER_STATUS_PROVISIONING,
+	models.ClusterStatusActive:       pb.ClusterStatus_CLUSTER_STATUS_ACTIVE,
+	models.ClusterStatusDegraded:     pb.ClusterStatus_CLUSTER_STATUS_DEGRADED,
+	models.ClusterStatusMaintenance:  pb.ClusterStatus_CLUSTER_STATUS_MAINTENANCE,
+	models.ClusterStatusFailed:       pb.ClusterStatus_CLUSTER_STATUS_FAILED,
+}
+
+var nodeStatuses = map[models.NodeStatus]pb.NodeStatus{
+	models.NodeStatusDiscovered:   pb.NodeStatus_NODE_STATUS_DISCOVERED,
+	models.NodeStatusProvisioning: pb.NodeStatus_NODE_STATUS_PROVISIONING,
+	models.NodeStatusReady:        pb.NodeStatus_NODE_STATUS_READY,
+	models.NodeStatusNotReady:     pb.NodeStatus_NODE_STATUS_NOT_READY,
+	models.NodeStatusMaintenance:  pb.NodeStatus_NODE_STATUS_MAINTENANCE,
+	models.NodeStatusFailed:       pb.NodeStatus_NODE_STATUS_FAILED,
+	models.NodeStatusUnknown:      pb.NodeStatus_NODE_STATUS_UNKNOWN,
+}
+
+var nodeRoles = map[models.NodeRole]pb.NodeRole{
+	models.NodeRoleMaster: pb.NodeRole_NODE_ROLE_MASTER,
+	models.NodeRoleWorker: pb.NodeRole_NODE_ROLE_WORKER,
+}
+
+var gpioDirections = map[models.GPIODirection]pb.GPIODirection{
+	models.GPIODirectionInput:  pb.GPIODirection_GPIO_DIRECTION_INPUT,
+	models.GPIODirectionOutput: pb.GPIODirection_GPIO_DIRECTION_OUTPUT,
+}
+
+var gpioPullModes = map[models.GPIOPullMode]pb.GPIOPullMode{
+	models.GPIOPullNone: pb.GPIOPullMode_GPIO_PULL_MODE_NONE,
+	models.GPIOPullUp:   pb.GPIOPullMode_GPIO_PULL_MODE_UP,
+	models.GPIOPullDown: pb.GPIOPullMode_GPIO_PULL_MODE_DOWN,
+}
+
+var gpioDeviceTypes = map[models.GPIODeviceType]pb.GPIODeviceType{
+	models.GPIODeviceTypeDigital: pb.GPIODeviceType_GPIO_DEVICE_TYPE_DIGITAL,
+	models.GPIODeviceTypeAnalog:  pb.GPIODeviceType_GPIO_DEVICE_TYPE_ANALOG,
+	models.GPIODeviceTypePWM:     pb.GPIODeviceType_GPIO_DEVICE_TYPE_PWM,
+	models.GPIODeviceTypeSPI:     pb.GPIODeviceType_GPIO_DEVICE_TYPE_SPI,
+	models.GPIODeviceTypeI2C:     pb.GPIODeviceType_GPIO_DEVICE_TYPE_I2C,
+}
+
+var gpioStatuses = map[models.GPIOStatus]pb.GPIOStatus{
+	models.GPIOStatusActive:   pb.GPIOStatus_GPIO_STATUS_ACTIVE,
+	models.GPIOStatusInactive: pb.GPIOStatus_GPIO_STATUS_INACTIVE,
+	models.GPIOStatusError:    pb.GPIOStatus_GPIO_STATUS_ERROR,
+}
+
+// reverseLookup returns the model value mapped to a proto enum value
+func reverseLookup[M ~string, P comparable](values map[M]P, value P) M {
+	for model, proto := range values {
+		if proto == value {
+			return model
+		}
+	}
+	return ""
+}
+
+func clusterStatusToProto(status models.ClusterStatus) pb.ClusterStatus {
+	return clusterStatuses[status]
+}
+
+func clusterStatusFromProto(status pb.ClusterStatus) models.ClusterStatus {
+	return reverseLookup(clusterStatuses, status)
+}
+
+func nodeStatusToProto(status models.NodeStatus) pb.NodeStatus {
+	return nodeStatuses[status]
+}
+
+func nodeStatusFromProto(status pb.NodeStatus) models.NodeStatus {
+	return reverseLookup(nodeStatuses, status)
+}
+
+func nodeRoleToProto(role models.NodeRole) pb.NodeRole {
+	return nodeRoles[role]
+}
+
+func nodeRoleFromProto(role pb.NodeRole) models.NodeRole {
+	return reverseLookup(nodeRoles, role)
+}
+
+func gpioDirectionToProto(direction models.GPIODirection) pb.GPIODirection {
+	return gpioDirections[direction]
+}
+
+func gpioDirectionFromProto(direction pb.GPIODirection) models.GPIODirection {
+	return reverseLookup(gpioDirections, direction)
+}
+
+func gpioPullModeToProto(pullMode models.GPIOPullMode) pb.GPIOPullMode {
+	return gpioPullModes[pullMode]
+}
+
+func gpioPullModeFromProto(pullMode pb.GPIOPullMode) models.GPIOPullMode {
+	return reverseLookup(gpioPullModes, pullMode)
+}
+
+func gpioDeviceTypeToProto(deviceType models.GPIODeviceType) pb.GPIODeviceType {
+	return gpioDeviceTypes[deviceType]
+}
+
+func gpioDeviceTypeFromProto(deviceType pb.GPIODeviceType) models.GPIODeviceType {
+	return reverseLookup(gpioDeviceTypes, deviceType)
+}
+
+func gpioStatusToProto(status models.GPIOStatus) pb.GPIOStatus {
+	return gpioStatuses[status]
+}
+
+func gpioStatusFromProto(status pb.GPIOStatus) models.GPIOStatus {
+	return reverseLookup(gpioStatuses, status)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/dsyorkd/pi-controller/internal/services"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// SetEnrollmentService enables agent enrollment and lets enrolled agents
+// authenticate with their certificates
+func (s *PiControllerServer) SetEnrollmentService(enrollment *services.EnrollmentService) {
+	s.enrollmentService = enrollment
+}
+
+// EnrollAgent issues an agent presenting a bootstrap token a certificate for
+// its node. It is the only RPC an agent calls without a certificate.
+func (s *PiControllerServer) EnrollAgent(ctx context.Context, req *pb.EnrollAgentRequest) (*pb.EnrollAgentResponse, error) {
+	if s.enrollmentService == nil {
+		return nil, status.Error(codes.Unimplemented, "Agent enrollment is not enabled")
+	}
+
+	node, certPEM, err := s.enrollmentService.Enroll(services.EnrollRequest{
+		BootstrapToken: req.BootstrapToken,
+		CSR:            req.Csr,
+		Name:           req.Name,
+		IPAddress:      req.IpAddress,
+		MACAddress:     req.MacAddress,
+		Architecture:   req.Architecture,
+		Model:          req.Model,
+		SerialNumber:   req.SerialNumber,
+		CPUCores:       int(req.CpuCores),
+		Memory:         req.Memory,
+	})
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to enroll agent")
+	}
+
+	return &pb.EnrollAgentResponse{
+		Node:          nodeToProto(node),
+		Certificate:   certPEM,
+		CaCertificate: s.enrollmentService.CA().CertificatePEM(),
+	}, nil
+}
+
+// RenewAgentCertificate issues an enrolled agent a new certificate for its
+// node, authenticated by its current one
+func (s *PiControllerServer) RenewAgentCertificate(ctx context.Context, req *pb.RenewAgentCertificateRequest) (*pb.EnrollAgentResponse, error) {
+	nodeID, err := s.peerNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	node, certPEM, err := s.enrollmentService.Renew(nodeID, req.Csr)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to renew agent certificate")
+	}
+
+	return &pb.EnrollAgentResponse{
+		Node:          nodeToProto(node),
+		Certificate:   certPEM,
+		CaCertificate: s.enrollmentService.CA().CertificatePEM(),
+	}, nil
+}
+
+// peerNode returns the node whose certificate the caller presented
+func (s *PiControllerServer) peerNode(ctx context.Context) (uint, error) {
+	if s.enrollmentService == nil {
+		return 0, status.Error(codes.Unauthenticated, "Agent certificates are not enabled")
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "Client certificate required")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return 0, status.Error(codes.Unauthenticated, "Client certificate required")
+	}
+
+	nodeID, err := s.enrollmentService.AuthenticateNode(tlsInfo.State.VerifiedChains[0][0])
+	if err != nil {
+		s.logger.WithError(err).Warn("Rejected client certificate")
+		return 0, status.Error(codes.Unauthenticated, "Invalid client certificate")
+	}
+	return nodeID, nil
+}
+
+// authorizeNode lets an enrolled agent act on its own node. Other callers
+// need a token granting the required role.
+func (s *PiControllerServer) authorizeNode(ctx context.Context, nodeID uint, requiredRole string) (bool, error) {
+	if peerNodeID, err := s.peerNode(ctx); err == nil {
+		if peerNodeID != nodeID {
+			return false, status.Error(codes.PermissionDenied, "Agents may only act on their own node")
+		}
+		return true, nil
+	}
+
+	if _, err := s.authorize(ctx, requiredRole); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// nodeCredentials wraps the server's TLS credentials to keep track of the
+// connections agents make with their node certificates, so revoking a node's
+// certificates can close them
+type nodeCredentials struct {
+	credentials.TransportCredentials
+	enrollment *services.EnrollmentService
+	conns      *nodeConns
+}
+
+func newNodeCredentials(creds credentials.TransportCredentials, enrollment *services.EnrollmentService) *nodeCredentials {
+	return &nodeCredentials{
+		TransportCredentials: creds,
+		enrollment:           enrollment,
+		conns:                &nodeConns{byNode: make(map[uint]map[*nodeConn]struct{})},
+	}
+}
+
+// ServerHandshake records connections made with a valid node certificate
+func (c *nodeCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return conn, authInfo, err
+	}
+	tlsInfo, ok := authInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return conn, authInfo, nil
+	}
+	nodeID, err := c.enrollment.AuthenticateNode(tlsInfo.State.VerifiedChains[0][0])
+	if err != nil {
+		// Every call on the connection is rejected by peerNode
+		return conn, authInfo, nil
+	}
+	return c.conns.add(nodeID, conn), authInfo, nil
+}
+
+func (c *nodeCredentials) Clone() credentials.TransportCredentials {
+	return &nodeCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		enrollment:           c.enrollment,
+		conns:                c.conns,
+	}
+}
+
+// disconnect closes the connections a node's agent made
+func (c *nodeCredentials) disconnect(nodeID uint) {
+	c.conns.closeNode(nodeID)
+}
+
+// nodeConns holds the open connections of each node's agent
+type nodeConns struct {
+	mu     sync.Mutex
+	byNode map[uint]map[*nodeConn]struct{}
+}
+
+// nodeConn is an agent connection that forgets itself once closed
+type nodeConn struct {
+	net.Conn
+	nodeID uint
+	conns  *nodeConns
+}
+
+func (c *nodeConn) Close() error {
+	c.conns.remove(c)
+	return c.Conn.Close()
+}
+
+func (n *nodeConns) add(nodeID uint, conn net.Conn) *nodeConn {
+	tracked := &nodeConn{Conn: conn, nodeID: nodeID, conns: n}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.byNode[nodeID] == nil {
+		n.byNode[nodeID] = make(map[*nodeConn]struct{})
+	}
+	n.byNode[nodeID][tracked] = struct{}{}
+	return tracked
+}
+
+func (n *nodeConns) remove(conn *nodeConn) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.byNode[conn.nodeID], conn)
+	if len(n.byNode[conn.nodeID]) == 0 {
+		delete(n.byNode, conn.nodeID)
+	}
+}
+
+func (n *nodeConns) closeNode(nodeID uint) {
+	n.mu.Lock()
+	conns := n.byNode[nodeID]
+	delete(n.byNode, nodeID)
+	n.mu.Unlock()
+
+	for conn := range conns {
+		conn.Conn.Close()
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+func TestNodeCredentials_RevocationClosesConnections(t *testing.T) {
+	ctx := context.Background()
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	ca, err := pki.LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	nodes := services.NewNodeService(db, logger.Default())
+	enrollment, err := services.NewEnrollmentService(db, nodes, ca, time.Hour, time.Hour, logger.Default())
+	require.NoError(t, err)
+
+	_, token, err := enrollment.CreateBootstrapToken(services.CreateBootstrapTokenRequest{})
+	require.NoError(t, err)
+	key, csr, err := pki.NewCSR()
+	require.NoError(t, err)
+	node, certPEM, err := enrollment.Enroll(services.EnrollRequest{
+		BootstrapToken: token,
+		CSR:            csr,
+		Name:           "pi-1",
+		IPAddress:      "192.168.1.10",
+		MACAddress:     "dc:a6:32:00:00:01",
+		CPUCores:       4,
+		Memory:         1024,
+	})
+	require.NoError(t, err)
+	agentCreds, err := pki.SaveNodeCredentials(t.TempDir(), key, certPEM, ca.CertificatePEM())
+	require.NoError(t, err)
+
+	controller := NewPiControllerServer(db, logger.Default(), nil,
+		services.NewClusterService(db, logger.Default()), nodes, services.NewGPIOService(db, logger.Default()))
+	controller.SetEnrollmentService(enrollment)
+	creds := newNodeCredentials(credentials.NewTLS(enrollment.ServerTLSConfig()), enrollment)
+	enrollment.AddRevocationHandler(creds.disconnect)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterPiControllerServiceServer(server, controller)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(credentials.NewTLS(agentCreds.ClientTLSConfig())),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewPiControllerServiceClient(conn)
+
+	_, err = client.GetNode(ctx, &pb.GetNodeRequest{Id: uint32(node.ID)})
+	require.NoError(t, err)
+	require.Equal(t, connectivity.Ready, conn.GetState())
+
+	_, err = enrollment.RevokeNodeCertificates(node.ID)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return conn.GetState() != connectivity.Ready }, time.Second, 10*time.Millisecond,
+		"the agent's connection is closed")
+
+	_, err = client.GetNode(ctx, &pb.GetNodeRequest{Id: uint32(node.ID)})
+	assert.Error(t, err, "the revoked certificate cannot reconnect")
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"runtime"
 	"strings"
 	"time"
 
@@ -10,10 +11,11 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
-	"gorm.io/gorm"
 
 	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/events"
 	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
 	"github.com/dsyorkd/pi-controller/internal/storage"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
@@ -21,17 +23,27 @@ import (
 // PiControllerServer implements the gRPC PiControllerService
 type PiControllerServer struct {
 	pb.UnimplementedPiControllerServiceServer
-	database    *storage.Database
-	logger      logger.Interface
-	authManager *middleware.AuthManager
+	database       *storage.Database
+	logger         logger.Interface
+	authManager    *middleware.AuthManager
+	clusterService *services.ClusterService
+	nodeService    *services.NodeService
+	gpioService    *services.GPIOService
+	startTime      time.Time
+
+	enrollmentService *services.EnrollmentService
 }
 
 // NewPiControllerServer creates a new gRPC server instance
-func NewPiControllerServer(database *storage.Database, logger logger.Interface, authManager *middleware.AuthManager) *PiControllerServer {
+func NewPiControllerServer(database *storage.Database, logger logger.Interface, authManager *middleware.AuthManager, clusterService *services.ClusterService, nodeService *services.NodeService, gpioService *services.GPIOService) *PiControllerServer {
 	return &PiControllerServer{
-		database:    database,
-		logger:      logger.WithField("component", "grpc-server"),
-		authManager: authManager,
+		database:       database,
+		logger:         logger.WithField("component", "grpc-server"),
+		authManager:    authManager,
+		clusterService: clusterService,
+		nodeService:    nodeService,
+		gpioService:    gpioService,
+		startTime:      time.Now(),
 	}
 }
 
@@ -41,162 +53,546 @@ func (s *PiControllerServer) Health(ctx context.Context, req *pb.HealthRequest)
 		Status:    "ok",
 		Timestamp: timestamppb.Now(),
 		Version:   "dev",
-		Uptime:    "unknown", // TODO: Calculate actual uptime
+		Uptime:    time.Since(s.startTime).String(),
+	}, nil
+}
+
+// GetSystemInfo returns runtime information about the controller process
+func (s *PiControllerServer) GetSystemInfo(ctx context.Context, req *pb.SystemInfoRequest) (*pb.SystemInfoResponse, error) {
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return &pb.SystemInfoResponse{
+		GoVersion:  runtime.Version(),
+		GoOs:       runtime.GOOS,
+		GoArch:     runtime.GOARCH,
+		CpuCount:   int32(runtime.NumCPU()),
+		Goroutines: int32(runtime.NumGoroutine()),
+		Memory: &pb.MemoryInfo{
+			Alloc:       m.Alloc,
+			TotalAlloc:  m.TotalAlloc,
+			Sys:         m.Sys,
+			HeapAlloc:   m.HeapAlloc,
+			HeapSys:     m.HeapSys,
+			HeapInuse:   m.HeapInuse,
+			HeapIdle:    m.HeapIdle,
+			HeapObjects: m.HeapObjects,
+		},
+		Gc: &pb.GCInfo{
+			NumGc:      m.NumGC,
+			PauseTotal: m.PauseTotalNs,
+			LastGc:     timestamppb.New(time.Unix(0, int64(m.LastGC))),
+		},
+		Timestamp: timestamppb.Now(),
+		Uptime:    time.Since(s.startTime).String(),
 	}, nil
 }
 
 // CreateCluster creates a new cluster
 func (s *PiControllerServer) CreateCluster(ctx context.Context, req *pb.CreateClusterRequest) (*pb.Cluster, error) {
-	cluster := models.Cluster{
+	if _, err := s.authorize(ctx, middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.clusterService.Create(services.CreateClusterRequest{
 		Name:           req.Name,
 		Description:    req.Description,
 		Version:        req.Version,
 		MasterEndpoint: req.MasterEndpoint,
-		Status:         models.ClusterStatusPending,
-	}
-
-	result := s.database.DB().Create(&cluster)
-	if result.Error != nil {
-		s.logger.WithError(result.Error).Error("Failed to create cluster")
-		return nil, status.Error(codes.Internal, "Failed to create cluster")
+	})
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to create cluster")
 	}
 
-	return s.clusterToProto(&cluster), nil
+	return clusterToProto(cluster), nil
 }
 
 // GetCluster retrieves a cluster by ID
 func (s *PiControllerServer) GetCluster(ctx context.Context, req *pb.GetClusterRequest) (*pb.Cluster, error) {
-	var cluster models.Cluster
-	result := s.database.DB().Preload("Nodes").First(&cluster, req.Id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, status.Error(codes.NotFound, "Cluster not found")
-		}
-		s.logger.WithError(result.Error).Error("Failed to get cluster")
-		return nil, status.Error(codes.Internal, "Failed to retrieve cluster")
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.clusterService.GetByID(uint(req.Id))
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve cluster")
+	}
+
+	nodes, err := s.clusterService.GetNodes(cluster.ID)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve cluster nodes")
 	}
+	cluster.Nodes = nodes
 
-	return s.clusterToProto(&cluster), nil
+	return clusterToProto(cluster), nil
 }
 
 // ListClusters retrieves all clusters
 func (s *PiControllerServer) ListClusters(ctx context.Context, req *pb.ListClustersRequest) (*pb.ListClustersResponse, error) {
-	var clusters []models.Cluster
-	
-	query := s.database.DB().Preload("Nodes")
-	
-	// Apply pagination if specified
-	if req.PageSize > 0 {
-		offset := 0
-		if req.Page > 0 {
-			offset = int(req.Page-1) * int(req.PageSize)
-		}
-		query = query.Offset(offset).Limit(int(req.PageSize))
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
 	}
 
-	result := query.Find(&clusters)
-	if result.Error != nil {
-		s.logger.WithError(result.Error).Error("Failed to list clusters")
-		return nil, status.Error(codes.Internal, "Failed to retrieve clusters")
+	limit, offset := pagination(req.Page, req.PageSize)
+	clusters, total, err := s.clusterService.List(services.ClusterListOptions{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve clusters")
 	}
 
-	// Convert to protobuf
 	pbClusters := make([]*pb.Cluster, len(clusters))
-	for i, cluster := range clusters {
-		pbClusters[i] = s.clusterToProto(&cluster)
+	for i := range clusters {
+		pbClusters[i] = clusterToProto(&clusters[i])
 	}
 
-	// Get total count for pagination
-	var totalCount int64
-	s.database.DB().Model(&models.Cluster{}).Count(&totalCount)
-
 	return &pb.ListClustersResponse{
 		Clusters:   pbClusters,
-		TotalCount: int32(totalCount),
+		TotalCount: int32(total),
 	}, nil
 }
 
+// UpdateCluster updates a cluster
+func (s *PiControllerServer) UpdateCluster(ctx context.Context, req *pb.UpdateClusterRequest) (*pb.Cluster, error) {
+	if _, err := s.authorize(ctx, middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	update := services.UpdateClusterRequest{
+		Name:           req.Name,
+		Description:    req.Description,
+		Version:        req.Version,
+		MasterEndpoint: req.MasterEndpoint,
+	}
+	if req.Status != nil {
+		clusterStatus := clusterStatusFromProto(*req.Status)
+		if clusterStatus == "" {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cluster status")
+		}
+		update.Status = &clusterStatus
+	}
+
+	cluster, err := s.clusterService.Update(uint(req.Id), update)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to update cluster")
+	}
+
+	return clusterToProto(cluster), nil
+}
+
+// DeleteCluster deletes a cluster
+func (s *PiControllerServer) DeleteCluster(ctx context.Context, req *pb.DeleteClusterRequest) (*pb.DeleteClusterResponse, error) {
+	claims, err := s.authorize(ctx, middleware.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.clusterService.Delete(uint(req.Id)); err != nil {
+		return nil, s.serviceError(err, "Failed to delete cluster")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"event_type": "cluster_delete",
+		"user_id":    claims.UserID,
+		"cluster_id": req.Id,
+	}).Info("Cluster deleted")
+
+	return &pb.DeleteClusterResponse{Success: true}, nil
+}
+
 // CreateNode creates a new node
 func (s *PiControllerServer) CreateNode(ctx context.Context, req *pb.CreateNodeRequest) (*pb.Node, error) {
-	node := models.Node{
+	if _, err := s.authorize(ctx, middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	create := services.CreateNodeRequest{
 		Name:         req.Name,
 		IPAddress:    req.IpAddress,
 		MACAddress:   req.MacAddress,
+		Role:         nodeRoleFromProto(req.Role),
 		Architecture: req.Architecture,
 		Model:        req.Model,
 		SerialNumber: req.SerialNumber,
 		CPUCores:     int(req.CpuCores),
 		Memory:       req.Memory,
-		Status:       models.NodeStatusDiscovered,
-		Role:         models.NodeRoleWorker,
 	}
-
+	if req.Role == pb.NodeRole_NODE_ROLE_UNSPECIFIED {
+		create.Role = models.NodeRoleWorker
+	}
 	if req.ClusterId != nil {
 		clusterID := uint(*req.ClusterId)
-		node.ClusterID = &clusterID
+		create.ClusterID = &clusterID
 	}
 
-	result := s.database.DB().Create(&node)
-	if result.Error != nil {
-		s.logger.WithError(result.Error).Error("Failed to create node")
-		return nil, status.Error(codes.Internal, "Failed to create node")
+	node, err := s.nodeService.Create(create)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to create node")
 	}
 
-	return s.nodeToProto(&node), nil
+	return nodeToProto(node), nil
 }
 
-// GetNode retrieves a node by ID
+// GetNode retrieves a node by ID. Enrolled agents may get their own node.
 func (s *PiControllerServer) GetNode(ctx context.Context, req *pb.GetNodeRequest) (*pb.Node, error) {
-	var node models.Node
-	result := s.database.DB().First(&node, req.Id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, status.Error(codes.NotFound, "Node not found")
+	if _, err := s.authorizeNode(ctx, uint(req.Id), middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	node, err := s.nodeService.GetByID(uint(req.Id), true)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve node")
+	}
+
+	return nodeToProto(node), nil
+}
+
+// ListNodes retrieves nodes with optional cluster and status filters
+func (s *PiControllerServer) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (*pb.ListNodesResponse, error) {
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	limit, offset := pagination(req.Page, req.PageSize)
+	opts := services.NodeListOptions{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if req.ClusterId != nil {
+		clusterID := uint(*req.ClusterId)
+		opts.ClusterID = &clusterID
+	}
+	if req.Status != nil {
+		nodeStatus := nodeStatusFromProto(*req.Status)
+		if nodeStatus == "" {
+			return nil, status.Error(codes.InvalidArgument, "Invalid node status")
 		}
-		s.logger.WithError(result.Error).Error("Failed to get node")
-		return nil, status.Error(codes.Internal, "Failed to retrieve node")
+		opts.Status = &nodeStatus
+	}
+
+	nodes, total, err := s.nodeService.List(opts)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve nodes")
+	}
+
+	pbNodes := make([]*pb.Node, len(nodes))
+	for i := range nodes {
+		pbNodes[i] = nodeToProto(&nodes[i])
 	}
 
-	return s.nodeToProto(&node), nil
+	return &pb.ListNodesResponse{
+		Nodes:      pbNodes,
+		TotalCount: int32(total),
+	}, nil
 }
 
-// ReadGPIO reads the current value from a GPIO device
-func (s *PiControllerServer) ReadGPIO(ctx context.Context, req *pb.ReadGPIORequest) (*pb.ReadGPIOResponse, error) {
-	// Validate authentication - GPIO read operations require at least viewer role
-	claims, err := s.validateAuthentication(ctx)
+// UpdateNode updates a node. Enrolled agents may report their own node's
+// status and system information, but not change its role or cluster.
+func (s *PiControllerServer) UpdateNode(ctx context.Context, req *pb.UpdateNodeRequest) (*pb.Node, error) {
+	isAgent, err := s.authorizeNode(ctx, uint(req.Id), middleware.RoleOperator)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Require at least viewer role for GPIO read operations
-	if err := s.requireRole(claims, middleware.RoleViewer); err != nil {
+	if isAgent && (req.Role != nil || req.ClusterId != nil || req.Name != nil) {
+		return nil, status.Error(codes.PermissionDenied, "Agents may not change their node's name, role or cluster")
+	}
+
+	update := services.UpdateNodeRequest{
+		Name:          req.Name,
+		IPAddress:     req.IpAddress,
+		MACAddress:    req.MacAddress,
+		Architecture:  req.Architecture,
+		Model:         req.Model,
+		SerialNumber:  req.SerialNumber,
+		Memory:        req.Memory,
+		OSVersion:     req.OsVersion,
+		KernelVersion: req.KernelVersion,
+		KubeVersion:   req.KubeVersion,
+		NodeName:      req.NodeName,
+	}
+	if req.Status != nil {
+		nodeStatus := nodeStatusFromProto(*req.Status)
+		update.Status = &nodeStatus
+	}
+	if req.Role != nil {
+		nodeRole := nodeRoleFromProto(*req.Role)
+		update.Role = &nodeRole
+	}
+	if req.CpuCores != nil {
+		cpuCores := int(*req.CpuCores)
+		update.CPUCores = &cpuCores
+	}
+	if req.ClusterId != nil {
+		clusterID := uint(*req.ClusterId)
+		update.ClusterID = &clusterID
+	}
+
+	node, err := s.nodeService.Update(uint(req.Id), update)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to update node")
+	}
+
+	return nodeToProto(node), nil
+}
+
+// DeleteNode deletes a node
+func (s *PiControllerServer) DeleteNode(ctx context.Context, req *pb.DeleteNodeRequest) (*pb.DeleteNodeResponse, error) {
+	claims, err := s.authorize(ctx, middleware.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.nodeService.Delete(uint(req.Id)); err != nil {
+		return nil, s.serviceError(err, "Failed to delete node")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"event_type": "node_delete",
+		"user_id":    claims.UserID,
+		"node_id":    req.Id,
+	}).Info("Node deleted")
+
+	return &pb.DeleteNodeResponse{Success: true}, nil
+}
+
+// ProvisionNode assigns a node to a cluster and starts provisioning
+func (s *PiControllerServer) ProvisionNode(ctx context.Context, req *pb.ProvisionNodeRequest) (*pb.ProvisionNodeResponse, error) {
+	claims, err := s.authorize(ctx, middleware.RoleOperator)
+	if err != nil {
 		return nil, err
 	}
 
-	var device models.GPIODevice
-	result := s.database.DB().First(&device, req.Id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, status.Error(codes.NotFound, "GPIO device not found")
+	if req.ClusterId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cluster_id is required")
+	}
+
+	if err := s.nodeService.Provision(uint(req.Id), uint(req.ClusterId)); err != nil {
+		return nil, s.serviceError(err, "Failed to provision node")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"event_type": "node_provision",
+		"user_id":    claims.UserID,
+		"node_id":    req.Id,
+		"cluster_id": req.ClusterId,
+	}).Info("Node provisioning started")
+
+	return &pb.ProvisionNodeResponse{
+		Success: true,
+		Message: "Node provisioning started",
+	}, nil
+}
+
+// DeprovisionNode removes a node from its cluster
+func (s *PiControllerServer) DeprovisionNode(ctx context.Context, req *pb.DeprovisionNodeRequest) (*pb.DeprovisionNodeResponse, error) {
+	claims, err := s.authorize(ctx, middleware.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := services.DeprovisionOptions{
+		Force:        req.Force,
+		DrainTimeout: time.Duration(req.DrainTimeoutSeconds) * time.Second,
+	}
+	if err := s.nodeService.Deprovision(ctx, uint(req.Id), opts); err != nil {
+		return nil, s.serviceError(err, "Failed to deprovision node")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"event_type": "node_deprovision",
+		"user_id":    claims.UserID,
+		"node_id":    req.Id,
+		"force":      req.Force,
+	}).Info("Node deprovisioned")
+
+	return &pb.DeprovisionNodeResponse{
+		Success: true,
+		Message: "Node deprovisioned",
+	}, nil
+}
+
+// CreateGPIODevice creates a new GPIO device
+func (s *PiControllerServer) CreateGPIODevice(ctx context.Context, req *pb.CreateGPIODeviceRequest) (*pb.GPIODevice, error) {
+	if _, err := s.authorize(ctx, middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	device, err := s.gpioService.Create(services.CreateGPIODeviceRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		NodeID:      uint(req.NodeId),
+		PinNumber:   int(req.PinNumber),
+		Direction:   gpioDirectionFromProto(req.Direction),
+		PullMode:    gpioPullModeFromProto(req.PullMode),
+		DeviceType:  gpioDeviceTypeFromProto(req.DeviceType),
+		Config:      gpioConfigFromProto(req.Config),
+	})
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to create GPIO device")
+	}
+
+	return gpioDeviceToProto(device), nil
+}
+
+// GetGPIODevice retrieves a GPIO device by ID
+func (s *PiControllerServer) GetGPIODevice(ctx context.Context, req *pb.GetGPIODeviceRequest) (*pb.GPIODevice, error) {
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	device, err := s.gpioService.GetByID(uint(req.Id))
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve GPIO device")
+	}
+
+	return gpioDeviceToProto(device), nil
+}
+
+// ListGPIODevices retrieves GPIO devices with optional node, type and status filters
+func (s *PiControllerServer) ListGPIODevices(ctx context.Context, req *pb.ListGPIODevicesRequest) (*pb.ListGPIODevicesResponse, error) {
+	if _, err := s.authorize(ctx, middleware.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	limit, offset := pagination(req.Page, req.PageSize)
+	opts := services.GPIOListOptions{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if req.NodeId != nil {
+		nodeID := uint(*req.NodeId)
+		opts.NodeID = &nodeID
+	}
+	if req.DeviceType != nil {
+		deviceType := gpioDeviceTypeFromProto(*req.DeviceType)
+		if deviceType == "" {
+			return nil, status.Error(codes.InvalidArgument, "Invalid GPIO device type")
+		}
+		opts.DeviceType = &deviceType
+	}
+	if req.Status != nil {
+		gpioStatus := gpioStatusFromProto(*req.Status)
+		if gpioStatus == "" {
+			return nil, status.Error(codes.InvalidArgument, "Invalid GPIO status")
 		}
-		s.logger.WithError(result.Error).Error("Failed to get GPIO device")
-		return nil, status.Error(codes.Internal, "Failed to retrieve GPIO device")
+		opts.Status = &gpioStatus
 	}
 
-	if !device.IsActive() {
-		return nil, status.Error(codes.FailedPrecondition, "GPIO device is not active")
+	devices, total, err := s.gpioService.List(opts)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve GPIO devices")
 	}
 
-	// TODO: Implement actual GPIO read operation
-	// For now, return the stored value
-	
-	// Record the reading
-	reading := models.GPIOReading{
-		DeviceID:  device.ID,
-		Value:     float64(device.Value),
-		Timestamp: time.Now(),
+	pbDevices := make([]*pb.GPIODevice, len(devices))
+	for i := range devices {
+		pbDevices[i] = gpioDeviceToProto(&devices[i])
+	}
+
+	return &pb.ListGPIODevicesResponse{
+		GpioDevices: pbDevices,
+		TotalCount:  int32(total),
+	}, nil
+}
+
+// UpdateGPIODevice updates a GPIO device
+func (s *PiControllerServer) UpdateGPIODevice(ctx context.Context, req *pb.UpdateGPIODeviceRequest) (*pb.GPIODevice, error) {
+	if _, err := s.authorize(ctx, middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	update := services.UpdateGPIODeviceRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if req.Direction != nil {
+		direction := gpioDirectionFromProto(*req.Direction)
+		update.Direction = &direction
+	}
+	if req.PullMode != nil {
+		pullMode := gpioPullModeFromProto(*req.PullMode)
+		update.PullMode = &pullMode
+	}
+	if req.DeviceType != nil {
+		deviceType := gpioDeviceTypeFromProto(*req.DeviceType)
+		update.DeviceType = &deviceType
+	}
+	if req.Status != nil {
+		gpioStatus := gpioStatusFromProto(*req.Status)
+		update.Status = &gpioStatus
+	}
+	if req.Config != nil {
+		config := gpioConfigFromProto(req.Config)
+		update.Config = &config
+	}
+
+	device, err := s.gpioService.Update(uint(req.Id), update)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to update GPIO device")
+	}
+
+	return gpioDeviceToProto(device), nil
+}
+
+// DeleteGPIODevice deletes a GPIO device and its readings
+func (s *PiControllerServer) DeleteGPIODevice(ctx context.Context, req *pb.DeleteGPIODeviceRequest) (*pb.DeleteGPIODeviceResponse, error) {
+	claims, err := s.authorize(ctx, middleware.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.gpioService.Delete(uint(req.Id)); err != nil {
+		return nil, s.serviceError(err, "Failed to delete GPIO device")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"event_type": "gpio_delete",
+		"user_id":    claims.UserID,
+		"device_id":  req.Id,
+	}).Info("GPIO device deleted")
+
+	return &pb.DeleteGPIODeviceResponse{Success: true}, nil
+}
+
+// ReportGPIOAllocations records the pods holding a node's GPIO devices through
+// the Kubernetes device plugin of its agent
+func (s *PiControllerServer) ReportGPIOAllocations(ctx context.Context, req *pb.ReportGPIOAllocationsRequest) (*pb.ReportGPIOAllocationsResponse, error) {
+	if _, err := s.authorizeNode(ctx, uint(req.NodeId), middleware.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	allocations := make(map[int]string, len(req.Allocations))
+	for _, allocation := range req.Allocations {
+		if allocation.AllocatedTo == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Allocation of pin %d has no holder", allocation.PinNumber)
+		}
+		allocations[int(allocation.PinNumber)] = allocation.AllocatedTo
+	}
+
+	updated, err := s.gpioService.RecordAllocations(uint(req.NodeId), allocations)
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to record GPIO allocations")
+	}
+
+	return &pb.ReportGPIOAllocationsResponse{Updated: int32(updated)}, nil
+}
+
+// ReadGPIO reads the current value from a GPIO device
+func (s *PiControllerServer) ReadGPIO(ctx context.Context, req *pb.ReadGPIORequest) (*pb.ReadGPIOResponse, error) {
+	// GPIO read operations require at least viewer role
+	claims, err := s.authorize(ctx, middleware.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.gpioService.Read(uint(req.Id))
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to read GPIO device")
 	}
-	s.database.DB().Create(&reading)
 
 	// Audit log the GPIO read operation
 	s.logger.WithFields(map[string]interface{}{
@@ -211,58 +607,26 @@ func (s *PiControllerServer) ReadGPIO(ctx context.Context, req *pb.ReadGPIOReque
 		DeviceId:  uint32(device.ID),
 		Pin:       int32(device.PinNumber),
 		Value:     float64(device.Value),
-		Timestamp: timestamppb.New(reading.Timestamp),
+		Timestamp: timestamppb.New(device.UpdatedAt),
 	}, nil
 }
 
 // WriteGPIO writes a value to a GPIO device
 func (s *PiControllerServer) WriteGPIO(ctx context.Context, req *pb.WriteGPIORequest) (*pb.WriteGPIOResponse, error) {
-	// Validate authentication - GPIO write operations require at least operator role
-	claims, err := s.validateAuthentication(ctx)
+	// GPIO write operations require at least operator role (more privileged than read)
+	claims, err := s.authorize(ctx, middleware.RoleOperator)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Require at least operator role for GPIO write operations (more privileged than read)
-	if err := s.requireRole(claims, middleware.RoleOperator); err != nil {
-		return nil, err
-	}
 
-	var device models.GPIODevice
-	result := s.database.DB().First(&device, req.Id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, status.Error(codes.NotFound, "GPIO device not found")
-		}
-		s.logger.WithError(result.Error).Error("Failed to get GPIO device")
-		return nil, status.Error(codes.Internal, "Failed to retrieve GPIO device")
-	}
-
-	if !device.IsActive() {
-		return nil, status.Error(codes.FailedPrecondition, "GPIO device is not active")
+	if err := s.gpioService.Write(uint(req.Id), int(req.Value)); err != nil {
+		return nil, s.serviceError(err, "Failed to write GPIO device")
 	}
 
-	if !device.IsOutput() {
-		return nil, status.Error(codes.FailedPrecondition, "GPIO device is not configured for output")
-	}
-
-	// TODO: Implement actual GPIO write operation
-	
-	// Update the device value
-	device.SetValue(int(req.Value))
-	result = s.database.DB().Save(&device)
-	if result.Error != nil {
-		s.logger.WithError(result.Error).Error("Failed to update GPIO device value")
-		return nil, status.Error(codes.Internal, "Failed to update GPIO device")
-	}
-
-	// Record the write operation
-	reading := models.GPIOReading{
-		DeviceID:  device.ID,
-		Value:     float64(req.Value),
-		Timestamp: time.Now(),
+	device, err := s.gpioService.GetByID(uint(req.Id))
+	if err != nil {
+		return nil, s.serviceError(err, "Failed to retrieve GPIO device")
 	}
-	s.database.DB().Create(&reading)
 
 	// Audit log the GPIO write operation
 	s.logger.WithFields(map[string]interface{}{
@@ -270,48 +634,226 @@ func (s *PiControllerServer) WriteGPIO(ctx context.Context, req *pb.WriteGPIOReq
 		"user_id":    claims.UserID,
 		"device_id":  device.ID,
 		"pin":        device.PinNumber,
-		"value":      req.Value,
+		"value":      device.Value,
 	}).Info("GPIO write operation performed")
 
 	return &pb.WriteGPIOResponse{
 		DeviceId:  uint32(device.ID),
 		Pin:       int32(device.PinNumber),
-		Value:     req.Value,
-		Timestamp: timestamppb.New(reading.Timestamp),
+		Value:     int32(device.Value),
+		Timestamp: timestamppb.New(device.UpdatedAt),
 	}, nil
 }
 
-// Helper functions for model conversion
+const (
+	// replayBatchSize bounds how many stored readings are loaded at once when
+	// replaying history to a stream
+	replayBatchSize = 500
+
+	// maxStreamSampleRate caps the polling rate a stream may request
+	maxStreamSampleRate = 10
+)
+
+// StreamGPIOReadings streams readings for a device or every device on a node.
+// Stored readings since the requested time are replayed first, then live
+// readings follow. A positive sample rate also polls the devices through their
+// agent so input pins produce readings while the stream is open. Polled
+// readings are sent to this stream only; they are not stored and have no ID.
+func (s *PiControllerServer) StreamGPIOReadings(req *pb.StreamGPIOReadingsRequest, stream pb.PiControllerService_StreamGPIOReadingsServer) error {
+	ctx := stream.Context()
+	claims, err := s.authorize(ctx, middleware.RoleViewer)
+	if err != nil {
+		return err
+	}
 
-func (s *PiControllerServer) clusterToProto(cluster *models.Cluster) *pb.Cluster {
-	pbCluster := &pb.Cluster{
-		Id:             uint32(cluster.ID),
-		Name:           cluster.Name,
-		Description:    cluster.Description,
-		Version:        cluster.Version,
-		MasterEndpoint: cluster.MasterEndpoint,
+	var filter services.ReadingStreamFilter
+	switch {
+	case req.DeviceId != 0 && req.NodeId != nil:
+		return status.Error(codes.InvalidArgument, "Specify either device_id or node_id, not both")
+	case req.DeviceId != 0:
+		deviceID := uint(req.DeviceId)
+		if _, err := s.gpioService.GetByID(deviceID); err != nil {
+			return s.serviceError(err, "Failed to retrieve GPIO device")
+		}
+		filter.DeviceID = &deviceID
+	case req.NodeId != nil:
+		nodeID := uint(*req.NodeId)
+		if _, err := s.nodeService.GetByID(nodeID, false); err != nil {
+			return s.serviceError(err, "Failed to retrieve node")
+		}
+		filter.NodeID = &nodeID
+	default:
+		return status.Error(codes.InvalidArgument, "device_id or node_id is required")
+	}
+	if req.SampleRate < 0 || req.SampleRate > maxStreamSampleRate {
+		return status.Errorf(codes.InvalidArgument, "sample_rate must be between 0 and %d", maxStreamSampleRate)
+	}
+
+	// Subscribe before replaying so nothing recorded during the replay is missed
+	sub := s.gpioService.SubscribeReadings(filter, 0)
+	defer sub.Close()
+
+	log := s.logger.WithFields(map[string]interface{}{
+		"user_id":   claims.UserID,
+		"device_id": req.DeviceId,
+		"node_id":   req.GetNodeId(),
+	})
+	log.Info("GPIO reading stream opened")
+	defer log.Info("GPIO reading stream closed")
+
+	var lastID uint
+	if req.Since != nil {
+		for {
+			readings, err := s.gpioService.ReadingsSince(filter, req.Since.AsTime(), lastID, replayBatchSize)
+			if err != nil {
+				return s.serviceError(err, "Failed to replay GPIO readings")
+			}
+			for i := range readings {
+				if err := stream.Send(readingToProto(&readings[i])); err != nil {
+					return err
+				}
+				lastID = readings[i].ID
+			}
+			if len(readings) < replayBatchSize {
+				break
+			}
+		}
 	}
 
-	// Convert nodes if loaded
-	if cluster.Nodes != nil {
-		pbCluster.Nodes = make([]*pb.Node, len(cluster.Nodes))
-		for i, node := range cluster.Nodes {
-			pbCluster.Nodes[i] = s.nodeToProto(&node)
+	var polled <-chan *pb.GPIOReading
+	if req.SampleRate > 0 {
+		samples := make(chan *pb.GPIOReading, maxStreamSampleRate)
+		go s.pollReadings(ctx, filter, time.Second/time.Duration(req.SampleRate), samples)
+		polled = samples
+	}
+
+	var reportedDrops uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case reading := <-polled:
+			if err := stream.Send(reading); err != nil {
+				return err
+			}
+		case event, ok := <-sub.C():
+			if !ok {
+				return nil
+			}
+			reading := event.Payload.(events.GPIOReading)
+			// Readings recorded while replaying were already sent from history
+			if reading.ReadingID <= lastID {
+				continue
+			}
+			if err := stream.Send(readingEventToProto(reading)); err != nil {
+				return err
+			}
+			if dropped := sub.Dropped(); dropped > reportedDrops {
+				log.WithField("dropped", dropped-reportedDrops).Warn("GPIO reading stream consumer is falling behind")
+				reportedDrops = dropped
+			}
 		}
 	}
+}
+
+// pollReadings samples every active device matched by the filter through its
+// agent at each interval and sends the readings to out until ctx is done. A
+// slow stream holds the poller back rather than queueing samples.
+func (s *PiControllerServer) pollReadings(ctx context.Context, filter services.ReadingStreamFilter, interval time.Duration, out chan<- *pb.GPIOReading) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 
-	return pbCluster
+		for _, id := range s.pollDevices(filter) {
+			reading, err := s.gpioService.Sample(ctx, id)
+			if err != nil {
+				s.logger.WithFields(map[string]interface{}{
+					"device_id": id,
+					"error":     err,
+				}).Debug("Failed to poll GPIO device")
+				continue
+			}
+			select {
+			case out <- readingToProto(reading):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
 }
 
-func (s *PiControllerServer) nodeToProto(node *models.Node) *pb.Node {
-	pbNode := &pb.Node{
-		Id:         uint32(node.ID),
-		Name:       node.Name,
-		IpAddress:  node.IPAddress,
-		MacAddress: node.MACAddress,
+// pollDevices returns the IDs of the active devices matched by the filter
+func (s *PiControllerServer) pollDevices(filter services.ReadingStreamFilter) []uint {
+	if filter.DeviceID != nil {
+		return []uint{*filter.DeviceID}
 	}
 
-	return pbNode
+	active := models.GPIOStatusActive
+	devices, _, err := s.gpioService.List(services.GPIOListOptions{NodeID: filter.NodeID, Status: &active})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to list GPIO devices for polling")
+		return nil
+	}
+	ids := make([]uint, len(devices))
+	for i := range devices {
+		ids[i] = devices[i].ID
+	}
+	return ids
+}
+
+// pagination converts a 1-based page and page size into a limit and offset
+func pagination(page, pageSize int32) (int, int) {
+	if pageSize <= 0 {
+		return 0, 0
+	}
+	offset := 0
+	if page > 1 {
+		offset = int(page-1) * int(pageSize)
+	}
+	return int(pageSize), offset
+}
+
+// serviceError maps service layer errors to gRPC status errors
+func (s *PiControllerServer) serviceError(err error, message string) error {
+	switch {
+	case services.IsNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case services.IsAlreadyExists(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case err == services.ErrHasAssociatedResources, services.IsConflict(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case services.IsValidationFailed(err), services.IsInvalidInput(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case services.IsUnauthorized(err):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case services.IsForbidden(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case services.IsAgentUnreachable(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case services.IsGPIOError(err):
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	s.logger.WithError(err).Error(message)
+	return status.Error(codes.Internal, message)
+}
+
+// authorize validates the caller's token and checks it grants the required role
+func (s *PiControllerServer) authorize(ctx context.Context, requiredRole string) (*middleware.JWTClaims, error) {
+	claims, err := s.validateAuthentication(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireRole(claims, requiredRole); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
 // validateAuthentication validates the authentication token from gRPC metadata
@@ -383,4 +925,4 @@ func (s *PiControllerServer) requireRole(claims *middleware.JWTClaims, requiredR
 	}
 
 	return status.Error(codes.PermissionDenied, "Insufficient permissions")
-}
\ No newline at end of file
+}
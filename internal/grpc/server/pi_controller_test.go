@@ -0,0 +1,484 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+type testController struct {
+	client      pb.PiControllerServiceClient
+	authManager *middleware.AuthManager
+	db          *storage.Database
+	gpio        *services.GPIOService
+}
+
+// fakeAgent is an in-process PiAgentService that keeps pin values in memory
+type fakeAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu         sync.Mutex
+	configured map[int32]*pb.ConfigureGPIOPinRequest
+	values     map[int32]int32
+}
+
+func newFakeAgent(values map[int32]int32) *fakeAgent {
+	return &fakeAgent{
+		configured: make(map[int32]*pb.ConfigureGPIOPinRequest),
+		values:     values,
+	}
+}
+
+func (a *fakeAgent) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPIOPinRequest) (*pb.ConfigureGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Like the agent, applying a changed configuration drives outputs low
+	if req.Direction == pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT && !proto.Equal(a.configured[req.Pin], req) {
+		a.values[req.Pin] = 0
+	}
+	a.configured[req.Pin] = req
+	return &pb.ConfigureGPIOPinResponse{Success: true}, nil
+}
+
+func (a *fakeAgent) ReadGPIOPin(ctx context.Context, req *pb.ReadGPIOPinRequest) (*pb.ReadGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &pb.ReadGPIOPinResponse{Pin: req.Pin, Value: a.values[req.Pin]}, nil
+}
+
+func (a *fakeAgent) WriteGPIOPin(ctx context.Context, req *pb.WriteGPIOPinRequest) (*pb.WriteGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[req.Pin] = req.Value
+	return &pb.WriteGPIOPinResponse{Pin: req.Pin, Value: req.Value}, nil
+}
+
+// agentConnector routes every node to one in-process agent
+type agentConnector struct {
+	conn *grpc.ClientConn
+}
+
+func (c *agentConnector) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	return pb.NewPiAgentServiceClient(c.conn), nil
+}
+
+// bufconnDial serves registered services on an in-memory listener and returns a client connection
+func bufconnDial(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func setupTestController(t *testing.T) *testController {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	authManager, err := middleware.NewAuthManager(&middleware.AuthConfig{
+		JWTSecret:         []byte("test-secret-key-at-least-32-chars-long"),
+		AccessTokenExpiry: 15 * time.Minute,
+	}, logger.Default())
+	require.NoError(t, err)
+
+	gpioService := services.NewGPIOService(db, logger.Default())
+	controller := NewPiControllerServer(db, logger.Default(), authManager,
+		services.NewClusterService(db, logger.Default()),
+		services.NewNodeService(db, logger.Default()),
+		gpioService,
+	)
+
+	conn := bufconnDial(t, func(server *grpc.Server) {
+		pb.RegisterPiControllerServiceServer(server, controller)
+	})
+
+	return &testController{
+		client:      pb.NewPiControllerServiceClient(conn),
+		authManager: authManager,
+		db:          db,
+		gpio:        gpioService,
+	}
+}
+
+// as returns a context carrying a bearer token for the given role
+func (c *testController) as(t *testing.T, role string) context.Context {
+	token, err := c.authManager.GenerateToken("user-"+role, role, middleware.TokenTypeAccess)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, code, status.Code(err), err.Error())
+}
+
+func TestPiControllerServer_ClusterCRUD(t *testing.T) {
+	c := setupTestController(t)
+	ctx := c.as(t, middleware.RoleAdmin)
+
+	cluster, err := c.client.CreateCluster(ctx, &pb.CreateClusterRequest{Name: "lab", Version: "v1.30.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "lab", cluster.Name)
+	assert.Equal(t, pb.ClusterStatus_CLUSTER_STATUS_ACTIVE, cluster.Status)
+
+	_, err = c.client.CreateCluster(ctx, &pb.CreateClusterRequest{Name: "lab"})
+	assertCode(t, err, codes.AlreadyExists)
+
+	description := "home lab"
+	degraded := pb.ClusterStatus_CLUSTER_STATUS_DEGRADED
+	updated, err := c.client.UpdateCluster(ctx, &pb.UpdateClusterRequest{Id: cluster.Id, Description: &description, Status: &degraded})
+	require.NoError(t, err)
+	assert.Equal(t, "home lab", updated.Description)
+	assert.Equal(t, degraded, updated.Status)
+
+	list, err := c.client.ListClusters(ctx, &pb.ListClustersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), list.TotalCount)
+
+	clusterID := cluster.Id
+	_, err = c.client.CreateNode(ctx, &pb.CreateNodeRequest{
+		Name:       "pi-1",
+		IpAddress:  "192.168.1.10",
+		MacAddress: "aa:bb:cc:dd:ee:01",
+		CpuCores:   4,
+		Memory:     1024,
+		ClusterId:  &clusterID,
+	})
+	require.NoError(t, err)
+
+	got, err := c.client.GetCluster(ctx, &pb.GetClusterRequest{Id: cluster.Id})
+	require.NoError(t, err)
+	assert.Len(t, got.Nodes, 1)
+
+	_, err = c.client.DeleteCluster(ctx, &pb.DeleteClusterRequest{Id: cluster.Id})
+	assertCode(t, err, codes.FailedPrecondition)
+
+	_, err = c.client.GetCluster(ctx, &pb.GetClusterRequest{Id: 999})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestPiControllerServer_NodeLifecycle(t *testing.T) {
+	c := setupTestController(t)
+	ctx := c.as(t, middleware.RoleAdmin)
+
+	cluster, err := c.client.CreateCluster(ctx, &pb.CreateClusterRequest{Name: "lab"})
+	require.NoError(t, err)
+
+	node, err := c.client.CreateNode(ctx, &pb.CreateNodeRequest{
+		Name:       "pi-1",
+		IpAddress:  "192.168.1.10",
+		MacAddress: "aa:bb:cc:dd:ee:01",
+		CpuCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pb.NodeRole_NODE_ROLE_WORKER, node.Role)
+	assert.Nil(t, node.ClusterId)
+
+	_, err = c.client.CreateNode(ctx, &pb.CreateNodeRequest{Name: "bad", IpAddress: "not-an-ip", MacAddress: "aa:bb:cc:dd:ee:02"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	provisioned, err := c.client.ProvisionNode(ctx, &pb.ProvisionNodeRequest{Id: node.Id, ClusterId: cluster.Id})
+	require.NoError(t, err)
+	assert.True(t, provisioned.Success)
+
+	clusterID := cluster.Id
+	nodes, err := c.client.ListNodes(ctx, &pb.ListNodesRequest{ClusterId: &clusterID})
+	require.NoError(t, err)
+	require.Len(t, nodes.Nodes, 1)
+	assert.Equal(t, pb.NodeStatus_NODE_STATUS_PROVISIONING, nodes.Nodes[0].Status)
+
+	name := "pi-renamed"
+	updated, err := c.client.UpdateNode(ctx, &pb.UpdateNodeRequest{Id: node.Id, Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "pi-renamed", updated.Name)
+
+	_, err = c.client.DeprovisionNode(ctx, &pb.DeprovisionNodeRequest{Id: node.Id})
+	require.NoError(t, err)
+
+	got, err := c.client.GetNode(ctx, &pb.GetNodeRequest{Id: node.Id})
+	require.NoError(t, err)
+	assert.Nil(t, got.ClusterId)
+
+	deleted, err := c.client.DeleteNode(ctx, &pb.DeleteNodeRequest{Id: node.Id})
+	require.NoError(t, err)
+	assert.True(t, deleted.Success)
+
+	_, err = c.client.GetNode(ctx, &pb.GetNodeRequest{Id: node.Id})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestPiControllerServer_GPIODevices(t *testing.T) {
+	c := setupTestController(t)
+	ctx := c.as(t, middleware.RoleAdmin)
+
+	node, err := c.client.CreateNode(ctx, &pb.CreateNodeRequest{
+		Name:       "pi-1",
+		IpAddress:  "192.168.1.10",
+		MacAddress: "aa:bb:cc:dd:ee:01",
+		CpuCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+
+	device, err := c.client.CreateGPIODevice(ctx, &pb.CreateGPIODeviceRequest{
+		Name:      "led",
+		NodeId:    node.Id,
+		PinNumber: 18,
+		Direction: pb.GPIODirection_GPIO_DIRECTION_OUTPUT,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pb.GPIODeviceType_GPIO_DEVICE_TYPE_DIGITAL, device.DeviceType)
+	assert.Equal(t, pb.GPIOPullMode_GPIO_PULL_MODE_NONE, device.PullMode)
+
+	_, err = c.client.CreateGPIODevice(ctx, &pb.CreateGPIODeviceRequest{Name: "dup", NodeId: node.Id, PinNumber: 18, Direction: pb.GPIODirection_GPIO_DIRECTION_INPUT})
+	assertCode(t, err, codes.AlreadyExists)
+
+	pwm := pb.GPIODeviceType_GPIO_DEVICE_TYPE_PWM
+	updated, err := c.client.UpdateGPIODevice(ctx, &pb.UpdateGPIODeviceRequest{Id: device.Id, DeviceType: &pwm})
+	require.NoError(t, err)
+	assert.Equal(t, pwm, updated.DeviceType)
+
+	nodeID := node.Id
+	devices, err := c.client.ListGPIODevices(ctx, &pb.ListGPIODevicesRequest{NodeId: &nodeID, DeviceType: &pwm})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), devices.TotalCount)
+
+	// No agent connector is configured, so hardware access is unavailable
+	_, err = c.client.ReadGPIO(ctx, &pb.ReadGPIORequest{Id: device.Id})
+	assertCode(t, err, codes.Unavailable)
+
+	_, err = c.client.DeleteGPIODevice(ctx, &pb.DeleteGPIODeviceRequest{Id: device.Id})
+	require.NoError(t, err)
+
+	_, err = c.client.GetGPIODevice(ctx, &pb.GetGPIODeviceRequest{Id: device.Id})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestPiControllerServer_ReportGPIOAllocations(t *testing.T) {
+	c := setupTestController(t)
+	ctx := c.as(t, middleware.RoleOperator)
+
+	node, err := c.client.CreateNode(ctx, &pb.CreateNodeRequest{
+		Name:       "pi-1",
+		IpAddress:  "192.168.1.10",
+		MacAddress: "aa:bb:cc:dd:ee:01",
+	})
+	require.NoError(t, err)
+	for _, pin := range []int32{18, 23} {
+		_, err := c.client.CreateGPIODevice(ctx, &pb.CreateGPIODeviceRequest{
+			Name:      "pin",
+			NodeId:    node.Id,
+			PinNumber: pin,
+			Direction: pb.GPIODirection_GPIO_DIRECTION_OUTPUT,
+		})
+		require.NoError(t, err)
+	}
+
+	report := func(allocations ...*pb.GPIOAllocation) int32 {
+		resp, err := c.client.ReportGPIOAllocations(ctx, &pb.ReportGPIOAllocationsRequest{NodeId: node.Id, Allocations: allocations})
+		require.NoError(t, err)
+		return resp.Updated
+	}
+	allocatedTo := func() map[int32]string {
+		nodeID := node.Id
+		resp, err := c.client.ListGPIODevices(ctx, &pb.ListGPIODevicesRequest{NodeId: &nodeID})
+		require.NoError(t, err)
+		holders := make(map[int32]string)
+		for _, device := range resp.GpioDevices {
+			holders[device.PinNumber] = device.AllocatedTo
+		}
+		return holders
+	}
+
+	assert.Equal(t, int32(1), report(&pb.GPIOAllocation{PinNumber: 18, AllocatedTo: "default/fan/controller"}))
+	assert.Equal(t, map[int32]string{18: "default/fan/controller", 23: ""}, allocatedTo())
+
+	assert.Equal(t, int32(0), report(&pb.GPIOAllocation{PinNumber: 18, AllocatedTo: "default/fan/controller"}), "unchanged allocations are not rewritten")
+
+	assert.Equal(t, int32(2), report(&pb.GPIOAllocation{PinNumber: 23, AllocatedTo: "default/led/blink"}))
+	assert.Equal(t, map[int32]string{18: "", 23: "default/led/blink"}, allocatedTo())
+
+	_, err = c.client.ReportGPIOAllocations(ctx, &pb.ReportGPIOAllocationsRequest{NodeId: node.Id + 1})
+	assertCode(t, err, codes.NotFound)
+	_, err = c.client.ReportGPIOAllocations(ctx, &pb.ReportGPIOAllocationsRequest{NodeId: node.Id, Allocations: []*pb.GPIOAllocation{{PinNumber: 18}}})
+	assertCode(t, err, codes.InvalidArgument)
+	_, err = c.client.ReportGPIOAllocations(c.as(t, middleware.RoleViewer), &pb.ReportGPIOAllocationsRequest{NodeId: node.Id})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+func TestPiControllerServer_Authorization(t *testing.T) {
+	c := setupTestController(t)
+
+	_, err := c.client.ListClusters(context.Background(), &pb.ListClustersRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	_, err = c.client.ListNodes(bad, &pb.ListNodesRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+
+	viewer := c.as(t, middleware.RoleViewer)
+	_, err = c.client.ListClusters(viewer, &pb.ListClustersRequest{})
+	assert.NoError(t, err)
+	_, err = c.client.GetSystemInfo(viewer, &pb.SystemInfoRequest{})
+	assert.NoError(t, err)
+	_, err = c.client.CreateCluster(viewer, &pb.CreateClusterRequest{Name: "lab"})
+	assertCode(t, err, codes.PermissionDenied)
+	_, err = c.client.WriteGPIO(viewer, &pb.WriteGPIORequest{Id: 1, Value: 1})
+	assertCode(t, err, codes.PermissionDenied)
+
+	operator := c.as(t, middleware.RoleOperator)
+	cluster, err := c.client.CreateCluster(operator, &pb.CreateClusterRequest{Name: "lab"})
+	require.NoError(t, err)
+	_, err = c.client.DeleteCluster(operator, &pb.DeleteClusterRequest{Id: cluster.Id})
+	assertCode(t, err, codes.PermissionDenied)
+
+	_, err = c.client.DeleteCluster(c.as(t, middleware.RoleAdmin), &pb.DeleteClusterRequest{Id: cluster.Id})
+	assert.NoError(t, err)
+
+	// Health stays unauthenticated for probes
+	_, err = c.client.Health(context.Background(), &pb.HealthRequest{})
+	assert.NoError(t, err)
+}
+
+func TestPiControllerServer_StreamGPIOReadings(t *testing.T) {
+	c := setupTestController(t)
+	ctx := c.as(t, middleware.RoleViewer)
+	admin := c.as(t, middleware.RoleAdmin)
+
+	agentConn := bufconnDial(t, func(server *grpc.Server) {
+		pb.RegisterPiAgentServiceServer(server, newFakeAgent(map[int32]int32{23: 1}))
+	})
+	c.gpio.SetAgentConnector(&agentConnector{conn: agentConn})
+
+	node, err := c.client.CreateNode(admin, &pb.CreateNodeRequest{
+		Name:       "pi-1",
+		IpAddress:  "192.168.1.10",
+		MacAddress: "aa:bb:cc:dd:ee:01",
+		CpuCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+	led, err := c.client.CreateGPIODevice(admin, &pb.CreateGPIODeviceRequest{Name: "led", NodeId: node.Id, PinNumber: 18, Direction: pb.GPIODirection_GPIO_DIRECTION_OUTPUT})
+	require.NoError(t, err)
+	button, err := c.client.CreateGPIODevice(admin, &pb.CreateGPIODeviceRequest{Name: "button", NodeId: node.Id, PinNumber: 23, Direction: pb.GPIODirection_GPIO_DIRECTION_INPUT})
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Hour)
+	require.NoError(t, c.db.DB().Create(&models.GPIOReading{DeviceID: uint(led.Id), Value: 0, Timestamp: since.Add(-time.Minute)}).Error)
+	require.NoError(t, c.db.DB().Create(&models.GPIOReading{DeviceID: uint(led.Id), Value: 1, Timestamp: since.Add(time.Minute)}).Error)
+
+	t.Run("replays history then goes live", func(t *testing.T) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := c.client.StreamGPIOReadings(streamCtx, &pb.StreamGPIOReadingsRequest{DeviceId: led.Id, Since: timestamppb.New(since)})
+		require.NoError(t, err)
+
+		replayed, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), replayed.Value)
+
+		// Writes made while the stream is open arrive live
+		go func() {
+			_, _ = c.client.WriteGPIO(admin, &pb.WriteGPIORequest{Id: led.Id, Value: 0})
+		}()
+		live, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, led.Id, live.DeviceId)
+		assert.Equal(t, float64(0), live.Value)
+		assert.Greater(t, live.Id, replayed.Id)
+	})
+
+	t.Run("polls node devices at the sample rate", func(t *testing.T) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var stored int64
+		require.NoError(t, c.db.DB().Model(&models.GPIOReading{}).Count(&stored).Error)
+
+		nodeID := node.Id
+		stream, err := c.client.StreamGPIOReadings(streamCtx, &pb.StreamGPIOReadingsRequest{NodeId: &nodeID, SampleRate: 10})
+		require.NoError(t, err)
+
+		seen := map[uint32]bool{}
+		for len(seen) < 2 {
+			reading, err := stream.Recv()
+			require.NoError(t, err)
+			assert.Zero(t, reading.Id, "polled readings are not stored")
+			seen[reading.DeviceId] = true
+		}
+		assert.True(t, seen[button.Id])
+
+		var after int64
+		require.NoError(t, c.db.DB().Model(&models.GPIOReading{}).Count(&after).Error)
+		assert.Equal(t, stored, after)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		nodeID := node.Id
+		for _, req := range []*pb.StreamGPIOReadingsRequest{
+			{},
+			{DeviceId: led.Id, NodeId: &nodeID},
+			{DeviceId: led.Id, SampleRate: 100},
+		} {
+			stream, err := c.client.StreamGPIOReadings(ctx, req)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assertCode(t, err, codes.InvalidArgument)
+		}
+
+		stream, err := c.client.StreamGPIOReadings(ctx, &pb.StreamGPIOReadingsRequest{DeviceId: 999})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assertCode(t, err, codes.NotFound)
+
+		stream, err = c.client.StreamGPIOReadings(context.Background(), &pb.StreamGPIOReadingsRequest{DeviceId: led.Id})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assertCode(t, err, codes.Unauthenticated)
+	})
+}
+
+func TestPagination(t *testing.T) {
+	limit, offset := pagination(0, 0)
+	assert.Equal(t, 0, limit)
+	assert.Equal(t, 0, offset)
+
+	limit, offset = pagination(3, 20)
+	assert.Equal(t, 20, limit)
+	assert.Equal(t, 40, offset)
+}
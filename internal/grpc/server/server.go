@@ -8,7 +8,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
 	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/services"
 	"github.com/dsyorkd/pi-controller/internal/storage"
 	pb "github.com/dsyorkd/pi-controller/proto"
 )
@@ -21,12 +23,30 @@ type Server struct {
 	server   *grpc.Server
 }
 
-// New creates a new gRPC server instance
+// New creates a new gRPC server instance with its own services. Without an
+// auth manager every authenticated RPC is rejected.
 func New(cfg *config.GRPCConfig, logger logger.Interface, db *storage.Database) (*Server, error) {
+	clusterService := services.NewClusterService(db, logger)
+	nodeService := services.NewNodeService(db, logger)
+	gpioService := services.NewGPIOService(db, logger)
+
+	return NewWithServices(cfg, logger, db, nil, clusterService, nodeService, gpioService, nil)
+}
+
+// NewWithServices creates a new gRPC server instance using the auth manager and
+// services shared with the REST API. With an enrollment service the server
+// presents a certificate from the controller's CA and accepts enrolled
+// agents' certificates, in place of the configured TLS files, and closes an
+// agent's connections when its node's certificates are revoked.
+func NewWithServices(cfg *config.GRPCConfig, logger logger.Interface, db *storage.Database, authManager *middleware.AuthManager, clusterService *services.ClusterService, nodeService *services.NodeService, gpioService *services.GPIOService, enrollmentService *services.EnrollmentService) (*Server, error) {
 	var opts []grpc.ServerOption
 
 	// Add TLS credentials if configured
-	if cfg.IsTLSEnabled() {
+	if enrollmentService != nil {
+		creds := newNodeCredentials(credentials.NewTLS(enrollmentService.ServerTLSConfig()), enrollmentService)
+		enrollmentService.AddRevocationHandler(creds.disconnect)
+		opts = append(opts, grpc.Creds(creds))
+	} else if cfg.IsTLSEnabled() {
 		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertFile, cfg.TLSKeyFile)
 		if err != nil {
 			return nil, err
@@ -48,9 +68,9 @@ func New(cfg *config.GRPCConfig, logger logger.Interface, db *storage.Database)
 	}
 
 	// Register service implementation
-	piControllerServer := &PiControllerServer{
-		database: db,
-		logger:   logger,
+	piControllerServer := NewPiControllerServer(db, logger, authManager, clusterService, nodeService, gpioService)
+	if enrollmentService != nil {
+		piControllerServer.SetEnrollmentService(enrollmentService)
 	}
 	pb.RegisterPiControllerServiceServer(grpcServer, piControllerServer)
 
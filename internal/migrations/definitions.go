@@ -37,6 +37,48 @@ func getAllMigrations() []MigrationDefinition {
 			Up:          addPerformanceIndexes,
 			Down:        dropPerformanceIndexes,
 		},
+		{
+			ID:          "20261016000001",
+			Description: "Add GPIO edge detection settings",
+			Up:          addGPIOEdgeColumns,
+			Down:        dropGPIOEdgeColumns,
+		},
+		{
+			ID:          "20261016000002",
+			Description: "Create provisioning_jobs and provisioning_steps tables",
+			Up:          createProvisioningTables,
+			Down:        dropProvisioningTables,
+		},
+		{
+			ID:          "20261016000004",
+			Description: "Add device plugin allocation to gpio_devices",
+			Up:          addGPIOAllocationColumns,
+			Down:        dropGPIOAllocationColumns,
+		},
+		{
+			ID:          "20261016000005",
+			Description: "Create cluster_backups and cluster_backup_locks tables",
+			Up:          createClusterBackupsTable,
+			Down:        dropClusterBackupsTable,
+		},
+		{
+			ID:          "20261016000006",
+			Description: "Create cluster_upgrades table",
+			Up:          createClusterUpgradesTable,
+			Down:        dropClusterUpgradesTable,
+		},
+		{
+			ID:          "20261016000007",
+			Description: "Create node_registrations table",
+			Up:          createNodeRegistrationsTable,
+			Down:        dropNodeRegistrationsTable,
+		},
+		{
+			ID:          "20261016000008",
+			Description: "Create bootstrap_tokens and node_certificates tables",
+			Up:          createPKITables,
+			Down:        dropPKITables,
+		},
 	}
 }
 
@@ -249,4 +291,275 @@ func dropPerformanceIndexes(db *gorm.DB) error {
 	`
 	
 	return db.Exec(sql).Error
-}
\ No newline at end of file
+}
+
+// addGPIOEdgeColumns adds the edge detection settings to gpio_devices
+func addGPIOEdgeColumns(db *gorm.DB) error {
+	sql := `
+	ALTER TABLE gpio_devices ADD COLUMN edge TEXT DEFAULT '' NOT NULL;
+	ALTER TABLE gpio_devices ADD COLUMN debounce_ms INTEGER DEFAULT 0 NOT NULL;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropGPIOEdgeColumns removes the edge detection settings from gpio_devices
+func dropGPIOEdgeColumns(db *gorm.DB) error {
+	sql := `
+	ALTER TABLE gpio_devices DROP COLUMN debounce_ms;
+	ALTER TABLE gpio_devices DROP COLUMN edge;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// createProvisioningTables creates the provisioning_jobs table and the
+// provisioning_steps table recording each job's steps and their output
+func createProvisioningTables(db *gorm.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS provisioning_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		node_id INTEGER NOT NULL,
+		cluster_id INTEGER,
+		type TEXT NOT NULL,
+		status TEXT DEFAULT 'running' NOT NULL,
+		error TEXT,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+		FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE SET NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_provisioning_jobs_node_id ON provisioning_jobs(node_id);
+
+	CREATE TABLE IF NOT EXISTS provisioning_steps (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		status TEXT DEFAULT 'pending' NOT NULL,
+		error TEXT,
+		stdout TEXT,
+		stderr TEXT,
+		started_at DATETIME,
+		finished_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (job_id) REFERENCES provisioning_jobs(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_provisioning_steps_job_id ON provisioning_steps(job_id);
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropProvisioningTables drops the provisioning_steps and provisioning_jobs
+// tables
+func dropProvisioningTables(db *gorm.DB) error {
+	sql := `
+	DROP INDEX IF EXISTS idx_provisioning_steps_job_id;
+	DROP TABLE IF EXISTS provisioning_steps;
+	DROP INDEX IF EXISTS idx_provisioning_jobs_node_id;
+	DROP TABLE IF EXISTS provisioning_jobs;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// addGPIOAllocationColumns records which pod holds each GPIO device through
+// the device plugin
+func addGPIOAllocationColumns(db *gorm.DB) error {
+	sql := `
+	ALTER TABLE gpio_devices ADD COLUMN allocated_to TEXT DEFAULT '' NOT NULL;
+	ALTER TABLE gpio_devices ADD COLUMN allocated_at DATETIME;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropGPIOAllocationColumns removes the device plugin allocation from gpio_devices
+func dropGPIOAllocationColumns(db *gorm.DB) error {
+	sql := `
+	ALTER TABLE gpio_devices DROP COLUMN allocated_at;
+	ALTER TABLE gpio_devices DROP COLUMN allocated_to;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// createClusterBackupsTable creates the cluster_backups and
+// cluster_backup_locks tables
+func createClusterBackupsTable(db *gorm.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS cluster_backups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		cluster_id INTEGER NOT NULL,
+		node_id INTEGER NOT NULL,
+		trigger TEXT NOT NULL,
+		status TEXT DEFAULT 'running' NOT NULL,
+		datastore TEXT,
+		storage TEXT NOT NULL,
+		key TEXT NOT NULL,
+		size_bytes INTEGER DEFAULT 0 NOT NULL,
+		checksum TEXT,
+		error TEXT,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_cluster_backups_cluster_id ON cluster_backups(cluster_id);
+
+	CREATE TABLE IF NOT EXISTS cluster_backup_locks (
+		cluster_id INTEGER PRIMARY KEY,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE CASCADE
+	);
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropClusterBackupsTable drops the cluster_backups and cluster_backup_locks
+// tables
+func dropClusterBackupsTable(db *gorm.DB) error {
+	sql := `
+	DROP TABLE IF EXISTS cluster_backup_locks;
+	DROP INDEX IF EXISTS idx_cluster_backups_cluster_id;
+	DROP TABLE IF EXISTS cluster_backups;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// createClusterUpgradesTable creates the cluster_upgrades table
+func createClusterUpgradesTable(db *gorm.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS cluster_upgrades (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		cluster_id INTEGER NOT NULL,
+		from_version TEXT,
+		to_version TEXT NOT NULL,
+		batch_size INTEGER NOT NULL,
+		status TEXT DEFAULT 'running' NOT NULL,
+		phase TEXT,
+		current_nodes TEXT,
+		nodes_total INTEGER DEFAULT 0 NOT NULL,
+		nodes_upgraded INTEGER DEFAULT 0 NOT NULL,
+		message TEXT,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_cluster_upgrades_cluster_id ON cluster_upgrades(cluster_id);
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropClusterUpgradesTable drops the cluster_upgrades table
+func dropClusterUpgradesTable(db *gorm.DB) error {
+	sql := `
+	DROP INDEX IF EXISTS idx_cluster_upgrades_cluster_id;
+	DROP TABLE IF EXISTS cluster_upgrades;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// createNodeRegistrationsTable creates the node_registrations table
+func createNodeRegistrationsTable(db *gorm.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS node_registrations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		mac_address TEXT NOT NULL,
+		serial_number TEXT,
+		hostname TEXT,
+		ip_address TEXT,
+		model TEXT,
+		source TEXT,
+		status TEXT DEFAULT 'pending' NOT NULL,
+		node_id INTEGER,
+		message TEXT,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		decided_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_node_registrations_mac_address ON node_registrations(mac_address);
+	CREATE INDEX IF NOT EXISTS idx_node_registrations_status ON node_registrations(status);
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropNodeRegistrationsTable drops the node_registrations table
+func dropNodeRegistrationsTable(db *gorm.DB) error {
+	sql := `
+	DROP INDEX IF EXISTS idx_node_registrations_status;
+	DROP INDEX IF EXISTS idx_node_registrations_mac_address;
+	DROP TABLE IF EXISTS node_registrations;
+	`
+
+	return db.Exec(sql).Error
+}
+
+// createPKITables creates the bootstrap_tokens and node_certificates tables
+func createPKITables(db *gorm.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS bootstrap_tokens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_id TEXT NOT NULL,
+		secret_hash TEXT NOT NULL,
+		description TEXT,
+		node_id INTEGER,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_bootstrap_tokens_token_id ON bootstrap_tokens(token_id);
+
+	CREATE TABLE IF NOT EXISTS node_certificates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		node_id INTEGER NOT NULL,
+		serial_number TEXT NOT NULL,
+		fingerprint TEXT,
+		not_before DATETIME NOT NULL,
+		not_after DATETIME NOT NULL,
+		revoked_at DATETIME,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_node_certificates_serial_number ON node_certificates(serial_number);
+	CREATE INDEX IF NOT EXISTS idx_node_certificates_node_id ON node_certificates(node_id);
+	`
+
+	return db.Exec(sql).Error
+}
+
+// dropPKITables drops the bootstrap_tokens and node_certificates tables
+func dropPKITables(db *gorm.DB) error {
+	sql := `
+	DROP INDEX IF EXISTS idx_node_certificates_node_id;
+	DROP INDEX IF EXISTS idx_node_certificates_serial_number;
+	DROP TABLE IF EXISTS node_certificates;
+	DROP INDEX IF EXISTS idx_bootstrap_tokens_token_id;
+	DROP TABLE IF EXISTS bootstrap_tokens;
+	`
+
+	return db.Exec(sql).Error
+}
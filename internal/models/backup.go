@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// ClusterBackup records one snapshot of a cluster's datastore, taken through
+// the agent on the cluster's master node
+type ClusterBackup struct {
+	ID         uint          `json:"id" gorm:"primarykey"`
+	ClusterID  uint          `json:"cluster_id" gorm:"not null;index"`
+	NodeID     uint          `json:"node_id" gorm:"not null"`
+	Trigger    BackupTrigger `json:"trigger" gorm:"not null"`
+	Status     BackupStatus  `json:"status" gorm:"default:'running'"`
+	Datastore  string        `json:"datastore,omitempty"` // etcd or sqlite
+	Storage    string        `json:"storage" gorm:"not null"`
+	Key        string        `json:"key" gorm:"not null"`
+	SizeBytes  int64         `json:"size_bytes"`
+	Checksum   string        `json:"checksum,omitempty"` // hex SHA-256 of the snapshot
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// BackupTrigger defines what started a backup
+type BackupTrigger string
+
+const (
+	BackupTriggerManual    BackupTrigger = "manual"
+	BackupTriggerScheduled BackupTrigger = "scheduled"
+)
+
+// BackupStatus defines the possible states of a backup
+type BackupStatus string
+
+const (
+	BackupStatusRunning   BackupStatus = "running"
+	BackupStatusSucceeded BackupStatus = "succeeded"
+	BackupStatusFailed    BackupStatus = "failed"
+)
+
+// IsRestorable returns true if the backup holds a complete snapshot
+func (b *ClusterBackup) IsRestorable() bool {
+	return b.Status == BackupStatusSucceeded
+}
+
+// TableName returns the table name for the ClusterBackup model
+func (ClusterBackup) TableName() string {
+	return "cluster_backups"
+}
+
+// ClusterBackupLock marks a cluster as having a backup or restore running in
+// any controller process, such as the controller and its CLI. Locks expire in
+// case their process exits without releasing them.
+type ClusterBackupLock struct {
+	ClusterID uint      `json:"cluster_id" gorm:"primarykey;autoIncrement:false"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for the ClusterBackupLock model
+func (ClusterBackupLock) TableName() string {
+	return "cluster_backup_locks"
+}
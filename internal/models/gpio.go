@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,6 +18,8 @@ type GPIODevice struct {
 	Value       int            `json:"value" gorm:"default:0"`
 	DeviceType  GPIODeviceType `json:"device_type" gorm:"default:'digital'"`
 	Status      GPIOStatus     `json:"status" gorm:"default:'active'"`
+	AllocatedTo string         `json:"allocated_to,omitempty"` // namespace/pod/container holding the pin through the device plugin
+	AllocatedAt *time.Time     `json:"allocated_at,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
@@ -29,6 +32,15 @@ type GPIODevice struct {
 	Config GPIOConfig `json:"config" gorm:"embedded"`
 }
 
+const (
+	// GPIOResourceName is the extended resource the agent's device plugin
+	// advertises, one device per active GPIO device on the node
+	GPIOResourceName = "pi-controller.io/gpio"
+	// GPIONodeLabelPrefix prefixes the Kubernetes node labels naming the GPIO
+	// devices configured on a node, e.g. pi-controller.io/gpio-18=pwm
+	GPIONodeLabelPrefix = "pi-controller.io/gpio-"
+)
+
 // GPIODirection defines the direction of a GPIO pin
 type GPIODirection string
 
@@ -57,6 +69,15 @@ const (
 	GPIODeviceTypeI2C     GPIODeviceType = "i2c"
 )
 
+// GPIOEdge defines which signal edges of an input are reported as readings
+type GPIOEdge string
+
+const (
+	GPIOEdgeRising  GPIOEdge = "rising"
+	GPIOEdgeFalling GPIOEdge = "falling"
+	GPIOEdgeBoth    GPIOEdge = "both"
+)
+
 // GPIOStatus defines the status of a GPIO device
 type GPIOStatus string
 
@@ -84,6 +105,30 @@ type GPIOConfig struct {
 
 	// Sampling configuration
 	SampleRate int `json:"sample_rate,omitempty"` // samples per second
+
+	// Edge detection for inputs; each edge is recorded as a reading
+	Edge       GPIOEdge `json:"edge,omitempty"`        // rising, falling or both; empty disables
+	DebounceMS int      `json:"debounce_ms,omitempty"` // ignore edges within this long of the last one
+}
+
+// WatchesEdges returns true if edges on the device should be streamed from its agent
+func (g *GPIODevice) WatchesEdges() bool {
+	return g.IsActive() && g.IsInput() && g.Config.Edge != ""
+}
+
+// NodeLabel returns the Kubernetes node label advertising the device
+func (g *GPIODevice) NodeLabel() (key, value string) {
+	return fmt.Sprintf("%s%d", GPIONodeLabelPrefix, g.PinNumber), string(g.DeviceType)
+}
+
+// PluginDeviceID returns the ID the device plugin advertises the device under
+func (g *GPIODevice) PluginDeviceID() string {
+	return fmt.Sprintf("gpio-%d", g.PinNumber)
+}
+
+// IsAllocated returns true if a pod holds the device through the device plugin
+func (g *GPIODevice) IsAllocated() bool {
+	return g.AllocatedTo != ""
 }
 
 // IsOutput returns true if the GPIO is configured as output
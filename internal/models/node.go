@@ -35,6 +35,9 @@ type Node struct {
 	KernelVersion string `json:"kernel_version"`
 	LastSeen     time.Time `json:"last_seen"`
 	
+	// Agent connection state as tracked by the controller (not persisted)
+	AgentState string `json:"agent_state,omitempty" gorm:"-"`
+	
 	// Relationships
 	Cluster     *Cluster     `json:"cluster,omitempty" gorm:"foreignKey:ClusterID"`
 	GPIODevices []GPIODevice `json:"gpio_devices,omitempty" gorm:"foreignKey:NodeID"`
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// BootstrapToken is a one-time token an agent presents on first contact to
+// obtain a client certificate. The token is "<token_id>.<secret>"; only a
+// hash of the secret is stored. A token created for a node enrolls the agent
+// as that node; otherwise the agent's node is matched or created on
+// enrollment.
+type BootstrapToken struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	TokenID     string     `json:"token_id" gorm:"uniqueIndex;not null"`
+	SecretHash  string     `json:"-" gorm:"not null"` // hex SHA-256 of the secret
+	Description string     `json:"description,omitempty"`
+	NodeID      *uint      `json:"node_id,omitempty"` // node the token enrolls, or enrolled
+	ExpiresAt   time.Time  `json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsUsable returns true if the token has not been used and has not expired
+func (t *BootstrapToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TableName returns the table name for the BootstrapToken model
+func (BootstrapToken) TableName() string {
+	return "bootstrap_tokens"
+}
+
+// NodeCertificate records a client certificate the controller's CA issued to
+// a node's agent
+type NodeCertificate struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	NodeID       uint       `json:"node_id" gorm:"not null;index"`
+	SerialNumber string     `json:"serial_number" gorm:"uniqueIndex;not null"` // hex
+	Fingerprint  string     `json:"fingerprint"`                               // hex SHA-256 of the certificate
+	NotBefore    time.Time  `json:"not_before"`
+	NotAfter     time.Time  `json:"not_after"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsRevoked returns true if the certificate has been revoked
+func (c *NodeCertificate) IsRevoked() bool {
+	return c.RevokedAt != nil
+}
+
+// TableName returns the table name for the NodeCertificate model
+func (NodeCertificate) TableName() string {
+	return "node_certificates"
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+)
+
+// ProvisioningJob records one run of provisioning or deprovisioning a node,
+// kept after it finishes so failed joins can be inspected
+type ProvisioningJob struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	NodeID     uint       `json:"node_id" gorm:"not null;index"`
+	ClusterID  *uint      `json:"cluster_id,omitempty"`
+	Type       JobType    `json:"type" gorm:"not null"`
+	Status     JobStatus  `json:"status" gorm:"default:'running'"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relationships
+	Steps []ProvisioningStep `json:"steps,omitempty" gorm:"foreignKey:JobID"`
+}
+
+// JobType defines what a provisioning job does to a node
+type JobType string
+
+const (
+	JobTypeProvision   JobType = "provision"
+	JobTypeDeprovision JobType = "deprovision"
+	JobTypeRestore     JobType = "restore" // restores the cluster datastore on a master
+	JobTypeUpgrade     JobType = "upgrade" // upgrades Kubernetes on a node during a cluster upgrade
+)
+
+// JobStatus defines the possible states of a provisioning job
+type JobStatus string
+
+const (
+	JobStatusRunning     JobStatus = "running"
+	JobStatusSucceeded   JobStatus = "succeeded"
+	JobStatusFailed      JobStatus = "failed"
+	JobStatusInterrupted JobStatus = "interrupted" // stopped by a controller shutdown; resumed on restart
+)
+
+// IsFinished returns true if the job is no longer running
+func (j *ProvisioningJob) IsFinished() bool {
+	return j.Status != JobStatusRunning
+}
+
+// TableName returns the table name for the ProvisioningJob model
+func (ProvisioningJob) TableName() string {
+	return "provisioning_jobs"
+}
+
+// ProvisioningStep records one step of a provisioning job with the tail of
+// the output of the commands it ran
+type ProvisioningStep struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	JobID      uint       `json:"job_id" gorm:"not null;index"`
+	Position   int        `json:"position" gorm:"not null"`
+	Name       string     `json:"name" gorm:"not null"`
+	Status     StepStatus `json:"status" gorm:"default:'pending'"`
+	Error      string     `json:"error,omitempty"`
+	Stdout     string     `json:"stdout,omitempty" gorm:"type:text"`
+	Stderr     string     `json:"stderr,omitempty" gorm:"type:text"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// StepStatus defines the possible states of a provisioning step
+type StepStatus string
+
+const (
+	StepStatusPending   StepStatus = "pending"
+	StepStatusRunning   StepStatus = "running"
+	StepStatusSucceeded StepStatus = "succeeded"
+	StepStatusFailed    StepStatus = "failed"
+	StepStatusSkipped   StepStatus = "skipped" // succeeded in an earlier job that this one resumes
+)
+
+// IsDone returns true if the step does not need to run again
+func (s *ProvisioningStep) IsDone() bool {
+	return s.Status == StepStatusSucceeded || s.Status == StepStatusSkipped
+}
+
+// TableName returns the table name for the ProvisioningStep model
+func (ProvisioningStep) TableName() string {
+	return "provisioning_steps"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// NodeRegistration is a node found by discovery that has not been added to
+// the inventory yet, or the decision made about one. Nodes are identified by
+// MAC address. Approving a registration creates the node; rejecting it keeps
+// discovery from queueing the node again.
+type NodeRegistration struct {
+	ID           uint               `json:"id" gorm:"primarykey"`
+	MACAddress   string             `json:"mac_address" gorm:"uniqueIndex;not null"`
+	SerialNumber string             `json:"serial_number,omitempty"`
+	Hostname     string             `json:"hostname"`
+	IPAddress    string             `json:"ip_address"`
+	Model        string             `json:"model,omitempty"`
+	Source       string             `json:"source"` // discovery method that found the node
+	Status       RegistrationStatus `json:"status" gorm:"default:'pending'"`
+	NodeID       *uint              `json:"node_id,omitempty"` // node created on approval
+	Message      string             `json:"message,omitempty"`
+	FirstSeen    time.Time          `json:"first_seen"`
+	LastSeen     time.Time          `json:"last_seen"`
+	DecidedAt    *time.Time         `json:"decided_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// RegistrationStatus defines the possible states of a node registration
+type RegistrationStatus string
+
+const (
+	RegistrationStatusPending  RegistrationStatus = "pending"
+	RegistrationStatusApproved RegistrationStatus = "approved"
+	RegistrationStatusRejected RegistrationStatus = "rejected"
+)
+
+// IsPending returns true if the registration awaits a decision
+func (r *NodeRegistration) IsPending() bool {
+	return r.Status == RegistrationStatusPending
+}
+
+// TableName returns the table name for the NodeRegistration model
+func (NodeRegistration) TableName() string {
+	return "node_registrations"
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// ClusterUpgrade records a rolling upgrade of a cluster's nodes to a
+// Kubernetes version. Masters are upgraded one at a time, then workers in
+// batches; the progress of each node is recorded as an upgrade job of the node.
+type ClusterUpgrade struct {
+	ID            uint          `json:"id" gorm:"primarykey"`
+	ClusterID     uint          `json:"cluster_id" gorm:"not null;index"`
+	FromVersion   string        `json:"from_version,omitempty"`
+	ToVersion     string        `json:"to_version" gorm:"not null"`
+	BatchSize     int           `json:"batch_size" gorm:"not null"`
+	Status        UpgradeStatus `json:"status" gorm:"default:'running'"`
+	Phase         UpgradePhase  `json:"phase,omitempty"`
+	CurrentNodes  string        `json:"current_nodes,omitempty"` // comma-separated names of the batch being upgraded
+	NodesTotal    int           `json:"nodes_total"`
+	NodesUpgraded int           `json:"nodes_upgraded"`
+	Message       string        `json:"message,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	FinishedAt    *time.Time    `json:"finished_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// UpgradeStatus defines the possible states of a cluster upgrade
+type UpgradeStatus string
+
+const (
+	UpgradeStatusRunning     UpgradeStatus = "running"
+	UpgradeStatusSucceeded   UpgradeStatus = "succeeded"
+	UpgradeStatusFailed      UpgradeStatus = "failed"
+	UpgradeStatusInterrupted UpgradeStatus = "interrupted"
+)
+
+// UpgradePhase defines which nodes an upgrade is working through
+type UpgradePhase string
+
+const (
+	UpgradePhaseMasters UpgradePhase = "masters"
+	UpgradePhaseWorkers UpgradePhase = "workers"
+)
+
+// IsFinished returns true if the upgrade is no longer running
+func (u *ClusterUpgrade) IsFinished() bool {
+	return u.Status != UpgradeStatusRunning
+}
+
+// TableName returns the table name for the ClusterUpgrade model
+func (ClusterUpgrade) TableName() string {
+	return "cluster_upgrades"
+}
@@ -0,0 +1,346 @@
+// Package pki implements the controller's certificate authority and the
+// agent's enrolled credentials. The CA issues the controller a certificate
+// named ControllerName and each enrolled agent a certificate named after its
+// node, so both ends of controller<->agent gRPC authenticate each other.
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ControllerName is the common name and DNS name of the controller's
+// certificate. Agents verify the controller against it regardless of the
+// address they dial.
+const ControllerName = "pi-controller"
+
+// nodeOrganization marks certificates issued to enrolled nodes
+const nodeOrganization = "pi-controller:nodes"
+
+// nodeNamePrefix precedes the node ID in node certificate names
+const nodeNamePrefix = "node-"
+
+// File names in the CA directory
+const (
+	caCertFile = "ca.crt"
+	caKeyFile  = "ca.key"
+)
+
+// caValidity is the lifetime of a newly created CA
+const caValidity = 10 * 365 * 24 * time.Hour
+
+// clockSkew backdates issued certificates so hosts with slightly slow clocks
+// accept them
+const clockSkew = 5 * time.Minute
+
+// NodeName returns the common name and DNS name of a node's certificate
+func NodeName(nodeID uint) string {
+	return nodeNamePrefix + strconv.FormatUint(uint64(nodeID), 10)
+}
+
+// NodeIDFromCertificate returns the ID of the node a certificate was issued
+// to
+func NodeIDFromCertificate(cert *x509.Certificate) (uint, error) {
+	isNode := false
+	for _, org := range cert.Subject.Organization {
+		if org == nodeOrganization {
+			isNode = true
+		}
+	}
+	name, found := strings.CutPrefix(cert.Subject.CommonName, nodeNamePrefix)
+	if !isNode || !found {
+		return 0, fmt.Errorf("certificate %q is not a node certificate", cert.Subject.CommonName)
+	}
+	id, err := strconv.ParseUint(name, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("certificate %q has an invalid node ID", cert.Subject.CommonName)
+	}
+	return uint(id), nil
+}
+
+// PublicKeyHash returns "sha256:<hex>" of a certificate's public key. Agents
+// are given the CA's hash to pin the controller on first contact.
+func PublicKeyHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// CA is the controller's certificate authority
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// LoadOrCreateCA loads the CA from dir, creating a new self-signed CA there
+// if none exists
+func LoadOrCreateCA(dir string) (*CA, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+
+	certPEM, err := os.ReadFile(certPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return createCA(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	key, err := parseKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA key: %w", err)
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, fmt.Errorf("CA key does not match the CA certificate")
+	}
+	return &CA{cert: cert, certPEM: certPEM, key: key}, nil
+}
+
+func createCA(dir string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: ControllerName + " CA"},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := encodeKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	certPEM := encodeCertificatePEM(der)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create CA directory: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, caKeyFile), keyPEM, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write CA key: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, caCertFile), certPEM, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write CA certificate: %w", err)
+	}
+	return &CA{cert: cert, certPEM: certPEM, key: key}, nil
+}
+
+// Certificate returns the CA certificate
+func (ca *CA) Certificate() *x509.Certificate {
+	return ca.cert
+}
+
+// CertificatePEM returns the PEM-encoded CA certificate
+func (ca *CA) CertificatePEM() []byte {
+	return ca.certPEM
+}
+
+// Pool returns a certificate pool holding only the CA
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// Hash returns the CA's public key hash agents pin on first contact
+func (ca *CA) Hash() string {
+	return PublicKeyHash(ca.cert)
+}
+
+// IssueControllerCertificate issues the controller a certificate named
+// ControllerName, used both to serve agents and to dial them. The chain
+// includes the CA so enrolling agents can check it against their pinned
+// hash.
+func (ca *CA) IssueControllerCertificate(ttl time.Duration) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate controller key: %w", err)
+	}
+	der, err := ca.sign(&key.PublicKey, pkix.Name{CommonName: ControllerName}, ControllerName, ttl)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der, ca.cert.Raw}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// SignNodeCertificate signs a PEM certificate signing request, binding the
+// requester's key to the node. The CSR's subject is ignored.
+func (ca *CA) SignNodeCertificate(csrPEM []byte, nodeID uint, ttl time.Duration) (*x509.Certificate, []byte, error) {
+	csr, err := ParseCertificateRequest(csrPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := NodeName(nodeID)
+	subject := pkix.Name{CommonName: name, Organization: []string{nodeOrganization}}
+	der, err := ca.sign(csr.PublicKey, subject, name, ttl)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, encodeCertificatePEM(der), nil
+}
+
+// ParseCertificateRequest parses a PEM certificate signing request and
+// checks its signature
+func ParseCertificateRequest(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("certificate signing request is not PEM encoded")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate signing request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate signing request signature: %w", err)
+	}
+	return csr, nil
+}
+
+// sign issues a leaf certificate usable for both client and server
+// authentication, since controller and agents each dial the other
+func (ca *CA) sign(pub any, subject pkix.Name, dnsName string, ttl time.Duration) ([]byte, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		DNSNames:     []string{dnsName},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, pub, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	return der, nil
+}
+
+// SerialString formats a certificate serial number as stored in the
+// inventory
+func SerialString(cert *x509.Certificate) string {
+	return cert.SerialNumber.Text(16)
+}
+
+// Fingerprint returns the hex SHA-256 of a certificate
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+func encodeCertificatePEM(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func parseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("certificate is not PEM encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func encodeKeyPEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseKeyPEM(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, fmt.Errorf("key is not a PEM encoded EC private key")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// writeFileAtomic replaces a file so readers never see it half written
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
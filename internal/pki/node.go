@@ -0,0 +1,289 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File names in the agent's credentials directory
+const (
+	nodeCertFile = "node.crt"
+	nodeKeyFile  = "node.key"
+)
+
+// ErrNoCredentials is returned when the agent has not enrolled yet
+var ErrNoCredentials = errors.New("no enrolled credentials")
+
+// NodeCredentials is an enrolled agent's key, certificate and CA. The
+// certificate can be replaced while connections use it; new handshakes pick
+// up the current one.
+type NodeCredentials struct {
+	dir  string
+	pool *x509.CertPool
+	ca   *x509.Certificate
+
+	mu   sync.RWMutex
+	cert tls.Certificate
+}
+
+// NewCSR generates a key and a PEM certificate signing request for it
+func NewCSR() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "pi-agent"},
+	}, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate signing request: %w", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// LoadNodeCredentials loads the credentials saved in dir, returning
+// ErrNoCredentials if the agent has not enrolled
+func LoadNodeCredentials(dir string) (*NodeCredentials, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, nodeCertFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, nodeKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node key: %w", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	key, err := parseKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node key: %w", err)
+	}
+	creds, err := newNodeCredentials(dir, caPEM)
+	if err != nil {
+		return nil, err
+	}
+	if creds.cert, err = creds.verify(key, certPEM); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// SaveNodeCredentials saves the key and the certificates returned by
+// enrollment to dir
+func SaveNodeCredentials(dir string, key *ecdsa.PrivateKey, certPEM, caPEM []byte) (*NodeCredentials, error) {
+	creds, err := newNodeCredentials(dir, caPEM)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, caCertFile), caPEM, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write CA certificate: %w", err)
+	}
+	if err := creds.Update(key, certPEM); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+func newNodeCredentials(dir string, caPEM []byte) (*NodeCredentials, error) {
+	ca, err := parseCertificatePEM(caPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	if !ca.IsCA {
+		return nil, fmt.Errorf("CA certificate %q is not a CA", ca.Subject.CommonName)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return &NodeCredentials{dir: dir, pool: pool, ca: ca}, nil
+}
+
+// Update replaces the key and certificate after renewal and saves them
+func (c *NodeCredentials) Update(key *ecdsa.PrivateKey, certPEM []byte) error {
+	cert, err := c.verify(key, certPEM)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := encodeKeyPEM(key)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := writeFileAtomic(filepath.Join(c.dir, nodeKeyFile), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write node key: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(c.dir, nodeCertFile), certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write node certificate: %w", err)
+	}
+	c.cert = cert
+	return nil
+}
+
+// verify checks that the certificate is a node certificate from the CA for
+// the key
+func (c *NodeCredentials) verify(key *ecdsa.PrivateKey, certPEM []byte) (tls.Certificate, error) {
+	leaf, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid node certificate: %w", err)
+	}
+	if !key.PublicKey.Equal(leaf.PublicKey) {
+		return tls.Certificate{}, fmt.Errorf("node key does not match the node certificate")
+	}
+	if _, err := NodeIDFromCertificate(leaf); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := leaf.CheckSignatureFrom(c.ca); err != nil {
+		return tls.Certificate{}, fmt.Errorf("node certificate was not issued by the CA: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// Leaf returns the current certificate
+func (c *NodeCredentials) Leaf() *x509.Certificate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert.Leaf
+}
+
+// NodeID returns the ID of the node the credentials were issued to
+func (c *NodeCredentials) NodeID() uint {
+	id, _ := NodeIDFromCertificate(c.Leaf())
+	return id
+}
+
+// NeedsRenewal returns true once the certificate is due for renewal
+func (c *NodeCredentials) NeedsRenewal(now time.Time) bool {
+	return NeedsRenewal(c.Leaf(), now)
+}
+
+// NeedsRenewal returns true once two thirds of a certificate's lifetime have
+// passed
+func NeedsRenewal(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotBefore.Add(lifetime * 2 / 3))
+}
+
+func (c *NodeCredentials) certificate() *tls.Certificate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cert := c.cert
+	return &cert
+}
+
+// ClientTLSConfig returns the configuration for dialing the controller with
+// the node's certificate
+func (c *NodeCredentials) ClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    c.pool,
+		ServerName: ControllerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return c.certificate(), nil
+		},
+	}
+}
+
+// ServerTLSConfig returns the configuration for the agent's server, which
+// only accepts the controller's certificate
+func (c *NodeCredentials) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  c.pool,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.certificate(), nil
+		},
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.VerifiedChains) == 0 || state.VerifiedChains[0][0].Subject.CommonName != ControllerName {
+				return fmt.Errorf("client is not the controller")
+			}
+			return nil
+		},
+	}
+}
+
+// PinnedTLSConfig returns the configuration for enrolling, before the agent
+// has the CA certificate. The controller must present a chain whose CA has
+// the pinned public key hash and which names ControllerName. An empty hash
+// trusts whichever CA the controller presents.
+func PinnedTLSConfig(caHash string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The chain is verified against the pinned CA below
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, err := verifyPinnedChain(rawCerts, caHash)
+			return err
+		},
+	}
+}
+
+// verifyPinnedChain verifies a chain presented by the controller against
+// its pinned CA, returning the CA
+func verifyPinnedChain(rawCerts [][]byte, caHash string) (*x509.Certificate, error) {
+	if len(rawCerts) < 2 {
+		return nil, fmt.Errorf("controller did not present its CA certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid controller certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	ca := certs[len(certs)-1]
+	if !ca.IsCA {
+		return nil, fmt.Errorf("controller did not present its CA certificate")
+	}
+	if caHash != "" && PublicKeyHash(ca) != caHash {
+		return nil, fmt.Errorf("controller CA %s does not match the pinned hash %s", PublicKeyHash(ca), caHash)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1 : len(certs)-1] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       ControllerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid controller certificate: %w", err)
+	}
+	return ca, nil
+}
+
+// MatchesHash reports whether a PEM CA certificate has the pinned public key
+// hash. An empty hash matches any CA.
+func MatchesHash(caPEM []byte, caHash string) bool {
+	if caHash == "" {
+		return true
+	}
+	ca, err := parseCertificatePEM(caPEM)
+	return err == nil && PublicKeyHash(ca) == caHash
+}
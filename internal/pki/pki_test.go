@@ -0,0 +1,257 @@
+package pki
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handshake runs a TLS handshake between a client and server configuration
+// over loopback
+func handshake(t *testing.T, client, server *tls.Config) (clientErr, serverErr error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		tlsConn := tls.Server(conn, server)
+		err = tlsConn.Handshake()
+		if err == nil {
+			tlsConn.Write([]byte{0})
+		}
+		done <- err
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	tlsConn := tls.Client(conn, client)
+	clientErr = tlsConn.Handshake()
+	if clientErr == nil {
+		// TLS 1.3 servers verify the client's certificate after the client
+		// finishes, so a rejection only shows on the first read
+		_, clientErr = tlsConn.Read(make([]byte, 1))
+	}
+	return clientErr, <-done
+}
+
+func enroll(t *testing.T, ca *CA, nodeID uint, ttl time.Duration) *NodeCredentials {
+	key, csr, err := NewCSR()
+	require.NoError(t, err)
+	_, certPEM, err := ca.SignNodeCertificate(csr, nodeID, ttl)
+	require.NoError(t, err)
+	creds, err := SaveNodeCredentials(t.TempDir(), key, certPEM, ca.CertificatePEM())
+	require.NoError(t, err)
+	return creds
+}
+
+func TestLoadOrCreateCA(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := LoadOrCreateCA(dir)
+	require.NoError(t, err)
+	assert.True(t, ca.Certificate().IsCA)
+
+	info, err := os.Stat(filepath.Join(dir, caKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadOrCreateCA(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Hash(), loaded.Hash(), "the existing CA is loaded")
+
+	other, err := LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, caKeyFile), mustKeyPEM(t, other), 0600))
+	_, err = LoadOrCreateCA(dir)
+	assert.Error(t, err, "a key for another certificate is rejected")
+}
+
+func mustKeyPEM(t *testing.T, ca *CA) []byte {
+	keyPEM, err := encodeKeyPEM(ca.key)
+	require.NoError(t, err)
+	return keyPEM
+}
+
+func TestSignNodeCertificate(t *testing.T) {
+	ca, err := LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+
+	_, csr, err := NewCSR()
+	require.NoError(t, err)
+	cert, certPEM, err := ca.SignNodeCertificate(csr, 42, time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, certPEM)
+	assert.Equal(t, "node-42", cert.Subject.CommonName)
+	assert.Equal(t, []string{"node-42"}, cert.DNSNames)
+
+	id, err := NodeIDFromCertificate(cert)
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+
+	controller, err := ca.IssueControllerCertificate(time.Hour)
+	require.NoError(t, err)
+	_, err = NodeIDFromCertificate(controller.Leaf)
+	assert.Error(t, err, "the controller's certificate names no node")
+
+	_, _, err = ca.SignNodeCertificate([]byte("not a csr"), 42, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca, err := LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	controller, err := ca.IssueControllerCertificate(time.Hour)
+	require.NoError(t, err)
+	creds := enroll(t, ca, 7, time.Hour)
+
+	controllerServer := &tls.Config{
+		Certificates: []tls.Certificate{controller},
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		ClientCAs:    ca.Pool(),
+	}
+	controllerClient := &tls.Config{
+		Certificates: []tls.Certificate{controller},
+		RootCAs:      ca.Pool(),
+		ServerName:   NodeName(7),
+	}
+
+	t.Run("agent to controller", func(t *testing.T) {
+		clientErr, serverErr := handshake(t, creds.ClientTLSConfig(), controllerServer)
+		assert.NoError(t, clientErr)
+		assert.NoError(t, serverErr)
+	})
+
+	t.Run("controller to agent", func(t *testing.T) {
+		clientErr, serverErr := handshake(t, controllerClient, creds.ServerTLSConfig())
+		assert.NoError(t, clientErr)
+		assert.NoError(t, serverErr)
+	})
+
+	t.Run("agent server rejects other nodes", func(t *testing.T) {
+		other := enroll(t, ca, 8, time.Hour)
+		client := other.ClientTLSConfig()
+		client.ServerName = NodeName(7)
+		_, serverErr := handshake(t, client, creds.ServerTLSConfig())
+		assert.Error(t, serverErr)
+	})
+
+	t.Run("agent server rejects other CAs", func(t *testing.T) {
+		otherCA, err := LoadOrCreateCA(t.TempDir())
+		require.NoError(t, err)
+		impostor, err := otherCA.IssueControllerCertificate(time.Hour)
+		require.NoError(t, err)
+		client := controllerClient.Clone()
+		client.Certificates = []tls.Certificate{impostor}
+		_, serverErr := handshake(t, client, creds.ServerTLSConfig())
+		assert.Error(t, serverErr)
+	})
+
+	t.Run("pinned enrollment", func(t *testing.T) {
+		clientErr, serverErr := handshake(t, PinnedTLSConfig(ca.Hash()), controllerServer)
+		assert.NoError(t, clientErr)
+		assert.NoError(t, serverErr)
+
+		clientErr, _ = handshake(t, PinnedTLSConfig("sha256:00"), controllerServer)
+		assert.Error(t, clientErr, "a controller with another CA is rejected")
+
+		assert.True(t, MatchesHash(ca.CertificatePEM(), ca.Hash()))
+		assert.False(t, MatchesHash(ca.CertificatePEM(), "sha256:00"))
+	})
+}
+
+func TestNodeCredentials(t *testing.T) {
+	ca, err := LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	creds := enroll(t, ca, 3, 3*time.Hour)
+	assert.Equal(t, uint(3), creds.NodeID())
+
+	_, err = LoadNodeCredentials(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	loaded, err := LoadNodeCredentials(creds.dir)
+	require.NoError(t, err)
+	assert.Equal(t, creds.Leaf().SerialNumber, loaded.Leaf().SerialNumber)
+
+	issued := creds.Leaf().NotBefore
+	assert.False(t, creds.NeedsRenewal(issued.Add(time.Hour)))
+	assert.True(t, creds.NeedsRenewal(issued.Add(3*time.Hour)))
+
+	key, csr, err := NewCSR()
+	require.NoError(t, err)
+	_, certPEM, err := ca.SignNodeCertificate(csr, 3, 3*time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, creds.Update(key, certPEM))
+	assert.NotEqual(t, loaded.Leaf().SerialNumber, creds.Leaf().SerialNumber)
+
+	reloaded, err := LoadNodeCredentials(creds.dir)
+	require.NoError(t, err)
+	assert.Equal(t, creds.Leaf().SerialNumber, reloaded.Leaf().SerialNumber, "the renewed certificate is saved")
+
+	otherKey, _, err := NewCSR()
+	require.NoError(t, err)
+	assert.Error(t, creds.Update(otherKey, certPEM), "the key must match the certificate")
+}
+
+func TestAgentTokens(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "agent-token.key")
+	issuer, err := LoadOrCreateAgentTokenIssuer(keyPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "only the controller may read the signing key")
+
+	verifier, err := LoadAgentTokenVerifier(keyPath + ".pub")
+	require.NoError(t, err)
+
+	token, expiresAt, err := issuer.Issue(ControllerName, "admin")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(agentTokenTTL), expiresAt, time.Minute)
+	claims, err := verifier.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, ControllerName, claims.Subject)
+	assert.Equal(t, "admin", claims.Role)
+
+	reloaded, err := LoadOrCreateAgentTokenIssuer(keyPath)
+	require.NoError(t, err)
+	token, _, err = reloaded.Issue(ControllerName, "admin")
+	require.NoError(t, err)
+	_, err = verifier.Verify(token)
+	assert.NoError(t, err, "the key is reused across restarts")
+
+	other, err := LoadOrCreateAgentTokenIssuer(filepath.Join(t.TempDir(), "agent-token.key"))
+	require.NoError(t, err)
+	token, _, err = other.Issue(ControllerName, "admin")
+	require.NoError(t, err)
+	_, err = verifier.Verify(token)
+	assert.Error(t, err, "tokens must be signed with the controller's key")
+
+	// Tokens signed with a shared secret are refused even if their claims
+	// match, so the controller API's tokens never reach agents
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, AgentTokenClaims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    ControllerName,
+			Subject:   "mallory",
+			Audience:  jwt.ClaimStrings{AgentTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte("shared-secret"))
+	require.NoError(t, err)
+	_, err = verifier.Verify(hmacToken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package pki
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AgentTokenAudience is the audience of the tokens the controller presents to
+// agents. Agents accept no other tokens, and the controller's own APIs, which
+// only accept tokens signed with their secret, never accept these.
+const AgentTokenAudience = "pi-agent"
+
+// agentTokenTTL is the lifetime of an agent token
+const agentTokenTTL = 15 * time.Minute
+
+// AgentTokenClaims are the claims of an agent token. The subject is the
+// caller's user ID.
+type AgentTokenClaims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// AgentTokenIssuer signs agent tokens with a key only the controller holds.
+// Agents verify them with its public key, so a compromised agent cannot mint
+// tokens for other agents or for the controller.
+type AgentTokenIssuer struct {
+	key ed25519.PrivateKey
+}
+
+// LoadOrCreateAgentTokenIssuer loads the Ed25519 signing key from keyPath,
+// creating it if none exists. The public key agents need is kept next to it
+// as keyPath.pub.
+func LoadOrCreateAgentTokenIssuer(keyPath string) (*AgentTokenIssuer, error) {
+	keyPEM, err := os.ReadFile(keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return createAgentTokenIssuer(keyPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent token key: %w", err)
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("agent token key is not a PEM encoded private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent token key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("agent token key is not an Ed25519 key")
+	}
+
+	issuer := &AgentTokenIssuer{key: key}
+	if _, err := os.Stat(keyPath + ".pub"); errors.Is(err, os.ErrNotExist) {
+		if err := issuer.writePublicKey(keyPath + ".pub"); err != nil {
+			return nil, err
+		}
+	}
+	return issuer, nil
+}
+
+func createAgentTokenIssuer(keyPath string) (*AgentTokenIssuer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate agent token key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode agent token key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create agent token key directory: %w", err)
+	}
+	if err := writeFileAtomic(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write agent token key: %w", err)
+	}
+	issuer := &AgentTokenIssuer{key: key}
+	if err := issuer.writePublicKey(keyPath + ".pub"); err != nil {
+		return nil, err
+	}
+	return issuer, nil
+}
+
+// writePublicKey writes the PEM public key agents verify tokens with
+func (i *AgentTokenIssuer) writePublicKey(path string) error {
+	pubPEM, err := i.PublicKeyPEM()
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, pubPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write agent token public key: %w", err)
+	}
+	return nil
+}
+
+// PublicKeyPEM returns the PEM public key agents verify tokens with
+func (i *AgentTokenIssuer) PublicKeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(i.key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode agent token public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Issue signs a token for a caller with a role, returning it and when it
+// expires
+func (i *AgentTokenIssuer) Issue(userID, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(agentTokenTTL)
+	claims := AgentTokenClaims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    ControllerName,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{AgentTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(now.Add(-clockSkew)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(i.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign agent token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// AgentTokenVerifier verifies agent tokens with the controller's public key
+type AgentTokenVerifier struct {
+	key ed25519.PublicKey
+}
+
+// LoadAgentTokenVerifier loads the public key written next to the
+// controller's agent token key
+func LoadAgentTokenVerifier(path string) (*AgentTokenVerifier, error) {
+	pubPEM, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent token public key: %w", err)
+	}
+	return NewAgentTokenVerifier(pubPEM)
+}
+
+// NewAgentTokenVerifier creates a verifier from a PEM public key
+func NewAgentTokenVerifier(pubPEM []byte) (*AgentTokenVerifier, error) {
+	block, _ := pem.Decode(pubPEM)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("agent token public key is not a PEM encoded public key")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent token public key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("agent token public key is not an Ed25519 key")
+	}
+	return &AgentTokenVerifier{key: key}, nil
+}
+
+// Verify checks a token was issued by the controller for agents and has not
+// expired, and returns its claims
+func (v *AgentTokenVerifier) Verify(token string) (*AgentTokenClaims, error) {
+	claims := &AgentTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return v.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithAudience(AgentTokenAudience),
+		jwt.WithIssuer(ControllerName),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent token: %w", err)
+	}
+	if claims.Subject == "" || claims.Role == "" {
+		return nil, fmt.Errorf("agent token has no subject or role")
+	}
+	return claims, nil
+}
@@ -0,0 +1,92 @@
+package provisioning
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+)
+
+// Config contains provisioning engine configuration
+type Config struct {
+	// SSH connection
+	SSHUser               string
+	SSHPort               int
+	SSHKeyFile            string
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+
+	// k3s installation
+	K3sVersion       string
+	InstallScriptURL string
+	ReleaseURL       string
+
+	// Timeouts
+	CommandTimeout      time.Duration
+	RegistrationTimeout time.Duration
+	PollInterval        time.Duration
+}
+
+// applyDefaults sets default values for unset configuration fields
+func applyDefaults(config Config) Config {
+	if config.SSHUser == "" {
+		config.SSHUser = "pi"
+	}
+	if config.SSHPort == 0 {
+		config.SSHPort = 22
+	}
+	if config.InstallScriptURL == "" {
+		config.InstallScriptURL = "https://get.k3s.io"
+	}
+	if config.ReleaseURL == "" {
+		config.ReleaseURL = "https://github.com/k3s-io/k3s/releases/download"
+	}
+	if config.CommandTimeout == 0 {
+		config.CommandTimeout = 10 * time.Minute
+	}
+	if config.RegistrationTimeout == 0 {
+		config.RegistrationTimeout = 5 * time.Minute
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 5 * time.Second
+	}
+
+	return config
+}
+
+// ConfigFromYAML converts the YAML-based config to the provisioning Config
+func ConfigFromYAML(yamlConfig config.ProvisioningConfig) (Config, error) {
+	provisioningConfig := Config{
+		SSHUser:               yamlConfig.SSHUser,
+		SSHPort:               yamlConfig.SSHPort,
+		SSHKeyFile:            yamlConfig.SSHKeyFile,
+		KnownHostsFile:        yamlConfig.KnownHostsFile,
+		InsecureIgnoreHostKey: yamlConfig.InsecureIgnoreHostKey,
+		K3sVersion:            yamlConfig.K3sVersion,
+		InstallScriptURL:      yamlConfig.InstallScriptURL,
+		ReleaseURL:            strings.TrimSuffix(yamlConfig.ReleaseURL, "/"),
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"command_timeout", yamlConfig.CommandTimeout, &provisioningConfig.CommandTimeout},
+		{"registration_timeout", yamlConfig.RegistrationTimeout, &provisioningConfig.RegistrationTimeout},
+		{"poll_interval", yamlConfig.PollInterval, &provisioningConfig.PollInterval},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return provisioningConfig, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.dest = parsed
+	}
+
+	return provisioningConfig, nil
+}
@@ -0,0 +1,505 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// Output streams of a provisioning step
+const (
+	streamCommand = "command"
+	streamStdout  = "stdout"
+	streamStderr  = "stderr"
+)
+
+// Engine provisions nodes by running a sequence of steps on them over SSH.
+// Each run is recorded as a provisioning job with the status and output of
+// its steps, so provisioning interrupted by a restart or failure resumes
+// from the first step that has not succeeded.
+type Engine struct {
+	config   Config
+	dialer   Dialer
+	nodes    *services.NodeService
+	clusters *services.ClusterService
+	jobs     *services.JobService
+	logger   logger.Interface
+
+	mu      sync.Mutex
+	ctx     context.Context
+	running map[uint]bool
+	masters map[uint]chan struct{} // per cluster, held by the master installing k3s
+	wg      sync.WaitGroup
+}
+
+// New creates a provisioning engine
+func New(config Config, dialer Dialer, nodes *services.NodeService, clusters *services.ClusterService, jobs *services.JobService, logger logger.Interface) *Engine {
+	return &Engine{
+		config:   applyDefaults(config),
+		dialer:   dialer,
+		nodes:    nodes,
+		clusters: clusters,
+		jobs:     jobs,
+		logger:   logger.WithField("component", "provisioning"),
+		running:  make(map[uint]bool),
+		masters:  make(map[uint]chan struct{}),
+	}
+}
+
+// Start resumes provisioning of nodes a previous run left in the provisioning
+// state. Provisioning stops when ctx is cancelled and resumes on the next Start.
+func (e *Engine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+
+	status := models.NodeStatusProvisioning
+	nodes, _, err := e.nodes.List(services.NodeListOptions{Status: &status})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := e.Provision(node.ID, true); err != nil {
+			e.logger.WithFields(map[string]interface{}{
+				"node_id": node.ID,
+				"error":   err,
+			}).Error("Failed to resume node provisioning")
+		}
+	}
+	return nil
+}
+
+// Wait blocks until all running provisioning has stopped
+func (e *Engine) Wait() {
+	e.wg.Wait()
+}
+
+// Provision starts a provisioning job for a node in the background. With
+// resume, steps that succeeded in the node's previous job are skipped;
+// otherwise every step runs again.
+func (e *Engine) Provision(nodeID uint, resume bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.ctx == nil {
+		return fmt.Errorf("provisioning engine is not started")
+	}
+	if e.running[nodeID] {
+		e.logger.WithField("node_id", nodeID).Info("Node provisioning already in progress")
+		return nil
+	}
+
+	node, err := e.nodes.GetByID(nodeID, false)
+	if err != nil {
+		return err
+	}
+	plan := planFor(node.Role)
+	record, err := e.createJob(node, plan, resume)
+	if err != nil {
+		return err
+	}
+
+	e.running[nodeID] = true
+	e.wg.Add(1)
+	go e.run(e.ctx, record, plan)
+	return nil
+}
+
+// createJob records a job running the plan, carrying over the steps that
+// succeeded in the node's previous job when resuming the same plan
+func (e *Engine) createJob(node *models.Node, plan []step, resume bool) (*models.ProvisioningJob, error) {
+	steps := make([]models.ProvisioningStep, len(plan))
+	for i, st := range plan {
+		steps[i] = models.ProvisioningStep{
+			Position: i,
+			Name:     st.name,
+			Status:   models.StepStatusPending,
+		}
+	}
+
+	if resume {
+		previous, err := e.jobs.Latest(node.ID, models.JobTypeProvision)
+		if err != nil && !services.IsNotFound(err) {
+			return nil, err
+		}
+		if previous != nil && sameCluster(previous.ClusterID, node.ClusterID) && matchesPlan(previous.Steps, plan) {
+			for i := range steps {
+				if previous.Steps[i].IsDone() {
+					steps[i].Status = models.StepStatusSkipped
+				}
+			}
+		}
+	}
+
+	return e.jobs.Create(node.ID, node.ClusterID, models.JobTypeProvision, steps)
+}
+
+func matchesPlan(steps []models.ProvisioningStep, plan []step) bool {
+	if len(steps) != len(plan) {
+		return false
+	}
+	for i := range plan {
+		if steps[i].Name != plan[i].name {
+			return false
+		}
+	}
+	return true
+}
+
+func sameCluster(a, b *uint) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// run executes the steps of a job that have not already succeeded
+func (e *Engine) run(ctx context.Context, record *models.ProvisioningJob, plan []step) {
+	nodeID := record.NodeID
+	defer e.wg.Done()
+	defer func() {
+		e.mu.Lock()
+		delete(e.running, nodeID)
+		e.mu.Unlock()
+	}()
+
+	log := e.logger.WithFields(map[string]interface{}{
+		"node_id": nodeID,
+		"job_id":  record.ID,
+	})
+
+	j, err := e.newJob(record)
+	if err != nil {
+		e.fail(record, err)
+		return
+	}
+	defer j.close()
+
+	for i, st := range plan {
+		step := &record.Steps[i]
+		if step.IsDone() {
+			continue
+		}
+
+		if err := e.jobs.StartStep(record, step); err != nil {
+			log.WithError(err).Error("Failed to record provisioning step")
+		}
+		log.WithField("step", st.name).Info("Running provisioning step")
+
+		j.beginStep(st.name)
+		err := st.run(ctx, j)
+		stdout, stderr := j.endStep()
+
+		if ctx.Err() != nil {
+			// Interrupted by shutdown; the step runs again on resume
+			e.finishStep(step, models.StepStatusPending, "interrupted by controller shutdown", stdout, stderr)
+			e.finishJob(record, models.JobStatusInterrupted, "")
+			log.WithField("step", st.name).Info("Node provisioning interrupted")
+			return
+		}
+		if err != nil {
+			message := j.redact(err.Error())
+			e.finishStep(step, models.StepStatusFailed, message, stdout, stderr)
+			e.fail(record, fmt.Errorf("step %s failed: %s", st.name, message))
+			return
+		}
+		e.finishStep(step, models.StepStatusSucceeded, "", stdout, stderr)
+	}
+
+	ready := models.NodeStatusReady
+	if _, err := e.nodes.Update(nodeID, services.UpdateNodeRequest{Status: &ready}); err != nil {
+		log.WithError(err).Error("Failed to mark provisioned node ready")
+		e.finishJob(record, models.JobStatusFailed, "failed to mark node ready")
+		return
+	}
+	e.finishJob(record, models.JobStatusSucceeded, "")
+	log.WithField("node_name", j.node.NodeName).Info("Node provisioned successfully")
+}
+
+func (e *Engine) finishStep(step *models.ProvisioningStep, status models.StepStatus, message, stdout, stderr string) {
+	if err := e.jobs.FinishStep(step, status, message, stdout, stderr); err != nil {
+		e.logger.WithFields(map[string]interface{}{
+			"job_id": step.JobID,
+			"step":   step.Name,
+			"error":  err,
+		}).Error("Failed to record provisioning step")
+	}
+}
+
+func (e *Engine) finishJob(record *models.ProvisioningJob, status models.JobStatus, message string) {
+	if err := e.jobs.Finish(record, status, message); err != nil {
+		e.logger.WithFields(map[string]interface{}{
+			"job_id": record.ID,
+			"error":  err,
+		}).Error("Failed to record provisioning job outcome")
+	}
+}
+
+// fail marks a job and its node's provisioning as failed
+func (e *Engine) fail(record *models.ProvisioningJob, cause error) {
+	e.logger.WithFields(map[string]interface{}{
+		"node_id": record.NodeID,
+		"job_id":  record.ID,
+		"error":   cause,
+	}).Error("Node provisioning failed")
+
+	e.finishJob(record, models.JobStatusFailed, cause.Error())
+
+	failed := models.NodeStatusFailed
+	if _, err := e.nodes.Update(record.NodeID, services.UpdateNodeRequest{Status: &failed}); err != nil {
+		e.logger.WithError(err).WithField("node_id", record.NodeID).Error("Failed to mark node provisioning failed")
+	}
+}
+
+// readyMaster returns another ready master in the node's cluster, or nil if
+// there is none
+func (e *Engine) readyMaster(node *models.Node) (*models.Node, error) {
+	role := models.NodeRoleMaster
+	status := models.NodeStatusReady
+	masters, _, err := e.nodes.List(services.NodeListOptions{
+		ClusterID: node.ClusterID,
+		Role:      &role,
+		Status:    &status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cluster master: %w", err)
+	}
+	for i := range masters {
+		if masters[i].ID != node.ID {
+			return &masters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// lockMasters waits until no other master of the cluster is installing k3s
+// and returns the function releasing the cluster to the next one
+func (e *Engine) lockMasters(ctx context.Context, clusterID uint) (func(), error) {
+	e.mu.Lock()
+	lock, ok := e.masters[clusterID]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		e.masters[clusterID] = lock
+	}
+	e.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// job is the state of one provisioning run of a node
+type job struct {
+	engine  *Engine
+	record  *models.ProvisioningJob
+	node    *models.Node
+	cluster *models.Cluster
+	master  *models.Node // ready master the node joins
+	token   string       // cluster join token, fetched on first use
+	unlock  func()       // releases the cluster's masters lock, if held
+
+	sessions map[uint]Session
+
+	// Output of the running step
+	step   string
+	stdout *tailBuffer
+	stderr *tailBuffer
+}
+
+func (e *Engine) newJob(record *models.ProvisioningJob) (*job, error) {
+	node, err := e.nodes.GetByID(record.NodeID, false)
+	if err != nil {
+		return nil, err
+	}
+	if node.ClusterID == nil {
+		return nil, fmt.Errorf("node is not assigned to a cluster")
+	}
+	cluster, err := e.clusters.GetByID(*node.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job{
+		engine:   e,
+		record:   record,
+		node:     node,
+		cluster:  cluster,
+		sessions: make(map[uint]Session),
+	}, nil
+}
+
+func (j *job) close() {
+	for _, session := range j.sessions {
+		session.Close()
+	}
+	if j.unlock != nil {
+		j.unlock()
+	}
+}
+
+// session returns a session to a node, reusing one already open
+func (j *job) session(ctx context.Context, node *models.Node) (Session, error) {
+	if session, ok := j.sessions[node.ID]; ok {
+		return session, nil
+	}
+	session, err := j.engine.dialer.Dial(ctx, node.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+	j.sessions[node.ID] = session
+	return session, nil
+}
+
+func (j *job) nodeSession(ctx context.Context) (Session, error) {
+	return j.session(ctx, j.node)
+}
+
+func (j *job) masterSession(ctx context.Context) (Session, error) {
+	master, err := j.joinMaster()
+	if err != nil {
+		return nil, err
+	}
+	return j.session(ctx, master)
+}
+
+// joinMaster returns the ready master the node joins through
+func (j *job) joinMaster() (*models.Node, error) {
+	if j.master != nil {
+		return j.master, nil
+	}
+	master, err := j.engine.readyMaster(j.node)
+	if err != nil {
+		return nil, err
+	}
+	if master == nil {
+		return nil, fmt.Errorf("cluster %s has no ready master node", j.cluster.Name)
+	}
+	j.master = master
+	return master, nil
+}
+
+// beginStep starts collecting the output of a step
+func (j *job) beginStep(name string) {
+	j.step = name
+	j.stdout = newTailBuffer(services.StepOutputExcerpt)
+	j.stderr = newTailBuffer(services.StepOutputExcerpt)
+}
+
+// endStep returns excerpts of the output of the step that ran
+func (j *job) endStep() (string, string) {
+	return j.stdout.String(), j.stderr.String()
+}
+
+// log records and publishes a line of output from the running step. Commands
+// are recorded with the step's stdout, prefixed like a shell prompt.
+func (j *job) log(stream, line string) {
+	line = j.redact(line)
+	if j.record != nil {
+		j.engine.jobs.PublishLog(j.record, j.step, stream, line)
+	}
+
+	switch stream {
+	case streamCommand:
+		j.stdout.Write([]byte("$ " + line + "\n"))
+	case streamStdout:
+		j.stdout.Write([]byte(line + "\n"))
+	case streamStderr:
+		j.stderr.Write([]byte(line + "\n"))
+	}
+}
+
+// exec runs a command in a session and returns its trimmed stdout, recording
+// its output with the running step
+func (j *job) exec(ctx context.Context, open func(context.Context) (Session, error), command string) (string, error) {
+	return j.execCommand(ctx, open, command, true)
+}
+
+// execSecret runs a command whose output must not be recorded, such as one
+// reading credentials
+func (j *job) execSecret(ctx context.Context, open func(context.Context) (Session, error), command string) (string, error) {
+	return j.execCommand(ctx, open, command, false)
+}
+
+func (j *job) execCommand(ctx context.Context, open func(context.Context) (Session, error), command string, record bool) (string, error) {
+	session, err := open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, j.engine.config.CommandTimeout)
+	defer cancel()
+
+	j.log(streamCommand, command)
+
+	var result *CommandResult
+	if record {
+		stdout := newLineWriter(func(line string) { j.log(streamStdout, line) })
+		stderr := newLineWriter(func(line string) { j.log(streamStderr, line) })
+		result, err = session.Run(ctx, command, stdout, stderr)
+		stdout.Flush()
+		stderr.Flush()
+	} else {
+		result, err = session.Run(ctx, command, nil, nil)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// joinToken returns the cluster join token, reading it from the join master
+func (j *job) joinToken(ctx context.Context) (string, error) {
+	if j.token != "" {
+		return j.token, nil
+	}
+	token, err := j.execSecret(ctx, j.masterSession, "sudo cat "+k3sTokenPath)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("master returned an empty join token")
+	}
+	j.token = token
+	return token, nil
+}
+
+// serviceActive reports whether a systemd unit is already running on the node
+func (j *job) serviceActive(ctx context.Context, unit string) bool {
+	_, err := j.exec(ctx, j.nodeSession, "systemctl is-active --quiet "+unit)
+	return err == nil
+}
+
+// serverURL returns the API server URL agents and joining servers use
+func (j *job) serverURL(master *models.Node) string {
+	if j.cluster.MasterEndpoint != "" {
+		return j.cluster.MasterEndpoint
+	}
+	return fmt.Sprintf("https://%s:%d", master.IPAddress, k3sAPIPort)
+}
+
+// installCommand builds the k3s install script invocation
+func (j *job) installCommand(env []string, args []string) string {
+	config := j.engine.config
+	if config.K3sVersion != "" {
+		env = append([]string{"INSTALL_K3S_VERSION=" + shellQuote(config.K3sVersion)}, env...)
+	}
+
+	command := "curl -sfL " + shellQuote(config.InstallScriptURL) + " | sudo"
+	if len(env) > 0 {
+		command += " env " + strings.Join(env, " ")
+	}
+	return command + " sh -s - " + strings.Join(args, " ")
+}
+
+// redact removes the join token from text that is logged or persisted
+func (j *job) redact(text string) string {
+	if j.token == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, j.token, "[redacted]")
+}
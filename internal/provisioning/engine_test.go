@@ -0,0 +1,501 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+const (
+	testJoinToken   = "K10abc::server:s3cret"
+	testKubeVersion = "v1.30.4+k3s1"
+	testKubeConfig  = "apiVersion: v1\nclusters:\n- cluster:\n    server: https://127.0.0.1:6443\n"
+	testK3sSHA256   = "6c2e21a5a2ae0a15e7bd2a5f7a0e4a1c3fb8a9cf4f1b0f5e1d6d1a2b3c4d5e6f"
+)
+
+// fakeK3s simulates k3s across the fake hosts of a cluster
+type fakeK3s struct {
+	mu           sync.Mutex
+	registered   map[string]bool
+	failInstalls map[string]int // remaining install failures by hostname
+	tampered     bool           // release binaries do not match their checksums
+}
+
+func newFakeK3s() *fakeK3s {
+	return &fakeK3s{
+		registered:   make(map[string]bool),
+		failInstalls: make(map[string]int),
+	}
+}
+
+// host returns the command handler of a node with the given hostname
+func (k *fakeK3s) host(hostname string) commandHandler {
+	var installed, server bool
+	version, staged := testKubeVersion, ""
+
+	return func(command string) (string, string, int) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		switch {
+		case command == "uname -m":
+			return "aarch64\n", "", 0
+		case command == "hostname":
+			return hostname + "\n", "", 0
+		case command == "sudo -n true":
+			return "", "", 0
+		case command == "command -v curl":
+			return "/usr/bin/curl\n", "", 0
+		case strings.HasPrefix(command, "systemctl is-active"):
+			if installed {
+				return "", "", 0
+			}
+			return "", "", 3
+		case strings.HasPrefix(command, "curl -sfL ") && strings.Contains(command, "/sha256sum-"):
+			return testK3sSHA256 + "  k3s-arm64\n" + strings.Repeat("0", 64) + "  k3s-arm64.tar\n", "", 0
+		case strings.HasPrefix(command, "echo ") && strings.HasSuffix(command, " | sha256sum -c -"):
+			if k.tampered || !strings.Contains(command, testK3sSHA256) {
+				return "", "sha256sum: WARNING: 1 computed checksum did NOT match\n", 1
+			}
+			return k3sBinaryPath + ".upgrade: OK\n", "", 0
+		case strings.HasPrefix(command, "sudo rm -f "):
+			staged = ""
+			return "", "", 0
+		case strings.HasPrefix(command, "curl -sfL"):
+			if k.failInstalls[hostname] > 0 {
+				k.failInstalls[hostname]--
+				return "", "[ERROR] Failed to download k3s\n", 1
+			}
+			installed = true
+			server = strings.Contains(command, " sh -s - server")
+			k.registered[hostname] = true
+			return "[INFO] systemd: Starting k3s\n", "", 0
+		case command == k3sBinaryPath+" --version" && installed:
+			return "k3s version " + version + " (faeaf1b0)\n", "", 0
+		case strings.HasPrefix(command, "sudo curl -sfL -o "):
+			// Release binaries are downloaded from .../<version>/<asset>
+			parts := strings.Split(strings.Trim(strings.Fields(command)[5], "'"), "/")
+			staged = parts[len(parts)-2]
+			return "", "", 0
+		case strings.HasPrefix(command, "sudo chmod 0755 "), strings.HasPrefix(command, "sudo systemctl restart "):
+			return "", "", 0
+		case strings.HasPrefix(command, "sudo mv ") && staged != "":
+			version, staged = staged, ""
+			return "", "", 0
+		case command == "sudo cat "+k3sTokenPath && installed && server:
+			return testJoinToken + "\n", "", 0
+		case command == "sudo cat "+k3sKubeConfigPath && installed && server:
+			return testKubeConfig, "", 0
+		case strings.HasPrefix(command, "sudo k3s kubectl get node ") && installed && server:
+			name := strings.Trim(strings.Fields(command)[5], "'")
+			if k.registered[name] {
+				return "True " + testKubeVersion, "", 0
+			}
+			return "", fmt.Sprintf("Error from server (NotFound): nodes %q not found\n", name), 1
+		}
+		return "", "sh: command not found\n", 127
+	}
+}
+
+type testEnv struct {
+	db       *storage.Database
+	nodes    *services.NodeService
+	clusters *services.ClusterService
+	jobs     *services.JobService
+	bus      *events.Bus
+	cluster  *models.Cluster
+	config   Config
+	k3s      *fakeK3s
+
+	// clientKey is the key the engine authenticates to fake hosts with
+	clientKey ssh.PublicKey
+
+	// The worker answers on 127.0.0.1 and the master on 127.0.0.2, sharing a port
+	worker *fakeHost
+	master *fakeHost
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	keyFile, clientKey := writeClientKey(t)
+	k3s := newFakeK3s()
+	worker := startFakeHost(t, "127.0.0.1:0", clientKey, k3s.host("pi-worker"))
+	master := startFakeHost(t, fmt.Sprintf("127.0.0.2:%d", worker.port()), clientKey, k3s.host("pi-master"))
+
+	clusters := services.NewClusterService(db, logger.Default())
+	cluster, err := clusters.Create(services.CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+
+	bus := events.NewBus()
+	jobs := services.NewJobService(db, logger.Default())
+	jobs.SetEventBus(bus)
+
+	return &testEnv{
+		db:        db,
+		nodes:     services.NewNodeService(db, logger.Default()),
+		clusters:  clusters,
+		jobs:      jobs,
+		bus:       bus,
+		cluster:   cluster,
+		k3s:       k3s,
+		clientKey: clientKey,
+		worker:    worker,
+		master:    master,
+		config: Config{
+			SSHPort:               worker.port(),
+			SSHKeyFile:            keyFile,
+			InsecureIgnoreHostKey: true,
+			CommandTimeout:        5 * time.Second,
+			RegistrationTimeout:   2 * time.Second,
+			PollInterval:          10 * time.Millisecond,
+		},
+	}
+}
+
+// startEngine starts an engine as the controller would on boot
+func (env *testEnv) startEngine(t *testing.T) *Engine {
+	dialer, err := NewSSHDialer(env.config)
+	require.NoError(t, err)
+
+	engine := New(env.config, dialer, env.nodes, env.clusters, env.jobs, logger.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		engine.Wait()
+	})
+	require.NoError(t, engine.Start(ctx))
+	env.nodes.SetProvisioner(engine)
+	return engine
+}
+
+func (env *testEnv) createNode(t *testing.T, name, ip string, role models.NodeRole, mac string) *models.Node {
+	node, err := env.nodes.Create(services.CreateNodeRequest{
+		Name:       name,
+		IPAddress:  ip,
+		MACAddress: mac,
+		Role:       role,
+		CPUCores:   4,
+		Memory:     4096,
+	})
+	require.NoError(t, err)
+	return node
+}
+
+// latestJob returns the node's most recent provisioning job
+func (env *testEnv) latestJob(t *testing.T, nodeID uint) *models.ProvisioningJob {
+	job, err := env.jobs.Latest(nodeID, models.JobTypeProvision)
+	require.NoError(t, err)
+	return job
+}
+
+// stepStatuses returns the steps of the node's most recent job by name
+func (env *testEnv) stepStatuses(t *testing.T, nodeID uint) map[string]models.ProvisioningStep {
+	job := env.latestJob(t, nodeID)
+	byName := make(map[string]models.ProvisioningStep, len(job.Steps))
+	for _, step := range job.Steps {
+		byName[step.Name] = step
+	}
+	return byName
+}
+
+func TestEngine_ProvisionMasterThenWorker(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+
+	provisioned, err := env.nodes.GetByID(master.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusReady, provisioned.Status)
+	assert.Equal(t, "pi-master", provisioned.NodeName)
+	assert.Equal(t, "aarch64", provisioned.Architecture)
+	assert.Equal(t, testKubeVersion, provisioned.KubeVersion)
+
+	job := env.latestJob(t, master.ID)
+	assert.Equal(t, models.JobStatusSucceeded, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	require.Len(t, job.Steps, len(serverPlan))
+	for i, step := range job.Steps {
+		assert.Equal(t, serverPlan[i].name, step.Name)
+		assert.Equal(t, models.StepStatusSucceeded, step.Status)
+		assert.NotNil(t, step.FinishedAt)
+	}
+	assert.Contains(t, job.Steps[0].Stdout, "$ uname -m\naarch64\n")
+	assert.NotContains(t, job.Steps[2].Stdout, "apiVersion", "the kubeconfig must not be recorded")
+
+	cluster, err := env.clusters.GetByID(env.cluster.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.2:6443", cluster.MasterEndpoint)
+	kubeConfig, err := base64.StdEncoding.DecodeString(cluster.KubeConfig)
+	require.NoError(t, err)
+	assert.Contains(t, string(kubeConfig), "server: https://127.0.0.2:6443")
+	assert.Contains(t, installCommand(t, env.master), "--cluster-init")
+
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	engine.Wait()
+
+	provisioned, err = env.nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusReady, provisioned.Status)
+	assert.Equal(t, "pi-worker", provisioned.NodeName)
+
+	install := installCommand(t, env.worker)
+	assert.Contains(t, install, "K3S_URL='https://127.0.0.2:6443'")
+	assert.Contains(t, install, "K3S_TOKEN='"+testJoinToken+"'")
+	assert.Contains(t, install, "sh -s - agent --node-name 'pi-worker'")
+
+	steps := env.stepStatuses(t, worker.ID)
+	for _, step := range steps {
+		assert.NotContains(t, step.Stdout, testJoinToken, "the join token must not be recorded")
+	}
+	assert.Contains(t, steps[stepInstallAgent].Stdout, "K3S_TOKEN='[redacted]'")
+}
+
+func TestEngine_ConcurrentMastersInitOnce(t *testing.T) {
+	env := setupTestEnv(t)
+	second := startFakeHost(t, fmt.Sprintf("127.0.0.3:%d", env.config.SSHPort), env.clientKey, env.k3s.host("pi-master-2"))
+	engine := env.startEngine(t)
+
+	first := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+	other := env.createNode(t, "master-2", "127.0.0.3", models.NodeRoleMaster, "aa:bb:cc:dd:ee:03")
+	require.NoError(t, env.nodes.Provision(first.ID, env.cluster.ID))
+	require.NoError(t, env.nodes.Provision(other.ID, env.cluster.ID))
+	engine.Wait()
+
+	for _, id := range []uint{first.ID, other.ID} {
+		node, err := env.nodes.GetByID(id, false)
+		require.NoError(t, err)
+		assert.Equal(t, models.NodeStatusReady, node.Status, node.Name)
+	}
+
+	inits := 0
+	for _, host := range []*fakeHost{env.master, second} {
+		if strings.Contains(installCommand(t, host), "--cluster-init") {
+			inits++
+		} else {
+			assert.Contains(t, installCommand(t, host), "--server")
+		}
+	}
+	assert.Equal(t, 1, inits, "only one master initialises the datastore")
+}
+
+func TestEngine_WorkerWithoutMasterFails(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	engine.Wait()
+
+	node, err := env.nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusFailed, node.Status)
+
+	job := env.latestJob(t, worker.ID)
+	assert.Equal(t, models.JobStatusFailed, job.Status)
+	assert.Contains(t, job.Error, "step fetch-join-token failed")
+
+	steps := env.stepStatuses(t, worker.ID)
+	assert.Equal(t, models.StepStatusSucceeded, steps[stepPreflight].Status)
+	assert.Equal(t, models.StepStatusFailed, steps[stepFetchJoinToken].Status)
+	assert.Contains(t, steps[stepFetchJoinToken].Error, "no ready master")
+	assert.Equal(t, models.StepStatusPending, steps[stepInstallAgent].Status)
+}
+
+func TestEngine_RetryResumesFailedStep(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+
+	env.k3s.failInstalls["pi-worker"] = 1
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	engine.Wait()
+
+	node, err := env.nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusFailed, node.Status)
+
+	failedJob := env.latestJob(t, worker.ID)
+	failed := env.stepStatuses(t, worker.ID)[stepInstallAgent]
+	assert.Equal(t, models.StepStatusFailed, failed.Status)
+	assert.Contains(t, failed.Error, "Failed to download k3s")
+	assert.Contains(t, failed.Stderr, "[ERROR] Failed to download k3s")
+	assert.NotContains(t, failed.Error, testJoinToken, "the join token must not be persisted")
+
+	// Provisioning the failed node again continues from the failed step
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	engine.Wait()
+
+	node, err = env.nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusReady, node.Status)
+
+	steps := env.stepStatuses(t, worker.ID)
+	assert.Equal(t, models.StepStatusSkipped, steps[stepPreflight].Status)
+	assert.Equal(t, models.StepStatusSkipped, steps[stepFetchJoinToken].Status)
+	assert.Equal(t, models.StepStatusSucceeded, steps[stepInstallAgent].Status)
+	assert.Empty(t, steps[stepInstallAgent].Error)
+
+	// The failed job is kept for inspection
+	jobs, total, err := env.jobs.List(services.JobListOptions{NodeID: worker.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, failedJob.ID, jobs[1].ID)
+	assert.Equal(t, models.JobStatusFailed, jobs[1].Status)
+}
+
+func TestEngine_ResumesAfterRestart(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+
+	// A previous controller assigned the worker and finished preflight before stopping
+	env.nodes.SetProvisioner(nil)
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	nodeName := "pi-worker"
+	_, err := env.nodes.Update(worker.ID, services.UpdateNodeRequest{NodeName: &nodeName})
+	require.NoError(t, err)
+	steps := make([]models.ProvisioningStep, len(agentPlan))
+	for i, st := range agentPlan {
+		steps[i] = models.ProvisioningStep{Position: i, Name: st.name, Status: models.StepStatusPending}
+	}
+	steps[0].Status = models.StepStatusSucceeded
+	previous, err := env.jobs.Create(worker.ID, &env.cluster.ID, models.JobTypeProvision, steps)
+	require.NoError(t, err)
+
+	restarted := env.startEngine(t)
+	restarted.Wait()
+
+	node, err := env.nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusReady, node.Status)
+	assert.NotContains(t, env.worker.ran(), "uname -m", "completed steps are not run again")
+	assert.Equal(t, models.StepStatusSkipped, env.stepStatuses(t, worker.ID)[stepPreflight].Status)
+
+	previous, err = env.jobs.Get(worker.ID, previous.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusInterrupted, previous.Status, "the job cut short by the restart is marked interrupted")
+}
+
+func TestEngine_PublishesOutput(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+
+	sub := env.bus.Subscribe(events.OfType(events.TypeProvisioningLog), 1024)
+	defer sub.Close()
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	engine.Wait()
+	job := env.latestJob(t, worker.ID)
+
+	var lines []events.ProvisioningLog
+	for len(sub.C()) > 0 {
+		lines = append(lines, (<-sub.C()).Payload.(events.ProvisioningLog))
+	}
+	require.NotEmpty(t, lines)
+	var sawArch, sawInstall bool
+	for _, line := range lines {
+		assert.Equal(t, job.ID, line.JobID)
+		assert.Equal(t, worker.ID, line.NodeID)
+		assert.NotContains(t, line.Line, testJoinToken, "the join token must not be published")
+
+		sawArch = sawArch || (line.Step == stepPreflight && line.Stream == "stdout" && line.Line == "aarch64")
+		sawInstall = sawInstall || (line.Step == stepInstallAgent && line.Stream == "command" && strings.HasPrefix(line.Line, "curl -sfL"))
+	}
+	assert.True(t, sawArch, "command output is published")
+	assert.True(t, sawInstall, "commands are published")
+}
+
+func TestProvision_ConflictingCluster(t *testing.T) {
+	env := setupTestEnv(t)
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+	other, err := env.clusters.Create(services.CreateClusterRequest{Name: "lab"})
+	require.NoError(t, err)
+
+	require.NoError(t, env.nodes.Provision(worker.ID, env.cluster.ID))
+	err = env.nodes.Provision(worker.ID, other.ID)
+	assert.True(t, services.IsConflict(err))
+}
+
+func TestSSHDialer_HostKeyChecking(t *testing.T) {
+	keyFile, clientKey := writeClientKey(t)
+	host := startFakeHost(t, "127.0.0.1:0", clientKey, func(string) (string, string, int) { return "ok\n", "", 0 })
+
+	// A known_hosts entry for a different key is rejected
+	_, otherKey := writeClientKey(t)
+	knownHosts := t.TempDir() + "/known_hosts"
+	address := net.JoinHostPort("127.0.0.1", fmt.Sprint(host.port()))
+	writeKnownHosts(t, knownHosts, address, otherKey)
+
+	dialer, err := NewSSHDialer(Config{SSHPort: host.port(), SSHKeyFile: keyFile, KnownHostsFile: knownHosts})
+	require.NoError(t, err)
+	_, err = dialer.Dial(context.Background(), "127.0.0.1")
+	assert.Error(t, err)
+
+	writeKnownHosts(t, knownHosts, address, host.hostKey.PublicKey())
+	dialer, err = NewSSHDialer(Config{SSHPort: host.port(), SSHKeyFile: keyFile, KnownHostsFile: knownHosts})
+	require.NoError(t, err)
+	session, err := dialer.Dial(context.Background(), "127.0.0.1")
+	require.NoError(t, err)
+	defer session.Close()
+
+	var output strings.Builder
+	result, err := session.Run(context.Background(), "echo ok", &output, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok\n", result.Stdout)
+	assert.Equal(t, "ok\n", output.String())
+
+	_, err = NewSSHDialer(Config{SSHKeyFile: keyFile})
+	assert.Error(t, err, "host key checking must be configured")
+}
+
+func installCommand(t *testing.T, host *fakeHost) string {
+	for _, command := range host.ran() {
+		if strings.HasPrefix(command, "curl -sfL") {
+			return command
+		}
+	}
+	t.Fatal("k3s was not installed")
+	return ""
+}
+
+func writeKnownHosts(t *testing.T, path, address string, key ssh.PublicKey) {
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, key)
+	require.NoError(t, os.WriteFile(path, []byte(line+"\n"), 0600))
+}
@@ -0,0 +1,75 @@
+package provisioning
+
+import (
+	"bytes"
+	"sync"
+)
+
+// truncatedMarker prefixes an output excerpt that lost its beginning
+const truncatedMarker = "[truncated]\n"
+
+// lineWriter splits written output into lines and passes each to a callback.
+// A trailing partial line is held until more output or Flush completes it.
+type lineWriter struct {
+	emit    func(line string)
+	pending []byte
+}
+
+func newLineWriter(emit func(line string)) *lineWriter {
+	return &lineWriter{emit: emit}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(bytes.TrimRight(w.pending[:i], "\r")))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits a trailing partial line
+func (w *lineWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.emit(string(w.pending))
+		w.pending = nil
+	}
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func newTailBuffer(limit int) *tailBuffer {
+	return &tailBuffer{limit: limit}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, p...)
+	if over := len(b.data) - b.limit; over > 0 {
+		b.data = append(b.data[:0], b.data[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.truncated {
+		return truncatedMarker + string(b.data)
+	}
+	return string(b.data)
+}
@@ -0,0 +1,186 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshHandshakeTimeout bounds connecting and authenticating to a node
+const sshHandshakeTimeout = 30 * time.Second
+
+// CommandResult is the output of a command run on a node
+type CommandResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// CommandError is returned when a command exits with a non-zero status
+type CommandError struct {
+	Command string
+	Result  *CommandResult
+}
+
+func (e *CommandError) Error() string {
+	detail := strings.TrimSpace(e.Result.Stderr)
+	if detail == "" {
+		detail = strings.TrimSpace(e.Result.Stdout)
+	}
+	if detail == "" {
+		return fmt.Sprintf("command %q exited with status %d", e.Command, e.Result.ExitCode)
+	}
+	return fmt.Sprintf("command %q exited with status %d: %s", e.Command, e.Result.ExitCode, detail)
+}
+
+// Session runs shell commands on a node
+type Session interface {
+	// Run executes a command and waits for it to exit. Output is also copied
+	// to stdout and stderr as it arrives when they are not nil. A non-zero
+	// exit status is reported as a *CommandError alongside the result.
+	Run(ctx context.Context, command string, stdout, stderr io.Writer) (*CommandResult, error)
+	Close() error
+}
+
+// Dialer opens command sessions to nodes
+type Dialer interface {
+	Dial(ctx context.Context, host string) (Session, error)
+}
+
+// SSHDialer opens sessions over SSH with public key authentication
+type SSHDialer struct {
+	config *ssh.ClientConfig
+	port   int
+}
+
+// NewSSHDialer creates a dialer from the provisioning SSH settings. Host keys
+// are checked against the known hosts file unless checking is disabled.
+func NewSSHDialer(config Config) (*SSHDialer, error) {
+	config = applyDefaults(config)
+
+	key, err := os.ReadFile(config.SSHKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key: %w", err)
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case config.InsecureIgnoreHostKey:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	case config.KnownHostsFile != "":
+		hostKeyCallback, err = knownhosts.New(config.KnownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known hosts: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("a known hosts file is required unless host key checking is disabled")
+	}
+
+	return &SSHDialer{
+		config: &ssh.ClientConfig{
+			User:            config.SSHUser,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sshHandshakeTimeout,
+		},
+		port: config.SSHPort,
+	}, nil
+}
+
+// Dial connects and authenticates to a node
+func (d *SSHDialer) Dial(ctx context.Context, host string) (Session, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(d.port))
+
+	dialer := net.Dialer{Timeout: d.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	// The handshake does not take a context, so bound it with a deadline
+	deadline := time.Now().Add(d.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, address, d.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH handshake with %s failed: %w", address, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return &sshSession{client: ssh.NewClient(clientConn, chans, reqs)}, nil
+}
+
+// sshSession runs each command in its own SSH session on a shared connection
+type sshSession struct {
+	client *ssh.Client
+}
+
+func (s *sshSession) Run(ctx context.Context, command string, stdoutCopy, stderrCopy io.Writer) (*CommandResult, error) {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = teeTo(&stdout, stdoutCopy)
+	session.Stderr = teeTo(&stderr, stderrCopy)
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(command) }()
+
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		session.Close()
+		<-done
+		return nil, ctx.Err()
+	case err = <-done:
+	}
+
+	result := &CommandResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitStatus()
+		return result, &CommandError{Command: command, Result: result}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("command %q failed: %w", command, err)
+	}
+	return result, nil
+}
+
+func (s *sshSession) Close() error {
+	return s.client.Close()
+}
+
+// teeTo returns a writer to buf that also copies to w when it is not nil
+func teeTo(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// shellQuote quotes a value for use as a single POSIX shell word
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
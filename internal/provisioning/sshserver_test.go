@@ -0,0 +1,145 @@
+package provisioning
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// commandHandler answers a command run on a fake host
+type commandHandler func(command string) (stdout, stderr string, exitCode int)
+
+// fakeHost is a local SSH server standing in for a node. Commands are
+// answered by a handler instead of being executed.
+type fakeHost struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+
+	mu       sync.Mutex
+	handler  commandHandler
+	commands []string
+}
+
+// startFakeHost listens on address and accepts the client key
+func startFakeHost(t *testing.T, address string, clientKey ssh.PublicKey, handler commandHandler) *fakeHost {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	host := &fakeHost{listener: listener, hostKey: hostKey, handler: handler}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, io.EOF
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go host.serve(conn, config)
+		}
+	}()
+	return host
+}
+
+func (h *fakeHost) port() int {
+	return h.listener.Addr().(*net.TCPAddr).Port
+}
+
+// ran returns the commands run on the host so far
+func (h *fakeHost) ran() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.commands...)
+}
+
+func (h *fakeHost) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go h.session(channel, requests)
+	}
+}
+
+func (h *fakeHost) session(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			req.Reply(false, nil)
+			continue
+		}
+		var exec struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &exec); err != nil {
+			req.Reply(false, nil)
+			continue
+		}
+		req.Reply(true, nil)
+
+		h.mu.Lock()
+		h.commands = append(h.commands, exec.Command)
+		handler := h.handler
+		h.mu.Unlock()
+
+		stdout, stderr, exitCode := handler(exec.Command)
+		io.WriteString(channel, stdout)
+		io.WriteString(channel.Stderr(), stderr)
+		channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(exitCode)}))
+		return
+	}
+}
+
+// writeClientKey generates a client key, writes it where NewSSHDialer can
+// read it and returns its public half
+func writeClientKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0600))
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return path, sshPub
+}
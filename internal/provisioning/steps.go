@@ -0,0 +1,228 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// Step names, persisted with each provisioning job
+const (
+	stepPreflight       = "preflight"
+	stepFetchJoinToken  = "fetch-join-token"
+	stepInstallServer   = "install-server"
+	stepInstallAgent    = "install-agent"
+	stepFetchKubeConfig = "fetch-kubeconfig"
+	stepWaitReady       = "wait-ready"
+)
+
+// Paths and ports of a k3s installation
+const (
+	k3sAPIPort        = 6443
+	k3sTokenPath      = "/var/lib/rancher/k3s/server/node-token"
+	k3sKubeConfigPath = "/etc/rancher/k3s/k3s.yaml"
+)
+
+// supportedArchitectures are the `uname -m` values k3s publishes binaries for
+var supportedArchitectures = map[string]bool{
+	"aarch64": true,
+	"arm64":   true,
+	"armv7l":  true,
+	"x86_64":  true,
+}
+
+// step is one idempotent unit of provisioning. Steps may run again after a
+// failure or restart, so each must tolerate its work having already been done.
+type step struct {
+	name string
+	run  func(ctx context.Context, j *job) error
+}
+
+var (
+	serverPlan = []step{
+		{stepPreflight, preflight},
+		{stepInstallServer, installServer},
+		{stepFetchKubeConfig, fetchKubeConfig},
+		{stepWaitReady, waitReady},
+	}
+	agentPlan = []step{
+		{stepPreflight, preflight},
+		{stepFetchJoinToken, fetchJoinToken},
+		{stepInstallAgent, installAgent},
+		{stepWaitReady, waitReady},
+	}
+)
+
+// planFor returns the steps that provision a node in its role
+func planFor(role models.NodeRole) []step {
+	if role == models.NodeRoleMaster {
+		return serverPlan
+	}
+	return agentPlan
+}
+
+// preflight checks the node can run k3s and records its hostname, which
+// becomes the Kubernetes node name
+func preflight(ctx context.Context, j *job) error {
+	arch, err := j.exec(ctx, j.nodeSession, "uname -m")
+	if err != nil {
+		return err
+	}
+	if !supportedArchitectures[arch] {
+		return fmt.Errorf("unsupported architecture %q", arch)
+	}
+
+	hostname, err := j.exec(ctx, j.nodeSession, "hostname")
+	if err != nil {
+		return err
+	}
+	if hostname == "" {
+		return fmt.Errorf("node reported an empty hostname")
+	}
+
+	if _, err := j.exec(ctx, j.nodeSession, "sudo -n true"); err != nil {
+		return fmt.Errorf("passwordless sudo is required: %w", err)
+	}
+	if _, err := j.exec(ctx, j.nodeSession, "command -v curl"); err != nil {
+		return fmt.Errorf("curl is required: %w", err)
+	}
+
+	node, err := j.engine.nodes.Update(j.node.ID, services.UpdateNodeRequest{
+		Architecture: &arch,
+		NodeName:     &hostname,
+	})
+	if err != nil {
+		return err
+	}
+	j.node = node
+	return nil
+}
+
+// fetchJoinToken reads the cluster join token from a ready master
+func fetchJoinToken(ctx context.Context, j *job) error {
+	_, err := j.joinToken(ctx)
+	return err
+}
+
+// installServer installs k3s as a server, initialising the cluster's
+// datastore for the first master or joining an existing one. Masters of a
+// cluster install one at a time, each holding the cluster until its job ends,
+// so a master provisioned alongside the first joins it once it is ready
+// instead of initialising a second datastore.
+func installServer(ctx context.Context, j *job) error {
+	if j.serviceActive(ctx, "k3s") {
+		return nil
+	}
+
+	unlock, err := j.engine.lockMasters(ctx, j.cluster.ID)
+	if err != nil {
+		return err
+	}
+	j.unlock = unlock
+
+	args := []string{"server", "--node-name", shellQuote(j.node.NodeName)}
+	var env []string
+
+	master, err := j.engine.readyMaster(j.node)
+	if err != nil {
+		return err
+	}
+	if master == nil {
+		args = append(args, "--cluster-init")
+	} else {
+		j.master = master
+		token, err := j.joinToken(ctx)
+		if err != nil {
+			return err
+		}
+		env = append(env, "K3S_TOKEN="+shellQuote(token))
+		args = append(args, "--server", shellQuote(j.serverURL(master)))
+	}
+
+	_, err = j.exec(ctx, j.nodeSession, j.installCommand(env, args))
+	return err
+}
+
+// installAgent installs k3s as an agent joined to a ready master
+func installAgent(ctx context.Context, j *job) error {
+	if j.serviceActive(ctx, "k3s-agent") {
+		return nil
+	}
+
+	master, err := j.joinMaster()
+	if err != nil {
+		return err
+	}
+	token, err := j.joinToken(ctx)
+	if err != nil {
+		return err
+	}
+	env := []string{
+		"K3S_URL=" + shellQuote(j.serverURL(master)),
+		"K3S_TOKEN=" + shellQuote(token),
+	}
+	args := []string{"agent", "--node-name", shellQuote(j.node.NodeName)}
+
+	_, err = j.exec(ctx, j.nodeSession, j.installCommand(env, args))
+	return err
+}
+
+// fetchKubeConfig stores the server's admin kubeconfig on the cluster,
+// pointed at the node instead of the loopback address k3s writes
+func fetchKubeConfig(ctx context.Context, j *job) error {
+	kubeConfig, err := j.execSecret(ctx, j.nodeSession, "sudo cat "+k3sKubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	endpoint := j.serverURL(j.node)
+	kubeConfig = strings.ReplaceAll(kubeConfig, fmt.Sprintf("https://127.0.0.1:%d", k3sAPIPort), endpoint)
+
+	cluster, err := j.engine.clusters.SetKubeConfig(j.cluster.ID, endpoint, []byte(kubeConfig))
+	if err != nil {
+		return err
+	}
+	j.cluster = cluster
+	return nil
+}
+
+// waitReady polls the API server until the node has registered and reports
+// Ready, then records its kubelet version
+func waitReady(ctx context.Context, j *job) error {
+	ctx, cancel := context.WithTimeout(ctx, j.engine.config.RegistrationTimeout)
+	defer cancel()
+
+	run := j.nodeSession
+	if j.node.Role != models.NodeRoleMaster {
+		run = j.masterSession
+	}
+	command := fmt.Sprintf(
+		`sudo k3s kubectl get node %s -o jsonpath='{.status.conditions[?(@.type=="Ready")].status} {.status.nodeInfo.kubeletVersion}'`,
+		shellQuote(j.node.NodeName),
+	)
+
+	for {
+		output, err := j.exec(ctx, run, command)
+		if err == nil {
+			fields := strings.Fields(output)
+			if len(fields) == 2 && fields[0] == "True" {
+				node, err := j.engine.nodes.Update(j.node.ID, services.UpdateNodeRequest{KubeVersion: &fields[1]})
+				if err != nil {
+					return err
+				}
+				j.node = node
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node %s did not become ready within %s", j.node.NodeName, j.engine.config.RegistrationTimeout)
+		case <-time.After(j.engine.config.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+// k3sBinaryPath is where the k3s install script puts the k3s binary
+const k3sBinaryPath = "/usr/local/bin/k3s"
+
+// releaseAsset is a k3s binary published with each release, along with the
+// file listing its SHA-256 checksum
+type releaseAsset struct {
+	binary    string
+	checksums string
+}
+
+// releaseAssets maps `uname -m` values to the k3s binary published for them
+var releaseAssets = map[string]releaseAsset{
+	"aarch64": {binary: "k3s-arm64", checksums: "sha256sum-arm64.txt"},
+	"arm64":   {binary: "k3s-arm64", checksums: "sha256sum-arm64.txt"},
+	"armv7l":  {binary: "k3s-armhf", checksums: "sha256sum-arm.txt"},
+	"x86_64":  {binary: "k3s", checksums: "sha256sum-amd64.txt"},
+}
+
+// UpgradeNode replaces the k3s binary on a drained node with a release and
+// restarts k3s, leaving the node's configuration untouched. A node already
+// running the release with k3s active is left alone.
+func (e *Engine) UpgradeNode(ctx context.Context, record *models.ProvisioningJob, step string, node *models.Node, version string) (string, string, error) {
+	j := &job{engine: e, record: record, node: node, sessions: make(map[uint]Session)}
+	defer j.close()
+
+	j.beginStep(step)
+	err := upgradeK3s(ctx, j, version)
+	stdout, stderr := j.endStep()
+	return stdout, stderr, err
+}
+
+// upgradeK3s downloads the release binary for the node's architecture,
+// checks it against the release's published checksum, installs it over the
+// current one and restarts the k3s service
+func upgradeK3s(ctx context.Context, j *job, version string) error {
+	unit := "k3s-agent"
+	if j.node.Role == models.NodeRoleMaster {
+		unit = "k3s"
+	}
+
+	current, err := j.exec(ctx, j.nodeSession, k3sBinaryPath+" --version")
+	if err != nil {
+		return fmt.Errorf("k3s is not installed: %w", err)
+	}
+	if installedVersion(current) == version && j.serviceActive(ctx, unit) {
+		return nil
+	}
+
+	arch := j.node.Architecture
+	if arch == "" {
+		if arch, err = j.exec(ctx, j.nodeSession, "uname -m"); err != nil {
+			return err
+		}
+	}
+	asset, ok := releaseAssets[arch]
+	if !ok {
+		return fmt.Errorf("unsupported architecture %q", arch)
+	}
+
+	release := fmt.Sprintf("%s/%s/", j.engine.config.ReleaseURL, url.PathEscape(version))
+	sums, err := j.exec(ctx, j.nodeSession, "curl -sfL "+shellQuote(release+asset.checksums))
+	if err != nil {
+		return fmt.Errorf("failed to download release checksums: %w", err)
+	}
+	sum, err := releaseChecksum(sums, asset.binary)
+	if err != nil {
+		return err
+	}
+
+	staged := k3sBinaryPath + ".upgrade"
+	if _, err := j.exec(ctx, j.nodeSession, "sudo curl -sfL -o "+shellQuote(staged)+" "+shellQuote(release+asset.binary)); err != nil {
+		return err
+	}
+	if _, err := j.exec(ctx, j.nodeSession, "echo "+shellQuote(sum+"  "+staged)+" | sha256sum -c -"); err != nil {
+		j.exec(ctx, j.nodeSession, "sudo rm -f "+shellQuote(staged))
+		return fmt.Errorf("downloaded %s does not match its release checksum: %w", asset.binary, err)
+	}
+
+	commands := []string{
+		"sudo chmod 0755 " + shellQuote(staged),
+		"sudo mv " + shellQuote(staged) + " " + k3sBinaryPath,
+		"sudo systemctl restart " + unit,
+	}
+	for _, command := range commands {
+		if _, err := j.exec(ctx, j.nodeSession, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseChecksum returns the SHA-256 checksum of a binary from a release's
+// sha256sum file, whose lines are "<hex>  <name>"
+func releaseChecksum(sums, binary string) (string, error) {
+	for _, line := range strings.Split(sums, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != binary {
+			continue
+		}
+		if decoded, err := hex.DecodeString(fields[0]); err != nil || len(decoded) != 32 {
+			return "", fmt.Errorf("invalid release checksum for %s", binary)
+		}
+		return strings.ToLower(fields[0]), nil
+	}
+	return "", fmt.Errorf("release checksums do not list %s", binary)
+}
+
+// installedVersion returns the version from `k3s --version` output such as
+// "k3s version v1.30.2+k3s1 (faeaf1b0)"
+func installedVersion(output string) string {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[1] != "version" {
+		return ""
+	}
+	return fields[2]
+}
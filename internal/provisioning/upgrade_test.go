@@ -0,0 +1,96 @@
+package provisioning
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+func TestEngine_UpgradeNode(t *testing.T) {
+	env := setupTestEnv(t)
+	env.config.ReleaseURL = "https://releases.example.com/k3s"
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+	master, err := env.nodes.GetByID(master.ID, false)
+	require.NoError(t, err)
+
+	stdout, _, err := engine.UpgradeNode(context.Background(), nil, "upgrade-k3s", master, "v1.30.5+k3s1")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "$ sudo systemctl restart k3s\n")
+
+	ran := env.master.ran()
+	assert.Contains(t, ran, "curl -sfL 'https://releases.example.com/k3s/v1.30.5+k3s1/sha256sum-arm64.txt'")
+	assert.Contains(t, ran, "sudo curl -sfL -o '/usr/local/bin/k3s.upgrade' 'https://releases.example.com/k3s/v1.30.5+k3s1/k3s-arm64'")
+	assert.Contains(t, ran, "echo '"+testK3sSHA256+"  /usr/local/bin/k3s.upgrade' | sha256sum -c -")
+	assert.Contains(t, ran, "sudo mv '/usr/local/bin/k3s.upgrade' /usr/local/bin/k3s")
+	assert.Contains(t, ran, "sudo systemctl restart k3s")
+
+	// A node already running the release is left alone
+	before := len(env.master.ran())
+	_, _, err = engine.UpgradeNode(context.Background(), nil, "upgrade-k3s", master, "v1.30.5+k3s1")
+	require.NoError(t, err)
+	for _, command := range env.master.ran()[before:] {
+		assert.NotContains(t, command, "systemctl restart")
+	}
+}
+
+func TestEngine_UpgradeNodeTampered(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	master := env.createNode(t, "master", "127.0.0.2", models.NodeRoleMaster, "aa:bb:cc:dd:ee:01")
+
+	require.NoError(t, env.nodes.Provision(master.ID, env.cluster.ID))
+	engine.Wait()
+	master, err := env.nodes.GetByID(master.ID, false)
+	require.NoError(t, err)
+
+	env.k3s.mu.Lock()
+	env.k3s.tampered = true
+	env.k3s.mu.Unlock()
+	before := len(env.master.ran())
+	_, _, err = engine.UpgradeNode(context.Background(), nil, "upgrade-k3s", master, "v1.30.5+k3s1")
+	assert.ErrorContains(t, err, "does not match its release checksum")
+
+	ran := env.master.ran()[before:]
+	assert.Contains(t, ran, "sudo rm -f '/usr/local/bin/k3s.upgrade'")
+	for _, command := range ran {
+		assert.NotContains(t, command, "sudo mv ", "a tampered binary is never installed")
+		assert.NotContains(t, command, "systemctl restart")
+	}
+}
+
+func TestEngine_UpgradeNodeWithoutK3s(t *testing.T) {
+	env := setupTestEnv(t)
+	engine := env.startEngine(t)
+	worker := env.createNode(t, "worker", "127.0.0.1", models.NodeRoleWorker, "aa:bb:cc:dd:ee:02")
+
+	_, _, err := engine.UpgradeNode(context.Background(), nil, "upgrade-k3s", worker, "v1.30.5+k3s1")
+	assert.ErrorContains(t, err, "k3s is not installed")
+}
+
+func TestReleaseChecksum(t *testing.T) {
+	sums := testK3sSHA256 + "  k3s-arm64\n" + strings.Repeat("a", 64) + "  k3s-airgap-images-arm64.tar\n"
+
+	sum, err := releaseChecksum(sums, "k3s-arm64")
+	require.NoError(t, err)
+	assert.Equal(t, testK3sSHA256, sum)
+
+	_, err = releaseChecksum(sums, "k3s-armhf")
+	assert.ErrorContains(t, err, "do not list k3s-armhf")
+
+	_, err = releaseChecksum("not-a-checksum  k3s-arm64\n", "k3s-arm64")
+	assert.ErrorContains(t, err, "invalid release checksum")
+}
+
+func TestInstalledVersion(t *testing.T) {
+	assert.Equal(t, "v1.30.2+k3s1", installedVersion("k3s version v1.30.2+k3s1 (faeaf1b0)\ngo version go1.22.5\n"))
+	assert.Empty(t, installedVersion("sh: k3s: not found"))
+}
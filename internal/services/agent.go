@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// AgentConnector provides gRPC clients for the pi-agent running on a node
+type AgentConnector interface {
+	// Client returns a PiAgentService client for the given node
+	Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error)
+}
+
+// AgentStateProvider reports the connection state of the pi-agent on each node
+type AgentStateProvider interface {
+	// AgentState returns the current connection state of a node's agent
+	AgentState(nodeID uint) string
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// ApplyManifests server-side applies multi-document YAML or JSON manifests to
+// a cluster and returns what happened to each object. Objects that fail to
+// apply are reported in the results rather than as an error.
+func (s *DeploymentService) ApplyManifests(ctx context.Context, clusterID uint, manifests []byte, opts k8s.ApplyOptions) ([]k8s.ApplyResult, error) {
+	if len(manifests) == 0 {
+		return nil, errors.Wrapf(ErrValidationFailed, "manifests are required")
+	}
+
+	client, err := s.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := client.Apply(ctx, manifests, opts)
+	if err != nil {
+		if errors.Is(err, k8s.ErrInvalidManifest) {
+			return nil, errors.Wrapf(ErrValidationFailed, "%v", err)
+		}
+		return nil, kubeAPIError(err, "failed to apply manifests")
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Action == k8s.ApplyFailed {
+			failed++
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"objects":    len(results),
+		"failed":     failed,
+		"dry_run":    opts.DryRun,
+		"prune":      opts.PruneSelector,
+	}).Info("Manifests applied")
+
+	return results, nil
+}
@@ -0,0 +1,684 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dsyorkd/pi-controller/internal/backup"
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// backupCheckInterval is how often the scheduler looks for clusters due a backup
+const backupCheckInterval = time.Minute
+
+// restoreChunkSize is the size of each snapshot chunk streamed to an agent
+const restoreChunkSize = 64 * 1024
+
+// backupLockGrace is how long a cluster's lock outlives the backup timeout,
+// covering the bookkeeping around a backup or restore
+const backupLockGrace = time.Minute
+
+// Restore step names, recorded with each restore job
+const (
+	restoreStepVerify  = "verify-snapshot"
+	restoreStepRestore = "restore-datastore"
+)
+
+// BackupService snapshots the datastore of each cluster through the agent on
+// its master node, keeps the snapshots in a backup store and restores them.
+// One backup or restore of a cluster runs at a time, even across the controller
+// and its CLI.
+type BackupService struct {
+	db     *storage.Database
+	store  backup.Store
+	config backup.Config
+	logger logger.Interface
+	agents AgentConnector
+	jobs   *JobService
+
+	running sync.WaitGroup
+}
+
+// NewBackupService creates a new backup service
+func NewBackupService(db *storage.Database, store backup.Store, config backup.Config, logger logger.Interface) *BackupService {
+	return &BackupService{
+		db:     db,
+		store:  store,
+		config: config,
+		logger: logger.WithField("service", "backup"),
+	}
+}
+
+// SetAgentConnector sets the connector used to reach the pi-agent on each node
+func (s *BackupService) SetAgentConnector(agents AgentConnector) {
+	s.agents = agents
+}
+
+// SetJobService sets the service restores are recorded with
+func (s *BackupService) SetJobService(jobs *JobService) {
+	s.jobs = jobs
+}
+
+// Wait blocks until backups and restores started in the background finish
+func (s *BackupService) Wait() {
+	s.running.Wait()
+}
+
+// BackupListOptions is the options for listing a cluster's backups
+type BackupListOptions struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// List lists a cluster's backups, newest first
+func (s *BackupService) List(clusterID uint, opts BackupListOptions) ([]models.ClusterBackup, int64, error) {
+	if err := s.checkCluster(clusterID); err != nil {
+		return nil, 0, err
+	}
+
+	var backups []models.ClusterBackup
+	var total int64
+
+	query := s.db.DB().Model(&models.ClusterBackup{}).Where("cluster_id = ?", clusterID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to count backups")
+	}
+
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+	if err := query.Order("started_at DESC, id DESC").Find(&backups).Error; err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to list backups")
+	}
+
+	return backups, total, nil
+}
+
+// Get returns one of a cluster's backups
+func (s *BackupService) Get(clusterID, backupID uint) (*models.ClusterBackup, error) {
+	var record models.ClusterBackup
+	if err := s.db.DB().Where("cluster_id = ?", clusterID).First(&record, backupID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(ErrNotFound, "backup %d of cluster %d not found", backupID, clusterID)
+		}
+		return nil, errors.Wrapf(err, "failed to get backup")
+	}
+	return &record, nil
+}
+
+// Delete removes a backup and its snapshot
+func (s *BackupService) Delete(ctx context.Context, clusterID, backupID uint) error {
+	record, err := s.Get(clusterID, backupID)
+	if err != nil {
+		return err
+	}
+	if record.Status == models.BackupStatusRunning {
+		return errors.Wrapf(ErrConflict, "backup %d is still running", backupID)
+	}
+	return s.remove(ctx, record)
+}
+
+// remove deletes a backup's snapshot, then its record
+func (s *BackupService) remove(ctx context.Context, record *models.ClusterBackup) error {
+	if err := s.store.Delete(ctx, record.Key); err != nil {
+		return errors.Wrapf(err, "failed to delete snapshot of backup %d", record.ID)
+	}
+	if err := s.db.DB().Delete(&models.ClusterBackup{}, record.ID).Error; err != nil {
+		return errors.Wrapf(err, "failed to delete backup")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"backup_id":  record.ID,
+		"cluster_id": record.ClusterID,
+	}).Info("Backup deleted")
+	return nil
+}
+
+// Backup snapshots a cluster's datastore and waits for it to be stored. The
+// backup is returned even if it failed, along with the error.
+func (s *BackupService) Backup(ctx context.Context, clusterID uint, trigger models.BackupTrigger) (*models.ClusterBackup, error) {
+	record, node, err := s.begin(clusterID, trigger)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(clusterID)
+
+	err = s.runBackup(ctx, record, node)
+	return record, err
+}
+
+// StartBackup starts a backup of a cluster in the background and returns it
+// while it runs
+func (s *BackupService) StartBackup(clusterID uint, trigger models.BackupTrigger) (*models.ClusterBackup, error) {
+	record, node, err := s.begin(clusterID, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	started := *record
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		defer s.release(clusterID)
+		s.runBackup(context.Background(), record, node)
+	}()
+	return &started, nil
+}
+
+// begin reserves a cluster and records a running backup of it
+func (s *BackupService) begin(clusterID uint, trigger models.BackupTrigger) (*models.ClusterBackup, *models.Node, error) {
+	node, err := s.master(clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.reserve(clusterID); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	record := &models.ClusterBackup{
+		ClusterID: clusterID,
+		NodeID:    node.ID,
+		Trigger:   trigger,
+		Status:    models.BackupStatusRunning,
+		Storage:   s.store.Kind(),
+		StartedAt: now,
+	}
+	err = s.db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		record.Key = fmt.Sprintf("clusters/%d/%s-%d-%s.snapshot", clusterID, now.UTC().Format("20060102T150405Z"), record.ID, trigger)
+		return tx.Model(record).Update("key", record.Key).Error
+	})
+	if err != nil {
+		s.release(clusterID)
+		return nil, nil, errors.Wrapf(err, "failed to record backup")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"backup_id":  record.ID,
+		"cluster_id": clusterID,
+		"node_id":    node.ID,
+		"trigger":    trigger,
+	}).Info("Backup started")
+	return record, node, nil
+}
+
+// runBackup streams a snapshot from the node's agent into the store, then
+// records the outcome and applies the retention policy. Failed backups count
+// towards retention so their records do not pile up.
+func (s *BackupService) runBackup(ctx context.Context, record *models.ClusterBackup, node *models.Node) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	datastore, size, checksum, err := s.snapshot(ctx, record, node)
+	if err == nil && size == 0 {
+		err = fmt.Errorf("agent sent an empty snapshot")
+	}
+
+	now := time.Now()
+	record.FinishedAt = &now
+	if err != nil {
+		if deleteErr := s.store.Delete(context.Background(), record.Key); deleteErr != nil {
+			s.logger.WithError(deleteErr).WithField("backup_id", record.ID).Warn("Failed to delete snapshot of failed backup")
+		}
+		record.Status = models.BackupStatusFailed
+		record.Error = err.Error()
+	} else {
+		record.Status = models.BackupStatusSucceeded
+		record.Datastore = datastore
+		record.SizeBytes = size
+		record.Checksum = checksum
+	}
+	updateErr := s.db.DB().Model(record).
+		Select("status", "error", "datastore", "size_bytes", "checksum", "finished_at").
+		Updates(record).Error
+	if updateErr != nil {
+		s.logger.WithError(updateErr).WithField("backup_id", record.ID).Error("Failed to record backup outcome")
+	}
+
+	log := s.logger.WithFields(map[string]interface{}{
+		"backup_id":  record.ID,
+		"cluster_id": record.ClusterID,
+	})
+	if err != nil {
+		log.WithError(err).Error("Backup failed")
+	} else {
+		log.WithFields(map[string]interface{}{
+			"datastore":  datastore,
+			"size_bytes": size,
+		}).Info("Backup succeeded")
+	}
+
+	if retentionErr := s.applyRetention(context.Background(), record.ClusterID); retentionErr != nil {
+		log.WithError(retentionErr).Warn("Failed to apply backup retention")
+	}
+	return err
+}
+
+// snapshot streams a snapshot from the agent into the store, returning its
+// datastore, size and checksum
+func (s *BackupService) snapshot(ctx context.Context, record *models.ClusterBackup, node *models.Node) (string, int64, string, error) {
+	client, err := s.agentClient(ctx, node)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.SnapshotDatastore(ctx, &pb.SnapshotDatastoreRequest{Name: fmt.Sprintf("pi-controller-%d", record.ID)})
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to start snapshot: %w", err)
+	}
+
+	// Chunks are piped into the store as they arrive
+	reader, writer := io.Pipe()
+	received := make(chan pb.AgentDatastore, 1)
+	go func() {
+		datastore := pb.AgentDatastore_AGENT_DATASTORE_UNSPECIFIED
+		defer func() { received <- datastore }()
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				writer.Close()
+				return
+			}
+			if err != nil {
+				writer.CloseWithError(fmt.Errorf("snapshot stream failed: %w", err))
+				return
+			}
+			datastore = chunk.Datastore
+			if _, err := writer.Write(chunk.Data); err != nil {
+				return
+			}
+		}
+	}()
+
+	digest := &countingHash{Hash: sha256.New()}
+	err = s.store.Put(ctx, record.Key, io.TeeReader(reader, digest))
+	reader.CloseWithError(io.ErrClosedPipe)
+	cancel()
+	datastore := <-received
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	return datastoreName(datastore), digest.n, hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// countingHash hashes and counts the bytes written to it
+type countingHash struct {
+	hash.Hash
+	n int64
+}
+
+func (h *countingHash) Write(p []byte) (int, error) {
+	h.n += int64(len(p))
+	return h.Hash.Write(p)
+}
+
+// datastoreName returns the name a backup records for an agent datastore
+func datastoreName(datastore pb.AgentDatastore) string {
+	return strings.ToLower(strings.TrimPrefix(datastore.String(), "AGENT_DATASTORE_"))
+}
+
+// datastoreFromName is the inverse of datastoreName
+func datastoreFromName(name string) pb.AgentDatastore {
+	return pb.AgentDatastore(pb.AgentDatastore_value["AGENT_DATASTORE_"+strings.ToUpper(name)])
+}
+
+// Restore replaces a cluster's datastore with one of its backups and waits
+// for the restore to finish. The restore is recorded as a job of the master
+// node, returned even if the restore failed.
+func (s *BackupService) Restore(ctx context.Context, clusterID, backupID uint) (*models.ProvisioningJob, error) {
+	record, node, job, err := s.beginRestore(clusterID, backupID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(clusterID)
+
+	err = s.runRestore(ctx, record, node, job)
+	return job.recorded(), err
+}
+
+// StartRestore starts restoring a backup in the background and returns the
+// job recording it
+func (s *BackupService) StartRestore(clusterID, backupID uint) (*models.ProvisioningJob, error) {
+	record, node, job, err := s.beginRestore(clusterID, backupID)
+	if err != nil {
+		return nil, err
+	}
+
+	started := job.recorded()
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		defer s.release(clusterID)
+		s.runRestore(context.Background(), record, node, job)
+	}()
+	return started, nil
+}
+
+// beginRestore checks a backup can be restored, reserves its cluster and
+// records the restore job
+func (s *BackupService) beginRestore(clusterID, backupID uint) (*models.ClusterBackup, *models.Node, *recordedJob, error) {
+	record, err := s.Get(clusterID, backupID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !record.IsRestorable() {
+		return nil, nil, nil, errors.Wrapf(ErrConflict, "backup %d is %s, only succeeded backups can be restored", backupID, record.Status)
+	}
+
+	node, err := s.master(clusterID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.reserve(clusterID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	job := s.jobs.start(node, models.JobTypeRestore, []string{restoreStepVerify, restoreStepRestore}, s.logger)
+
+	s.logger.WithFields(map[string]interface{}{
+		"backup_id":  record.ID,
+		"cluster_id": clusterID,
+		"node_id":    node.ID,
+	}).Warn("Restoring cluster datastore")
+	return record, node, job, nil
+}
+
+// runRestore verifies a backup's snapshot, then streams it to the master's
+// agent, which stops k3s, restores the datastore and starts k3s again
+func (s *BackupService) runRestore(ctx context.Context, record *models.ClusterBackup, node *models.Node, job *recordedJob) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	log := s.logger.WithFields(map[string]interface{}{
+		"backup_id":  record.ID,
+		"cluster_id": record.ClusterID,
+	})
+
+	job.start(restoreStepVerify)
+	err := s.verify(ctx, record)
+	job.finish(restoreStepVerify, err)
+	if err != nil {
+		job.skip(restoreStepRestore)
+		job.done(models.JobStatusFailed, err.Error())
+		log.WithError(err).Error("Restore failed")
+		return err
+	}
+
+	job.start(restoreStepRestore)
+	err = s.sendRestore(ctx, record, node)
+	job.finish(restoreStepRestore, err)
+	if err != nil {
+		job.done(models.JobStatusFailed, err.Error())
+		log.WithError(err).Error("Restore failed")
+		return err
+	}
+
+	job.done(models.JobStatusSucceeded, "")
+	log.Info("Cluster datastore restored")
+	return nil
+}
+
+// verify reads a backup's snapshot and checks it against its checksum
+func (s *BackupService) verify(ctx context.Context, record *models.ClusterBackup) error {
+	reader, err := s.store.Get(ctx, record.Key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	digest := &countingHash{Hash: sha256.New()}
+	if _, err := io.Copy(digest, reader); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if digest.n != record.SizeBytes || hex.EncodeToString(digest.Sum(nil)) != record.Checksum {
+		return fmt.Errorf("snapshot of backup %d does not match its checksum", record.ID)
+	}
+	return nil
+}
+
+// sendRestore streams a backup's snapshot to the node's agent to restore
+func (s *BackupService) sendRestore(ctx context.Context, record *models.ClusterBackup, node *models.Node) error {
+	client, err := s.agentClient(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	reader, err := s.store.Get(ctx, record.Key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	stream, err := client.RestoreDatastore(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start restore: %w", err)
+	}
+
+	datastore := datastoreFromName(record.Datastore)
+	buf := make([]byte, restoreChunkSize)
+	for {
+		n, readErr := reader.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.RestoreDatastoreRequest{Datastore: datastore, Data: buf[:n]}); err != nil {
+				// The agent's error is returned by CloseAndRecv
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read snapshot: %w", readErr)
+		}
+	}
+
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("agent failed to restore: %w", err)
+	}
+	return nil
+}
+
+// StartScheduler backs up each cluster once per configured schedule until
+// ctx is cancelled. It does nothing if scheduled backups are disabled.
+func (s *BackupService) StartScheduler(ctx context.Context) {
+	if s.config.Schedule <= 0 {
+		s.logger.Info("Scheduled backups are disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(backupCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			s.RunScheduled()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunScheduled starts a backup of every cluster with a ready master whose
+// latest backup started more than one schedule ago
+func (s *BackupService) RunScheduled() {
+	var clusters []models.Cluster
+	if err := s.db.DB().Find(&clusters).Error; err != nil {
+		s.logger.WithError(err).Error("Failed to list clusters for scheduled backups")
+		return
+	}
+
+	due := time.Now().Add(-s.config.Schedule)
+	for _, cluster := range clusters {
+		var latest models.ClusterBackup
+		err := s.db.DB().Where("cluster_id = ?", cluster.ID).Order("started_at DESC").First(&latest).Error
+		if err == nil && latest.StartedAt.After(due) {
+			continue
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WithError(err).WithField("cluster_id", cluster.ID).Error("Failed to find latest backup")
+			continue
+		}
+
+		if _, err := s.StartBackup(cluster.ID, models.BackupTriggerScheduled); err != nil {
+			s.logger.WithError(err).WithField("cluster_id", cluster.ID).Debug("Skipping scheduled backup")
+		}
+	}
+}
+
+// FailInterrupted marks backups left running by a previous controller
+// process as failed
+func (s *BackupService) FailInterrupted() error {
+	result := s.db.DB().Model(&models.ClusterBackup{}).
+		Where("status = ?", models.BackupStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.BackupStatusFailed,
+			"error":       "interrupted by a controller restart",
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "failed to fail interrupted backups")
+	}
+	if result.RowsAffected > 0 {
+		s.logger.WithField("count", result.RowsAffected).Warn("Marked interrupted backups as failed")
+	}
+	return nil
+}
+
+// applyRetention deletes a cluster's backups beyond the configured count or
+// age. The newest succeeded backup is always kept.
+func (s *BackupService) applyRetention(ctx context.Context, clusterID uint) error {
+	if s.config.KeepLast <= 0 && s.config.MaxAge <= 0 {
+		return nil
+	}
+
+	var backups []models.ClusterBackup
+	err := s.db.DB().Where("cluster_id = ? AND status != ?", clusterID, models.BackupStatusRunning).
+		Order("started_at DESC, id DESC").Find(&backups).Error
+	if err != nil {
+		return errors.Wrapf(err, "failed to list backups")
+	}
+
+	cutoff := time.Now().Add(-s.config.MaxAge)
+	keptLatest := false
+	for i := range backups {
+		record := &backups[i]
+		if record.IsRestorable() && !keptLatest {
+			keptLatest = true
+			continue
+		}
+
+		expired := (s.config.KeepLast > 0 && i >= s.config.KeepLast) ||
+			(s.config.MaxAge > 0 && record.StartedAt.Before(cutoff))
+		if !expired {
+			continue
+		}
+		if err := s.remove(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// master returns a ready master node of a cluster
+func (s *BackupService) master(clusterID uint) (*models.Node, error) {
+	if err := s.checkCluster(clusterID); err != nil {
+		return nil, err
+	}
+
+	var node models.Node
+	err := s.db.DB().
+		Where("cluster_id = ? AND role = ? AND status = ?", clusterID, models.NodeRoleMaster, models.NodeStatusReady).
+		Order("id").First(&node).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrapf(ErrConflict, "cluster %d has no ready master node", clusterID)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find master node")
+	}
+	return &node, nil
+}
+
+// checkCluster returns ErrNotFound if a cluster does not exist
+func (s *BackupService) checkCluster(clusterID uint) error {
+	var count int64
+	if err := s.db.DB().Model(&models.Cluster{}).Where("id = ?", clusterID).Count(&count).Error; err != nil {
+		return errors.Wrapf(err, "failed to get cluster")
+	}
+	if count == 0 {
+		return errors.Wrapf(ErrNotFound, "cluster %d not found", clusterID)
+	}
+	return nil
+}
+
+// agentClient connects to a node's agent
+func (s *BackupService) agentClient(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	if s.agents == nil {
+		return nil, &AgentUnreachableError{NodeID: node.ID, Err: fmt.Errorf("no agent connector configured")}
+	}
+	client, err := s.agents.Client(ctx, node)
+	if err != nil {
+		return nil, &AgentUnreachableError{NodeID: node.ID, Err: err}
+	}
+	return client, nil
+}
+
+// reserve locks a cluster in the database while a backup or restore of it
+// runs, so the controller and its CLI never run two at once. A lock left
+// behind by a process that exited mid-operation is taken over once it expires.
+func (s *BackupService) reserve(clusterID uint) error {
+	now := time.Now()
+	locked := false
+	err := s.db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cluster_id = ? AND expires_at < ?", clusterID, now).Delete(&models.ClusterBackupLock{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ClusterBackupLock{
+			ClusterID: clusterID,
+			ExpiresAt: now.Add(s.config.Timeout + backupLockGrace),
+		})
+		locked = result.RowsAffected == 1
+		return result.Error
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to lock cluster %d", clusterID)
+	}
+	if !locked {
+		return errors.Wrapf(ErrConflict, "a backup or restore of cluster %d is already running", clusterID)
+	}
+	return nil
+}
+
+// release clears a lock taken by reserve
+func (s *BackupService) release(clusterID uint) {
+	if err := s.db.DB().Where("cluster_id = ?", clusterID).Delete(&models.ClusterBackupLock{}).Error; err != nil {
+		s.logger.WithError(err).WithField("cluster_id", clusterID).Error("Failed to unlock cluster")
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dsyorkd/pi-controller/internal/backup"
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// datastoreAgent is an in-process agent that snapshots and restores an
+// in-memory etcd datastore
+type datastoreAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu       sync.Mutex
+	data     []byte
+	restored []byte
+	names    []string
+	block    chan struct{} // if set, snapshots wait for it to close
+}
+
+func (a *datastoreAgent) SnapshotDatastore(req *pb.SnapshotDatastoreRequest, stream pb.PiAgentService_SnapshotDatastoreServer) error {
+	a.mu.Lock()
+	a.names = append(a.names, req.Name)
+	data, block := a.data, a.block
+	a.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+	for len(data) > 0 {
+		n := min(len(data), 1000)
+		if err := stream.Send(&pb.DatastoreChunk{Datastore: pb.AgentDatastore_AGENT_DATASTORE_ETCD, Data: data[:n]}); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func (a *datastoreAgent) RestoreDatastore(stream pb.PiAgentService_RestoreDatastoreServer) error {
+	var data []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.Datastore != pb.AgentDatastore_AGENT_DATASTORE_ETCD {
+			return status.Errorf(codes.FailedPrecondition, "node runs etcd, snapshot is %s", req.Datastore)
+		}
+		data = append(data, req.Data...)
+	}
+
+	a.mu.Lock()
+	a.restored = data
+	a.mu.Unlock()
+	return stream.SendAndClose(&pb.RestoreDatastoreResponse{Message: "restored", SizeBytes: int64(len(data))})
+}
+
+type backupEnv struct {
+	backups *BackupService
+	jobs    *JobService
+	store   *backup.LocalStore
+	agent   *datastoreAgent
+	db      *storage.Database
+	cluster *models.Cluster
+	master  *models.Node
+}
+
+// setupBackups creates a cluster with a ready master whose agent serves a
+// datastore snapshot
+func setupBackups(t *testing.T, config backup.Config) *backupEnv {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	clusters := NewClusterService(db, logger.Default())
+	cluster, err := clusters.Create(CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+
+	nodes := NewNodeService(db, logger.Default())
+	master, err := nodes.Create(CreateNodeRequest{
+		Name:       "master",
+		IPAddress:  "192.168.1.2",
+		MACAddress: "aa:bb:cc:dd:ee:02",
+		Role:       models.NodeRoleMaster,
+		ClusterID:  &cluster.ID,
+		CPUCores:   4,
+		Memory:     4096,
+	})
+	require.NoError(t, err)
+	ready := models.NodeStatusReady
+	master, err = nodes.Update(master.ID, UpdateNodeRequest{Status: &ready})
+	require.NoError(t, err)
+
+	store, err := backup.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	agent := &datastoreAgent{data: bytes.Repeat([]byte("etcd "), 5000)}
+	jobs := NewJobService(db, logger.Default())
+	backups := NewBackupService(db, store, config, logger.Default())
+	backups.SetAgentConnector(startFakeAgent(t, agent))
+	backups.SetJobService(jobs)
+	t.Cleanup(backups.Wait)
+
+	return &backupEnv{backups: backups, jobs: jobs, store: store, agent: agent, db: db, cluster: cluster, master: master}
+}
+
+func TestBackupService_Backup(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+	ctx := context.Background()
+
+	record, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupStatusSucceeded, record.Status)
+	assert.Equal(t, "etcd", record.Datastore)
+	assert.Equal(t, backup.StorageLocal, record.Storage)
+	assert.Equal(t, int64(len(env.agent.data)), record.SizeBytes)
+	assert.Len(t, record.Checksum, 64)
+	assert.Equal(t, env.master.ID, record.NodeID)
+	assert.NotNil(t, record.FinishedAt)
+	assert.Equal(t, []string{"pi-controller-1"}, env.agent.names)
+
+	reader, err := env.store.Get(ctx, record.Key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, env.agent.data, stored)
+
+	backups, total, err := env.backups.List(env.cluster.ID, BackupListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, record.ID, backups[0].ID)
+
+	t.Run("empty snapshots fail", func(t *testing.T) {
+		env.agent.data = nil
+		failed, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+		assert.Error(t, err)
+		assert.Equal(t, models.BackupStatusFailed, failed.Status)
+		assert.NotEmpty(t, failed.Error)
+
+		_, err = env.backups.StartRestore(env.cluster.ID, failed.ID)
+		assert.True(t, errors.Is(err, ErrConflict), "failed backups cannot be restored, got %v", err)
+	})
+
+	t.Run("delete removes the snapshot", func(t *testing.T) {
+		require.NoError(t, env.backups.Delete(ctx, env.cluster.ID, record.ID))
+		_, err := env.store.Get(ctx, record.Key)
+		assert.True(t, errors.Is(err, backup.ErrNotFound))
+		_, err = env.backups.Get(env.cluster.ID, record.ID)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("unknown clusters are not found", func(t *testing.T) {
+		_, err := env.backups.Backup(ctx, 999, models.BackupTriggerManual)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestBackupService_NoReadyMaster(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+	require.NoError(t, env.db.DB().Model(env.master).Update("status", models.NodeStatusFailed).Error)
+
+	_, err := env.backups.Backup(context.Background(), env.cluster.ID, models.BackupTriggerManual)
+	assert.True(t, errors.Is(err, ErrConflict), "got %v", err)
+}
+
+func TestBackupService_OneAtATime(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+	env.agent.block = make(chan struct{})
+
+	running, err := env.backups.StartBackup(env.cluster.ID, models.BackupTriggerManual)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupStatusRunning, running.Status)
+
+	_, err = env.backups.StartBackup(env.cluster.ID, models.BackupTriggerManual)
+	assert.True(t, errors.Is(err, ErrConflict), "got %v", err)
+
+	// The CLI runs its own service against the controller's database
+	cli := NewBackupService(env.db, env.store, backup.Config{Timeout: time.Minute}, logger.Default())
+	_, err = cli.Backup(context.Background(), env.cluster.ID, models.BackupTriggerManual)
+	assert.True(t, errors.Is(err, ErrConflict), "the controller's backup locks the cluster, got %v", err)
+	err = env.backups.Delete(context.Background(), env.cluster.ID, running.ID)
+	assert.True(t, errors.Is(err, ErrConflict), "running backups cannot be deleted, got %v", err)
+
+	close(env.agent.block)
+	env.backups.Wait()
+
+	finished, err := env.backups.Get(env.cluster.ID, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupStatusSucceeded, finished.Status)
+}
+
+func TestBackupService_ExpiredLock(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+
+	// A process that exited mid-backup left its lock behind
+	require.NoError(t, env.db.DB().Create(&models.ClusterBackupLock{
+		ClusterID: env.cluster.ID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}).Error)
+	_, err := env.backups.Backup(context.Background(), env.cluster.ID, models.BackupTriggerManual)
+	assert.True(t, errors.Is(err, ErrConflict), "got %v", err)
+
+	require.NoError(t, env.db.DB().Model(&models.ClusterBackupLock{}).
+		Where("cluster_id = ?", env.cluster.ID).
+		Update("expires_at", time.Now().Add(-time.Second)).Error)
+	record, err := env.backups.Backup(context.Background(), env.cluster.ID, models.BackupTriggerManual)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupStatusSucceeded, record.Status)
+
+	var locks int64
+	require.NoError(t, env.db.DB().Model(&models.ClusterBackupLock{}).Count(&locks).Error)
+	assert.Zero(t, locks, "finished backups release their lock")
+}
+
+func TestBackupService_Retention(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute, KeepLast: 2, MaxAge: 48 * time.Hour})
+	ctx := context.Background()
+
+	// An old backup beyond the maximum age
+	old := &models.ClusterBackup{
+		ClusterID: env.cluster.ID,
+		NodeID:    env.master.ID,
+		Trigger:   models.BackupTriggerScheduled,
+		Status:    models.BackupStatusSucceeded,
+		Storage:   backup.StorageLocal,
+		Key:       "clusters/1/old.snapshot",
+		StartedAt: time.Now().Add(-72 * time.Hour),
+	}
+	require.NoError(t, env.db.DB().Create(old).Error)
+	require.NoError(t, env.store.Put(ctx, old.Key, bytes.NewReader([]byte("old"))))
+
+	var kept []uint
+	for i := 0; i < 3; i++ {
+		record, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+		require.NoError(t, err)
+		kept = append(kept, record.ID)
+	}
+
+	backups, total, err := env.backups.List(env.cluster.ID, BackupListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uint{kept[2], kept[1]}, []uint{backups[0].ID, backups[1].ID})
+
+	_, err = env.store.Get(ctx, old.Key)
+	assert.True(t, errors.Is(err, backup.ErrNotFound), "expired snapshots are deleted")
+
+	t.Run("the newest succeeded backup is always kept", func(t *testing.T) {
+		env.agent.data = nil
+		for i := 0; i < 2; i++ {
+			_, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+			require.Error(t, err)
+		}
+		env.agent.data = []byte("etcd")
+		_, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+		require.NoError(t, err)
+
+		backups, _, err := env.backups.List(env.cluster.ID, BackupListOptions{})
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		assert.Equal(t, models.BackupStatusSucceeded, backups[0].Status)
+		assert.Equal(t, models.BackupStatusFailed, backups[1].Status)
+
+		env.agent.data = nil
+		for i := 0; i < 2; i++ {
+			_, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+			require.Error(t, err)
+		}
+		backups, _, err = env.backups.List(env.cluster.ID, BackupListOptions{})
+		require.NoError(t, err)
+		require.Len(t, backups, 3)
+		assert.Equal(t, models.BackupStatusSucceeded, backups[2].Status)
+	})
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+	ctx := context.Background()
+
+	record, err := env.backups.Backup(ctx, env.cluster.ID, models.BackupTriggerManual)
+	require.NoError(t, err)
+
+	job, err := env.backups.Restore(ctx, env.cluster.ID, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, env.agent.data, env.agent.restored)
+
+	job, err = env.jobs.Get(env.master.ID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTypeRestore, job.Type)
+	assert.Equal(t, models.JobStatusSucceeded, job.Status)
+	require.Len(t, job.Steps, 2)
+	assert.Equal(t, restoreStepVerify, job.Steps[0].Name)
+	assert.Equal(t, models.StepStatusSucceeded, job.Steps[0].Status)
+	assert.Equal(t, models.StepStatusSucceeded, job.Steps[1].Status)
+
+	t.Run("corrupt snapshots are not restored", func(t *testing.T) {
+		env.agent.restored = nil
+		require.NoError(t, env.store.Put(ctx, record.Key, bytes.NewReader([]byte("corrupt"))))
+
+		job, err := env.backups.Restore(ctx, env.cluster.ID, record.ID)
+		assert.Error(t, err)
+		assert.Nil(t, env.agent.restored)
+
+		job, err = env.jobs.Get(env.master.ID, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusFailed, job.Status)
+		assert.Equal(t, models.StepStatusFailed, job.Steps[0].Status)
+		assert.Equal(t, models.StepStatusSkipped, job.Steps[1].Status)
+	})
+}
+
+func TestBackupService_RunScheduled(t *testing.T) {
+	env := setupBackups(t, backup.Config{Schedule: time.Hour, Timeout: time.Minute})
+
+	env.backups.RunScheduled()
+	env.backups.Wait()
+	env.backups.RunScheduled()
+	env.backups.Wait()
+
+	backups, _, err := env.backups.List(env.cluster.ID, BackupListOptions{})
+	require.NoError(t, err)
+	require.Len(t, backups, 1, "a backup is not due again until the schedule elapses")
+	assert.Equal(t, models.BackupTriggerScheduled, backups[0].Trigger)
+	assert.Equal(t, models.BackupStatusSucceeded, backups[0].Status)
+
+	require.NoError(t, env.db.DB().Model(&backups[0]).Update("started_at", time.Now().Add(-2*time.Hour)).Error)
+	env.backups.RunScheduled()
+	env.backups.Wait()
+
+	_, total, err := env.backups.List(env.cluster.ID, BackupListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestBackupService_FailInterrupted(t *testing.T) {
+	env := setupBackups(t, backup.Config{Timeout: time.Minute})
+	interrupted := &models.ClusterBackup{
+		ClusterID: env.cluster.ID,
+		NodeID:    env.master.ID,
+		Trigger:   models.BackupTriggerScheduled,
+		Status:    models.BackupStatusRunning,
+		Storage:   backup.StorageLocal,
+		Key:       "clusters/1/interrupted.snapshot",
+		StartedAt: time.Now(),
+	}
+	require.NoError(t, env.db.DB().Create(interrupted).Error)
+
+	require.NoError(t, env.backups.FailInterrupted())
+	record, err := env.backups.Get(env.cluster.ID, interrupted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupStatusFailed, record.Status)
+	assert.NotNil(t, record.FinishedAt)
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"encoding/base64"
+	"sync"
+
 	"gorm.io/gorm"
 
 	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
 	"github.com/dsyorkd/pi-controller/internal/logger"
 	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/internal/storage"
@@ -13,67 +17,100 @@ import (
 type ClusterService struct {
 	store *storage.Database
 	log   logger.Interface
+	bus   *events.Bus
+	kube  *KubeClientRegistry
+
+	statusMu sync.Mutex
+	observed map[uint]clusterObservation
 }
 
 // NewClusterService creates a new ClusterService
 func NewClusterService(store *storage.Database, log logger.Interface) *ClusterService {
 	return &ClusterService{
-		store: store,
-		log:   log,
+		store:    store,
+		log:      log,
+		bus:      events.NewBus(),
+		observed: make(map[uint]clusterObservation),
 	}
 }
 
+// SetEventBus sets the bus cluster changes are published to
+func (s *ClusterService) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// SetKubeClientRegistry sets the registry whose cached client of a cluster is
+// dropped when the cluster is updated or deleted
+func (s *ClusterService) SetKubeClientRegistry(kube *KubeClientRegistry) {
+	s.kube = kube
+}
+
 // CreateClusterRequest is the request to create a cluster
 type CreateClusterRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Version        string `json:"version"`
+	MasterEndpoint string `json:"master_endpoint"`
 }
 
 // Create creates a new cluster
 func (s *ClusterService) Create(req CreateClusterRequest) (*models.Cluster, error) {
 	if req.Name == "" {
-		return nil, errors.Wrapf(ErrInvalidInput, "name is required")
+		return nil, errors.Wrapf(ErrValidationFailed, "name is required")
 	}
 
 	// Check for duplicate name
-	existing, err := s.GetByName(req.Name)
-	if err != nil && err.Error() != "not found" {
-		return nil, err
-	}
-	if existing != nil {
+	_, err := s.GetByName(req.Name)
+	if err == nil {
 		return nil, errors.Wrapf(ErrAlreadyExists, "cluster name already exists")
 	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
 
 	cluster := &models.Cluster{
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      models.ClusterStatusActive,
+		Name:           req.Name,
+		Description:    req.Description,
+		Version:        req.Version,
+		MasterEndpoint: req.MasterEndpoint,
+		Status:         models.ClusterStatusActive,
 	}
 
 	err = s.store.DB().Create(cluster).Error
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "failed to create cluster")
 	}
 
+	s.publish(events.ActionCreated, cluster)
+
 	return cluster, nil
 }
 
 // UpdateClusterRequest is the request to update a cluster
 type UpdateClusterRequest struct {
-	Name        *string               `json:"name"`
-	Description *string               `json:"description"`
-	Status      *models.ClusterStatus `json:"status"`
+	Name           *string               `json:"name"`
+	Description    *string               `json:"description"`
+	Status         *models.ClusterStatus `json:"status"`
+	Version        *string               `json:"version"`
+	MasterEndpoint *string               `json:"master_endpoint"`
 }
 
 // Update updates a cluster
 func (s *ClusterService) Update(id uint, req UpdateClusterRequest) (*models.Cluster, error) {
-	var cluster models.Cluster
-	err := s.store.DB().First(&cluster, id).Error
+	cluster, err := s.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Name != nil {
+	if req.Name != nil && *req.Name != cluster.Name {
+		if *req.Name == "" {
+			return nil, errors.Wrapf(ErrValidationFailed, "name is required")
+		}
+		if _, err := s.GetByName(*req.Name); err == nil {
+			return nil, errors.Wrapf(ErrAlreadyExists, "cluster name already exists")
+		} else if !IsNotFound(err) {
+			return nil, err
+		}
 		cluster.Name = *req.Name
 	}
 	if req.Description != nil {
@@ -82,27 +119,74 @@ func (s *ClusterService) Update(id uint, req UpdateClusterRequest) (*models.Clus
 	if req.Status != nil {
 		cluster.Status = *req.Status
 	}
+	if req.Version != nil {
+		cluster.Version = *req.Version
+	}
+	if req.MasterEndpoint != nil {
+		cluster.MasterEndpoint = *req.MasterEndpoint
+	}
+
+	err = s.store.DB().Save(cluster).Error
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update cluster")
+	}
+	s.invalidateClient(id)
+
+	s.publish(events.ActionUpdated, cluster)
+
+	return cluster, nil
+}
 
-	err = s.store.DB().Save(&cluster).Error
+// SetKubeConfig stores the admin kubeconfig and API endpoint of a cluster's
+// control plane once it has been installed
+func (s *ClusterService) SetKubeConfig(id uint, masterEndpoint string, kubeConfig []byte) (*models.Cluster, error) {
+	cluster, err := s.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &cluster, nil
+	cluster.MasterEndpoint = masterEndpoint
+	cluster.KubeConfig = base64.StdEncoding.EncodeToString(kubeConfig)
+
+	if err := s.store.DB().Save(cluster).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to store cluster kubeconfig")
+	}
+	s.invalidateClient(id)
+
+	s.log.WithFields(map[string]interface{}{
+		"cluster_id":      id,
+		"master_endpoint": masterEndpoint,
+	}).Info("Cluster kubeconfig updated")
+
+	s.publish(events.ActionUpdated, cluster)
+
+	return cluster, nil
 }
 
 // Delete deletes a cluster
 func (s *ClusterService) Delete(id uint) error {
-	// Check if cluster has nodes
-	// nodes, err := s.nodeStore.GetNodesByClusterID(id)
-	// if err != nil {
-	// 	return err
-	// }
-	// if len(nodes) > 0 {
-	// 	return fmt.Errorf("cannot delete cluster with existing nodes")
-	// }
+	cluster, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
 
-	return s.store.DB().Delete(&models.Cluster{}, id).Error
+	// Refuse to orphan nodes that are still assigned to the cluster
+	var nodeCount int64
+	if err := s.store.DB().Model(&models.Node{}).Where("cluster_id = ?", id).Count(&nodeCount).Error; err != nil {
+		return errors.Wrapf(err, "failed to count cluster nodes")
+	}
+	if nodeCount > 0 {
+		return ErrHasAssociatedResources
+	}
+
+	if err := s.store.DB().Delete(&models.Cluster{}, id).Error; err != nil {
+		return errors.Wrapf(err, "failed to delete cluster")
+	}
+	s.invalidateClient(id)
+
+	s.publish(events.ActionDeleted, cluster)
+
+	return nil
 }
 
 // ClusterListOptions is the options for listing clusters
@@ -115,12 +199,29 @@ type ClusterListOptions struct {
 // List lists clusters
 func (s *ClusterService) List(opts ClusterListOptions) ([]models.Cluster, int64, error) {
 	var clusters []models.Cluster
-	err := s.store.DB().Find(&clusters).Error
-	if err != nil {
-		return nil, 0, err
+	var total int64
+
+	query := s.store.DB().Model(&models.Cluster{})
+	if opts.Status != nil {
+		query = query.Where("status = ?", *opts.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to count clusters")
 	}
 
-	return clusters, int64(len(clusters)), nil
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	if err := query.Find(&clusters).Error; err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to fetch clusters")
+	}
+
+	return clusters, total, nil
 }
 
 // GetByID gets a cluster by ID
@@ -128,6 +229,9 @@ func (s *ClusterService) GetByID(id uint) (*models.Cluster, error) {
 	var cluster models.Cluster
 	err := s.store.DB().First(&cluster, id).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
 	return &cluster, nil
@@ -158,10 +262,67 @@ func (s *ClusterService) GetNodes(clusterID uint) ([]models.Node, error) {
 
 // GetStatus retrieves the status of a cluster
 func (s *ClusterService) GetStatus(id uint) (models.ClusterStatus, error) {
-	var cluster models.Cluster
-	err := s.store.DB().First(&cluster, id).Error
+	cluster, err := s.GetByID(id)
 	if err != nil {
 		return "", err
 	}
 	return cluster.Status, nil
 }
+
+// ClusterStatusReport is a cluster's status along with the progress of its
+// most recent upgrade
+type ClusterStatusReport struct {
+	ClusterID uint                   `json:"cluster_id"`
+	Status    models.ClusterStatus   `json:"status"`
+	Version   string                 `json:"version"`
+	Upgrade   *models.ClusterUpgrade `json:"upgrade,omitempty"`
+}
+
+// StatusReport retrieves the status of a cluster and its latest upgrade
+func (s *ClusterService) StatusReport(id uint) (*ClusterStatusReport, error) {
+	cluster, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	upgrade, err := latestUpgrade(s.store, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterStatusReport{
+		ClusterID: cluster.ID,
+		Status:    cluster.Status,
+		Version:   cluster.Version,
+		Upgrade:   upgrade,
+	}, nil
+}
+
+// invalidateClient drops the cached Kubernetes client of a changed cluster
+func (s *ClusterService) invalidateClient(id uint) {
+	if s.kube != nil {
+		s.kube.Invalidate(id)
+	}
+}
+
+// publish announces a cluster change on the event bus along with its node counts
+func (s *ClusterService) publish(action events.Action, cluster *models.Cluster) {
+	event := events.Cluster{
+		Action:    action,
+		ClusterID: cluster.ID,
+		Name:      cluster.Name,
+		Status:    string(cluster.Status),
+	}
+
+	if action != events.ActionDeleted {
+		var total, ready int64
+		if err := s.store.DB().Model(&models.Node{}).Where("cluster_id = ?", cluster.ID).Count(&total).Error; err != nil {
+			s.log.WithError(err).Warn("Failed to count cluster nodes for event")
+		}
+		if err := s.store.DB().Model(&models.Node{}).Where("cluster_id = ? AND status = ?", cluster.ID, models.NodeStatusReady).Count(&ready).Error; err != nil {
+			s.log.WithError(err).Warn("Failed to count ready cluster nodes for event")
+		}
+		event.NodesTotal = int(total)
+		event.NodesReady = int(ready)
+	}
+
+	s.bus.Publish(event)
+}
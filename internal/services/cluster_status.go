@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// clusterObservation is what the last reconcile saw of a cluster, used to
+// publish only changes
+type clusterObservation struct {
+	status     models.ClusterStatus
+	version    string
+	nodesReady int
+	nodesTotal int
+}
+
+// StartStatusReconciler derives the status of every cluster with a stored
+// kubeconfig from its Kubernetes API every interval until ctx is cancelled.
+// Nodes are also relabelled as soon as their GPIO devices change. Call it
+// after SetKubeClientRegistry.
+func (s *ClusterService) StartStatusReconciler(ctx context.Context, interval time.Duration) {
+	go s.watchGPIODevices(ctx, s.bus.Subscribe(events.OfType(events.TypeGPIODevice), events.DefaultBufferSize))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.ReconcileStatus(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ReconcileStatus queries the Kubernetes API of every cluster with a stored
+// kubeconfig once. Each cluster's status and version are updated from what it
+// reports, as are the node name and kubelet version of its nodes, and each
+// node is labelled with its GPIO devices. Clusters in maintenance keep their
+// status.
+func (s *ClusterService) ReconcileStatus(ctx context.Context) {
+	if s.kube == nil {
+		return
+	}
+
+	var clusters []models.Cluster
+	if err := s.store.DB().Where("kube_config <> ''").Find(&clusters).Error; err != nil {
+		s.log.WithError(err).Error("Failed to list clusters for status reconciliation")
+		return
+	}
+
+	// Forget clusters that were deleted or lost their kubeconfig, so they
+	// are published again if they come back
+	listed := make(map[uint]bool, len(clusters))
+	for _, cluster := range clusters {
+		listed[cluster.ID] = true
+	}
+	s.statusMu.Lock()
+	for id := range s.observed {
+		if !listed[id] {
+			delete(s.observed, id)
+		}
+	}
+	s.statusMu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(cluster *models.Cluster) {
+			defer wg.Done()
+			s.reconcileCluster(ctx, cluster)
+		}(&clusters[i])
+	}
+	wg.Wait()
+}
+
+func (s *ClusterService) reconcileCluster(ctx context.Context, cluster *models.Cluster) {
+	log := s.log.WithFields(map[string]interface{}{
+		"cluster_id":   cluster.ID,
+		"cluster_name": cluster.Name,
+	})
+
+	var info *k8s.ClusterInfo
+	client, err := s.kube.ClientFor(cluster)
+	if err == nil {
+		reqCtx, cancel := context.WithTimeout(ctx, kubeRequestTimeout)
+		info, err = client.GetClusterInfo(reqCtx)
+		cancel()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.WithError(err).Warn("Failed to query cluster")
+	}
+
+	observed := clusterObservation{status: clusterStatusFor(info), version: cluster.Version}
+	if info != nil {
+		observed.version = info.Version
+		observed.nodesReady = info.ReadyNodes
+		observed.nodesTotal = info.TotalNodes
+		nodes := s.syncClusterNodes(cluster, info.Nodes)
+		s.syncGPIOLabels(ctx, client, nodes)
+	}
+	if cluster.Status == models.ClusterStatusMaintenance {
+		observed.status = cluster.Status
+	}
+
+	changed := observed.status != cluster.Status || observed.version != cluster.Version
+	if changed {
+		updates := map[string]interface{}{"version": observed.version}
+		query := s.store.DB().Model(&models.Cluster{}).Where("id = ?", cluster.ID)
+		if observed.status != cluster.Status {
+			// An upgrade may have put the cluster into maintenance while it
+			// was being queried
+			updates["status"] = observed.status
+			query = query.Where("status <> ?", models.ClusterStatusMaintenance)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			log.WithError(result.Error).Error("Failed to update cluster status")
+			return
+		}
+		if result.RowsAffected == 0 {
+			log.Debug("Cluster entered maintenance while its status was reconciled")
+			return
+		}
+		if observed.status != cluster.Status {
+			log.WithFields(map[string]interface{}{
+				"old_status": cluster.Status,
+				"new_status": observed.status,
+			}).Info("Cluster status changed")
+		}
+	}
+
+	s.statusMu.Lock()
+	previous, seen := s.observed[cluster.ID]
+	s.observed[cluster.ID] = observed
+	s.statusMu.Unlock()
+
+	if seen && previous == observed && !changed {
+		return
+	}
+	s.bus.Publish(events.Cluster{
+		Action:     events.ActionUpdated,
+		ClusterID:  cluster.ID,
+		Name:       cluster.Name,
+		Status:     string(observed.status),
+		NodesReady: observed.nodesReady,
+		NodesTotal: observed.nodesTotal,
+	})
+}
+
+// clusterStatusFor derives a cluster's status from the readiness of its
+// nodes. A cluster whose API cannot be reached or that has no ready node has
+// failed; one with some nodes not ready is degraded.
+func clusterStatusFor(info *k8s.ClusterInfo) models.ClusterStatus {
+	switch {
+	case info == nil || info.ReadyNodes == 0:
+		return models.ClusterStatusFailed
+	case info.ReadyNodes < info.TotalNodes:
+		return models.ClusterStatusDegraded
+	default:
+		return models.ClusterStatusActive
+	}
+}
+
+// syncClusterNodes records the Kubernetes node name and kubelet version of a
+// cluster's nodes and returns the nodes found in Kubernetes. Nodes are matched
+// by their recorded Kubernetes node name, falling back to their IP address and
+// then their name.
+func (s *ClusterService) syncClusterNodes(cluster *models.Cluster, kubeNodes []k8s.NodeInfo) []models.Node {
+	var nodes []models.Node
+	if err := s.store.DB().Where("cluster_id = ?", cluster.ID).Find(&nodes).Error; err != nil {
+		s.log.WithError(err).WithField("cluster_id", cluster.ID).Error("Failed to list cluster nodes")
+		return nil
+	}
+
+	var matched []models.Node
+	for i := range nodes {
+		node := &nodes[i]
+		kubeNode := matchKubeNode(node, kubeNodes)
+		if kubeNode == nil {
+			continue
+		}
+		if kubeNode.Name == node.NodeName && kubeNode.Version == node.KubeVersion {
+			matched = append(matched, *node)
+			continue
+		}
+
+		err := s.store.DB().Model(node).Updates(map[string]interface{}{
+			"node_name":    kubeNode.Name,
+			"kube_version": kubeNode.Version,
+		}).Error
+		if err != nil {
+			s.log.WithError(err).WithField("node_id", node.ID).Error("Failed to sync node from Kubernetes")
+			continue
+		}
+		node.NodeName, node.KubeVersion = kubeNode.Name, kubeNode.Version
+		matched = append(matched, *node)
+
+		s.bus.Publish(events.Node{
+			Action:    events.ActionUpdated,
+			NodeID:    node.ID,
+			Name:      node.Name,
+			Status:    string(node.Status),
+			IPAddress: node.IPAddress,
+			ClusterID: node.ClusterID,
+		})
+	}
+	return matched
+}
+
+func matchKubeNode(node *models.Node, kubeNodes []k8s.NodeInfo) *k8s.NodeInfo {
+	matchers := []func(k8s.NodeInfo) bool{
+		func(k k8s.NodeInfo) bool { return node.NodeName != "" && k.Name == node.NodeName },
+		func(k k8s.NodeInfo) bool { return k.InternalIP != "" && k.InternalIP == node.IPAddress },
+		func(k k8s.NodeInfo) bool { return k.Name == node.Name },
+	}
+	for _, matches := range matchers {
+		for i := range kubeNodes {
+			if matches(kubeNodes[i]) {
+				return &kubeNodes[i]
+			}
+		}
+	}
+	return nil
+}
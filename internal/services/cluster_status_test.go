@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+func kubeNode(name, ip, kubeletVersion string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+		},
+	}
+}
+
+type clusterStatusEnv struct {
+	db        *storage.Database
+	bus       *events.Bus
+	clusters  *ClusterService
+	nodes     *NodeService
+	clientset *fake.Clientset
+	cluster   *models.Cluster
+	node      *models.Node
+	events    *events.Subscription
+}
+
+// setupClusterStatus creates a cluster whose API reports a ready master and
+// a worker that has not become ready, with the master recorded as a node
+func setupClusterStatus(t *testing.T) *clusterStatusEnv {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	clientset := fake.NewSimpleClientset(
+		kubeNode("pi-master", "192.168.1.10", "v1.30.4+k3s1", true),
+		kubeNode("pi-worker", "192.168.1.11", "v1.30.4+k3s1", false),
+	)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.4+k3s1"}
+
+	registry := NewKubeClientRegistry(logger.Default())
+	registry.build = func(kubeConfig []byte) (k8s.K8sClient, error) {
+		return k8s.NewClientForClientset(clientset, logrus.New()), nil
+	}
+
+	bus := events.NewBus()
+	clusters := NewClusterService(db, logger.Default())
+	clusters.SetEventBus(bus)
+	clusters.SetKubeClientRegistry(registry)
+	nodes := NewNodeService(db, logger.Default())
+
+	cluster, err := clusters.Create(CreateClusterRequest{Name: "home", Version: "v1.29.0"})
+	require.NoError(t, err)
+	cluster, err = clusters.SetKubeConfig(cluster.ID, "https://192.168.1.10:6443", []byte("apiVersion: v1\n"))
+	require.NoError(t, err)
+
+	node, err := nodes.Create(CreateNodeRequest{
+		Name:       "master",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleMaster,
+		ClusterID:  &cluster.ID,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+
+	sub := bus.Subscribe(events.OfType(events.TypeCluster, events.TypeNode), 16)
+	t.Cleanup(sub.Close)
+
+	return &clusterStatusEnv{db: db, bus: bus, clusters: clusters, nodes: nodes, clientset: clientset, cluster: cluster, node: node, events: sub}
+}
+
+// published drains the events published so far
+func (env *clusterStatusEnv) published() []events.Payload {
+	var payloads []events.Payload
+	for {
+		select {
+		case event := <-env.events.C():
+			payloads = append(payloads, event.Payload)
+		default:
+			return payloads
+		}
+	}
+}
+
+func (env *clusterStatusEnv) setReady(t *testing.T, name string, ready bool) {
+	node, err := env.clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Status.Conditions[0].Status = corev1.ConditionFalse
+	if ready {
+		node.Status.Conditions[0].Status = corev1.ConditionTrue
+	}
+	_, err = env.clientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestClusterService_ReconcileStatus(t *testing.T) {
+	t.Run("derives status and version from the Kubernetes API", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		env.published()
+
+		env.clusters.ReconcileStatus(context.Background())
+
+		cluster, err := env.clusters.GetByID(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ClusterStatusDegraded, cluster.Status)
+		assert.Equal(t, "v1.30.4+k3s1", cluster.Version)
+
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, "pi-master", node.NodeName, "matched by IP address")
+		assert.Equal(t, "v1.30.4+k3s1", node.KubeVersion)
+
+		published := env.published()
+		assert.Contains(t, published, events.Cluster{
+			Action:     events.ActionUpdated,
+			ClusterID:  env.cluster.ID,
+			Name:       "home",
+			Status:     string(models.ClusterStatusDegraded),
+			NodesReady: 1,
+			NodesTotal: 2,
+		})
+		assert.Contains(t, published, events.Node{
+			Action:    events.ActionUpdated,
+			NodeID:    env.node.ID,
+			Name:      "master",
+			Status:    string(env.node.Status),
+			IPAddress: "192.168.1.10",
+			ClusterID: &env.cluster.ID,
+		})
+	})
+
+	t.Run("publishes only changes", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		env.clusters.ReconcileStatus(context.Background())
+		env.published()
+
+		env.clusters.ReconcileStatus(context.Background())
+		assert.Empty(t, env.published())
+
+		env.setReady(t, "pi-worker", true)
+		env.clusters.ReconcileStatus(context.Background())
+
+		status, err := env.clusters.GetStatus(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ClusterStatusActive, status)
+		assert.Len(t, env.published(), 1)
+	})
+
+	t.Run("forgets deleted clusters", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		env.clusters.ReconcileStatus(context.Background())
+		require.Contains(t, env.clusters.observed, env.cluster.ID)
+
+		require.NoError(t, env.clusters.store.DB().Delete(&models.Cluster{}, env.cluster.ID).Error)
+		env.clusters.ReconcileStatus(context.Background())
+
+		assert.NotContains(t, env.clusters.observed, env.cluster.ID)
+	})
+
+	t.Run("fails clusters without ready nodes", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		env.setReady(t, "pi-master", false)
+
+		env.clusters.ReconcileStatus(context.Background())
+
+		status, err := env.clusters.GetStatus(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ClusterStatusFailed, status)
+	})
+
+	t.Run("keeps clusters in maintenance", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		maintenance := models.ClusterStatusMaintenance
+		_, err := env.clusters.Update(env.cluster.ID, UpdateClusterRequest{Status: &maintenance})
+		require.NoError(t, err)
+
+		env.clusters.ReconcileStatus(context.Background())
+
+		cluster, err := env.clusters.GetByID(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ClusterStatusMaintenance, cluster.Status)
+		assert.Equal(t, "v1.30.4+k3s1", cluster.Version)
+	})
+
+	t.Run("keeps clusters put into maintenance while reconciling", func(t *testing.T) {
+		env := setupClusterStatus(t)
+		env.clientset.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+			// An upgrade starts while the cluster's API is queried
+			maintenance := models.ClusterStatusMaintenance
+			_, err := env.clusters.Update(env.cluster.ID, UpdateClusterRequest{Status: &maintenance})
+			require.NoError(t, err)
+			return false, nil, nil
+		})
+
+		env.clusters.ReconcileStatus(context.Background())
+
+		status, err := env.clusters.GetStatus(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ClusterStatusMaintenance, status)
+	})
+}
+
+func TestClusterStatusFor(t *testing.T) {
+	assert.Equal(t, models.ClusterStatusFailed, clusterStatusFor(nil), "unreachable")
+	assert.Equal(t, models.ClusterStatusFailed, clusterStatusFor(&k8s.ClusterInfo{}), "no nodes")
+	assert.Equal(t, models.ClusterStatusFailed, clusterStatusFor(&k8s.ClusterInfo{TotalNodes: 2}))
+	assert.Equal(t, models.ClusterStatusDegraded, clusterStatusFor(&k8s.ClusterInfo{TotalNodes: 2, ReadyNodes: 1}))
+	assert.Equal(t, models.ClusterStatusActive, clusterStatusFor(&k8s.ClusterInfo{TotalNodes: 2, ReadyNodes: 2}))
+}
@@ -2,52 +2,217 @@ package services
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/dsyorkd/pi-controller/internal/storage"
+	"gorm.io/gorm"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/pkg/k8s"
-	corev1 "k8s.io/api/core/v1"
 )
 
-// DeploymentService is the service for managing deployments
+// ClusterStore looks up the cluster a workload runs in
+type ClusterStore interface {
+	GetCluster(id uint) (*models.Cluster, error)
+}
+
+// DeploymentService manages apps/v1 Deployments in managed clusters and
+// applies arbitrary manifests to them
 type DeploymentService struct {
-	store storage.Store
-	k8s   k8s.K8sClient
+	store   ClusterStore
+	clients KubeClientProvider
+	logger  logger.Interface
 }
 
-// NewDeploymentService creates a new DeploymentService
-func NewDeploymentService(store storage.Store, k8s k8s.K8sClient) *DeploymentService {
+// NewDeploymentService creates a new DeploymentService that talks to each
+// cluster through the client the provider returns for it
+func NewDeploymentService(store ClusterStore, clients KubeClientProvider, logger logger.Interface) *DeploymentService {
 	return &DeploymentService{
-		store: store,
-		k8s:   k8s,
+		store:   store,
+		clients: clients,
+		logger:  logger.WithField("service", "deployment"),
 	}
 }
 
-// CreateDeployment creates a new deployment
-func (s *DeploymentService) CreateDeployment(ctx context.Context, clusterID uint, pod *corev1.Pod) (*corev1.Pod, error) {
-	cluster, err := s.store.GetCluster(clusterID)
+// ListDeployments lists the deployments in a namespace of a cluster, or in
+// all namespaces if it is empty
+func (s *DeploymentService) ListDeployments(ctx context.Context, clusterID uint, namespace string) ([]appsv1.Deployment, error) {
+	client, err := s.clientFor(clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.k8s.CreatePod(ctx, cluster.Name, pod)
+	deployments, err := client.ListDeployments(ctx, namespace)
+	if err != nil {
+		return nil, kubeAPIError(err, "failed to list deployments")
+	}
+	return deployments, nil
+}
+
+// CreateDeployment creates a deployment in its namespace, or the default
+// namespace if it has none
+func (s *DeploymentService) CreateDeployment(ctx context.Context, clusterID uint, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	if deployment.Name == "" {
+		return nil, errors.Wrapf(ErrValidationFailed, "deployment name is required")
+	}
+
+	client, err := s.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := client.CreateDeployment(ctx, namespaceOrDefault(deployment.Namespace), deployment)
+	if err != nil {
+		return nil, kubeAPIError(err, "failed to create deployment %s", deployment.Name)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"namespace":  created.Namespace,
+		"deployment": created.Name,
+	}).Info("Deployment created")
+
+	return created, nil
 }
 
 // GetDeployment retrieves a deployment
-func (s *DeploymentService) GetDeployment(ctx context.Context, clusterID uint, name string) (*corev1.Pod, error) {
-	cluster, err := s.store.GetCluster(clusterID)
+func (s *DeploymentService) GetDeployment(ctx context.Context, clusterID uint, namespace, name string) (*appsv1.Deployment, error) {
+	client, err := s.clientFor(clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.k8s.GetPod(ctx, cluster.Name, name)
+	deployment, err := client.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return nil, kubeAPIError(err, "failed to get deployment %s", name)
+	}
+	return deployment, nil
 }
 
-// DeleteDeployment deletes a deployment
-func (s *DeploymentService) DeleteDeployment(ctx context.Context, clusterID uint, name string) error {
-	cluster, err := s.store.GetCluster(clusterID)
+// UpdateDeployment replaces a deployment's spec, starting a rollout if its
+// pod template changed
+func (s *DeploymentService) UpdateDeployment(ctx context.Context, clusterID uint, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	client, err := s.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := client.UpdateDeployment(ctx, namespaceOrDefault(deployment.Namespace), deployment)
+	if err != nil {
+		return nil, kubeAPIError(err, "failed to update deployment %s", deployment.Name)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"namespace":  updated.Namespace,
+		"deployment": updated.Name,
+	}).Info("Deployment updated")
+
+	return updated, nil
+}
+
+// DeleteDeployment deletes a deployment along with its pods
+func (s *DeploymentService) DeleteDeployment(ctx context.Context, clusterID uint, namespace, name string) error {
+	client, err := s.clientFor(clusterID)
 	if err != nil {
 		return err
 	}
 
-	return s.k8s.DeletePod(ctx, cluster.Name, name)
+	if err := client.DeleteDeployment(ctx, namespace, name); err != nil {
+		return kubeAPIError(err, "failed to delete deployment %s", name)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"namespace":  namespace,
+		"deployment": name,
+	}).Info("Deployment deleted")
+
+	return nil
+}
+
+// GetRolloutStatus reports the progress of a deployment's latest rollout
+func (s *DeploymentService) GetRolloutStatus(ctx context.Context, clusterID uint, namespace, name string) (*k8s.RolloutStatus, error) {
+	client, err := s.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := client.GetRolloutStatus(ctx, namespace, name)
+	if err != nil {
+		return nil, kubeAPIError(err, "failed to get rollout status of deployment %s", name)
+	}
+	return status, nil
+}
+
+// RollbackDeployment rolls a deployment back to an earlier revision, or to the
+// previous one if revision is 0
+func (s *DeploymentService) RollbackDeployment(ctx context.Context, clusterID uint, namespace, name string, revision int64) (*appsv1.Deployment, error) {
+	if revision < 0 {
+		return nil, errors.Wrapf(ErrValidationFailed, "revision must not be negative")
+	}
+
+	client, err := s.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := client.RollbackDeployment(ctx, namespace, name, revision)
+	if err != nil {
+		if errors.Is(err, k8s.ErrRevisionNotFound) {
+			return nil, errors.Wrapf(ErrValidationFailed, "%v", err)
+		}
+		return nil, kubeAPIError(err, "failed to roll back deployment %s", name)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cluster_id": clusterID,
+		"namespace":  namespace,
+		"deployment": name,
+		"revision":   revision,
+	}).Info("Deployment rolled back")
+
+	return deployment, nil
+}
+
+// clientFor returns the Kubernetes client of a cluster
+func (s *DeploymentService) clientFor(clusterID uint) (k8s.K8sClient, error) {
+	cluster, err := s.store.GetCluster(clusterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(ErrNotFound, "cluster not found")
+		}
+		return nil, errors.Wrapf(err, "failed to get cluster")
+	}
+
+	return s.clients.ClientFor(cluster)
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return namespace
+}
+
+// kubeAPIError maps a Kubernetes API error onto the service error it
+// corresponds to
+func kubeAPIError(err error, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	switch {
+	case apierrors.IsNotFound(err):
+		return errors.Wrapf(ErrNotFound, "%s: %v", message, err)
+	case apierrors.IsAlreadyExists(err):
+		return errors.Wrapf(ErrAlreadyExists, "%s: %v", message, err)
+	case apierrors.IsConflict(err):
+		return errors.Wrapf(ErrConflict, "%s: %v", message, err)
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return errors.Wrapf(ErrValidationFailed, "%s: %v", message, err)
+	default:
+		return errors.Wrapf(err, "%s", message)
+	}
 }
@@ -3,201 +3,291 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/dsyorkd/pi-controller/internal/logger"
 	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	corev1 "k8s.io/api/core/v1"
+	"gorm.io/gorm"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-func TestDeploymentService_CreateDeployment(t *testing.T) {
-	t.Run("should create a deployment", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+var deploymentsResource = schema.GroupResource{Group: "apps", Resource: "deployments"}
 
-		cluster := &models.Cluster{
-			ID:   1,
-			Name: "test-cluster",
-		}
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-pod",
-			},
-		}
+// clusterClients returns a provider that hands out client for every cluster
+func clusterClients(client k8s.K8sClient) *MockKubeClientProvider {
+	clients := &MockKubeClientProvider{}
+	clients.On("ClientFor", mock.Anything).Return(client, nil)
+	return clients
+}
 
-		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("CreatePod", mock.Anything, "test-cluster", pod).Return(pod, nil)
+// setupDeploymentService returns a service for cluster 1, whose client is
+// the returned mock
+func setupDeploymentService() (*DeploymentService, *MockStore, *MockK8sClient) {
+	store := &MockStore{}
+	k8sClient := &MockK8sClient{}
+	store.On("GetCluster", uint(1)).Return(&models.Cluster{ID: 1, Name: "test-cluster"}, nil).Maybe()
+	return NewDeploymentService(store, clusterClients(k8sClient), logger.Default()), store, k8sClient
+}
+
+func testDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web",
+		},
+	}
+}
 
-		createdPod, err := service.CreateDeployment(context.Background(), 1, pod)
+func TestDeploymentService_CreateDeployment(t *testing.T) {
+	t.Run("should create a deployment in the default namespace", func(t *testing.T) {
+		service, store, k8sClient := setupDeploymentService()
+		deployment := testDeployment()
+
+		k8sClient.On("CreateDeployment", mock.Anything, "default", deployment).Return(deployment, nil)
+
+		created, err := service.CreateDeployment(context.Background(), 1, deployment)
 		assert.NoError(t, err)
-		assert.NotNil(t, createdPod)
-		assert.Equal(t, "test-pod", createdPod.Name)
+		assert.Equal(t, "web", created.Name)
 
 		store.AssertExpectations(t)
 		k8sClient.AssertExpectations(t)
 	})
 
-	t.Run("should return an error when the store fails", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+	t.Run("should require a name", func(t *testing.T) {
+		service, _, _ := setupDeploymentService()
 
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-pod",
-			},
-		}
+		_, err := service.CreateDeployment(context.Background(), 1, &appsv1.Deployment{})
+		assert.True(t, IsValidationFailed(err))
+	})
 
-		store.On("GetCluster", uint(1)).Return(nil, errors.New("store error"))
+	t.Run("should return not found for unknown clusters", func(t *testing.T) {
+		store := &MockStore{}
+		service := NewDeploymentService(store, &MockKubeClientProvider{}, logger.Default())
 
-		_, err := service.CreateDeployment(context.Background(), 1, pod)
-		assert.Error(t, err)
+		store.On("GetCluster", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.CreateDeployment(context.Background(), 2, testDeployment())
+		assert.True(t, IsNotFound(err))
 
 		store.AssertExpectations(t)
 	})
 
-	t.Run("should return an error when the k8s client fails", func(t *testing.T) {
+	t.Run("should use the client of the deployment's cluster", func(t *testing.T) {
 		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+		clients := &MockKubeClientProvider{}
+		service := NewDeploymentService(store, clients, logger.Default())
 
 		cluster := &models.Cluster{
 			ID:   1,
 			Name: "test-cluster",
 		}
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-pod",
-			},
-		}
 
 		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("CreatePod", mock.Anything, "test-cluster", pod).Return(nil, errors.New("k8s error"))
+		clients.On("ClientFor", cluster).Return(nil, errors.New("cluster test-cluster has no kubeconfig"))
 
-		_, err := service.CreateDeployment(context.Background(), 1, pod)
+		_, err := service.CreateDeployment(context.Background(), 1, testDeployment())
 		assert.Error(t, err)
 
 		store.AssertExpectations(t)
+		clients.AssertExpectations(t)
+	})
+
+	t.Run("should report deployments that already exist", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+		deployment := testDeployment()
+
+		k8sClient.On("CreateDeployment", mock.Anything, "default", deployment).
+			Return(nil, apierrors.NewAlreadyExists(deploymentsResource, "web"))
+
+		_, err := service.CreateDeployment(context.Background(), 1, deployment)
+		assert.True(t, IsAlreadyExists(err))
+
 		k8sClient.AssertExpectations(t)
 	})
 }
 
 func TestDeploymentService_GetDeployment(t *testing.T) {
 	t.Run("should get a deployment", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+		service, _, k8sClient := setupDeploymentService()
 
-		cluster := &models.Cluster{
-			ID:   1,
-			Name: "test-cluster",
-		}
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-pod",
-			},
-		}
-
-		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("GetPod", mock.Anything, "test-cluster", "test-pod").Return(pod, nil)
+		k8sClient.On("GetDeployment", mock.Anything, "apps", "web").Return(testDeployment(), nil)
 
-		retrievedPod, err := service.GetDeployment(context.Background(), 1, "test-pod")
+		deployment, err := service.GetDeployment(context.Background(), 1, "apps", "web")
 		assert.NoError(t, err)
-		assert.NotNil(t, retrievedPod)
-		assert.Equal(t, "test-pod", retrievedPod.Name)
+		assert.Equal(t, "web", deployment.Name)
 
-		store.AssertExpectations(t)
 		k8sClient.AssertExpectations(t)
 	})
 
-	t.Run("should return an error when the store fails", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+	t.Run("should return not found for missing deployments", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
 
-		store.On("GetCluster", uint(1)).Return(nil, errors.New("store error"))
+		k8sClient.On("GetDeployment", mock.Anything, "apps", "web").
+			Return(nil, apierrors.NewNotFound(deploymentsResource, "web"))
 
-		_, err := service.GetDeployment(context.Background(), 1, "test-pod")
-		assert.Error(t, err)
+		_, err := service.GetDeployment(context.Background(), 1, "apps", "web")
+		assert.True(t, IsNotFound(err))
 
-		store.AssertExpectations(t)
+		k8sClient.AssertExpectations(t)
 	})
+}
 
-	t.Run("should return an error when the k8s client fails", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+func TestDeploymentService_ListDeployments(t *testing.T) {
+	service, _, k8sClient := setupDeploymentService()
 
-		cluster := &models.Cluster{
-			ID:   1,
-			Name: "test-cluster",
-		}
+	k8sClient.On("ListDeployments", mock.Anything, "").Return([]appsv1.Deployment{*testDeployment()}, nil)
 
-		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("GetPod", mock.Anything, "test-cluster", "test-pod").Return(nil, errors.New("k8s error"))
+	deployments, err := service.ListDeployments(context.Background(), 1, "")
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
 
-		_, err := service.GetDeployment(context.Background(), 1, "test-pod")
-		assert.Error(t, err)
+	k8sClient.AssertExpectations(t)
+}
+
+func TestDeploymentService_UpdateDeployment(t *testing.T) {
+	t.Run("should update a deployment", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+		deployment := testDeployment()
+		deployment.Namespace = "apps"
+
+		k8sClient.On("UpdateDeployment", mock.Anything, "apps", deployment).Return(deployment, nil)
+
+		_, err := service.UpdateDeployment(context.Background(), 1, deployment)
+		assert.NoError(t, err)
+
+		k8sClient.AssertExpectations(t)
+	})
+
+	t.Run("should report stale updates as conflicts", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+		deployment := testDeployment()
+
+		k8sClient.On("UpdateDeployment", mock.Anything, "default", deployment).
+			Return(nil, apierrors.NewConflict(deploymentsResource, "web", fmt.Errorf("the object has been modified")))
+
+		_, err := service.UpdateDeployment(context.Background(), 1, deployment)
+		assert.True(t, IsConflict(err))
 
-		store.AssertExpectations(t)
 		k8sClient.AssertExpectations(t)
 	})
 }
 
 func TestDeploymentService_DeleteDeployment(t *testing.T) {
 	t.Run("should delete a deployment", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+		service, _, k8sClient := setupDeploymentService()
 
-		cluster := &models.Cluster{
-			ID:   1,
-			Name: "test-cluster",
-		}
-
-		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("DeletePod", mock.Anything, "test-cluster", "test-pod").Return(nil)
+		k8sClient.On("DeleteDeployment", mock.Anything, "default", "web").Return(nil)
 
-		err := service.DeleteDeployment(context.Background(), 1, "test-pod")
+		err := service.DeleteDeployment(context.Background(), 1, "default", "web")
 		assert.NoError(t, err)
 
-		store.AssertExpectations(t)
 		k8sClient.AssertExpectations(t)
 	})
 
 	t.Run("should return an error when the store fails", func(t *testing.T) {
 		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
+		service := NewDeploymentService(store, &MockKubeClientProvider{}, logger.Default())
 
 		store.On("GetCluster", uint(1)).Return(nil, errors.New("store error"))
 
-		err := service.DeleteDeployment(context.Background(), 1, "test-pod")
+		err := service.DeleteDeployment(context.Background(), 1, "default", "web")
 		assert.Error(t, err)
 
 		store.AssertExpectations(t)
 	})
 
 	t.Run("should return an error when the k8s client fails", func(t *testing.T) {
-		store := &MockStore{}
-		k8sClient := &MockK8sClient{}
-		service := NewDeploymentService(store, k8sClient)
-
-		cluster := &models.Cluster{
-			ID:   1,
-			Name: "test-cluster",
-		}
+		service, _, k8sClient := setupDeploymentService()
 
-		store.On("GetCluster", uint(1)).Return(cluster, nil)
-		k8sClient.On("DeletePod", mock.Anything, "test-cluster", "test-pod").Return(errors.New("k8s error"))
+		k8sClient.On("DeleteDeployment", mock.Anything, "default", "web").Return(errors.New("k8s error"))
 
-		err := service.DeleteDeployment(context.Background(), 1, "test-pod")
+		err := service.DeleteDeployment(context.Background(), 1, "default", "web")
 		assert.Error(t, err)
 
-		store.AssertExpectations(t)
 		k8sClient.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestDeploymentService_GetRolloutStatus(t *testing.T) {
+	service, _, k8sClient := setupDeploymentService()
+
+	k8sClient.On("GetRolloutStatus", mock.Anything, "default", "web").
+		Return(&k8s.RolloutStatus{State: k8s.RolloutComplete, Revision: 2}, nil)
+
+	status, err := service.GetRolloutStatus(context.Background(), 1, "default", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, k8s.RolloutComplete, status.State)
+
+	k8sClient.AssertExpectations(t)
+}
+
+func TestDeploymentService_RollbackDeployment(t *testing.T) {
+	t.Run("should roll back to a revision", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+
+		k8sClient.On("RollbackDeployment", mock.Anything, "default", "web", int64(1)).Return(testDeployment(), nil)
+
+		_, err := service.RollbackDeployment(context.Background(), 1, "default", "web", 1)
+		assert.NoError(t, err)
+
+		k8sClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject revisions the deployment does not have", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+
+		k8sClient.On("RollbackDeployment", mock.Anything, "default", "web", int64(7)).
+			Return(nil, fmt.Errorf("%w: deployment web has no revision 7", k8s.ErrRevisionNotFound))
+
+		_, err := service.RollbackDeployment(context.Background(), 1, "default", "web", 7)
+		assert.True(t, IsValidationFailed(err))
+
+		k8sClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject negative revisions", func(t *testing.T) {
+		service, _, _ := setupDeploymentService()
+
+		_, err := service.RollbackDeployment(context.Background(), 1, "default", "web", -1)
+		assert.True(t, IsValidationFailed(err))
+	})
+}
+
+func TestDeploymentService_ApplyManifests(t *testing.T) {
+	manifests := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n")
+	opts := k8s.ApplyOptions{DryRun: true}
+
+	t.Run("should return the result of each object", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+		results := []k8s.ApplyResult{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings", Action: k8s.ApplyCreated}}
+
+		k8sClient.On("Apply", mock.Anything, manifests, opts).Return(results, nil)
+
+		applied, err := service.ApplyManifests(context.Background(), 1, manifests, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, results, applied)
+
+		k8sClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid manifests", func(t *testing.T) {
+		service, _, k8sClient := setupDeploymentService()
+
+		_, err := service.ApplyManifests(context.Background(), 1, nil, opts)
+		assert.True(t, IsValidationFailed(err))
+
+		k8sClient.On("Apply", mock.Anything, manifests, opts).
+			Return(nil, fmt.Errorf("%w: document 1: kind is required", k8s.ErrInvalidManifest))
+
+		_, err = service.ApplyManifests(context.Background(), 1, manifests, opts)
+		assert.True(t, IsValidationFailed(err))
+
+		k8sClient.AssertExpectations(t)
+	})
+}
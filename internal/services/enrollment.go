@@ -0,0 +1,531 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+)
+
+// Bootstrap tokens are "<token_id>.<secret>" of lower-case letters and digits
+const (
+	tokenIDLength     = 6
+	tokenSecretLength = 16
+	tokenAlphabet     = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// errInvalidBootstrapToken is returned for every token that cannot be used,
+// so callers cannot tell unknown tokens from used or expired ones
+var errInvalidBootstrapToken = errors.Wrapf(ErrUnauthorized, "invalid bootstrap token")
+
+// EnrollmentService runs the controller's certificate authority. Agents
+// enroll with a one-time bootstrap token and a certificate signing request,
+// and get a certificate bound to their node that they renew before it
+// expires. Renewing revokes the certificates the new one replaces. Revoking a
+// node's certificates closes the connections to and from it and refuses the
+// agent's.
+type EnrollmentService struct {
+	db       *storage.Database
+	nodes    *NodeService
+	ca       *pki.CA
+	certTTL  time.Duration
+	tokenTTL time.Duration
+	logger   logger.Interface
+	onRevoke []func(nodeID uint)
+
+	enrollMu sync.Mutex // serializes enrollments
+
+	mu             sync.RWMutex
+	revoked        map[string]bool // serial numbers of revoked, unexpired certificates
+	controllerCert *tls.Certificate
+}
+
+// NewEnrollmentService creates a new enrollment service issuing certificates
+// valid for certTTL and bootstrap tokens valid for tokenTTL unless requested
+// otherwise. Revocations recorded in the inventory are loaded.
+func NewEnrollmentService(db *storage.Database, nodes *NodeService, ca *pki.CA, certTTL, tokenTTL time.Duration, logger logger.Interface) (*EnrollmentService, error) {
+	s := &EnrollmentService{
+		db:       db,
+		nodes:    nodes,
+		ca:       ca,
+		certTTL:  certTTL,
+		tokenTTL: tokenTTL,
+		logger:   logger.WithField("service", "enrollment"),
+		revoked:  make(map[string]bool),
+	}
+
+	var certs []models.NodeCertificate
+	err := db.DB().Where("revoked_at IS NOT NULL AND not_after > ?", time.Now()).Find(&certs).Error
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load revoked certificates")
+	}
+	for _, cert := range certs {
+		s.revoked[cert.SerialNumber] = true
+	}
+	return s, nil
+}
+
+// AddRevocationHandler adds a function called with a node's ID after its
+// certificates are revoked, used to drop connections to and from it
+func (s *EnrollmentService) AddRevocationHandler(fn func(nodeID uint)) {
+	s.onRevoke = append(s.onRevoke, fn)
+}
+
+// CA returns the certificate authority
+func (s *EnrollmentService) CA() *pki.CA {
+	return s.ca
+}
+
+// CreateBootstrapTokenRequest represents the request to create a bootstrap
+// token
+type CreateBootstrapTokenRequest struct {
+	Description string `json:"description" validate:"max=255"`
+	// TTL is how long the token is valid, e.g. "1h"; defaults to the
+	// configured bootstrap token TTL
+	TTL string `json:"ttl"`
+	// NodeID binds the token to an existing node. Otherwise the agent's node
+	// is matched by MAC address or serial number, or created.
+	NodeID *uint `json:"node_id,omitempty"`
+}
+
+// EnrollRequest represents an agent's enrollment
+type EnrollRequest struct {
+	BootstrapToken string
+	CSR            []byte // PEM certificate signing request
+	Name           string
+	IPAddress      string
+	MACAddress     string
+	Architecture   string
+	Model          string
+	SerialNumber   string
+	CPUCores       int
+	Memory         int64
+}
+
+// CreateBootstrapToken creates a bootstrap token. The token itself is only
+// returned here; the inventory keeps a hash of its secret.
+func (s *EnrollmentService) CreateBootstrapToken(req CreateBootstrapTokenRequest) (*models.BootstrapToken, string, error) {
+	ttl := s.tokenTTL
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			return nil, "", errors.Wrapf(ErrValidationFailed, "ttl must be a positive duration")
+		}
+	}
+	if len(req.Description) > 255 {
+		return nil, "", errors.Wrapf(ErrValidationFailed, "description must be at most 255 characters")
+	}
+	if req.NodeID != nil {
+		if _, err := s.nodes.GetByID(*req.NodeID, false); err != nil {
+			return nil, "", err
+		}
+	}
+
+	tokenID, err := randomString(tokenIDLength)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(tokenSecretLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.BootstrapToken{
+		TokenID:     tokenID,
+		SecretHash:  hashSecret(secret),
+		Description: req.Description,
+		NodeID:      req.NodeID,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if err := s.db.DB().Create(token).Error; err != nil {
+		return nil, "", errors.Wrapf(err, "failed to create bootstrap token")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"token_id":   token.TokenID,
+		"expires_at": token.ExpiresAt,
+	}).Info("Created bootstrap token")
+	return token, tokenID + "." + secret, nil
+}
+
+// ListBootstrapTokens returns bootstrap tokens, newest first
+func (s *EnrollmentService) ListBootstrapTokens() ([]models.BootstrapToken, error) {
+	var tokens []models.BootstrapToken
+	if err := s.db.DB().Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to list bootstrap tokens")
+	}
+	return tokens, nil
+}
+
+// DeleteBootstrapToken deletes a bootstrap token so it can no longer be used
+func (s *EnrollmentService) DeleteBootstrapToken(id uint) error {
+	result := s.db.DB().Delete(&models.BootstrapToken{}, id)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "failed to delete bootstrap token")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(ErrNotFound, "bootstrap token %d not found", id)
+	}
+	return nil
+}
+
+// Enroll consumes a bootstrap token and issues the agent a certificate for
+// its node, returning the node and the PEM certificate
+func (s *EnrollmentService) Enroll(req EnrollRequest) (*models.Node, []byte, error) {
+	if _, err := pki.ParseCertificateRequest(req.CSR); err != nil {
+		return nil, nil, errors.Wrapf(ErrValidationFailed, "%v", err)
+	}
+
+	s.enrollMu.Lock()
+	defer s.enrollMu.Unlock()
+
+	token, err := s.findBootstrapToken(req.BootstrapToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, err := s.enrollingNode(token, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, certPEM, err := s.ca.SignNodeCertificate(req.CSR, node.ID, s.certTTL)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrValidationFailed, "%v", err)
+	}
+
+	err = s.db.DB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.BootstrapToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Updates(map[string]interface{}{"used_at": time.Now(), "node_id": node.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errInvalidBootstrapToken
+		}
+		return tx.Create(certificateRecord(node.ID, cert)).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Wrapf(err, "failed to record enrollment")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"node_id":   node.ID,
+		"name":      node.Name,
+		"token_id":  token.TokenID,
+		"serial":    pki.SerialString(cert),
+		"not_after": cert.NotAfter,
+	}).Info("Enrolled agent")
+	return node, certPEM, nil
+}
+
+// findBootstrapToken returns the usable token matching a presented token
+func (s *EnrollmentService) findBootstrapToken(presented string) (*models.BootstrapToken, error) {
+	tokenID, secret, ok := strings.Cut(presented, ".")
+	if !ok || tokenID == "" || secret == "" {
+		return nil, errInvalidBootstrapToken
+	}
+
+	var token models.BootstrapToken
+	err := s.db.DB().Where("token_id = ?", tokenID).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvalidBootstrapToken
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get bootstrap token")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(token.SecretHash)) != 1 || !token.IsUsable(time.Now()) {
+		s.logger.WithField("token_id", tokenID).Warn("Rejected bootstrap token")
+		return nil, errInvalidBootstrapToken
+	}
+	return &token, nil
+}
+
+// enrollingNode returns the node a token enrolls: the token's node, the
+// inventory node with the agent's hardware identity, or a new node. A node
+// matched by hardware must not hold a valid certificate, since the identity
+// the agent reports is not proof it is that node; re-enrolling a node that
+// still has one takes a token bound to it.
+func (s *EnrollmentService) enrollingNode(token *models.BootstrapToken, req EnrollRequest) (*models.Node, error) {
+	if token.NodeID != nil {
+		return s.nodes.GetByID(*token.NodeID, false)
+	}
+
+	node, err := findNodeByHardware(s.db, normalizeMAC(req.MACAddress), req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil {
+		var active int64
+		err := s.db.DB().Model(&models.NodeCertificate{}).
+			Where("node_id = ? AND revoked_at IS NULL AND not_after > ?", node.ID, time.Now()).
+			Count(&active).Error
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check certificates of node %d", node.ID)
+		}
+		if active > 0 {
+			s.logger.WithFields(map[string]interface{}{
+				"node_id":  node.ID,
+				"token_id": token.TokenID,
+			}).Warn("Refused to enroll an agent as a node that is already enrolled")
+			return nil, errors.Wrapf(ErrForbidden, "node %d is already enrolled, revoke its certificates or use a token bound to it", node.ID)
+		}
+		return node, nil
+	}
+
+	return s.nodes.Create(CreateNodeRequest{
+		Name:         req.Name,
+		IPAddress:    req.IPAddress,
+		MACAddress:   req.MACAddress,
+		Role:         models.NodeRoleWorker,
+		Architecture: req.Architecture,
+		Model:        req.Model,
+		SerialNumber: req.SerialNumber,
+		CPUCores:     req.CPUCores,
+		Memory:       req.Memory,
+	})
+}
+
+// Renew issues an enrolled node a new certificate for a new key and revokes
+// the node's other certificates, so an old key stops working once the agent
+// has moved on. The caller has authenticated the node by its current
+// certificate.
+func (s *EnrollmentService) Renew(nodeID uint, csrPEM []byte) (*models.Node, []byte, error) {
+	node, err := s.nodes.GetByID(nodeID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, certPEM, err := s.ca.SignNodeCertificate(csrPEM, node.ID, s.certTTL)
+	if err != nil {
+		return nil, nil, errors.Wrapf(ErrValidationFailed, "%v", err)
+	}
+
+	s.mu.Lock()
+	var replaced []models.NodeCertificate
+	err = s.db.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		if replaced, err = revokeCertificates(tx, node.ID, time.Now()); err != nil {
+			return err
+		}
+		return tx.Create(certificateRecord(node.ID, cert)).Error
+	})
+	if err == nil {
+		for _, old := range replaced {
+			s.revoked[old.SerialNumber] = true
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to record certificate")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"node_id":   node.ID,
+		"serial":    pki.SerialString(cert),
+		"not_after": cert.NotAfter,
+		"revoked":   len(replaced),
+	}).Info("Renewed agent certificate")
+	return node, certPEM, nil
+}
+
+// ListCertificates returns the certificates issued to a node, newest first
+func (s *EnrollmentService) ListCertificates(nodeID uint) ([]models.NodeCertificate, error) {
+	if _, err := s.nodes.GetByID(nodeID, false); err != nil {
+		return nil, err
+	}
+
+	var certs []models.NodeCertificate
+	if err := s.db.DB().Where("node_id = ?", nodeID).Order("not_before DESC").Find(&certs).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to list certificates")
+	}
+	return certs, nil
+}
+
+// RevokeNodeCertificates revokes every unexpired certificate issued to a
+// node and returns how many were revoked. The agent must enroll again with
+// a new bootstrap token.
+func (s *EnrollmentService) RevokeNodeCertificates(nodeID uint) (int, error) {
+	if _, err := s.nodes.GetByID(nodeID, false); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	var certs []models.NodeCertificate
+	err := s.db.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		certs, err = revokeCertificates(tx, nodeID, time.Now())
+		return err
+	})
+	if err == nil {
+		for _, cert := range certs {
+			s.revoked[cert.SerialNumber] = true
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to revoke certificates")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"node_id": nodeID,
+		"count":   len(certs),
+	}).Warn("Revoked node certificates")
+	for _, fn := range s.onRevoke {
+		fn(nodeID)
+	}
+	return len(certs), nil
+}
+
+// revokeCertificates marks a node's unexpired certificates revoked at now and
+// returns them
+func revokeCertificates(tx *gorm.DB, nodeID uint, now time.Time) ([]models.NodeCertificate, error) {
+	var certs []models.NodeCertificate
+	if err := tx.Where("node_id = ? AND revoked_at IS NULL AND not_after > ?", nodeID, now).Find(&certs).Error; err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(certs))
+	for _, cert := range certs {
+		ids = append(ids, cert.ID)
+	}
+	if err := tx.Model(&models.NodeCertificate{}).Where("id IN ?", ids).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// IsRevoked returns true if the certificate has been revoked
+func (s *EnrollmentService) IsRevoked(cert *x509.Certificate) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revoked[pki.SerialString(cert)]
+}
+
+// AuthenticateNode returns the node a verified peer certificate was issued
+// to
+func (s *EnrollmentService) AuthenticateNode(cert *x509.Certificate) (uint, error) {
+	nodeID, err := pki.NodeIDFromCertificate(cert)
+	if err != nil {
+		return 0, errors.Wrapf(ErrUnauthorized, "%v", err)
+	}
+	if s.IsRevoked(cert) {
+		return 0, errors.Wrapf(ErrUnauthorized, "certificate of node %d has been revoked", nodeID)
+	}
+	return nodeID, nil
+}
+
+// ControllerCertificate returns the controller's certificate, issuing a new
+// one when the current one is due for renewal
+func (s *EnrollmentService) ControllerCertificate() (*tls.Certificate, error) {
+	s.mu.RLock()
+	cert := s.controllerCert
+	s.mu.RUnlock()
+	if cert != nil && !pki.NeedsRenewal(cert.Leaf, time.Now()) {
+		return cert, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.controllerCert != nil && !pki.NeedsRenewal(s.controllerCert.Leaf, time.Now()) {
+		return s.controllerCert, nil
+	}
+	issued, err := s.ca.IssueControllerCertificate(s.certTTL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to issue controller certificate")
+	}
+	s.controllerCert = &issued
+	return s.controllerCert, nil
+}
+
+// ServerTLSConfig returns the configuration for the controller's gRPC
+// server. Agents present their certificate; callers without one, such as
+// enrolling agents and API clients with a token, are authenticated per RPC.
+func (s *EnrollmentService) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.ControllerCertificate()
+		},
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  s.ca.Pool(),
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) > 0 && s.IsRevoked(state.PeerCertificates[0]) {
+				return fmt.Errorf("certificate has been revoked")
+			}
+			return nil
+		},
+	}
+}
+
+// ClientTLSConfig returns the configuration for dialing agents with the
+// controller's certificate. The caller sets ServerName to the node's
+// certificate name.
+func (s *EnrollmentService) ClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    s.ca.Pool(),
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return s.ControllerCertificate()
+		},
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) > 0 && s.IsRevoked(state.PeerCertificates[0]) {
+				return fmt.Errorf("agent certificate has been revoked")
+			}
+			return nil
+		},
+	}
+}
+
+// certificateRecord returns the inventory record of an issued certificate
+func certificateRecord(nodeID uint, cert *x509.Certificate) *models.NodeCertificate {
+	return &models.NodeCertificate{
+		NodeID:       nodeID,
+		SerialNumber: pki.SerialString(cert),
+		Fingerprint:  pki.Fingerprint(cert),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+	}
+}
+
+// hashSecret returns the hex SHA-256 of a bootstrap token secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomString returns n random characters of the token alphabet
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	max := big.NewInt(int64(len(tokenAlphabet)))
+	for i := range buf {
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to generate token")
+		}
+		buf[i] = tokenAlphabet[index.Int64()]
+	}
+	return string(buf), nil
+}
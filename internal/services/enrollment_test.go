@@ -0,0 +1,183 @@
+package services
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/pki"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+func setupEnrollment(t *testing.T) (*EnrollmentService, *NodeService) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	ca, err := pki.LoadOrCreateCA(t.TempDir())
+	require.NoError(t, err)
+	nodes := NewNodeService(db, logger.Default())
+	enrollment, err := NewEnrollmentService(db, nodes, ca, time.Hour, time.Hour, logger.Default())
+	require.NoError(t, err)
+	return enrollment, nodes
+}
+
+func enrollRequest(t *testing.T, token string) EnrollRequest {
+	_, csr, err := pki.NewCSR()
+	require.NoError(t, err)
+	return EnrollRequest{
+		BootstrapToken: token,
+		CSR:            csr,
+		Name:           "pi-kitchen",
+		IPAddress:      "192.168.1.10",
+		MACAddress:     "dc:a6:32:00:00:01",
+		Architecture:   "arm64",
+		Model:          "Raspberry Pi 4 Model B",
+		SerialNumber:   "10000000abcdef01",
+		CPUCores:       4,
+		Memory:         4 << 30,
+	}
+}
+
+func TestEnrollmentService_Enroll(t *testing.T) {
+	enrollment, nodes := setupEnrollment(t)
+
+	record, token, err := enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{Description: "kitchen"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^[a-z0-9]{6}\.[a-z0-9]{16}$`, token)
+	assert.NotContains(t, record.SecretHash, token[7:], "only a hash of the secret is stored")
+
+	_, _, err = enrollment.Enroll(enrollRequest(t, record.TokenID+".wrongsecret12345"))
+	assert.True(t, IsUnauthorized(err))
+
+	node, certPEM, err := enrollment.Enroll(enrollRequest(t, token))
+	require.NoError(t, err)
+	assert.Equal(t, "pi-kitchen", node.Name)
+
+	nodeID, err := enrollment.AuthenticateNode(testCertificate(t, certPEM))
+	require.NoError(t, err)
+	assert.Equal(t, node.ID, nodeID)
+
+	_, _, err = enrollment.Enroll(enrollRequest(t, token))
+	assert.True(t, IsUnauthorized(err), "tokens are single use")
+
+	tokens, err := enrollment.ListBootstrapTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].UsedAt)
+	assert.Equal(t, node.ID, *tokens[0].NodeID)
+
+	// An unbound token cannot take over an enrolled node by reporting its
+	// hardware identity
+	_, token, err = enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{})
+	require.NoError(t, err)
+	_, _, err = enrollment.Enroll(enrollRequest(t, token))
+	assert.True(t, IsForbidden(err), "got %v", err)
+
+	// Once its certificates are revoked, re-enrolling a reimaged Pi matches
+	// its node by MAC address
+	_, err = enrollment.RevokeNodeCertificates(node.ID)
+	require.NoError(t, err)
+	again, _, err := enrollment.Enroll(enrollRequest(t, token))
+	require.NoError(t, err)
+	assert.Equal(t, node.ID, again.ID)
+	_, total, err := nodes.List(NodeListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// A token bound to a node enrolls the agent as that node
+	other, err := nodes.Create(CreateNodeRequest{Name: "pi-garage", IPAddress: "192.168.1.11", MACAddress: "dc:a6:32:00:00:02", Role: models.NodeRoleWorker, CPUCores: 4, Memory: 1})
+	require.NoError(t, err)
+	_, token, err = enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{NodeID: &other.ID})
+	require.NoError(t, err)
+	bound, _, err := enrollment.Enroll(enrollRequest(t, token))
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, bound.ID)
+
+	expired, token, err := enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{TTL: "1ms"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !expired.IsUsable(time.Now()) }, time.Second, time.Millisecond)
+	_, _, err = enrollment.Enroll(enrollRequest(t, token))
+	assert.True(t, IsUnauthorized(err), "expired tokens are rejected")
+
+	_, _, err = enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{TTL: "soon"})
+	assert.True(t, IsValidationFailed(err))
+
+	req := enrollRequest(t, token)
+	req.CSR = []byte("not a csr")
+	_, _, err = enrollment.Enroll(req)
+	assert.True(t, IsValidationFailed(err))
+}
+
+func TestEnrollmentService_RenewAndRevoke(t *testing.T) {
+	enrollment, _ := setupEnrollment(t)
+
+	_, token, err := enrollment.CreateBootstrapToken(CreateBootstrapTokenRequest{})
+	require.NoError(t, err)
+	node, certPEM, err := enrollment.Enroll(enrollRequest(t, token))
+	require.NoError(t, err)
+	enrolled := testCertificate(t, certPEM)
+
+	_, csr, err := pki.NewCSR()
+	require.NoError(t, err)
+	_, renewedPEM, err := enrollment.Renew(node.ID, csr)
+	require.NoError(t, err)
+	renewed := testCertificate(t, renewedPEM)
+	assert.NotEqual(t, enrolled.SerialNumber, renewed.SerialNumber)
+
+	// Renewing revokes the certificate it replaces
+	certs, err := enrollment.ListCertificates(node.ID)
+	require.NoError(t, err)
+	assert.Len(t, certs, 2)
+	_, err = enrollment.AuthenticateNode(enrolled)
+	assert.True(t, IsUnauthorized(err), "the replaced certificate is revoked")
+	_, err = enrollment.AuthenticateNode(renewed)
+	require.NoError(t, err)
+
+	var revokedNode uint
+	enrollment.AddRevocationHandler(func(nodeID uint) { revokedNode = nodeID })
+	count, err := enrollment.RevokeNodeCertificates(node.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, node.ID, revokedNode)
+
+	_, err = enrollment.AuthenticateNode(enrolled)
+	assert.True(t, IsUnauthorized(err))
+	_, err = enrollment.AuthenticateNode(renewed)
+	assert.True(t, IsUnauthorized(err))
+
+	// Revocations survive a restart
+	restarted, err := NewEnrollmentService(enrollment.db, enrollment.nodes, enrollment.ca, time.Hour, time.Hour, logger.Default())
+	require.NoError(t, err)
+	assert.True(t, restarted.IsRevoked(renewed))
+
+	_, err = enrollment.RevokeNodeCertificates(999)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestEnrollmentService_ControllerCertificate(t *testing.T) {
+	enrollment, _ := setupEnrollment(t)
+
+	cert, err := enrollment.ControllerCertificate()
+	require.NoError(t, err)
+	assert.Equal(t, pki.ControllerName, cert.Leaf.Subject.CommonName)
+
+	again, err := enrollment.ControllerCertificate()
+	require.NoError(t, err)
+	assert.Same(t, cert, again, "the certificate is reused until it is due for renewal")
+}
+
+func testCertificate(t *testing.T, certPEM []byte) *x509.Certificate {
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
@@ -1,6 +1,11 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	apperrors "github.com/dsyorkd/pi-controller/internal/errors"
+)
 
 // Common service errors
 var (
@@ -32,6 +37,20 @@ var (
 	ErrInternalError = errors.New("internal server error")
 )
 
+// AgentUnreachableError indicates the pi-agent on a node could not be reached
+type AgentUnreachableError struct {
+	NodeID uint
+	Err    error
+}
+
+func (e *AgentUnreachableError) Error() string {
+	return fmt.Sprintf("agent on node %d is unreachable: %v", e.NodeID, e.Err)
+}
+
+func (e *AgentUnreachableError) Unwrap() error {
+	return e.Err
+}
+
 // IsNotFound checks if error is ErrNotFound
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -65,4 +84,16 @@ func IsConflict(err error) bool {
 // IsValidationFailed checks if error is ErrValidationFailed
 func IsValidationFailed(err error) bool {
 	return errors.Is(err, ErrValidationFailed)
-}
\ No newline at end of file
+}
+
+// IsAgentUnreachable checks if error is an AgentUnreachableError
+func IsAgentUnreachable(err error) bool {
+	var agentErr *AgentUnreachableError
+	return errors.As(err, &agentErr)
+}
+
+// IsGPIOError checks if error is a GPIO operation failure reported by an agent
+func IsGPIOError(err error) bool {
+	var gpioErr *apperrors.GPIOError
+	return errors.As(err, &gpioErr)
+}
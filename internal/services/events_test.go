@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+// nextEvent returns the next queued event, failing if none was published
+func nextEvent(t *testing.T, sub *events.Subscription) events.Event {
+	t.Helper()
+	select {
+	case event := <-sub.C():
+		return event
+	default:
+		require.FailNow(t, "expected an event to be published")
+		return events.Event{}
+	}
+}
+
+func TestServices_PublishStateChanges(t *testing.T) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(nil, 0)
+	defer sub.Close()
+
+	clusterService := NewClusterService(db, logger.Default())
+	clusterService.SetEventBus(bus)
+	nodeService := NewNodeService(db, logger.Default())
+	nodeService.SetEventBus(bus)
+	gpioService := NewGPIOService(db, logger.Default())
+	gpioService.SetEventBus(bus)
+
+	cluster, err := clusterService.Create(CreateClusterRequest{Name: "lab"})
+	require.NoError(t, err)
+	clusterEvent := nextEvent(t, sub).Payload.(events.Cluster)
+	assert.Equal(t, events.ActionCreated, clusterEvent.Action)
+	assert.Equal(t, cluster.ID, clusterEvent.ClusterID)
+
+	node, err := nodeService.Create(CreateNodeRequest{
+		Name:       "pi-1",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+	nodeEvent := nextEvent(t, sub).Payload.(events.Node)
+	assert.Equal(t, events.ActionCreated, nodeEvent.Action)
+	assert.Equal(t, string(models.NodeStatusDiscovered), nodeEvent.Status)
+
+	require.NoError(t, nodeService.Provision(node.ID, cluster.ID))
+	nodeEvent = nextEvent(t, sub).Payload.(events.Node)
+	assert.Equal(t, string(models.NodeStatusProvisioning), nodeEvent.Status)
+	require.NotNil(t, nodeEvent.ClusterID)
+	assert.Equal(t, cluster.ID, *nodeEvent.ClusterID)
+
+	ready := models.NodeStatusReady
+	_, err = nodeService.Update(node.ID, UpdateNodeRequest{Status: &ready})
+	require.NoError(t, err)
+	assert.Equal(t, string(ready), nextEvent(t, sub).Payload.(events.Node).Status)
+
+	// Losing the agent flips a ready node to not ready
+	require.NoError(t, nodeService.HandleAgentStateChange(node.ID, false))
+	assert.Equal(t, string(models.NodeStatusNotReady), nextEvent(t, sub).Payload.(events.Node).Status)
+
+	// A reachability report that changes nothing publishes nothing
+	require.NoError(t, nodeService.HandleAgentStateChange(node.ID, false))
+	assert.Empty(t, sub.C())
+
+	degraded := models.ClusterStatusDegraded
+	_, err = clusterService.Update(cluster.ID, UpdateClusterRequest{Status: &degraded})
+	require.NoError(t, err)
+	clusterEvent = nextEvent(t, sub).Payload.(events.Cluster)
+	assert.Equal(t, string(degraded), clusterEvent.Status)
+	assert.Equal(t, 1, clusterEvent.NodesTotal)
+	assert.Equal(t, 0, clusterEvent.NodesReady)
+
+	device, err := gpioService.Create(CreateGPIODeviceRequest{
+		Name:      "led",
+		NodeID:    node.ID,
+		PinNumber: 18,
+		Direction: models.GPIODirectionOutput,
+	})
+	require.NoError(t, err)
+	deviceEvent := nextEvent(t, sub).Payload.(events.GPIODevice)
+	assert.Equal(t, events.ActionCreated, deviceEvent.Action)
+	assert.Equal(t, node.ID, deviceEvent.NodeID)
+
+	require.NoError(t, gpioService.Delete(device.ID))
+	assert.Equal(t, events.ActionDeleted, nextEvent(t, sub).Payload.(events.GPIODevice).Action)
+
+	require.NoError(t, nodeService.Deprovision(context.Background(), node.ID, DeprovisionOptions{}))
+	nodeEvent = nextEvent(t, sub).Payload.(events.Node)
+	assert.Nil(t, nodeEvent.ClusterID)
+
+	require.NoError(t, nodeService.Delete(node.ID))
+	assert.Equal(t, events.ActionDeleted, nextEvent(t, sub).Payload.(events.Node).Action)
+
+	require.NoError(t, clusterService.Delete(cluster.ID))
+	assert.Equal(t, events.ActionDeleted, nextEvent(t, sub).Payload.(events.Cluster).Action)
+
+	assert.Zero(t, sub.Dropped())
+}
@@ -1,30 +1,57 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 
 	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
 	"github.com/dsyorkd/pi-controller/internal/logger"
 	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/internal/storage"
+	pb "github.com/dsyorkd/pi-controller/proto"
 )
 
+// agentCallTimeout bounds each gRPC call made to a node's agent
+const agentCallTimeout = 5 * time.Second
+
 // GPIOService handles GPIO device business logic
 type GPIOService struct {
 	db     *storage.Database
 	logger logger.Interface
+	agents AgentConnector
+	bus    *events.Bus
+
+	watchMu sync.Mutex
+	watches map[uint]*edgeWatch // running edge streams by device ID
 }
 
 // NewGPIOService creates a new GPIO service
 func NewGPIOService(db *storage.Database, logger logger.Interface) *GPIOService {
 	return &GPIOService{
-		db:     db,
-		logger: logger.WithField("service", "gpio"),
+		db:      db,
+		logger:  logger.WithField("service", "gpio"),
+		bus:     events.NewBus(),
+		watches: make(map[uint]*edgeWatch),
 	}
 }
 
+// SetEventBus sets the bus device changes and readings are published to
+func (s *GPIOService) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// SetAgentConnector sets the connector used to reach the pi-agent on each node
+func (s *GPIOService) SetAgentConnector(agents AgentConnector) {
+	s.agents = agents
+}
+
 // CreateGPIODeviceRequest represents the request to create a GPIO device
 type CreateGPIODeviceRequest struct {
 	Name        string                   `json:"name" validate:"required,min=1,max=100"`
@@ -43,6 +70,7 @@ type UpdateGPIODeviceRequest struct {
 	Description *string                  `json:"description,omitempty" validate:"omitempty,max=500"`
 	Direction   *models.GPIODirection    `json:"direction,omitempty" validate:"omitempty,oneof=input output"`
 	PullMode    *models.GPIOPullMode     `json:"pull_mode,omitempty" validate:"omitempty,oneof=none up down"`
+	DeviceType  *models.GPIODeviceType   `json:"device_type,omitempty" validate:"omitempty,oneof=digital analog pwm spi i2c"`
 	Status      *models.GPIOStatus       `json:"status,omitempty" validate:"omitempty,oneof=active inactive error"`
 	Config      *models.GPIOConfig       `json:"config,omitempty"`
 }
@@ -157,6 +185,16 @@ func (s *GPIOService) GetByNodeAndPin(nodeID uint, pinNumber int) (*models.GPIOD
 
 // Create creates a new GPIO device
 func (s *GPIOService) Create(req CreateGPIODeviceRequest) (*models.GPIODevice, error) {
+	if err := validateCreateGPIODeviceRequest(req); err != nil {
+		return nil, err
+	}
+	if req.PullMode == "" {
+		req.PullMode = models.GPIOPullNone
+	}
+	if req.DeviceType == "" {
+		req.DeviceType = models.GPIODeviceTypeDigital
+	}
+
 	// Validate node exists
 	var node models.Node
 	if err := s.db.DB().First(&node, req.NodeID).Error; err != nil {
@@ -203,11 +241,17 @@ func (s *GPIOService) Create(req CreateGPIODeviceRequest) (*models.GPIODevice, e
 		"pin_number": device.PinNumber,
 	}).Info("GPIO device created successfully")
 
+	s.publishDevice(events.ActionCreated, &device)
+
 	return &device, nil
 }
 
 // Update updates an existing GPIO device
 func (s *GPIOService) Update(id uint, req UpdateGPIODeviceRequest) (*models.GPIODevice, error) {
+	if err := validateUpdateGPIODeviceRequest(req); err != nil {
+		return nil, err
+	}
+
 	device, err := s.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -226,12 +270,18 @@ func (s *GPIOService) Update(id uint, req UpdateGPIODeviceRequest) (*models.GPIO
 	if req.PullMode != nil {
 		device.PullMode = *req.PullMode
 	}
+	if req.DeviceType != nil {
+		device.DeviceType = *req.DeviceType
+	}
 	if req.Status != nil {
 		device.Status = *req.Status
 	}
 	if req.Config != nil {
 		device.Config = *req.Config
 	}
+	if device.Config.Edge != "" && !device.IsInput() {
+		return nil, errors.Wrapf(ErrValidationFailed, "edge detection requires an input device")
+	}
 
 	if err := s.db.DB().Save(device).Error; err != nil {
 		s.logger.WithFields(map[string]interface{}{
@@ -246,6 +296,8 @@ func (s *GPIOService) Update(id uint, req UpdateGPIODeviceRequest) (*models.GPIO
 		"name": device.Name,
 	}).Info("GPIO device updated successfully")
 
+	s.publishDevice(events.ActionUpdated, device)
+
 	return device, nil
 }
 
@@ -279,6 +331,8 @@ func (s *GPIOService) Delete(id uint) error {
 		"name": device.Name,
 	}).Info("GPIO device deleted successfully")
 
+	s.publishDevice(events.ActionDeleted, device)
+
 	return nil
 }
 
@@ -293,18 +347,40 @@ func (s *GPIOService) Read(id uint) (*models.GPIODevice, error) {
 		return nil, errors.Wrapf(ErrValidationFailed, "GPIO device %d is not active", id)
 	}
 
-	// TODO: Implement actual GPIO reading from hardware
-	// For now, we'll just return the current stored value
-	// In a real implementation, this would:
-	// 1. Connect to the node's agent via gRPC
-	// 2. Request a GPIO read operation
-	// 3. Update the device value and create a reading record
+	ctx, cancel := context.WithTimeout(context.Background(), agentCallTimeout)
+	defer cancel()
+
+	client, err := s.configurePin(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.ReadGPIOPin(ctx, &pb.ReadGPIOPinRequest{Pin: int32(device.PinNumber)})
+	if err != nil {
+		return nil, s.agentError(device, "read", err)
+	}
+
+	timestamp := time.Now()
+	if resp.Timestamp != nil {
+		timestamp = resp.Timestamp.AsTime()
+	}
+
+	// Persist the value reported by the hardware
+	device.SetValue(int(resp.Value))
+	if err := s.db.DB().Save(device).Error; err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"device_id": id,
+			"value":     resp.Value,
+			"error":     err,
+		}).Error("Failed to update GPIO device value")
+		return nil, errors.Wrapf(err, "failed to update GPIO device value")
+	}
 
 	// Create a reading record
 	reading := models.GPIOReading{
 		DeviceID:  device.ID,
-		Value:     float64(device.Value),
-		Timestamp: time.Now(),
+		Value:     float64(resp.Value),
+		Timestamp: timestamp,
 	}
 
 	if err := s.db.DB().Create(&reading).Error; err != nil {
@@ -314,6 +390,7 @@ func (s *GPIOService) Read(id uint) (*models.GPIODevice, error) {
 		}).Error("Failed to create GPIO reading")
 		return nil, errors.Wrapf(err, "failed to create GPIO reading")
 	}
+	s.publishReading(device, reading)
 
 	s.logger.WithFields(map[string]interface{}{
 		"device_id": id,
@@ -323,6 +400,42 @@ func (s *GPIOService) Read(id uint) (*models.GPIODevice, error) {
 	return device, nil
 }
 
+// Sample reads a GPIO device through its agent without storing anything. The
+// returned reading has no ID.
+func (s *GPIOService) Sample(ctx context.Context, id uint) (*models.GPIOReading, error) {
+	device, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !device.IsActive() {
+		return nil, errors.Wrapf(ErrValidationFailed, "GPIO device %d is not active", id)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, agentCallTimeout)
+	defer cancel()
+
+	client, err := s.configurePin(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.ReadGPIOPin(ctx, &pb.ReadGPIOPinRequest{Pin: int32(device.PinNumber)})
+	if err != nil {
+		return nil, s.agentError(device, "read", err)
+	}
+
+	timestamp := time.Now()
+	if resp.Timestamp != nil {
+		timestamp = resp.Timestamp.AsTime()
+	}
+	return &models.GPIOReading{
+		DeviceID:  device.ID,
+		Value:     float64(resp.Value),
+		Timestamp: timestamp,
+	}, nil
+}
+
 // Write writes a value to a GPIO device
 func (s *GPIOService) Write(id uint, value int) error {
 	device, err := s.GetByID(id)
@@ -338,12 +451,35 @@ func (s *GPIOService) Write(id uint, value int) error {
 		return errors.Wrapf(ErrValidationFailed, "GPIO device %d is not configured as output", id)
 	}
 
-	// TODO: Implement actual GPIO writing to hardware
-	// For now, we'll just update the stored value
-	// In a real implementation, this would:
-	// 1. Connect to the node's agent via gRPC
-	// 2. Send a GPIO write command
-	// 3. Update the device value upon success
+	if device.IsAllocated() {
+		return errors.Wrapf(ErrConflict, "GPIO device %d is allocated to %s", id, device.AllocatedTo)
+	}
+
+	if device.DeviceType == models.GPIODeviceTypeDigital && value != 0 && value != 1 {
+		return errors.Wrapf(ErrValidationFailed, "digital GPIO device %d only accepts values 0 or 1", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), agentCallTimeout)
+	defer cancel()
+
+	client, err := s.configurePin(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.WriteGPIOPin(ctx, &pb.WriteGPIOPinRequest{
+		Pin:   int32(device.PinNumber),
+		Value: int32(value),
+	})
+	if err != nil {
+		return s.agentError(device, "write", err)
+	}
+
+	timestamp := time.Now()
+	if resp.Timestamp != nil {
+		timestamp = resp.Timestamp.AsTime()
+	}
+	value = int(resp.Value)
 
 	// Update device value
 	device.SetValue(value)
@@ -360,7 +496,7 @@ func (s *GPIOService) Write(id uint, value int) error {
 	reading := models.GPIOReading{
 		DeviceID:  device.ID,
 		Value:     float64(value),
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 	}
 
 	if err := s.db.DB().Create(&reading).Error; err != nil {
@@ -370,6 +506,8 @@ func (s *GPIOService) Write(id uint, value int) error {
 			"error":     err,
 		}).Error("Failed to create GPIO reading after write")
 		// Don't return error here as the write was successful
+	} else {
+		s.publishReading(device, reading)
 	}
 
 	s.logger.WithFields(map[string]interface{}{
@@ -380,6 +518,153 @@ func (s *GPIOService) Write(id uint, value int) error {
 	return nil
 }
 
+// SetPWM changes the frequency and duty cycle of a PWM device and persists
+// them as the device's configuration
+func (s *GPIOService) SetPWM(id uint, frequency, dutyCycle int) (*models.GPIODevice, error) {
+	device, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !device.IsActive() {
+		return nil, errors.Wrapf(ErrValidationFailed, "GPIO device %d is not active", id)
+	}
+
+	if device.DeviceType != models.GPIODeviceTypePWM {
+		return nil, errors.Wrapf(ErrValidationFailed, "GPIO device %d is not a PWM device", id)
+	}
+
+	if device.IsAllocated() {
+		return nil, errors.Wrapf(ErrConflict, "GPIO device %d is allocated to %s", id, device.AllocatedTo)
+	}
+
+	if frequency <= 0 {
+		return nil, errors.Wrapf(ErrValidationFailed, "PWM frequency must be positive")
+	}
+
+	if dutyCycle < 0 || dutyCycle > 100 {
+		return nil, errors.Wrapf(ErrValidationFailed, "PWM duty cycle must be between 0 and 100")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), agentCallTimeout)
+	defer cancel()
+
+	client, err := s.configurePin(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.SetGPIOPWM(ctx, &pb.SetGPIOPWMRequest{
+		Pin:       int32(device.PinNumber),
+		Frequency: int32(frequency),
+		DutyCycle: int32(dutyCycle),
+	})
+	if err != nil {
+		return nil, s.agentError(device, "pwm", err)
+	}
+	if !resp.Success {
+		return nil, errors.NewGPIOError(device.PinNumber, "pwm", fmt.Errorf("%s", resp.Message))
+	}
+
+	device.Config.Frequency = int(resp.Frequency)
+	device.Config.DutyCycle = int(resp.DutyCycle)
+	if err := s.db.DB().Save(device).Error; err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"device_id": id,
+			"error":     err,
+		}).Error("Failed to update GPIO device PWM configuration")
+		return nil, errors.Wrapf(err, "failed to update GPIO device PWM configuration")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"device_id":  id,
+		"frequency":  device.Config.Frequency,
+		"duty_cycle": device.Config.DutyCycle,
+	}).Info("GPIO device PWM updated successfully")
+
+	s.publishDevice(events.ActionUpdated, device)
+
+	return device, nil
+}
+
+// configurePin connects to the device's node agent and applies the device's
+// pin configuration so reads and writes survive agent restarts. Agents leave
+// pins already configured the same way alone, so outputs keep their value.
+func (s *GPIOService) configurePin(ctx context.Context, device *models.GPIODevice) (pb.PiAgentServiceClient, error) {
+	if s.agents == nil {
+		return nil, &AgentUnreachableError{NodeID: device.NodeID, Err: fmt.Errorf("no agent connector configured")}
+	}
+
+	client, err := s.agents.Client(ctx, &device.Node)
+	if err != nil {
+		return nil, &AgentUnreachableError{NodeID: device.NodeID, Err: err}
+	}
+
+	req := &pb.ConfigureGPIOPinRequest{
+		Pin:       int32(device.PinNumber),
+		Direction: directionToAgent(device.Direction),
+		PullMode:  pullModeToAgent(device.PullMode),
+	}
+	if device.DeviceType == models.GPIODeviceTypePWM {
+		req.PwmFrequency = int32(device.Config.Frequency)
+		req.PwmDutyCycle = int32(device.Config.DutyCycle)
+	}
+
+	resp, err := client.ConfigureGPIOPin(ctx, req)
+	if err != nil {
+		return nil, s.agentError(device, "configure", err)
+	}
+	if !resp.Success {
+		return nil, errors.NewGPIOError(device.PinNumber, "configure", fmt.Errorf("%s", resp.Message))
+	}
+
+	return client, nil
+}
+
+// agentError converts an error returned by an agent call into a service error,
+// separating transport failures from failed GPIO operations
+func (s *GPIOService) agentError(device *models.GPIODevice, operation string, err error) error {
+	s.logger.WithFields(map[string]interface{}{
+		"device_id": device.ID,
+		"node_id":   device.NodeID,
+		"pin":       device.PinNumber,
+		"operation": operation,
+		"error":     err,
+	}).Error("Agent GPIO operation failed")
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return &AgentUnreachableError{NodeID: device.NodeID, Err: err}
+	}
+	return errors.NewGPIOError(device.PinNumber, operation, err)
+}
+
+// directionToAgent converts a model direction to the agent protobuf enum
+func directionToAgent(direction models.GPIODirection) pb.AgentGPIODirection {
+	switch direction {
+	case models.GPIODirectionInput:
+		return pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_INPUT
+	case models.GPIODirectionOutput:
+		return pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT
+	default:
+		return pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_UNSPECIFIED
+	}
+}
+
+// pullModeToAgent converts a model pull mode to the agent protobuf enum
+func pullModeToAgent(pullMode models.GPIOPullMode) pb.AgentGPIOPullMode {
+	switch pullMode {
+	case models.GPIOPullNone:
+		return pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_NONE
+	case models.GPIOPullUp:
+		return pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_UP
+	case models.GPIOPullDown:
+		return pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_DOWN
+	default:
+		return pb.AgentGPIOPullMode_AGENT_GPIO_PULL_MODE_UNSPECIFIED
+	}
+}
+
 // GetReadings returns GPIO readings for a device with optional filtering
 func (s *GPIOService) GetReadings(filter GPIOReadingFilter) ([]models.GPIOReading, int64, error) {
 	var readings []models.GPIOReading
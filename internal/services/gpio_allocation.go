@@ -0,0 +1,74 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+// RecordAllocations records which pods hold a node's GPIO devices, as reported
+// by the device plugin of the node's agent. allocations maps pin numbers to the
+// namespace/pod/container holding them; every other device on the node is
+// released. It returns the number of devices whose allocation changed.
+func (s *GPIOService) RecordAllocations(nodeID uint, allocations map[int]string) (int, error) {
+	var node models.Node
+	if err := s.db.DB().First(&node, nodeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.Wrapf(ErrNotFound, "node %d not found", nodeID)
+		}
+		return 0, errors.Wrapf(err, "failed to get node")
+	}
+
+	var devices []models.GPIODevice
+	if err := s.db.DB().Where("node_id = ?", nodeID).Find(&devices).Error; err != nil {
+		return 0, errors.Wrapf(err, "failed to list GPIO devices")
+	}
+
+	known := make(map[int]bool, len(devices))
+	changed := 0
+	now := time.Now()
+	for i := range devices {
+		device := &devices[i]
+		known[device.PinNumber] = true
+
+		holder := allocations[device.PinNumber]
+		if holder == device.AllocatedTo {
+			continue
+		}
+
+		updates := map[string]interface{}{"allocated_to": holder, "allocated_at": nil}
+		if holder != "" {
+			updates["allocated_at"] = now
+		}
+		if err := s.db.DB().Model(device).Updates(updates).Error; err != nil {
+			return changed, errors.Wrapf(err, "failed to record allocation of GPIO device %d", device.ID)
+		}
+
+		s.logger.WithFields(map[string]interface{}{
+			"device_id":    device.ID,
+			"node_id":      nodeID,
+			"pin":          device.PinNumber,
+			"allocated_to": holder,
+			"released":     holder == "",
+		}).Info("GPIO device allocation changed")
+
+		s.publishDevice(events.ActionUpdated, device)
+		changed++
+	}
+
+	for pin, holder := range allocations {
+		if !known[pin] {
+			s.logger.WithFields(map[string]interface{}{
+				"node_id":      nodeID,
+				"pin":          pin,
+				"allocated_to": holder,
+			}).Warn("Device plugin allocated a pin with no GPIO device")
+		}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// syncGPIOLabels labels the Kubernetes node of each of nodes with the node's
+// active GPIO devices, e.g. pi-controller.io/gpio-18=pwm, removing the labels
+// of devices that are gone or no longer active
+func (s *ClusterService) syncGPIOLabels(ctx context.Context, client k8s.K8sClient, nodes []models.Node) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	nodeIDs := make([]uint, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, node.ID)
+	}
+
+	var devices []models.GPIODevice
+	err := s.store.DB().Where("node_id IN ? AND status = ?", nodeIDs, models.GPIOStatusActive).Find(&devices).Error
+	if err != nil {
+		s.log.WithError(err).Error("Failed to list GPIO devices for node labels")
+		return
+	}
+
+	labels := make(map[uint]map[string]string)
+	for i := range devices {
+		if labels[devices[i].NodeID] == nil {
+			labels[devices[i].NodeID] = make(map[string]string)
+		}
+		key, value := devices[i].NodeLabel()
+		labels[devices[i].NodeID][key] = value
+	}
+
+	for _, node := range nodes {
+		if node.NodeName == "" {
+			continue
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, kubeRequestTimeout)
+		_, err := client.SyncNodeLabels(reqCtx, node.NodeName, models.GPIONodeLabelPrefix, labels[node.ID])
+		cancel()
+		if err != nil {
+			s.log.WithError(err).WithFields(map[string]interface{}{
+				"node_id":   node.ID,
+				"node_name": node.NodeName,
+			}).Warn("Failed to sync GPIO node labels")
+		}
+	}
+}
+
+// watchGPIODevices relabels a node as soon as one of its GPIO devices changes
+// rather than waiting for the next reconcile
+func (s *ClusterService) watchGPIODevices(ctx context.Context, sub *events.Subscription) {
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-sub.C():
+			if device, ok := event.Payload.(events.GPIODevice); ok {
+				s.syncNodeGPIOLabels(ctx, device.NodeID)
+			}
+		}
+	}
+}
+
+// syncNodeGPIOLabels relabels a single node, if it has joined a cluster whose
+// API the controller can reach
+func (s *ClusterService) syncNodeGPIOLabels(ctx context.Context, nodeID uint) {
+	if s.kube == nil {
+		return
+	}
+
+	var node models.Node
+	if err := s.store.DB().Preload("Cluster").First(&node, nodeID).Error; err != nil {
+		s.log.WithError(err).WithField("node_id", nodeID).Warn("Failed to get node for GPIO labels")
+		return
+	}
+	if node.Cluster == nil || node.Cluster.KubeConfig == "" || node.NodeName == "" {
+		return
+	}
+
+	client, err := s.kube.ClientFor(node.Cluster)
+	if err != nil {
+		s.log.WithError(err).WithField("cluster_id", node.Cluster.ID).Warn("Failed to get cluster client for GPIO labels")
+		return
+	}
+	s.syncGPIOLabels(ctx, client, []models.Node{node})
+}
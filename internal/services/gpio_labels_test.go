@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+// gpioLabels returns the GPIO labels of a Kubernetes node
+func (env *clusterStatusEnv) gpioLabels(t *testing.T, name string) map[string]string {
+	node, err := env.clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	labels := make(map[string]string)
+	for key, value := range node.Labels {
+		if strings.HasPrefix(key, models.GPIONodeLabelPrefix) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+func TestClusterService_GPIOLabels(t *testing.T) {
+	env := setupClusterStatus(t)
+	gpio := NewGPIOService(env.db, logger.Default())
+	gpio.SetEventBus(env.bus)
+
+	fan, err := gpio.Create(CreateGPIODeviceRequest{
+		Name:       "fan",
+		NodeID:     env.node.ID,
+		PinNumber:  18,
+		Direction:  models.GPIODirectionOutput,
+		DeviceType: models.GPIODeviceTypePWM,
+	})
+	require.NoError(t, err)
+	button, err := gpio.Create(CreateGPIODeviceRequest{
+		Name:      "button",
+		NodeID:    env.node.ID,
+		PinNumber: 4,
+		Direction: models.GPIODirectionInput,
+	})
+	require.NoError(t, err)
+	inactive := models.GPIOStatusInactive
+	_, err = gpio.Update(button.ID, UpdateGPIODeviceRequest{Status: &inactive})
+	require.NoError(t, err)
+
+	t.Run("labels nodes with their active devices on reconcile", func(t *testing.T) {
+		env.clusters.ReconcileStatus(context.Background())
+
+		assert.Equal(t, map[string]string{"pi-controller.io/gpio-18": "pwm"}, env.gpioLabels(t, "pi-master"))
+		assert.Empty(t, env.gpioLabels(t, "pi-worker"), "the worker is not a known node")
+	})
+
+	t.Run("relabels a node when its devices change", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		env.clusters.StartStatusReconciler(ctx, time.Hour)
+
+		active := models.GPIOStatusActive
+		_, err := gpio.Update(button.ID, UpdateGPIODeviceRequest{Status: &active})
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return env.gpioLabels(t, "pi-master")["pi-controller.io/gpio-4"] == "digital"
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, gpio.Delete(fan.ID))
+		assert.Eventually(t, func() bool {
+			_, labelled := env.gpioLabels(t, "pi-master")["pi-controller.io/gpio-18"]
+			return !labelled
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
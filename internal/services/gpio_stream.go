@@ -0,0 +1,88 @@
+package services
+
+import (
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+// ReadingStreamFilter selects GPIO readings by device or node. A nil field
+// matches everything.
+type ReadingStreamFilter struct {
+	DeviceID *uint
+	NodeID   *uint
+}
+
+func (f ReadingStreamFilter) matches(e events.Event) bool {
+	reading, ok := e.Payload.(events.GPIOReading)
+	if !ok {
+		return false
+	}
+	if f.DeviceID != nil && *f.DeviceID != reading.DeviceID {
+		return false
+	}
+	if f.NodeID != nil && *f.NodeID != reading.NodeID {
+		return false
+	}
+	return true
+}
+
+// SubscribeReadings registers on the event bus for GPIO readings recorded
+// after this call. Every event delivered carries an events.GPIOReading
+// payload. A buffer of zero uses events.DefaultBufferSize. Callers must Close
+// the subscription.
+func (s *GPIOService) SubscribeReadings(filter ReadingStreamFilter, buffer int) *events.Subscription {
+	return s.bus.Subscribe(filter.matches, buffer)
+}
+
+// ReadingsSince returns up to limit stored readings matching the filter that
+// were taken at or after since and have an ID greater than afterID, oldest first
+func (s *GPIOService) ReadingsSince(filter ReadingStreamFilter, since time.Time, afterID uint, limit int) ([]models.GPIOReading, error) {
+	var readings []models.GPIOReading
+
+	query := s.db.DB().Model(&models.GPIOReading{}).Where("timestamp >= ? AND id > ?", since, afterID)
+	if filter.DeviceID != nil {
+		query = query.Where("device_id = ?", *filter.DeviceID)
+	}
+	if filter.NodeID != nil {
+		query = query.Where("device_id IN (?)", s.db.DB().Model(&models.GPIODevice{}).Select("id").Where("node_id = ?", *filter.NodeID))
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Order("id ASC").Find(&readings).Error; err != nil {
+		s.logger.WithError(err).Error("Failed to fetch GPIO reading history")
+		return nil, errors.Wrapf(err, "failed to fetch GPIO reading history")
+	}
+
+	return readings, nil
+}
+
+// publishReading announces a stored reading on the event bus
+func (s *GPIOService) publishReading(device *models.GPIODevice, reading models.GPIOReading) {
+	s.bus.Publish(events.GPIOReading{
+		ReadingID: reading.ID,
+		DeviceID:  reading.DeviceID,
+		NodeID:    device.NodeID,
+		Pin:       device.PinNumber,
+		Value:     reading.Value,
+		Timestamp: reading.Timestamp,
+	})
+}
+
+// publishDevice announces a GPIO device change on the event bus
+func (s *GPIOService) publishDevice(action events.Action, device *models.GPIODevice) {
+	s.bus.Publish(events.GPIODevice{
+		Action:     action,
+		DeviceID:   device.ID,
+		NodeID:     device.NodeID,
+		Name:       device.Name,
+		Pin:        device.PinNumber,
+		Direction:  string(device.Direction),
+		DeviceType: string(device.DeviceType),
+		Status:     string(device.Status),
+	})
+}
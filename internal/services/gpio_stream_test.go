@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+func TestGPIOService_SubscribeReadings(t *testing.T) {
+	service, output, input := setupGPIOService(t)
+	agent := newFakeAgent()
+	agent.setValue(23, 1)
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	byNode := service.SubscribeReadings(ReadingStreamFilter{NodeID: &output.NodeID}, 0)
+	defer byNode.Close()
+	byDevice := service.SubscribeReadings(ReadingStreamFilter{DeviceID: &input.ID}, 0)
+	defer byDevice.Close()
+
+	require.NoError(t, service.Write(output.ID, 1))
+	_, err := service.Read(input.ID)
+	require.NoError(t, err)
+
+	first := (<-byNode.C()).Payload.(events.GPIOReading)
+	assert.Equal(t, output.ID, first.DeviceID)
+	assert.Equal(t, 18, first.Pin)
+	assert.NotZero(t, first.ReadingID)
+	second := (<-byNode.C()).Payload.(events.GPIOReading)
+	assert.Equal(t, input.ID, second.DeviceID)
+	assert.Equal(t, float64(1), second.Value)
+
+	// Device changes on the bus are not readings
+	name := "switch"
+	_, err = service.Update(input.ID, UpdateGPIODeviceRequest{Name: &name})
+	require.NoError(t, err)
+
+	require.Len(t, byDevice.C(), 1)
+	assert.Equal(t, input.ID, (<-byDevice.C()).Payload.(events.GPIOReading).DeviceID)
+}
+
+func TestGPIOService_ReadingsSince(t *testing.T) {
+	service, output, input := setupGPIOService(t)
+	start := time.Now().Add(-time.Hour)
+
+	for i, deviceID := range []uint{output.ID, input.ID, output.ID} {
+		require.NoError(t, service.db.DB().Create(&models.GPIOReading{
+			DeviceID:  deviceID,
+			Value:     float64(i),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}).Error)
+	}
+
+	readings, err := service.ReadingsSince(ReadingStreamFilter{DeviceID: &output.ID}, start, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, readings, 2)
+	assert.True(t, readings[0].ID < readings[1].ID)
+
+	readings, err = service.ReadingsSince(ReadingStreamFilter{NodeID: &output.NodeID}, start.Add(time.Minute), 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, readings, 2)
+
+	readings, err = service.ReadingsSince(ReadingStreamFilter{NodeID: &output.NodeID}, start, readings[0].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	assert.Equal(t, float64(2), readings[0].Value)
+
+	otherNode := output.NodeID + 1
+	readings, err = service.ReadingsSince(ReadingStreamFilter{NodeID: &otherNode}, start, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, readings)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/grpc/agentpool"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// fakeAgent is an in-process PiAgentService that keeps pin values in memory
+type fakeAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu         sync.Mutex
+	configured map[int32]*pb.ConfigureGPIOPinRequest
+	values     map[int32]int32
+	watching   int
+
+	watches chan *pb.WatchGPIOPinRequest // requests of opened WatchGPIOPin streams
+	edges   chan *pb.GPIOPinEvent        // events to send on open streams
+	drops   chan struct{}                // fails an open stream
+}
+
+func newFakeAgent() *fakeAgent {
+	return &fakeAgent{
+		configured: make(map[int32]*pb.ConfigureGPIOPinRequest),
+		values:     make(map[int32]int32),
+		watches:    make(chan *pb.WatchGPIOPinRequest, 8),
+		edges:      make(chan *pb.GPIOPinEvent),
+		drops:      make(chan struct{}),
+	}
+}
+
+func (a *fakeAgent) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPIOPinRequest) (*pb.ConfigureGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Like the agent, applying a changed configuration drives outputs low
+	if req.Direction == pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT && !proto.Equal(a.configured[req.Pin], req) {
+		a.values[req.Pin] = 0
+	}
+	a.configured[req.Pin] = req
+	return &pb.ConfigureGPIOPinResponse{Success: true, ConfiguredAt: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) ReadGPIOPin(ctx context.Context, req *pb.ReadGPIOPinRequest) (*pb.ReadGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &pb.ReadGPIOPinResponse{Pin: req.Pin, Value: a.values[req.Pin], Timestamp: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) WriteGPIOPin(ctx context.Context, req *pb.WriteGPIOPinRequest) (*pb.WriteGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[req.Pin] = req.Value
+	return &pb.WriteGPIOPinResponse{Pin: req.Pin, Value: req.Value, Timestamp: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) SetGPIOPWM(ctx context.Context, req *pb.SetGPIOPWMRequest) (*pb.SetGPIOPWMResponse, error) {
+	return &pb.SetGPIOPWMResponse{
+		Success:      true,
+		Pin:          req.Pin,
+		Frequency:    req.Frequency,
+		DutyCycle:    req.DutyCycle,
+		ConfiguredAt: timestamppb.Now(),
+	}, nil
+}
+
+func (a *fakeAgent) WatchGPIOPin(req *pb.WatchGPIOPinRequest, stream pb.PiAgentService_WatchGPIOPinServer) error {
+	a.mu.Lock()
+	a.watching++
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.watching--
+		a.mu.Unlock()
+	}()
+
+	a.watches <- req
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-a.drops:
+			return status.Error(codes.Unavailable, "agent restarted")
+		case event := <-a.edges:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (a *fakeAgent) openWatches() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.watching
+}
+
+func (a *fakeAgent) setValue(pin int32, value int32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[pin] = value
+}
+
+// bufconnConnector is an AgentConnector that routes every node to one in-process agent
+type bufconnConnector struct {
+	conn *grpc.ClientConn
+}
+
+func (c *bufconnConnector) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	return pb.NewPiAgentServiceClient(c.conn), nil
+}
+
+func startFakeAgent(t *testing.T, agent pb.PiAgentServiceServer) *bufconnConnector {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(server, agent)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &bufconnConnector{conn: conn}
+}
+
+func setupGPIOService(t *testing.T) (*GPIOService, *models.GPIODevice, *models.GPIODevice) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	nodeService := NewNodeService(db, logger.Default())
+	node, err := nodeService.Create(CreateNodeRequest{
+		Name:       "pi-1",
+		IPAddress:  "127.0.0.1",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+
+	service := NewGPIOService(db, logger.Default())
+	output, err := service.Create(CreateGPIODeviceRequest{
+		Name:       "led",
+		NodeID:     node.ID,
+		PinNumber:  18,
+		Direction:  models.GPIODirectionOutput,
+		PullMode:   models.GPIOPullNone,
+		DeviceType: models.GPIODeviceTypeDigital,
+	})
+	require.NoError(t, err)
+
+	input, err := service.Create(CreateGPIODeviceRequest{
+		Name:       "button",
+		NodeID:     node.ID,
+		PinNumber:  23,
+		Direction:  models.GPIODirectionInput,
+		PullMode:   models.GPIOPullUp,
+		DeviceType: models.GPIODeviceTypeDigital,
+	})
+	require.NoError(t, err)
+
+	return service, output, input
+}
+
+func TestGPIOService_WriteThroughAgent(t *testing.T) {
+	service, output, _ := setupGPIOService(t)
+	agent := newFakeAgent()
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	require.NoError(t, service.Write(output.ID, 1))
+
+	agent.mu.Lock()
+	assert.Equal(t, int32(1), agent.values[18])
+	assert.Equal(t, pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT, agent.configured[18].Direction)
+	agent.mu.Unlock()
+
+	device, err := service.GetByID(output.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, device.Value)
+
+	readings, total, err := service.GetReadings(GPIOReadingFilter{DeviceID: output.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, float64(1), readings[0].Value)
+}
+
+func TestGPIOService_ReadOutput(t *testing.T) {
+	service, output, _ := setupGPIOService(t)
+	service.SetAgentConnector(startFakeAgent(t, newFakeAgent()))
+
+	require.NoError(t, service.Write(output.ID, 1))
+	device, err := service.Read(output.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, device.Value, "reading an output does not reset it")
+}
+
+func TestGPIOService_ReadThroughAgent(t *testing.T) {
+	service, _, input := setupGPIOService(t)
+	agent := newFakeAgent()
+	agent.setValue(23, 1)
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	device, err := service.Read(input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, device.Value)
+
+	stored, err := service.GetByID(input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Value)
+
+	_, total, err := service.GetReadings(GPIOReadingFilter{DeviceID: input.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestGPIOService_Sample(t *testing.T) {
+	service, _, input := setupGPIOService(t)
+	agent := newFakeAgent()
+	agent.setValue(23, 1)
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	reading, err := service.Sample(context.Background(), input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input.ID, reading.DeviceID)
+	assert.Equal(t, float64(1), reading.Value)
+	assert.Zero(t, reading.ID)
+
+	stored, err := service.GetByID(input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.Value, "sampling does not update the device")
+
+	_, total, err := service.GetReadings(GPIOReadingFilter{DeviceID: input.ID})
+	require.NoError(t, err)
+	assert.Zero(t, total, "sampling does not store readings")
+}
+
+func TestGPIOService_SetPWM(t *testing.T) {
+	service, output, _ := setupGPIOService(t)
+	service.SetAgentConnector(startFakeAgent(t, newFakeAgent()))
+
+	fan, err := service.Create(CreateGPIODeviceRequest{
+		Name:       "fan",
+		NodeID:     output.NodeID,
+		PinNumber:  12,
+		Direction:  models.GPIODirectionOutput,
+		DeviceType: models.GPIODeviceTypePWM,
+	})
+	require.NoError(t, err)
+
+	device, err := service.SetPWM(fan.ID, 1000, 40)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, device.Config.Frequency)
+	assert.Equal(t, 40, device.Config.DutyCycle)
+
+	stored, err := service.GetByID(fan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 40, stored.Config.DutyCycle)
+
+	_, err = service.SetPWM(fan.ID, 1000, 101)
+	assert.True(t, IsValidationFailed(err))
+	_, err = service.SetPWM(output.ID, 1000, 40)
+	assert.True(t, IsValidationFailed(err), "digital devices do not take PWM settings")
+}
+
+func TestGPIOService_WriteAllocated(t *testing.T) {
+	service, output, _ := setupGPIOService(t)
+	agent := newFakeAgent()
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	fan, err := service.Create(CreateGPIODeviceRequest{
+		Name:       "fan",
+		NodeID:     output.NodeID,
+		PinNumber:  12,
+		Direction:  models.GPIODirectionOutput,
+		DeviceType: models.GPIODeviceTypePWM,
+	})
+	require.NoError(t, err)
+
+	_, err = service.RecordAllocations(output.NodeID, map[int]string{18: "default/blinker/led", 12: "default/cooler/fan"})
+	require.NoError(t, err)
+
+	err = service.Write(output.ID, 1)
+	assert.True(t, IsConflict(err), "got %v", err)
+	assert.Contains(t, err.Error(), "default/blinker/led")
+	_, err = service.SetPWM(fan.ID, 1000, 40)
+	assert.True(t, IsConflict(err), "got %v", err)
+
+	agent.mu.Lock()
+	assert.Empty(t, agent.values, "the agent is not called")
+	agent.mu.Unlock()
+
+	// Once the pods release the pins they can be written again
+	_, err = service.RecordAllocations(output.NodeID, nil)
+	require.NoError(t, err)
+	require.NoError(t, service.Write(output.ID, 1))
+}
+
+func TestGPIOService_AgentUnreachable(t *testing.T) {
+	t.Run("no connector configured", func(t *testing.T) {
+		service, output, _ := setupGPIOService(t)
+
+		err := service.Write(output.ID, 1)
+		assert.True(t, IsAgentUnreachable(err))
+	})
+
+	t.Run("agent not listening", func(t *testing.T) {
+		service, output, input := setupGPIOService(t)
+		pool := agentpool.New(agentpool.Config{Port: 1, Insecure: true}, logger.Default())
+		defer pool.Stop()
+		service.SetAgentConnector(pool)
+
+		err := service.Write(output.ID, 1)
+		assert.True(t, IsAgentUnreachable(err))
+
+		_, err = service.Read(input.ID)
+		assert.True(t, IsAgentUnreachable(err))
+
+		// Nothing should be persisted when the hardware was not touched
+		device, err := service.GetByID(output.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, device.Value)
+
+		_, total, err := service.GetReadings(GPIOReadingFilter{DeviceID: output.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+const (
+	// edgeWatchResync is how often edge watches are reconciled with the
+	// database in addition to reacting to device changes
+	edgeWatchResync = time.Minute
+
+	// edgeWatchMaxBackoff caps the delay before reopening a failed agent stream
+	edgeWatchMaxBackoff = 30 * time.Second
+)
+
+// edgeWatch is a running agent stream for one device
+type edgeWatch struct {
+	settings edgeWatchSettings
+	cancel   context.CancelFunc
+}
+
+// edgeWatchSettings are the device fields a stream is opened with; a change
+// to any of them restarts the stream
+type edgeWatchSettings struct {
+	nodeID     uint
+	pin        int
+	pullMode   models.GPIOPullMode
+	edge       models.GPIOEdge
+	debounceMS int
+}
+
+func edgeWatchSettingsFor(device *models.GPIODevice) edgeWatchSettings {
+	return edgeWatchSettings{
+		nodeID:     device.NodeID,
+		pin:        device.PinNumber,
+		pullMode:   device.PullMode,
+		edge:       device.Config.Edge,
+		debounceMS: device.Config.DebounceMS,
+	}
+}
+
+// StartEdgeWatch streams edge interrupts from node agents for every active
+// input device with edge detection configured, recording each edge as a
+// reading, until ctx is cancelled. Call it after SetEventBus so device
+// changes restart the affected streams.
+func (s *GPIOService) StartEdgeWatch(ctx context.Context) {
+	changes := s.bus.Subscribe(events.OfType(events.TypeGPIODevice), 0)
+
+	go func() {
+		defer changes.Close()
+		defer s.stopEdgeWatches()
+
+		ticker := time.NewTicker(edgeWatchResync)
+		defer ticker.Stop()
+
+		for {
+			s.syncEdgeWatches(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-changes.C():
+			}
+		}
+	}()
+}
+
+// syncEdgeWatches starts, restarts and stops streams to match the devices
+// that currently want edge detection
+func (s *GPIOService) syncEdgeWatches(ctx context.Context) {
+	var devices []models.GPIODevice
+	err := s.db.DB().Preload("Node").
+		Where("direction = ? AND status = ? AND edge <> ''", models.GPIODirectionInput, models.GPIOStatusActive).
+		Find(&devices).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list GPIO devices for edge detection")
+		return
+	}
+
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	wanted := make(map[uint]bool, len(devices))
+	for i := range devices {
+		device := devices[i]
+		wanted[device.ID] = true
+
+		settings := edgeWatchSettingsFor(&device)
+		if watch, ok := s.watches[device.ID]; ok {
+			if watch.settings == settings {
+				continue
+			}
+			watch.cancel()
+		}
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		s.watches[device.ID] = &edgeWatch{settings: settings, cancel: cancel}
+		go s.runEdgeWatch(watchCtx, device)
+	}
+
+	for id, watch := range s.watches {
+		if !wanted[id] {
+			watch.cancel()
+			delete(s.watches, id)
+		}
+	}
+}
+
+func (s *GPIOService) stopEdgeWatches() {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for id, watch := range s.watches {
+		watch.cancel()
+		delete(s.watches, id)
+	}
+}
+
+// runEdgeWatch keeps a device's agent stream open, reconnecting with backoff.
+// The device and its node are reloaded for each connection, so reconnects
+// follow the node to a new address.
+func (s *GPIOService) runEdgeWatch(ctx context.Context, device models.GPIODevice) {
+	backoff := time.Second
+	for {
+		received := false
+		current, err := s.GetByID(device.ID)
+		if err == nil {
+			received, err = s.streamEdges(ctx, current)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = time.Second
+		}
+
+		s.logger.WithFields(map[string]interface{}{
+			"device_id": device.ID,
+			"node_id":   device.NodeID,
+			"pin":       device.PinNumber,
+			"retry_in":  backoff.String(),
+			"error":     err,
+		}).Warn("GPIO edge stream interrupted")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, edgeWatchMaxBackoff)
+	}
+}
+
+// streamEdges opens one agent stream for a device and records its events
+// until the stream fails, reporting whether any event was received
+func (s *GPIOService) streamEdges(ctx context.Context, device *models.GPIODevice) (bool, error) {
+	configureCtx, cancel := context.WithTimeout(ctx, agentCallTimeout)
+	client, err := s.configurePin(configureCtx, device)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+
+	stream, err := client.WatchGPIOPin(ctx, &pb.WatchGPIOPinRequest{
+		Pin:        int32(device.PinNumber),
+		Edge:       edgeToAgent(device.Config.Edge),
+		DebounceMs: int32(device.Config.DebounceMS),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"device_id": device.ID,
+		"pin":       device.PinNumber,
+		"edge":      device.Config.Edge,
+	}).Info("Watching GPIO device edges")
+
+	received := false
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return received, fmt.Errorf("edge stream closed: %w", err)
+		}
+		received = true
+		s.recordEdge(device, event)
+	}
+}
+
+// recordEdge stores an edge as the device's value and as a reading
+func (s *GPIOService) recordEdge(device *models.GPIODevice, event *pb.GPIOPinEvent) {
+	timestamp := time.Now()
+	if event.Timestamp != nil {
+		timestamp = event.Timestamp.AsTime()
+	}
+
+	if err := s.db.DB().Model(&models.GPIODevice{}).Where("id = ?", device.ID).Update("value", int(event.Value)).Error; err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"device_id": device.ID,
+			"error":     err,
+		}).Error("Failed to update GPIO device value from edge")
+	}
+
+	reading := models.GPIOReading{
+		DeviceID:  device.ID,
+		Value:     float64(event.Value),
+		Timestamp: timestamp,
+	}
+	if err := s.db.DB().Create(&reading).Error; err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"device_id": device.ID,
+			"error":     err,
+		}).Error("Failed to create GPIO reading from edge")
+		return
+	}
+	s.publishReading(device, reading)
+}
+
+// edgeToAgent converts a model edge to the agent protobuf enum
+func edgeToAgent(edge models.GPIOEdge) pb.AgentGPIOEdge {
+	switch edge {
+	case models.GPIOEdgeRising:
+		return pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING
+	case models.GPIOEdgeFalling:
+		return pb.AgentGPIOEdge_AGENT_GPIO_EDGE_FALLING
+	default:
+		return pb.AgentGPIOEdge_AGENT_GPIO_EDGE_BOTH
+	}
+}
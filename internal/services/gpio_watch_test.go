@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+func TestGPIOService_EdgeWatch(t *testing.T) {
+	service, _, input := setupGPIOService(t)
+	agent := newFakeAgent()
+	service.SetAgentConnector(startFakeAgent(t, agent))
+
+	readings := service.SubscribeReadings(ReadingStreamFilter{DeviceID: &input.ID}, 0)
+	defer readings.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.StartEdgeWatch(ctx)
+
+	config := models.GPIOConfig{Edge: models.GPIOEdgeRising, DebounceMS: 50}
+	_, err := service.Update(input.ID, UpdateGPIODeviceRequest{Config: &config})
+	require.NoError(t, err)
+
+	select {
+	case req := <-agent.watches:
+		assert.Equal(t, int32(23), req.Pin)
+		assert.Equal(t, pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING, req.Edge)
+		assert.Equal(t, int32(50), req.DebounceMs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("controller did not open a watch stream")
+	}
+
+	agent.edges <- &pb.GPIOPinEvent{
+		Pin:       23,
+		Edge:      pb.AgentGPIOEdge_AGENT_GPIO_EDGE_RISING,
+		Value:     1,
+		Timestamp: timestamppb.Now(),
+	}
+
+	select {
+	case event := <-readings.C():
+		reading := event.Payload.(events.GPIOReading)
+		assert.Equal(t, input.ID, reading.DeviceID)
+		assert.Equal(t, float64(1), reading.Value)
+	case <-time.After(5 * time.Second):
+		t.Fatal("edge was not published as a reading")
+	}
+
+	stored, err := service.GetByID(input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Value)
+	_, total, err := service.GetReadings(GPIOReadingFilter{DeviceID: input.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// Clearing the edge closes the stream
+	config.Edge = ""
+	_, err = service.Update(input.ID, UpdateGPIODeviceRequest{Config: &config})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return agent.openWatches() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+// addressRecorder records the node addresses agents are reached at
+type addressRecorder struct {
+	AgentConnector
+
+	mu        sync.Mutex
+	addresses []string
+}
+
+func (r *addressRecorder) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	r.mu.Lock()
+	r.addresses = append(r.addresses, node.IPAddress)
+	r.mu.Unlock()
+	return r.AgentConnector.Client(ctx, node)
+}
+
+func (r *addressRecorder) last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.addresses[len(r.addresses)-1]
+}
+
+func TestGPIOService_EdgeWatchReconnect(t *testing.T) {
+	service, _, input := setupGPIOService(t)
+	agent := newFakeAgent()
+	recorder := &addressRecorder{AgentConnector: startFakeAgent(t, agent)}
+	service.SetAgentConnector(recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.StartEdgeWatch(ctx)
+
+	config := models.GPIOConfig{Edge: models.GPIOEdgeBoth}
+	_, err := service.Update(input.ID, UpdateGPIODeviceRequest{Config: &config})
+	require.NoError(t, err)
+	select {
+	case <-agent.watches:
+	case <-time.After(5 * time.Second):
+		t.Fatal("controller did not open a watch stream")
+	}
+	assert.Equal(t, "127.0.0.1", recorder.last())
+
+	// The node moves while the stream is open, then the stream fails
+	require.NoError(t, service.db.DB().Model(&models.Node{}).Where("id = ?", input.NodeID).Update("ip_address", "127.0.0.2").Error)
+	agent.drops <- struct{}{}
+
+	select {
+	case <-agent.watches:
+	case <-time.After(5 * time.Second):
+		t.Fatal("controller did not reopen the watch stream")
+	}
+	assert.Equal(t, "127.0.0.2", recorder.last(), "reconnects use the node's current address")
+}
+
+func TestGPIOService_EdgeValidation(t *testing.T) {
+	service, output, input := setupGPIOService(t)
+
+	_, err := service.Update(output.ID, UpdateGPIODeviceRequest{Config: &models.GPIOConfig{Edge: models.GPIOEdgeBoth}})
+	assert.True(t, IsValidationFailed(err), "outputs cannot watch edges")
+
+	_, err = service.Update(input.ID, UpdateGPIODeviceRequest{Config: &models.GPIOConfig{Edge: "sideways"}})
+	assert.True(t, IsValidationFailed(err))
+
+	_, err = service.Update(input.ID, UpdateGPIODeviceRequest{Config: &models.GPIOConfig{Edge: models.GPIOEdgeBoth, DebounceMS: 60000}})
+	assert.True(t, IsValidationFailed(err))
+
+	device, err := service.Update(input.ID, UpdateGPIODeviceRequest{Config: &models.GPIOConfig{Edge: models.GPIOEdgeBoth, DebounceMS: 20}})
+	require.NoError(t, err)
+	assert.True(t, device.WatchesEdges())
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/base64"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// KubeClientProvider returns a Kubernetes client for a cluster
+type KubeClientProvider interface {
+	ClientFor(cluster *models.Cluster) (k8s.K8sClient, error)
+}
+
+// KubeClientRegistry builds a Kubernetes client for each cluster from its
+// stored kubeconfig the first time one is needed and caches it. A cached
+// client is rebuilt when the cluster's kubeconfig has changed since, and
+// dropped when the cluster service reports the cluster as updated or deleted.
+type KubeClientRegistry struct {
+	logger logger.Interface
+	build  func(kubeConfig []byte) (k8s.K8sClient, error)
+
+	mu      sync.Mutex
+	clients map[uint]cachedKubeClient
+}
+
+type cachedKubeClient struct {
+	kubeConfig string
+	client     k8s.K8sClient
+}
+
+// NewKubeClientRegistry creates an empty registry
+func NewKubeClientRegistry(logger logger.Interface) *KubeClientRegistry {
+	return &KubeClientRegistry{
+		logger:  logger.WithField("component", "kube-clients"),
+		build:   newKubeClient,
+		clients: make(map[uint]cachedKubeClient),
+	}
+}
+
+func newKubeClient(kubeConfig []byte) (k8s.K8sClient, error) {
+	return k8s.NewClientFromKubeConfig(kubeConfig, logrus.New())
+}
+
+// ClientFor returns the cached client of a cluster, building one if there is
+// none or the cluster's kubeconfig has changed
+func (r *KubeClientRegistry) ClientFor(cluster *models.Cluster) (k8s.K8sClient, error) {
+	if cluster.KubeConfig == "" {
+		return nil, errors.Wrapf(ErrValidationFailed, "cluster %s has no kubeconfig", cluster.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.clients[cluster.ID]; ok && cached.kubeConfig == cluster.KubeConfig {
+		return cached.client, nil
+	}
+
+	kubeConfig, err := base64.StdEncoding.DecodeString(cluster.KubeConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode kubeconfig of cluster %s", cluster.Name)
+	}
+	client, err := r.build(kubeConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create client for cluster %s", cluster.Name)
+	}
+
+	r.clients[cluster.ID] = cachedKubeClient{kubeConfig: cluster.KubeConfig, client: client}
+	r.logger.WithFields(map[string]interface{}{
+		"cluster_id":   cluster.ID,
+		"cluster_name": cluster.Name,
+	}).Debug("Created Kubernetes client for cluster")
+
+	return client, nil
+}
+
+// Invalidate drops the cached client of a cluster, so the next ClientFor
+// builds a new one
+func (r *KubeClientRegistry) Invalidate(clusterID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, clusterID)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// countingRegistry returns a registry whose clients are mocks, along with the
+// kubeconfigs it has built clients from
+func countingRegistry() (*KubeClientRegistry, *[]string) {
+	var built []string
+	registry := NewKubeClientRegistry(logger.Default())
+	registry.build = func(kubeConfig []byte) (k8s.K8sClient, error) {
+		built = append(built, string(kubeConfig))
+		return &MockK8sClient{}, nil
+	}
+	return registry, &built
+}
+
+func TestKubeClientRegistry(t *testing.T) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	defer cleanup()
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	registry, built := countingRegistry()
+	clusters := NewClusterService(db, logger.Default())
+	clusters.SetKubeClientRegistry(registry)
+
+	home, err := clusters.Create(CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+	lab, err := clusters.Create(CreateClusterRequest{Name: "lab"})
+	require.NoError(t, err)
+
+	t.Run("refuses clusters without a kubeconfig", func(t *testing.T) {
+		_, err := registry.ClientFor(home)
+		assert.True(t, IsValidationFailed(err))
+		assert.Empty(t, *built)
+	})
+
+	home, err = clusters.SetKubeConfig(home.ID, "https://192.168.1.2:6443", []byte("home-v1"))
+	require.NoError(t, err)
+	lab, err = clusters.SetKubeConfig(lab.ID, "https://192.168.2.2:6443", []byte("lab-v1"))
+	require.NoError(t, err)
+
+	t.Run("builds one client per cluster and caches it", func(t *testing.T) {
+		first, err := registry.ClientFor(home)
+		require.NoError(t, err)
+		again, err := registry.ClientFor(home)
+		require.NoError(t, err)
+		other, err := registry.ClientFor(lab)
+		require.NoError(t, err)
+
+		assert.Same(t, first, again)
+		assert.NotSame(t, first, other)
+		assert.Equal(t, []string{"home-v1", "lab-v1"}, *built)
+	})
+
+	t.Run("rebuilds a client when the kubeconfig changes", func(t *testing.T) {
+		*built = nil
+		home, err = clusters.SetKubeConfig(home.ID, "https://192.168.1.2:6443", []byte("home-v2"))
+		require.NoError(t, err)
+
+		_, err := registry.ClientFor(home)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home-v2"}, *built)
+	})
+
+	t.Run("drops the client when the cluster is updated", func(t *testing.T) {
+		*built = nil
+		endpoint := "https://192.168.1.3:6443"
+		home, err = clusters.Update(home.ID, UpdateClusterRequest{MasterEndpoint: &endpoint})
+		require.NoError(t, err)
+
+		_, err := registry.ClientFor(home)
+		require.NoError(t, err)
+		_, err = registry.ClientFor(lab)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home-v2"}, *built, "only the updated cluster's client is rebuilt")
+	})
+}
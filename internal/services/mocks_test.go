@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
 	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -113,3 +115,229 @@ func (m *MockK8sClient) DeletePod(ctx context.Context, namespace, name string) e
 	args := m.Called(ctx, namespace, name)
 	return args.Error(0)
 }
+
+func (m *MockK8sClient) CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	args := m.Called(ctx, namespace, deployment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.Deployment), args.Error(1)
+}
+
+func (m *MockK8sClient) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	args := m.Called(ctx, namespace, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.Deployment), args.Error(1)
+}
+
+func (m *MockK8sClient) ListDeployments(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
+	args := m.Called(ctx, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]appsv1.Deployment), args.Error(1)
+}
+
+func (m *MockK8sClient) UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	args := m.Called(ctx, namespace, deployment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.Deployment), args.Error(1)
+}
+
+func (m *MockK8sClient) DeleteDeployment(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) GetRolloutStatus(ctx context.Context, namespace, name string) (*k8s.RolloutStatus, error) {
+	args := m.Called(ctx, namespace, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*k8s.RolloutStatus), args.Error(1)
+}
+
+func (m *MockK8sClient) RollbackDeployment(ctx context.Context, namespace, name string, revision int64) (*appsv1.Deployment, error) {
+	args := m.Called(ctx, namespace, name, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.Deployment), args.Error(1)
+}
+
+func (m *MockK8sClient) CreateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	args := m.Called(ctx, namespace, daemonSet)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.DaemonSet), args.Error(1)
+}
+
+func (m *MockK8sClient) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	args := m.Called(ctx, namespace, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.DaemonSet), args.Error(1)
+}
+
+func (m *MockK8sClient) ListDaemonSets(ctx context.Context, namespace string) ([]appsv1.DaemonSet, error) {
+	args := m.Called(ctx, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]appsv1.DaemonSet), args.Error(1)
+}
+
+func (m *MockK8sClient) UpdateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	args := m.Called(ctx, namespace, daemonSet)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*appsv1.DaemonSet), args.Error(1)
+}
+
+func (m *MockK8sClient) DeleteDaemonSet(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) CreateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	args := m.Called(ctx, namespace, service)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.Service), args.Error(1)
+}
+
+func (m *MockK8sClient) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	args := m.Called(ctx, namespace, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.Service), args.Error(1)
+}
+
+func (m *MockK8sClient) ListServices(ctx context.Context, namespace string) ([]corev1.Service, error) {
+	args := m.Called(ctx, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]corev1.Service), args.Error(1)
+}
+
+func (m *MockK8sClient) UpdateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	args := m.Called(ctx, namespace, service)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.Service), args.Error(1)
+}
+
+func (m *MockK8sClient) DeleteService(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) CreateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	args := m.Called(ctx, namespace, configMap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.ConfigMap), args.Error(1)
+}
+
+func (m *MockK8sClient) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	args := m.Called(ctx, namespace, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.ConfigMap), args.Error(1)
+}
+
+func (m *MockK8sClient) ListConfigMaps(ctx context.Context, namespace string) ([]corev1.ConfigMap, error) {
+	args := m.Called(ctx, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]corev1.ConfigMap), args.Error(1)
+}
+
+func (m *MockK8sClient) UpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	args := m.Called(ctx, namespace, configMap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*corev1.ConfigMap), args.Error(1)
+}
+
+func (m *MockK8sClient) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) Apply(ctx context.Context, manifests []byte, opts k8s.ApplyOptions) ([]k8s.ApplyResult, error) {
+	args := m.Called(ctx, manifests, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]k8s.ApplyResult), args.Error(1)
+}
+
+func (m *MockK8sClient) GetClusterInfo(ctx context.Context) (*k8s.ClusterInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*k8s.ClusterInfo), args.Error(1)
+}
+
+func (m *MockK8sClient) SyncNodeLabels(ctx context.Context, nodeName, prefix string, labels map[string]string) (bool, error) {
+	args := m.Called(ctx, nodeName, prefix, labels)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockK8sClient) GetNode(ctx context.Context, nodeName string) (*k8s.NodeInfo, error) {
+	args := m.Called(ctx, nodeName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*k8s.NodeInfo), args.Error(1)
+}
+
+func (m *MockK8sClient) CordonNode(ctx context.Context, nodeName string) error {
+	args := m.Called(ctx, nodeName)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) UncordonNode(ctx context.Context, nodeName string) error {
+	args := m.Called(ctx, nodeName)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) DrainNode(ctx context.Context, nodeName string, opts k8s.DrainOptions) error {
+	args := m.Called(ctx, nodeName, opts)
+	return args.Error(0)
+}
+
+func (m *MockK8sClient) DeleteNode(ctx context.Context, nodeName string) error {
+	args := m.Called(ctx, nodeName)
+	return args.Error(0)
+}
+
+// MockKubeClientProvider is a mock implementation of the KubeClientProvider interface
+type MockKubeClientProvider struct {
+	mock.Mock
+}
+
+func (m *MockKubeClientProvider) ClientFor(cluster *models.Cluster) (k8s.K8sClient, error) {
+	args := m.Called(cluster)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(k8s.K8sClient), args.Error(1)
+}
@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
 	"github.com/dsyorkd/pi-controller/internal/logger"
 	"github.com/dsyorkd/pi-controller/internal/models"
 	"github.com/dsyorkd/pi-controller/internal/storage"
@@ -15,6 +16,12 @@ import (
 type NodeService struct {
 	db     *storage.Database
 	logger logger.Interface
+	agents AgentStateProvider
+	bus    *events.Bus
+
+	provisioner Provisioner
+	jobs        *JobService
+	kube        KubeClientProvider
 }
 
 // NewNodeService creates a new node service
@@ -22,9 +29,38 @@ func NewNodeService(db *storage.Database, logger logger.Interface) *NodeService
 	return &NodeService{
 		db:     db,
 		logger: logger.WithField("service", "node"),
+		bus:    events.NewBus(),
+		kube:   NewKubeClientRegistry(logger),
 	}
 }
 
+// SetAgentStateProvider sets the provider used to report each node's agent connection state
+func (s *NodeService) SetAgentStateProvider(agents AgentStateProvider) {
+	s.agents = agents
+}
+
+// SetEventBus sets the bus node changes are published to
+func (s *NodeService) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// SetProvisioner sets the engine that installs Kubernetes on provisioned
+// nodes. Without one, Provision only records the cluster assignment.
+func (s *NodeService) SetProvisioner(provisioner Provisioner) {
+	s.provisioner = provisioner
+}
+
+// SetJobService sets the service deprovisioning is recorded with
+func (s *NodeService) SetJobService(jobs *JobService) {
+	s.jobs = jobs
+}
+
+// SetKubeClientProvider sets where Kubernetes clients for removing nodes from
+// their cluster come from. By default the service keeps its own registry.
+func (s *NodeService) SetKubeClientProvider(kube KubeClientProvider) {
+	s.kube = kube
+}
+
 // CreateNodeRequest represents the request to create a node
 type CreateNodeRequest struct {
 	Name         string           `json:"name" validate:"required,min=1,max=100"`
@@ -112,6 +148,10 @@ func (s *NodeService) List(opts NodeListOptions) ([]models.Node, int64, error) {
 		return nil, 0, errors.Wrapf(err, "failed to fetch nodes")
 	}
 
+	for i := range nodes {
+		s.applyAgentState(&nodes[i])
+	}
+
 	s.logger.WithFields(map[string]interface{}{
 		"count": len(nodes),
 		"total": total,
@@ -140,6 +180,7 @@ func (s *NodeService) GetByID(id uint, includeGPIO bool) (*models.Node, error) {
 		return nil, errors.Wrapf(err, "failed to fetch node")
 	}
 
+	s.applyAgentState(&node)
 	return &node, nil
 }
 
@@ -181,6 +222,10 @@ func (s *NodeService) GetByIPAddress(ipAddress string) (*models.Node, error) {
 
 // Create creates a new node
 func (s *NodeService) Create(req CreateNodeRequest) (*models.Node, error) {
+	if err := validateCreateNodeRequest(req); err != nil {
+		return nil, err
+	}
+
 	// Check if node with same name already exists
 	if _, err := s.GetByName(req.Name); err != ErrNotFound {
 		if err == nil {
@@ -237,11 +282,17 @@ func (s *NodeService) Create(req CreateNodeRequest) (*models.Node, error) {
 		"ip":   node.IPAddress,
 	}).Info("Node created successfully")
 
+	s.publish(events.ActionCreated, &node)
+
 	return &node, nil
 }
 
 // Update updates an existing node
 func (s *NodeService) Update(id uint, req UpdateNodeRequest) (*models.Node, error) {
+	if err := validateUpdateNodeRequest(req); err != nil {
+		return nil, err
+	}
+
 	node, err := s.GetByID(id, false)
 	if err != nil {
 		return nil, err
@@ -332,6 +383,8 @@ func (s *NodeService) Update(id uint, req UpdateNodeRequest) (*models.Node, erro
 		"name": node.Name,
 	}).Info("Node updated successfully")
 
+	s.publish(events.ActionUpdated, node)
+
 	return node, nil
 }
 
@@ -360,6 +413,8 @@ func (s *NodeService) Delete(id uint) error {
 		"name": node.Name,
 	}).Info("Node deleted successfully")
 
+	s.publish(events.ActionDeleted, node)
+
 	return nil
 }
 
@@ -411,9 +466,17 @@ func (s *NodeService) Provision(id uint, clusterID uint) error {
 		return errors.Wrapf(err, "failed to validate cluster")
 	}
 
+	sameCluster := node.ClusterID != nil && *node.ClusterID == clusterID
+	if node.Status == models.NodeStatusProvisioning && !sameCluster {
+		return errors.Wrapf(ErrConflict, "node is already being provisioned into cluster %d", *node.ClusterID)
+	}
+	// A failed or interrupted provisioning into the same cluster picks up where it stopped
+	resume := sameCluster && (node.Status == models.NodeStatusProvisioning || node.Status == models.NodeStatusFailed)
+
 	// Update node status and cluster assignment
 	node.Status = models.NodeStatusProvisioning
 	node.ClusterID = &clusterID
+	node.Cluster = nil
 
 	if err := s.db.DB().Save(node).Error; err != nil {
 		s.logger.WithFields(map[string]interface{}{
@@ -429,39 +492,80 @@ func (s *NodeService) Provision(id uint, clusterID uint) error {
 		"node_name":    node.Name,
 		"cluster_id":   clusterID,
 		"cluster_name": cluster.Name,
+		"resume":       resume,
 	}).Info("Node provisioning started")
 
+	s.publish(events.ActionUpdated, node)
+
+	if s.provisioner != nil {
+		if err := s.provisioner.Provision(id, resume); err != nil {
+			return errors.Wrapf(err, "failed to start provisioning")
+		}
+	}
+
 	return nil
 }
 
-// Deprovision removes a node from its cluster and marks it as discovered
-func (s *NodeService) Deprovision(id uint) error {
+// HandleAgentStateChange updates a node's status and last seen time when the
+// reachability of its agent changes
+func (s *NodeService) HandleAgentStateChange(id uint, reachable bool) error {
 	node, err := s.GetByID(id, false)
 	if err != nil {
 		return err
 	}
 
-	oldClusterID := node.ClusterID
+	updates := map[string]interface{}{}
+	if reachable {
+		updates["last_seen"] = time.Now()
+		if node.Status == models.NodeStatusNotReady || node.Status == models.NodeStatusUnknown {
+			updates["status"] = models.NodeStatusReady
+		}
+	} else if node.Status == models.NodeStatusReady {
+		updates["status"] = models.NodeStatusNotReady
+	}
 
-	// Update node status and remove cluster assignment
-	node.Status = models.NodeStatusDiscovered
-	node.ClusterID = nil
-	node.NodeName = ""
-	node.KubeVersion = ""
+	if len(updates) == 0 {
+		return nil
+	}
 
-	if err := s.db.DB().Save(node).Error; err != nil {
+	if err := s.db.DB().Model(&models.Node{}).Where("id = ?", id).Updates(updates).Error; err != nil {
 		s.logger.WithFields(map[string]interface{}{
-			"node_id": id,
-			"error":   err,
-		}).Error("Failed to deprovision node")
-		return errors.Wrapf(err, "failed to deprovision node")
+			"id":    id,
+			"error": err,
+		}).Error("Failed to update node agent state")
+		return errors.Wrapf(err, "failed to update node agent state")
 	}
 
 	s.logger.WithFields(map[string]interface{}{
-		"node_id":      id,
-		"node_name":    node.Name,
-		"cluster_id":   oldClusterID,
-	}).Info("Node deprovisioned successfully")
+		"id":        id,
+		"name":      node.Name,
+		"reachable": reachable,
+		"status":    updates["status"],
+	}).Info("Node agent reachability changed")
+
+	if status, ok := updates["status"].(models.NodeStatus); ok {
+		node.Status = status
+		s.publish(events.ActionUpdated, node)
+	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// applyAgentState fills in the node's agent connection state when a provider is configured
+func (s *NodeService) applyAgentState(node *models.Node) {
+	if s.agents != nil {
+		node.AgentState = s.agents.AgentState(node.ID)
+	}
+}
+
+// publish announces a node change on the event bus
+func (s *NodeService) publish(action events.Action, node *models.Node) {
+	s.bus.Publish(events.Node{
+		Action:    action,
+		NodeID:    node.ID,
+		Name:      node.Name,
+		Status:    string(node.Status),
+		IPAddress: node.IPAddress,
+		ClusterID: node.ClusterID,
+	})
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// kubeRequestTimeout bounds the Kubernetes API calls of deprovisioning other
+// than draining, which has its own timeout
+const kubeRequestTimeout = 30 * time.Second
+
+// Deprovisioning step names, recorded with each deprovision job
+const (
+	deprovisionStepCordon  = "cordon"
+	deprovisionStepDrain   = "drain"
+	deprovisionStepDelete  = "delete-node"
+	deprovisionStepRelease = "release-node"
+)
+
+// DeprovisionOptions controls how a node is removed from its cluster
+type DeprovisionOptions struct {
+	// Force continues when the node cannot be cordoned, drained or deleted
+	// through the Kubernetes API, and deletes its pods without honouring
+	// PodDisruptionBudgets
+	Force bool
+	// DrainTimeout bounds evicting the node's pods. Zero uses k8s.DefaultDrainTimeout.
+	DrainTimeout time.Duration
+}
+
+// Deprovision removes a node from its cluster and marks it as discovered. A
+// node that joined Kubernetes is first cordoned, drained and deleted from the
+// API server; the assignment is only cleared once that has succeeded, unless
+// forced. Cancelling ctx stops the Kubernetes steps.
+func (s *NodeService) Deprovision(ctx context.Context, id uint, opts DeprovisionOptions) error {
+	node, err := s.GetByID(id, false)
+	if err != nil {
+		return err
+	}
+
+	oldClusterID := node.ClusterID
+	job := s.startDeprovisionJob(node)
+
+	log := s.logger.WithFields(map[string]interface{}{
+		"node_id":   id,
+		"node_name": node.NodeName,
+		"force":     opts.Force,
+	})
+
+	if err := s.removeFromKubernetes(ctx, node, opts, job); err != nil {
+		if ctx.Err() != nil {
+			// Forcing never outlives the request or the controller
+			job.done(models.JobStatusFailed, err.Error())
+			return errors.Wrapf(ctx.Err(), "deprovisioning node %d was interrupted", id)
+		}
+		if !opts.Force {
+			job.done(models.JobStatusFailed, err.Error())
+			return errors.Wrapf(ErrConflict, "failed to remove node from Kubernetes (deprovision with force to skip): %v", err)
+		}
+		log.WithError(err).Warn("Forcing deprovision after failing to remove node from Kubernetes")
+	}
+
+	// Update node status and remove cluster assignment
+	job.start(deprovisionStepRelease)
+	node.Status = models.NodeStatusDiscovered
+	node.ClusterID = nil
+	node.Cluster = nil // Save would otherwise restore cluster_id from the preloaded association
+	node.NodeName = ""
+	node.KubeVersion = ""
+
+	if err := s.db.DB().Save(node).Error; err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"node_id": id,
+			"error":   err,
+		}).Error("Failed to deprovision node")
+		job.finish(deprovisionStepRelease, err)
+		job.done(models.JobStatusFailed, err.Error())
+		return errors.Wrapf(err, "failed to deprovision node")
+	}
+	job.finish(deprovisionStepRelease, nil)
+	job.done(models.JobStatusSucceeded, "")
+
+	s.logger.WithFields(map[string]interface{}{
+		"node_id":    id,
+		"node_name":  node.Name,
+		"cluster_id": oldClusterID,
+	}).Info("Node deprovisioned successfully")
+
+	s.publish(events.ActionUpdated, node)
+
+	return nil
+}
+
+// removeFromKubernetes cordons, drains and deletes the node's Kubernetes Node
+// object. Nodes that never joined a cluster are skipped. With force, every
+// step is attempted and the first error is returned.
+func (s *NodeService) removeFromKubernetes(ctx context.Context, node *models.Node, opts DeprovisionOptions, job *recordedJob) error {
+	if node.ClusterID == nil || node.NodeName == "" {
+		job.skip(deprovisionStepCordon, deprovisionStepDrain, deprovisionStepDelete)
+		return nil
+	}
+
+	var cluster models.Cluster
+	if err := s.db.DB().First(&cluster, *node.ClusterID).Error; err != nil {
+		return errors.Wrapf(err, "failed to load cluster %d", *node.ClusterID)
+	}
+	if cluster.KubeConfig == "" {
+		// Without credentials the node cannot have been joined by the controller
+		job.skip(deprovisionStepCordon, deprovisionStepDrain, deprovisionStepDelete)
+		return nil
+	}
+
+	client, err := s.kube.ClientFor(&cluster)
+	if err != nil {
+		return err
+	}
+
+	drainTimeout := opts.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = k8s.DefaultDrainTimeout
+	}
+
+	steps := []struct {
+		name    string
+		timeout time.Duration
+		run     func(ctx context.Context) error
+	}{
+		{deprovisionStepCordon, kubeRequestTimeout, func(ctx context.Context) error {
+			return client.CordonNode(ctx, node.NodeName)
+		}},
+		{deprovisionStepDrain, drainTimeout + kubeRequestTimeout, func(ctx context.Context) error {
+			return client.DrainNode(ctx, node.NodeName, k8s.DrainOptions{
+				Timeout: drainTimeout,
+				Force:   opts.Force,
+			})
+		}},
+		{deprovisionStepDelete, kubeRequestTimeout, func(ctx context.Context) error {
+			return client.DeleteNode(ctx, node.NodeName)
+		}},
+	}
+
+	var firstErr error
+	for _, st := range steps {
+		job.start(st.name)
+
+		stepCtx, cancel := context.WithTimeout(ctx, st.timeout)
+		err := st.run(stepCtx)
+		cancel()
+
+		job.finish(st.name, err)
+		if err == nil {
+			continue
+		}
+		if !opts.Force || ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", st.name, err)
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", st.name, err)
+		}
+	}
+	return firstErr
+}
+
+func (s *NodeService) startDeprovisionJob(node *models.Node) *recordedJob {
+	return s.jobs.start(node, models.JobTypeDeprovision, []string{deprovisionStepCordon, deprovisionStepDrain, deprovisionStepDelete, deprovisionStepRelease}, s.logger)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// fakeKubeClients hands out a client backed by a fake clientset
+type fakeKubeClients struct {
+	clientset *fake.Clientset
+}
+
+func (f *fakeKubeClients) ClientFor(cluster *models.Cluster) (k8s.K8sClient, error) {
+	return k8s.NewClientForClientset(f.clientset, logrus.New()), nil
+}
+
+type deprovisionEnv struct {
+	nodes     *NodeService
+	jobs      *JobService
+	clientset *fake.Clientset
+	node      *models.Node
+}
+
+// setupDeprovision creates a node that has joined a cluster as pi-worker and
+// runs one pod
+func setupDeprovision(t *testing.T) *deprovisionEnv {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	clusters := NewClusterService(db, logger.Default())
+	cluster, err := clusters.Create(CreateClusterRequest{Name: "home"})
+	require.NoError(t, err)
+	_, err = clusters.SetKubeConfig(cluster.ID, "https://192.168.1.2:6443", []byte("apiVersion: v1\n"))
+	require.NoError(t, err)
+
+	jobs := NewJobService(db, logger.Default())
+	nodes := NewNodeService(db, logger.Default())
+	nodes.SetJobService(jobs)
+
+	node, err := nodes.Create(CreateNodeRequest{
+		Name:       "worker",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		ClusterID:  &cluster.ID,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+	nodeName := "pi-worker"
+	ready := models.NodeStatusReady
+	node, err = nodes.Update(node.ID, UpdateNodeRequest{NodeName: &nodeName, Status: &ready})
+	require.NoError(t, err)
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pi-worker"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"},
+			Spec:       corev1.PodSpec{NodeName: "pi-worker"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	nodes.SetKubeClientProvider(&fakeKubeClients{clientset: clientset})
+
+	return &deprovisionEnv{nodes: nodes, jobs: jobs, clientset: clientset, node: node}
+}
+
+// blockEvictions refuses every eviction as a PodDisruptionBudget would
+func (env *deprovisionEnv) blockEvictions() {
+	env.clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 1)
+	})
+}
+
+// allowEvictions deletes evicted pods
+func (env *deprovisionEnv) allowEvictions() {
+	env.clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		name := action.(k8stesting.CreateAction).GetObject().(metav1.Object).GetName()
+		return true, nil, env.clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), action.GetNamespace(), name)
+	})
+}
+
+func (env *deprovisionEnv) kubeNodeExists(t *testing.T) bool {
+	_, err := env.clientset.CoreV1().Nodes().Get(context.Background(), "pi-worker", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestNodeService_Deprovision(t *testing.T) {
+	t.Run("drains and deletes the Kubernetes node before releasing it", func(t *testing.T) {
+		env := setupDeprovision(t)
+		env.allowEvictions()
+
+		require.NoError(t, env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{}))
+
+		assert.False(t, env.kubeNodeExists(t))
+		pods, err := env.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, pods.Items)
+
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, models.NodeStatusDiscovered, node.Status)
+		assert.Nil(t, node.ClusterID)
+		assert.Empty(t, node.NodeName)
+
+		job, err := env.jobs.Latest(env.node.ID, models.JobTypeDeprovision)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusSucceeded, job.Status)
+		for _, step := range job.Steps {
+			assert.Equal(t, models.StepStatusSucceeded, step.Status, step.Name)
+		}
+	})
+
+	t.Run("keeps the node when a disruption budget blocks the drain", func(t *testing.T) {
+		env := setupDeprovision(t)
+		env.blockEvictions()
+
+		err := env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{DrainTimeout: 50 * time.Millisecond})
+		require.Error(t, err)
+		assert.True(t, IsConflict(err))
+
+		assert.True(t, env.kubeNodeExists(t), "the Kubernetes node is not deleted")
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.NotNil(t, node.ClusterID, "the cluster assignment is kept")
+		assert.Equal(t, "pi-worker", node.NodeName)
+
+		job, err := env.jobs.Latest(env.node.ID, models.JobTypeDeprovision)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusFailed, job.Status)
+		assert.Equal(t, models.StepStatusSucceeded, job.Steps[0].Status)
+		assert.Equal(t, models.StepStatusFailed, job.Steps[1].Status)
+		assert.Contains(t, job.Steps[1].Error, "default/web")
+		assert.Equal(t, models.StepStatusPending, job.Steps[2].Status)
+	})
+
+	t.Run("stops draining when the caller gives up", func(t *testing.T) {
+		env := setupDeprovision(t)
+		env.blockEvictions()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err := env.nodes.Deprovision(ctx, env.node.ID, DeprovisionOptions{DrainTimeout: time.Minute})
+		require.Error(t, err)
+		assert.Less(t, time.Since(started), 10*time.Second, "the drain timeout is not waited out")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.NotNil(t, node.ClusterID, "the cluster assignment is kept")
+		job, err := env.jobs.Latest(env.node.ID, models.JobTypeDeprovision)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusFailed, job.Status)
+	})
+
+	t.Run("force deletes pods and releases the node", func(t *testing.T) {
+		env := setupDeprovision(t)
+		env.blockEvictions()
+
+		require.NoError(t, env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{Force: true}))
+
+		assert.False(t, env.kubeNodeExists(t))
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.Nil(t, node.ClusterID)
+	})
+
+	t.Run("force releases the node when the API server fails", func(t *testing.T) {
+		env := setupDeprovision(t)
+		env.clientset.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewServiceUnavailable("API server unavailable")
+		})
+
+		err := env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{})
+		assert.True(t, IsConflict(err))
+
+		require.NoError(t, env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{Force: true}))
+		node, err := env.nodes.GetByID(env.node.ID, false)
+		require.NoError(t, err)
+		assert.Nil(t, node.ClusterID)
+
+		job, err := env.jobs.Latest(env.node.ID, models.JobTypeDeprovision)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusSucceeded, job.Status)
+		assert.Equal(t, models.StepStatusFailed, job.Steps[0].Status, "failed steps are recorded")
+	})
+
+	t.Run("skips Kubernetes for nodes that never joined", func(t *testing.T) {
+		env := setupDeprovision(t)
+		empty := ""
+		_, err := env.nodes.Update(env.node.ID, UpdateNodeRequest{NodeName: &empty})
+		require.NoError(t, err)
+
+		require.NoError(t, env.nodes.Deprovision(context.Background(), env.node.ID, DeprovisionOptions{}))
+		assert.True(t, env.kubeNodeExists(t))
+
+		job, err := env.jobs.Latest(env.node.ID, models.JobTypeDeprovision)
+		require.NoError(t, err)
+		assert.Equal(t, models.StepStatusSkipped, job.Steps[0].Status)
+		assert.Equal(t, models.StepStatusSucceeded, job.Steps[3].Status)
+	})
+}
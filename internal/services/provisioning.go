@@ -0,0 +1,332 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+)
+
+// StepOutputExcerpt is the number of trailing bytes of each output stream
+// kept on a provisioning step
+const StepOutputExcerpt = 4096
+
+// Provisioner installs Kubernetes on nodes that have been assigned to a cluster
+type Provisioner interface {
+	// Provision starts provisioning a node in the background. With resume,
+	// steps that already succeeded for the node are skipped.
+	Provision(nodeID uint, resume bool) error
+}
+
+// JobService records provisioning jobs and their steps and publishes their
+// progress and output
+type JobService struct {
+	db     *storage.Database
+	logger logger.Interface
+	bus    *events.Bus
+}
+
+// NewJobService creates a new provisioning job service
+func NewJobService(db *storage.Database, logger logger.Interface) *JobService {
+	return &JobService{
+		db:     db,
+		logger: logger.WithField("service", "job"),
+		bus:    events.NewBus(),
+	}
+}
+
+// SetEventBus sets the bus job progress and output are published to
+func (s *JobService) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// JobListOptions represents options for listing provisioning jobs
+type JobListOptions struct {
+	NodeID uint
+	Limit  int
+	Offset int
+}
+
+// Create records a new running job with its steps. Jobs of the node still
+// recorded as running were cut short by a controller restart and are marked
+// interrupted.
+func (s *JobService) Create(nodeID uint, clusterID *uint, jobType models.JobType, steps []models.ProvisioningStep) (*models.ProvisioningJob, error) {
+	job := &models.ProvisioningJob{
+		NodeID:    nodeID,
+		ClusterID: clusterID,
+		Type:      jobType,
+		Status:    models.JobStatusRunning,
+		StartedAt: time.Now(),
+		Steps:     steps,
+	}
+
+	err := s.db.WithTx(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ProvisioningJob{}).
+			Where("node_id = ? AND status = ?", nodeID, models.JobStatusRunning).
+			Updates(map[string]interface{}{
+				"status":      models.JobStatusInterrupted,
+				"finished_at": job.StartedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Create(job).Error
+	})
+	if err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"node_id": nodeID,
+			"error":   err,
+		}).Error("Failed to create provisioning job")
+		return nil, errors.Wrapf(err, "failed to create provisioning job")
+	}
+
+	s.publish(events.ActionCreated, job, "")
+	return job, nil
+}
+
+// List returns a node's jobs, newest first, without their steps
+func (s *JobService) List(opts JobListOptions) ([]models.ProvisioningJob, int64, error) {
+	var jobs []models.ProvisioningJob
+	var total int64
+
+	query := s.db.DB().Model(&models.ProvisioningJob{}).Where("node_id = ?", opts.NodeID)
+
+	if err := query.Count(&total).Error; err != nil {
+		s.logger.WithError(err).Error("Failed to count provisioning jobs")
+		return nil, 0, errors.Wrapf(err, "failed to count provisioning jobs")
+	}
+
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	if err := query.Order("id DESC").Find(&jobs).Error; err != nil {
+		s.logger.WithError(err).Error("Failed to fetch provisioning jobs")
+		return nil, 0, errors.Wrapf(err, "failed to fetch provisioning jobs")
+	}
+
+	return jobs, total, nil
+}
+
+// Get returns one of a node's jobs with its steps in order
+func (s *JobService) Get(nodeID, jobID uint) (*models.ProvisioningJob, error) {
+	return s.find(s.db.DB().Where("id = ? AND node_id = ?", jobID, nodeID))
+}
+
+// Latest returns the most recent job of a type for a node with its steps
+func (s *JobService) Latest(nodeID uint, jobType models.JobType) (*models.ProvisioningJob, error) {
+	return s.find(s.db.DB().Where("node_id = ? AND type = ?", nodeID, jobType).Order("id DESC"))
+}
+
+func (s *JobService) find(query *gorm.DB) (*models.ProvisioningJob, error) {
+	var job models.ProvisioningJob
+
+	err := query.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Wrapf(ErrNotFound, "provisioning job not found")
+		}
+		s.logger.WithError(err).Error("Failed to fetch provisioning job")
+		return nil, errors.Wrapf(err, "failed to fetch provisioning job")
+	}
+
+	return &job, nil
+}
+
+// StartStep marks a step of a job as running
+func (s *JobService) StartStep(job *models.ProvisioningJob, step *models.ProvisioningStep) error {
+	now := time.Now()
+	step.Status = models.StepStatusRunning
+	step.Error = ""
+	step.StartedAt = &now
+	step.FinishedAt = nil
+
+	if err := s.db.DB().Save(step).Error; err != nil {
+		return errors.Wrapf(err, "failed to record provisioning step")
+	}
+
+	s.publish(events.ActionUpdated, job, step.Name)
+	return nil
+}
+
+// FinishStep records the outcome of a step with excerpts of its output
+func (s *JobService) FinishStep(step *models.ProvisioningStep, status models.StepStatus, message, stdout, stderr string) error {
+	now := time.Now()
+	step.Status = status
+	step.Error = message
+	step.Stdout = stdout
+	step.Stderr = stderr
+	step.FinishedAt = &now
+
+	if err := s.db.DB().Save(step).Error; err != nil {
+		return errors.Wrapf(err, "failed to record provisioning step")
+	}
+	return nil
+}
+
+// Finish records the final outcome of a job
+func (s *JobService) Finish(job *models.ProvisioningJob, status models.JobStatus, message string) error {
+	now := time.Now()
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+
+	err := s.db.DB().Model(job).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       message,
+		"finished_at": now,
+	}).Error
+	if err != nil {
+		return errors.Wrapf(err, "failed to record provisioning job outcome")
+	}
+
+	s.publish(events.ActionUpdated, job, "")
+	return nil
+}
+
+// PublishLog publishes a line of output from a step of a job. Lines are not
+// stored; FinishStep keeps an excerpt of each stream.
+func (s *JobService) PublishLog(job *models.ProvisioningJob, step, stream, line string) {
+	s.bus.Publish(events.ProvisioningLog{
+		JobID:     job.ID,
+		NodeID:    job.NodeID,
+		Step:      step,
+		Stream:    stream,
+		Line:      line,
+		Timestamp: time.Now(),
+	})
+}
+
+// Follow subscribes to the progress and output of a job. Callers must Close
+// the subscription.
+func (s *JobService) Follow(jobID uint) *events.Subscription {
+	return s.bus.Subscribe(func(e events.Event) bool {
+		switch payload := e.Payload.(type) {
+		case events.ProvisioningJob:
+			return payload.JobID == jobID
+		case events.ProvisioningLog:
+			return payload.JobID == jobID
+		}
+		return false
+	}, 0)
+}
+
+func (s *JobService) publish(action events.Action, job *models.ProvisioningJob, step string) {
+	s.bus.Publish(events.ProvisioningJob{
+		Action: action,
+		JobID:  job.ID,
+		NodeID: job.NodeID,
+		Type:   string(job.Type),
+		Status: string(job.Status),
+		Step:   step,
+		Error:  job.Error,
+	})
+}
+
+// recordedJob records the progress of a job run by a service. Its methods do
+// nothing on a nil job, used when no job service is set.
+type recordedJob struct {
+	jobs   *JobService
+	record *models.ProvisioningJob
+	logger logger.Interface
+}
+
+// start records a new job of a node with the named steps pending. It returns
+// nil, logging why, if the job cannot be recorded.
+func (s *JobService) start(node *models.Node, jobType models.JobType, names []string, log logger.Interface) *recordedJob {
+	if s == nil {
+		return nil
+	}
+
+	steps := make([]models.ProvisioningStep, len(names))
+	for i, name := range names {
+		steps[i] = models.ProvisioningStep{Position: i, Name: name, Status: models.StepStatusPending}
+	}
+
+	record, err := s.Create(node.ID, node.ClusterID, jobType, steps)
+	if err != nil {
+		log.WithError(err).WithField("node_id", node.ID).Errorf("Failed to record %s job", jobType)
+		return nil
+	}
+	return &recordedJob{jobs: s, record: record, logger: log.WithField("job_id", record.ID)}
+}
+
+// recorded returns the job's record, or nil if it was not recorded
+func (j *recordedJob) recorded() *models.ProvisioningJob {
+	if j == nil {
+		return nil
+	}
+	record := *j.record
+	record.Steps = append([]models.ProvisioningStep(nil), j.record.Steps...)
+	return &record
+}
+
+func (j *recordedJob) step(name string) *models.ProvisioningStep {
+	for i := range j.record.Steps {
+		if j.record.Steps[i].Name == name {
+			return &j.record.Steps[i]
+		}
+	}
+	return nil
+}
+
+// start marks a step as running
+func (j *recordedJob) start(name string) {
+	if j == nil {
+		return
+	}
+	if err := j.jobs.StartStep(j.record, j.step(name)); err != nil {
+		j.logger.WithError(err).Errorf("Failed to record %s step", j.record.Type)
+	}
+}
+
+// finish records the outcome of a running step
+func (j *recordedJob) finish(name string, cause error) {
+	j.finishWithOutput(name, cause, "", "")
+}
+
+// finishWithOutput records the outcome of a running step with excerpts of
+// its output
+func (j *recordedJob) finishWithOutput(name string, cause error, stdout, stderr string) {
+	if j == nil {
+		return
+	}
+	status, message := models.StepStatusSucceeded, ""
+	if cause != nil {
+		status, message = models.StepStatusFailed, cause.Error()
+	}
+	if err := j.jobs.FinishStep(j.step(name), status, message, stdout, stderr); err != nil {
+		j.logger.WithError(err).Errorf("Failed to record %s step", j.record.Type)
+	}
+}
+
+// skip marks steps that do not apply
+func (j *recordedJob) skip(names ...string) {
+	if j == nil {
+		return
+	}
+	for _, name := range names {
+		if err := j.jobs.FinishStep(j.step(name), models.StepStatusSkipped, "", "", ""); err != nil {
+			j.logger.WithError(err).Errorf("Failed to record %s step", j.record.Type)
+		}
+	}
+}
+
+// done records the outcome of the job
+func (j *recordedJob) done(status models.JobStatus, message string) {
+	if j == nil {
+		return
+	}
+	if err := j.jobs.Finish(j.record, status, message); err != nil {
+		j.logger.WithError(err).Errorf("Failed to record %s job outcome", j.record.Type)
+	}
+}
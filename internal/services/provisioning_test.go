@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+func setupJobService(t *testing.T) (*JobService, *events.Bus, *models.Node) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	bus := events.NewBus()
+	jobs := NewJobService(db, logger.Default())
+	jobs.SetEventBus(bus)
+
+	node, err := NewNodeService(db, logger.Default()).Create(CreateNodeRequest{
+		Name:       "pi-1",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+	return jobs, bus, node
+}
+
+func jobSteps(names ...string) []models.ProvisioningStep {
+	steps := make([]models.ProvisioningStep, len(names))
+	for i, name := range names {
+		steps[i] = models.ProvisioningStep{Position: i, Name: name, Status: models.StepStatusPending}
+	}
+	return steps
+}
+
+func TestJobService_RecordsJobs(t *testing.T) {
+	jobs, _, node := setupJobService(t)
+
+	first, err := jobs.Create(node.ID, nil, models.JobTypeProvision, jobSteps("preflight", "install-agent"))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusRunning, first.Status)
+
+	step := &first.Steps[1]
+	require.NoError(t, jobs.StartStep(first, step))
+	require.NoError(t, jobs.FinishStep(step, models.StepStatusFailed, "exit status 1", "$ install\n", "download failed\n"))
+	require.NoError(t, jobs.Finish(first, models.JobStatusFailed, "step install-agent failed"))
+
+	second, err := jobs.Create(node.ID, nil, models.JobTypeProvision, jobSteps("preflight"))
+	require.NoError(t, err)
+
+	// A job left running by a previous controller is marked interrupted
+	_, err = jobs.Create(node.ID, nil, models.JobTypeProvision, jobSteps("preflight"))
+	require.NoError(t, err)
+	second, err = jobs.Get(node.ID, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusInterrupted, second.Status)
+	assert.NotNil(t, second.FinishedAt)
+
+	loaded, err := jobs.Get(node.ID, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusFailed, loaded.Status)
+	assert.Equal(t, "step install-agent failed", loaded.Error)
+	require.Len(t, loaded.Steps, 2)
+	assert.Equal(t, "install-agent", loaded.Steps[1].Name)
+	assert.Equal(t, "download failed\n", loaded.Steps[1].Stderr)
+	assert.Equal(t, "$ install\n", loaded.Steps[1].Stdout)
+
+	list, total, err := jobs.List(JobListOptions{NodeID: node.ID, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, list, 2)
+	assert.Greater(t, list[0].ID, list[1].ID, "newest jobs are listed first")
+
+	_, err = jobs.Get(node.ID+1, first.ID)
+	assert.True(t, IsNotFound(err), "jobs are only found through their own node")
+	_, err = jobs.Latest(node.ID, models.JobTypeDeprovision)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestJobService_Follow(t *testing.T) {
+	jobs, _, node := setupJobService(t)
+
+	job, err := jobs.Create(node.ID, nil, models.JobTypeProvision, jobSteps("preflight"))
+	require.NoError(t, err)
+	other, err := jobs.Create(node.ID, nil, models.JobTypeProvision, jobSteps("preflight"))
+	require.NoError(t, err)
+
+	sub := jobs.Follow(job.ID)
+	defer sub.Close()
+
+	jobs.PublishLog(other, "preflight", "stdout", "not followed")
+	jobs.PublishLog(job, "preflight", "stdout", "aarch64")
+	require.NoError(t, jobs.Finish(job, models.JobStatusSucceeded, ""))
+
+	line := nextEvent(t, sub).Payload.(events.ProvisioningLog)
+	assert.Equal(t, "aarch64", line.Line)
+	assert.Equal(t, "preflight", line.Step)
+
+	finished := nextEvent(t, sub).Payload.(events.ProvisioningJob)
+	assert.Equal(t, job.ID, finished.JobID)
+	assert.Equal(t, string(models.JobStatusSucceeded), finished.Status)
+	assert.Empty(t, sub.C())
+}
@@ -0,0 +1,606 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	"github.com/dsyorkd/pi-controller/pkg/discovery"
+)
+
+// Registration approval modes
+const (
+	ApprovalAuto   = "auto"
+	ApprovalManual = "manual"
+)
+
+// macPrefixPattern matches MAC address prefixes of at least three octets,
+// such as a vendor's OUI
+var macPrefixPattern = regexp.MustCompile(`^([0-9a-f]{2}[:-]){2,5}[0-9a-f]{2}$`)
+
+// registrationDecision is what a policy decides for a discovered node
+type registrationDecision int
+
+const (
+	registrationQueue registrationDecision = iota
+	registrationApprove
+	registrationDeny
+)
+
+// RegistrationPolicy decides whether a discovered node is added to the
+// inventory automatically, queued for approval or ignored. Deny rules take
+// precedence over allow rules.
+type RegistrationPolicy struct {
+	autoApprove bool
+	allow       []registrationRule
+	deny        []registrationRule
+}
+
+// registrationRule matches discovered nodes by IP network, MAC address prefix
+// or hostname pattern
+type registrationRule struct {
+	network   *net.IPNet
+	macPrefix string
+	hostname  string
+}
+
+// NewRegistrationPolicy parses an approval mode, auto or manual, and allow and
+// deny rules. Each rule is a CIDR, a MAC address prefix of at least three
+// octets or a hostname pattern as understood by path.Match.
+func NewRegistrationPolicy(approval string, allow, deny []string) (*RegistrationPolicy, error) {
+	policy := &RegistrationPolicy{}
+	switch approval {
+	case ApprovalAuto:
+		policy.autoApprove = true
+	case ApprovalManual, "":
+	default:
+		return nil, fmt.Errorf("approval must be %s or %s, got %q", ApprovalAuto, ApprovalManual, approval)
+	}
+
+	var err error
+	if policy.allow, err = parseRegistrationRules(allow); err != nil {
+		return nil, errors.Wrapf(err, "invalid allow rule")
+	}
+	if policy.deny, err = parseRegistrationRules(deny); err != nil {
+		return nil, errors.Wrapf(err, "invalid deny rule")
+	}
+	return policy, nil
+}
+
+func parseRegistrationRules(entries []string) ([]registrationRule, error) {
+	rules := make([]registrationRule, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case strings.Contains(entry, "/"):
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", entry, err)
+			}
+			rules = append(rules, registrationRule{network: network})
+		case macPrefixPattern.MatchString(entry):
+			rules = append(rules, registrationRule{macPrefix: strings.ReplaceAll(entry, "-", ":")})
+		default:
+			if _, err := path.Match(entry, ""); err != nil || entry == "" {
+				return nil, fmt.Errorf("%q is not a CIDR, MAC address prefix or hostname pattern", entry)
+			}
+			rules = append(rules, registrationRule{hostname: entry})
+		}
+	}
+	return rules, nil
+}
+
+func (r registrationRule) matches(node discovery.Node, mac string) bool {
+	switch {
+	case r.network != nil:
+		ip := net.ParseIP(node.IPAddress)
+		return ip != nil && r.network.Contains(ip)
+	case r.macPrefix != "":
+		return strings.HasPrefix(mac, r.macPrefix)
+	default:
+		matched, _ := path.Match(r.hostname, strings.ToLower(discoveredHostname(node)))
+		return matched
+	}
+}
+
+// decide returns what to do with a discovered node. A nil policy queues
+// every node.
+func (p *RegistrationPolicy) decide(node discovery.Node, mac string) registrationDecision {
+	if p == nil {
+		return registrationQueue
+	}
+	for _, rule := range p.deny {
+		if rule.matches(node, mac) {
+			return registrationDeny
+		}
+	}
+	if p.autoApprove {
+		return registrationApprove
+	}
+	for _, rule := range p.allow {
+		if rule.matches(node, mac) {
+			return registrationApprove
+		}
+	}
+	return registrationQueue
+}
+
+// RegistrationService adds nodes found by discovery to the inventory. Nodes
+// already in the inventory are matched by MAC address or serial number and
+// kept up to date; new nodes are registered according to the policy, either
+// directly or through an approval queue. Nodes discovery loses track of are
+// marked not_ready, or unknown if they never became ready.
+type RegistrationService struct {
+	db     *storage.Database
+	nodes  *NodeService
+	policy *RegistrationPolicy
+	logger logger.Interface
+
+	mu sync.Mutex // serializes discovery events
+}
+
+// NewRegistrationService creates a new registration service. A nil policy
+// queues every new node for approval.
+func NewRegistrationService(db *storage.Database, nodes *NodeService, policy *RegistrationPolicy, logger logger.Interface) *RegistrationService {
+	return &RegistrationService{
+		db:     db,
+		nodes:  nodes,
+		policy: policy,
+		logger: logger.WithField("service", "registration"),
+	}
+}
+
+// RegistrationListOptions represents options for listing registrations
+type RegistrationListOptions struct {
+	Status *models.RegistrationStatus
+	Limit  int
+	Offset int
+}
+
+// ApproveRegistrationRequest represents the request to approve a registration
+type ApproveRegistrationRequest struct {
+	// Name of the node; defaults to the discovered hostname
+	Name string `json:"name"`
+	// Role of the node; defaults to worker
+	Role models.NodeRole `json:"role"`
+}
+
+// HandleEvent records a discovery event in the inventory. It is registered
+// with discovery.Service.AddEventHandler.
+func (s *RegistrationService) HandleEvent(event discovery.NodeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	switch event.Type {
+	case discovery.NodeDiscovered, discovery.NodeUpdated:
+		err = s.observe(event.Node)
+	case discovery.NodeLost:
+		err = s.lose(event.Node)
+	}
+	if err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"event":      event.Type,
+			"id":         event.Node.ID,
+			"ip_address": event.Node.IPAddress,
+			"error":      err,
+		}).Warn("Failed to record discovered node")
+	}
+}
+
+// List returns registrations, pending ones first and then by when they were
+// last seen
+func (s *RegistrationService) List(opts RegistrationListOptions) ([]models.NodeRegistration, int64, error) {
+	query := s.db.DB().Model(&models.NodeRegistration{})
+	if opts.Status != nil {
+		query = query.Where("status = ?", *opts.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to count registrations")
+	}
+
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
+	var registrations []models.NodeRegistration
+	err := query.Order(fmt.Sprintf("CASE status WHEN '%s' THEN 0 ELSE 1 END", models.RegistrationStatusPending)).
+		Order("last_seen DESC").Find(&registrations).Error
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to list registrations")
+	}
+	return registrations, total, nil
+}
+
+// Get returns a registration by ID
+func (s *RegistrationService) Get(id uint) (*models.NodeRegistration, error) {
+	var registration models.NodeRegistration
+	if err := s.db.DB().First(&registration, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(ErrNotFound, "registration %d not found", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get registration")
+	}
+	return &registration, nil
+}
+
+// Approve adds a pending registration's node to the inventory
+func (s *RegistrationService) Approve(id uint, req ApproveRegistrationRequest) (*models.Node, error) {
+	if req.Role == "" {
+		req.Role = models.NodeRoleWorker
+	}
+	if req.Role != models.NodeRoleMaster && req.Role != models.NodeRoleWorker {
+		return nil, errors.Wrapf(ErrValidationFailed, "role must be master or worker")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registration, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !registration.IsPending() {
+		return nil, errors.Wrapf(ErrConflict, "registration %d is already %s", id, registration.Status)
+	}
+
+	node, err := s.register(registration, req.Name, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"registration_id": id,
+		"node_id":         node.ID,
+		"name":            node.Name,
+	}).Info("Approved discovered node")
+	return node, nil
+}
+
+// Reject keeps discovery from queueing a pending registration's node again
+// until the registration is deleted
+func (s *RegistrationService) Reject(id uint) (*models.NodeRegistration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registration, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !registration.IsPending() {
+		return nil, errors.Wrapf(ErrConflict, "registration %d is already %s", id, registration.Status)
+	}
+
+	now := time.Now()
+	registration.Status = models.RegistrationStatusRejected
+	registration.DecidedAt = &now
+	registration.Message = ""
+	if err := s.db.DB().Save(registration).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to reject registration")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"registration_id": id,
+		"mac_address":     registration.MACAddress,
+	}).Info("Rejected discovered node")
+	return registration, nil
+}
+
+// Delete forgets a registration. A node discovered again is registered anew.
+func (s *RegistrationService) Delete(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := s.db.DB().Delete(&models.NodeRegistration{}, id)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "failed to delete registration")
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(ErrNotFound, "registration %d not found", id)
+	}
+	return nil
+}
+
+// observe records a discovered node, updating it if it is in the inventory
+// and registering it otherwise
+func (s *RegistrationService) observe(discovered discovery.Node) error {
+	mac := normalizeMAC(discovered.MACAddress)
+	if mac == "" && discovered.SerialNumber == "" {
+		s.logger.WithField("id", discovered.ID).Debug("Ignoring discovered node without a hardware identity")
+		return nil
+	}
+
+	node, err := findNodeByHardware(s.db, mac, discovered.SerialNumber)
+	if err != nil {
+		return err
+	}
+	if node != nil {
+		return s.refresh(node, discovered)
+	}
+	if mac == "" {
+		s.logger.WithField("id", discovered.ID).Debug("Ignoring discovered node without a MAC address")
+		return nil
+	}
+
+	decision := s.policy.decide(discovered, mac)
+	if decision == registrationDeny {
+		s.logger.WithFields(map[string]interface{}{
+			"mac_address": mac,
+			"ip_address":  discovered.IPAddress,
+		}).Debug("Ignoring discovered node denied by policy")
+		return nil
+	}
+
+	var registration models.NodeRegistration
+	err = s.db.DB().Where("mac_address = ?", mac).First(&registration).Error
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return errors.Wrapf(err, "failed to get registration")
+	}
+
+	now := time.Now()
+	if isNew {
+		registration = models.NodeRegistration{MACAddress: mac, Status: models.RegistrationStatusPending, FirstSeen: now}
+	}
+	registration.Hostname = discoveredHostname(discovered)
+	registration.IPAddress = discovered.IPAddress
+	registration.Source = discovered.ServiceType
+	registration.LastSeen = now
+	if discovered.SerialNumber != "" {
+		registration.SerialNumber = discovered.SerialNumber
+	}
+	if model := discovered.TXTRecords["model"]; model != "" {
+		registration.Model = model
+	}
+
+	switch registration.Status {
+	case models.RegistrationStatusRejected:
+		return s.db.DB().Save(&registration).Error
+	case models.RegistrationStatusApproved:
+		// The node was approved once and has since been deleted
+		registration.Status = models.RegistrationStatusPending
+		registration.NodeID = nil
+		registration.DecidedAt = nil
+	}
+
+	if err := s.db.DB().Save(&registration).Error; err != nil {
+		return errors.Wrapf(err, "failed to record registration")
+	}
+	if isNew {
+		s.logger.WithFields(map[string]interface{}{
+			"registration_id": registration.ID,
+			"mac_address":     mac,
+			"hostname":        registration.Hostname,
+			"ip_address":      registration.IPAddress,
+		}).Info("Discovered new node")
+	}
+
+	if decision != registrationApprove {
+		return nil
+	}
+	if _, err := s.register(&registration, "", models.NodeRoleWorker); err != nil {
+		// Leave it queued with the reason, for an operator to resolve
+		s.db.DB().Model(&registration).Update("message", err.Error())
+		return err
+	}
+	return nil
+}
+
+// register creates the node of a registration and marks it approved
+func (s *RegistrationService) register(registration *models.NodeRegistration, name string, role models.NodeRole) (*models.Node, error) {
+	// Hostnames are often left at the image default, so a discovered name
+	// that is taken gets the end of the MAC address appended
+	suffix := strings.ReplaceAll(registration.MACAddress[len(registration.MACAddress)-8:], ":", "")
+	explicit := name != ""
+	if !explicit {
+		name = registration.Hostname
+		if name == "" {
+			name = "pi-" + suffix
+		}
+	}
+	_, err := s.nodes.GetByName(name)
+	if err == nil && !explicit {
+		name += "-" + suffix
+		_, err = s.nodes.GetByName(name)
+	}
+	if err != ErrNotFound {
+		if err == nil {
+			return nil, errors.Wrapf(ErrAlreadyExists, "node with name %s already exists", name)
+		}
+		return nil, err
+	}
+	if existing, err := s.nodes.GetByIPAddress(registration.IPAddress); err != ErrNotFound {
+		if err == nil {
+			return nil, errors.Wrapf(ErrConflict, "IP address %s is used by node %s", registration.IPAddress, existing.Name)
+		}
+		return nil, err
+	}
+
+	node := &models.Node{
+		Name:         name,
+		IPAddress:    registration.IPAddress,
+		MACAddress:   registration.MACAddress,
+		Status:       models.NodeStatusDiscovered,
+		Role:         role,
+		Model:        registration.Model,
+		SerialNumber: registration.SerialNumber,
+		LastSeen:     registration.LastSeen,
+	}
+	now := time.Now()
+	err = s.db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(node).Error; err != nil {
+			return err
+		}
+		return tx.Model(registration).Updates(map[string]interface{}{
+			"status":     models.RegistrationStatusApproved,
+			"node_id":    node.ID,
+			"message":    "",
+			"decided_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to register node")
+	}
+
+	registration.Status = models.RegistrationStatusApproved
+	registration.NodeID = &node.ID
+	registration.Message = ""
+	registration.DecidedAt = &now
+
+	s.logger.WithFields(map[string]interface{}{
+		"node_id":     node.ID,
+		"name":        node.Name,
+		"mac_address": node.MACAddress,
+		"ip_address":  node.IPAddress,
+	}).Info("Registered discovered node")
+	s.nodes.publish(events.ActionCreated, node)
+	return node, nil
+}
+
+// refresh records that a node in the inventory was seen at an address. Any
+// host can post to the phone-home endpoint, so its reports never move a node
+// or add to its identity: provisioning, agent calls and backups would follow
+// a forged address.
+func (s *RegistrationService) refresh(node *models.Node, discovered discovery.Node) error {
+	trusted := discovered.ServiceType != discovery.MethodPhoneHome
+
+	updates := map[string]interface{}{"last_seen": time.Now()}
+	if node.Status == models.NodeStatusUnknown {
+		updates["status"] = models.NodeStatusDiscovered
+	}
+	if trusted && node.SerialNumber == "" && discovered.SerialNumber != "" {
+		updates["serial_number"] = discovered.SerialNumber
+	}
+	moving := discovered.IPAddress != "" && discovered.IPAddress != node.IPAddress
+	if moving && !trusted {
+		s.logger.WithFields(map[string]interface{}{
+			"node_id":    node.ID,
+			"from":       node.IPAddress,
+			"ip_address": discovered.IPAddress,
+		}).Warn("Ignoring address change of an inventory node reported by phone-home")
+	} else if moving {
+		existing, err := s.nodes.GetByIPAddress(discovered.IPAddress)
+		switch {
+		case err == nil:
+			s.logger.WithFields(map[string]interface{}{
+				"node_id":    node.ID,
+				"ip_address": discovered.IPAddress,
+				"used_by":    existing.Name,
+			}).Warn("Discovered node moved to an address used by another node")
+		case err == ErrNotFound:
+			updates["ip_address"] = discovered.IPAddress
+		default:
+			return err
+		}
+	}
+
+	if err := s.db.DB().Model(&models.Node{}).Where("id = ?", node.ID).Updates(updates).Error; err != nil {
+		return errors.Wrapf(err, "failed to update discovered node")
+	}
+
+	_, statusChanged := updates["status"]
+	_, moved := updates["ip_address"]
+	if statusChanged || moved {
+		if statusChanged {
+			node.Status = models.NodeStatusDiscovered
+		}
+		if moved {
+			s.logger.WithFields(map[string]interface{}{
+				"node_id": node.ID,
+				"from":    node.IPAddress,
+				"to":      discovered.IPAddress,
+			}).Info("Discovered node changed address")
+			node.IPAddress = discovered.IPAddress
+		}
+		s.nodes.publish(events.ActionUpdated, node)
+	}
+	return nil
+}
+
+// lose marks a node in the inventory that discovery lost track of
+func (s *RegistrationService) lose(discovered discovery.Node) error {
+	node, err := findNodeByHardware(s.db, normalizeMAC(discovered.MACAddress), discovered.SerialNumber)
+	if err != nil || node == nil {
+		return err
+	}
+
+	var status models.NodeStatus
+	switch node.Status {
+	case models.NodeStatusReady:
+		status = models.NodeStatusNotReady
+	case models.NodeStatusDiscovered:
+		status = models.NodeStatusUnknown
+	default:
+		return nil
+	}
+
+	if err := s.db.DB().Model(&models.Node{}).Where("id = ?", node.ID).Update("status", status).Error; err != nil {
+		return errors.Wrapf(err, "failed to update lost node")
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"node_id": node.ID,
+		"name":    node.Name,
+		"status":  status,
+	}).Warn("Discovery lost track of node")
+
+	node.Status = status
+	s.nodes.publish(events.ActionUpdated, node)
+	return nil
+}
+
+// findNodeByHardware returns the node in the inventory with the MAC
+// address, or else the serial number, or nil if there is none
+func findNodeByHardware(db *storage.Database, mac, serial string) (*models.Node, error) {
+	var node models.Node
+	var err error
+	switch {
+	case mac != "":
+		err = db.DB().Where("LOWER(mac_address) = ?", mac).First(&node).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) && serial != "" {
+			err = db.DB().Where("serial_number = ?", serial).First(&node).Error
+		}
+	case serial != "":
+		err = db.DB().Where("serial_number = ?", serial).First(&node).Error
+	default:
+		return nil, nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find node")
+	}
+	return &node, nil
+}
+
+// normalizeMAC returns a MAC address in lower case with colons, or "" if it
+// is not one
+func normalizeMAC(mac string) string {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil {
+		return ""
+	}
+	return hw.String()
+}
+
+// discoveredHostname returns a discovered node's name without an mDNS domain
+func discoveredHostname(node discovery.Node) string {
+	name := strings.TrimSuffix(node.Name, ".")
+	return strings.TrimSuffix(name, ".local")
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	"github.com/dsyorkd/pi-controller/pkg/discovery"
+)
+
+func setupRegistration(t *testing.T, policy *RegistrationPolicy) (*RegistrationService, *NodeService) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	nodes := NewNodeService(db, logger.Default())
+	return NewRegistrationService(db, nodes, policy, logger.Default()), nodes
+}
+
+// discovered returns a Raspberry Pi as mDNS discovery reports it
+func discovered(name, ip, mac string) discovery.Node {
+	return discovery.Node{
+		ID:          "mdns-" + name,
+		Name:        name + ".local.",
+		IPAddress:   ip,
+		ServiceType: "_pi-controller._tcp",
+		TXTRecords:  map[string]string{"model": "Raspberry Pi 4 Model B"},
+		MACAddress:  mac,
+	}
+}
+
+func pendingRegistrations(t *testing.T, registrations *RegistrationService) []models.NodeRegistration {
+	pending := models.RegistrationStatusPending
+	list, _, err := registrations.List(RegistrationListOptions{Status: &pending})
+	require.NoError(t, err)
+	return list
+}
+
+func TestNewRegistrationPolicy(t *testing.T) {
+	_, err := NewRegistrationPolicy("sometimes", nil, nil)
+	assert.Error(t, err)
+	_, err = NewRegistrationPolicy(ApprovalManual, []string{"192.168.1.0/33"}, nil)
+	assert.Error(t, err)
+	_, err = NewRegistrationPolicy(ApprovalManual, nil, []string{"pi-[0-9"})
+	assert.Error(t, err)
+
+	policy, err := NewRegistrationPolicy(ApprovalManual,
+		[]string{"DC-A6-32", "10.0.0.0/24", "pi-*"},
+		[]string{"pi-lab-*", "dc:a6:32:00:00:01"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		node discovery.Node
+		want registrationDecision
+	}{
+		{"allowed by OUI", discovered("raspberrypi", "192.168.1.10", "dc:a6:32:12:34:56"), registrationApprove},
+		{"allowed by network", discovered("host", "10.0.0.7", "aa:bb:cc:dd:ee:01"), registrationApprove},
+		{"allowed by hostname", discovered("pi-kitchen", "192.168.1.11", "aa:bb:cc:dd:ee:02"), registrationApprove},
+		{"denied by hostname", discovered("pi-lab-1", "10.0.0.8", "dc:a6:32:12:34:57"), registrationDeny},
+		{"denied by MAC address", discovered("raspberrypi", "192.168.1.12", "dc:a6:32:00:00:01"), registrationDeny},
+		{"queued", discovered("laptop", "192.168.1.13", "aa:bb:cc:dd:ee:03"), registrationQueue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.decide(tt.node, normalizeMAC(tt.node.MACAddress)))
+		})
+	}
+
+	auto, err := NewRegistrationPolicy(ApprovalAuto, nil, []string{"laptop"})
+	require.NoError(t, err)
+	assert.Equal(t, registrationApprove, auto.decide(discovered("pi", "192.168.1.14", "aa:bb:cc:dd:ee:04"), "aa:bb:cc:dd:ee:04"))
+	assert.Equal(t, registrationDeny, auto.decide(discovered("laptop", "192.168.1.13", "aa:bb:cc:dd:ee:03"), "aa:bb:cc:dd:ee:03"))
+}
+
+func TestRegistrationService_ApprovalQueue(t *testing.T) {
+	registrations, nodes := setupRegistration(t, nil)
+
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("raspberrypi", "192.168.1.10", "DC:A6:32:12:34:56")})
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovery.Node{ID: "static-0", IPAddress: "192.168.1.99"}})
+
+	pending := pendingRegistrations(t, registrations)
+	require.Len(t, pending, 1, "nodes without a MAC address are not queued")
+	assert.Equal(t, "dc:a6:32:12:34:56", pending[0].MACAddress)
+	assert.Equal(t, "raspberrypi", pending[0].Hostname)
+	assert.Equal(t, "Raspberry Pi 4 Model B", pending[0].Model)
+	assert.Equal(t, "_pi-controller._tcp", pending[0].Source)
+
+	_, total, err := nodes.List(NodeListOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, total, "queued nodes are not in the inventory")
+
+	// Seen again at a new address
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: discovered("raspberrypi", "192.168.1.20", "dc:a6:32:12:34:56")})
+	pending = pendingRegistrations(t, registrations)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "192.168.1.20", pending[0].IPAddress)
+
+	_, err = registrations.Approve(pending[0].ID, ApproveRegistrationRequest{Role: "router"})
+	assert.True(t, IsValidationFailed(err))
+
+	node, err := registrations.Approve(pending[0].ID, ApproveRegistrationRequest{Name: "pi-kitchen"})
+	require.NoError(t, err)
+	assert.Equal(t, "pi-kitchen", node.Name)
+	assert.Equal(t, "192.168.1.20", node.IPAddress)
+	assert.Equal(t, models.NodeRoleWorker, node.Role)
+	assert.Equal(t, models.NodeStatusDiscovered, node.Status)
+	assert.Equal(t, "Raspberry Pi 4 Model B", node.Model)
+
+	registration, err := registrations.Get(pending[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RegistrationStatusApproved, registration.Status)
+	require.NotNil(t, registration.NodeID)
+	assert.Equal(t, node.ID, *registration.NodeID)
+
+	_, err = registrations.Approve(registration.ID, ApproveRegistrationRequest{})
+	assert.True(t, IsConflict(err))
+	_, err = registrations.Reject(registration.ID)
+	assert.True(t, IsConflict(err))
+	_, err = registrations.Approve(999, ApproveRegistrationRequest{})
+	assert.True(t, IsNotFound(err))
+}
+
+func TestRegistrationService_Reject(t *testing.T) {
+	registrations, _ := setupRegistration(t, nil)
+
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("laptop", "192.168.1.50", "aa:bb:cc:dd:ee:01")})
+	pending := pendingRegistrations(t, registrations)
+	require.Len(t, pending, 1)
+
+	rejected, err := registrations.Reject(pending[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RegistrationStatusRejected, rejected.Status)
+	assert.NotNil(t, rejected.DecidedAt)
+
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: discovered("laptop", "192.168.1.51", "aa:bb:cc:dd:ee:01")})
+	assert.Empty(t, pendingRegistrations(t, registrations), "rejected nodes are not queued again")
+
+	// Forgetting the registration queues the node the next time it is seen
+	require.NoError(t, registrations.Delete(rejected.ID))
+	assert.True(t, IsNotFound(registrations.Delete(rejected.ID)))
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: discovered("laptop", "192.168.1.51", "aa:bb:cc:dd:ee:01")})
+	assert.Len(t, pendingRegistrations(t, registrations), 1)
+}
+
+func TestRegistrationService_AutoApprove(t *testing.T) {
+	policy, err := NewRegistrationPolicy(ApprovalManual, []string{"dc:a6:32"}, []string{"192.168.9.0/24"})
+	require.NoError(t, err)
+	registrations, nodes := setupRegistration(t, policy)
+
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("raspberrypi", "192.168.1.10", "dc:a6:32:00:00:01")})
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("raspberrypi", "192.168.1.11", "dc:a6:32:00:00:02")})
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("raspberrypi", "192.168.9.12", "dc:a6:32:00:00:03")})
+
+	list, total, err := nodes.List(NodeListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total, "the denied network is not registered")
+	names := []string{list[0].Name, list[1].Name}
+	assert.ElementsMatch(t, []string{"raspberrypi", "raspberrypi-000002"}, names, "a taken hostname gets the end of the MAC address")
+	assert.Empty(t, pendingRegistrations(t, registrations))
+
+	// A node that cannot be registered stays queued with the reason
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovered("pi-clone", "192.168.1.10", "dc:a6:32:00:00:04")})
+	pending := pendingRegistrations(t, registrations)
+	require.Len(t, pending, 1)
+	assert.Contains(t, pending[0].Message, "192.168.1.10 is used by node raspberrypi")
+}
+
+func TestRegistrationService_TracksInventory(t *testing.T) {
+	registrations, nodes := setupRegistration(t, nil)
+
+	worker, err := nodes.Create(CreateNodeRequest{
+		Name:         "worker",
+		IPAddress:    "192.168.1.10",
+		MACAddress:   "DC:A6:32:12:34:56",
+		Role:         models.NodeRoleWorker,
+		SerialNumber: "10000000abcdef01",
+		CPUCores:     4,
+		Memory:       4096,
+	})
+	require.NoError(t, err)
+
+	// Matched by MAC address regardless of case, and moved to its new address
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: discovered("worker", "192.168.1.30", "dc:a6:32:12:34:56")})
+	node, err := nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.30", node.IPAddress)
+	assert.Empty(t, pendingRegistrations(t, registrations), "known nodes are not queued")
+
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeLost, Node: discovered("worker", "192.168.1.30", "dc:a6:32:12:34:56")})
+	node, err = nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusUnknown, node.Status)
+
+	// Matched by serial number when the MAC address is not advertised
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeDiscovered, Node: discovery.Node{IPAddress: "192.168.1.30", SerialNumber: "10000000abcdef01"}})
+	node, err = nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusDiscovered, node.Status)
+
+	ready := models.NodeStatusReady
+	_, err = nodes.Update(worker.ID, UpdateNodeRequest{Status: &ready})
+	require.NoError(t, err)
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeLost, Node: discovery.Node{SerialNumber: "10000000abcdef01"}})
+	node, err = nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.NodeStatusNotReady, node.Status)
+}
+
+func TestRegistrationService_PhoneHomeCannotMoveNodes(t *testing.T) {
+	registrations, nodes := setupRegistration(t, nil)
+
+	worker, err := nodes.Create(CreateNodeRequest{
+		Name:       "worker",
+		IPAddress:  "192.168.1.10",
+		MACAddress: "dc:a6:32:12:34:56",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     4096,
+	})
+	require.NoError(t, err)
+
+	// Anyone can post a node's MAC address to the phone-home endpoint
+	forged := discovery.Node{
+		ID:           "phone-home-dc:a6:32:12:34:56",
+		IPAddress:    "10.6.6.6",
+		ServiceType:  discovery.MethodPhoneHome,
+		MACAddress:   "dc:a6:32:12:34:56",
+		SerialNumber: "10000000deadbeef",
+	}
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: forged})
+	node, err := nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.10", node.IPAddress)
+	assert.Empty(t, node.SerialNumber)
+	assert.Empty(t, pendingRegistrations(t, registrations))
+
+	// Other discovery methods still track a node's address
+	registrations.HandleEvent(discovery.NodeEvent{Type: discovery.NodeUpdated, Node: discovered("worker", "192.168.1.30", "dc:a6:32:12:34:56")})
+	node, err = nodes.GetByID(worker.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.30", node.IPAddress)
+}
@@ -0,0 +1,464 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	"github.com/dsyorkd/pi-controller/pkg/k8s"
+)
+
+// Upgrade step names, recorded with each node's upgrade job
+const (
+	upgradeStepCordon    = "cordon"
+	upgradeStepDrain     = "drain"
+	upgradeStepInstall   = "upgrade-k3s"
+	upgradeStepWaitReady = "wait-ready"
+	upgradeStepUncordon  = "uncordon"
+)
+
+// Defaults for verifying an upgraded node
+const (
+	defaultUpgradeReadyTimeout = 10 * time.Minute
+	defaultUpgradePollInterval = 5 * time.Second
+)
+
+// k3sVersionPattern matches k3s release versions such as v1.30.2+k3s1
+var k3sVersionPattern = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+\+k3s[0-9]+$`)
+
+// NodeUpgrader installs a Kubernetes version on a single node
+type NodeUpgrader interface {
+	// UpgradeNode installs a version on a drained node and restarts
+	// Kubernetes on it, publishing its output as the named step of the node's
+	// upgrade job. The job is nil if it could not be recorded. Excerpts of
+	// the output are returned.
+	UpgradeNode(ctx context.Context, job *models.ProvisioningJob, step string, node *models.Node, version string) (stdout, stderr string, err error)
+}
+
+// UpgradeService upgrades the Kubernetes version of a cluster's nodes one
+// batch at a time. Masters are upgraded one at a time before any worker, and
+// workers in batches of the requested size. Each node is cordoned, drained,
+// upgraded, verified to be ready at the new version and uncordoned before the
+// next batch starts. An upgrade halts at the first node that fails, leaving
+// that node cordoned.
+type UpgradeService struct {
+	db       *storage.Database
+	kube     KubeClientProvider
+	logger   logger.Interface
+	upgrader NodeUpgrader
+	jobs     *JobService
+
+	readyTimeout time.Duration
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	ctx     context.Context
+	running map[uint]bool // clusters with an upgrade running
+	wg      sync.WaitGroup
+}
+
+// NewUpgradeService creates a new upgrade service
+func NewUpgradeService(db *storage.Database, kube KubeClientProvider, logger logger.Interface) *UpgradeService {
+	return &UpgradeService{
+		db:           db,
+		kube:         kube,
+		logger:       logger.WithField("service", "upgrade"),
+		readyTimeout: defaultUpgradeReadyTimeout,
+		pollInterval: defaultUpgradePollInterval,
+		running:      make(map[uint]bool),
+	}
+}
+
+// SetNodeUpgrader sets what installs the new version on each node. Without
+// one, upgrades cannot be started.
+func (s *UpgradeService) SetNodeUpgrader(upgrader NodeUpgrader) {
+	s.upgrader = upgrader
+}
+
+// SetJobService sets the service each node's upgrade is recorded with
+func (s *UpgradeService) SetJobService(jobs *JobService) {
+	s.jobs = jobs
+}
+
+// UpgradeRequest is the request to upgrade a cluster
+type UpgradeRequest struct {
+	// Version is the k3s release to install, such as v1.30.2+k3s1
+	Version string `json:"version"`
+	// BatchSize is the number of workers upgraded at once. Zero upgrades one
+	// at a time.
+	BatchSize int `json:"batch_size"`
+	// DrainTimeout bounds evicting each node's pods. Zero uses
+	// k8s.DefaultDrainTimeout.
+	DrainTimeout time.Duration `json:"-"`
+}
+
+// Start marks upgrades a previous controller process left running as
+// interrupted. Upgrades stop when ctx is cancelled; starting the same upgrade
+// again resumes it, skipping nodes already at the new version.
+func (s *UpgradeService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	result := s.db.DB().Model(&models.ClusterUpgrade{}).
+		Where("status = ?", models.UpgradeStatusRunning).
+		Updates(map[string]interface{}{
+			"status":        models.UpgradeStatusInterrupted,
+			"message":       "interrupted by a controller restart",
+			"current_nodes": "",
+			"finished_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "failed to mark interrupted upgrades")
+	}
+	if result.RowsAffected > 0 {
+		s.logger.WithField("count", result.RowsAffected).Warn("Marked interrupted cluster upgrades")
+	}
+	return nil
+}
+
+// Wait blocks until running upgrades have stopped
+func (s *UpgradeService) Wait() {
+	s.wg.Wait()
+}
+
+// Latest returns a cluster's most recent upgrade, or nil if it has never been
+// upgraded
+func (s *UpgradeService) Latest(clusterID uint) (*models.ClusterUpgrade, error) {
+	return latestUpgrade(s.db, clusterID)
+}
+
+func latestUpgrade(db *storage.Database, clusterID uint) (*models.ClusterUpgrade, error) {
+	var upgrade models.ClusterUpgrade
+	err := db.DB().Where("cluster_id = ?", clusterID).Order("id DESC").First(&upgrade).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get latest upgrade")
+	}
+	return &upgrade, nil
+}
+
+// Upgrade starts a rolling upgrade of a cluster in the background and returns
+// it while it runs
+func (s *UpgradeService) Upgrade(clusterID uint, req UpgradeRequest) (*models.ClusterUpgrade, error) {
+	if !k3sVersionPattern.MatchString(req.Version) {
+		return nil, errors.Wrapf(ErrValidationFailed, "version must be a k3s release such as v1.30.2+k3s1, got %q", req.Version)
+	}
+	if req.BatchSize < 0 {
+		return nil, errors.Wrapf(ErrValidationFailed, "batch_size must not be negative")
+	}
+	if req.BatchSize == 0 {
+		req.BatchSize = 1
+	}
+	if req.DrainTimeout <= 0 {
+		req.DrainTimeout = k8s.DefaultDrainTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx == nil {
+		return nil, fmt.Errorf("upgrade service is not started")
+	}
+	if s.upgrader == nil {
+		return nil, errors.Wrapf(ErrConflict, "node upgrades over SSH are not configured")
+	}
+	if s.running[clusterID] {
+		return nil, errors.Wrapf(ErrConflict, "an upgrade of cluster %d is already running", clusterID)
+	}
+
+	var cluster models.Cluster
+	if err := s.db.DB().First(&cluster, clusterID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrapf(ErrNotFound, "cluster %d not found", clusterID)
+		}
+		return nil, errors.Wrapf(err, "failed to get cluster")
+	}
+	client, err := s.kube.ClientFor(&cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	masters, workers, err := s.pending(&cluster, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := &models.ClusterUpgrade{
+		ClusterID:   clusterID,
+		FromVersion: cluster.Version,
+		ToVersion:   req.Version,
+		BatchSize:   req.BatchSize,
+		Status:      models.UpgradeStatusRunning,
+		NodesTotal:  len(masters) + len(workers),
+		StartedAt:   time.Now(),
+	}
+	if err := s.db.DB().Create(upgrade).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to record upgrade")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"upgrade_id": upgrade.ID,
+		"cluster_id": clusterID,
+		"from":       cluster.Version,
+		"to":         req.Version,
+		"nodes":      upgrade.NodesTotal,
+		"batch_size": req.BatchSize,
+	}).Info("Cluster upgrade started")
+
+	started := *upgrade
+	s.running[clusterID] = true
+	s.wg.Add(1)
+	go s.run(s.ctx, upgrade, &cluster, client, upgradeBatches(masters, workers, req.BatchSize), req)
+	return &started, nil
+}
+
+// pending returns the cluster's joined masters and workers that are not yet
+// at the version. Every joined node must be ready before an upgrade starts.
+func (s *UpgradeService) pending(cluster *models.Cluster, version string) ([]models.Node, []models.Node, error) {
+	var nodes []models.Node
+	err := s.db.DB().Where("cluster_id = ? AND node_name <> ''", cluster.ID).Order("id").Find(&nodes).Error
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to list cluster nodes")
+	}
+	if len(nodes) == 0 {
+		return nil, nil, errors.Wrapf(ErrConflict, "cluster %s has no nodes joined to Kubernetes", cluster.Name)
+	}
+
+	var masters, workers []models.Node
+	for _, node := range nodes {
+		if node.Status != models.NodeStatusReady {
+			return nil, nil, errors.Wrapf(ErrConflict, "node %s is %s, every node must be ready to upgrade", node.Name, node.Status)
+		}
+		if node.KubeVersion == version {
+			continue
+		}
+		if node.IsMaster() {
+			masters = append(masters, node)
+		} else {
+			workers = append(workers, node)
+		}
+	}
+	return masters, workers, nil
+}
+
+// upgradeBatch is a set of nodes upgraded at the same time
+type upgradeBatch struct {
+	phase models.UpgradePhase
+	nodes []models.Node
+}
+
+// upgradeBatches orders masters one per batch, then workers batchSize at a time
+func upgradeBatches(masters, workers []models.Node, batchSize int) []upgradeBatch {
+	var batches []upgradeBatch
+	for i := range masters {
+		batches = append(batches, upgradeBatch{models.UpgradePhaseMasters, masters[i : i+1]})
+	}
+	for start := 0; start < len(workers); start += batchSize {
+		end := min(start+batchSize, len(workers))
+		batches = append(batches, upgradeBatch{models.UpgradePhaseWorkers, workers[start:end]})
+	}
+	return batches
+}
+
+// run upgrades each batch in turn, halting at the first that fails. The
+// cluster is in maintenance while its nodes are upgraded.
+func (s *UpgradeService) run(ctx context.Context, upgrade *models.ClusterUpgrade, cluster *models.Cluster, client k8s.K8sClient, batches []upgradeBatch, req UpgradeRequest) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, cluster.ID)
+		s.mu.Unlock()
+	}()
+
+	log := s.logger.WithFields(map[string]interface{}{
+		"upgrade_id": upgrade.ID,
+		"cluster_id": cluster.ID,
+	})
+
+	previousStatus := cluster.Status
+	s.setClusterStatus(cluster.ID, models.ClusterStatusMaintenance, log)
+	defer s.setClusterStatus(cluster.ID, previousStatus, log)
+
+	for _, batch := range batches {
+		names := make([]string, len(batch.nodes))
+		for i, node := range batch.nodes {
+			names[i] = node.Name
+		}
+		s.update(upgrade, map[string]interface{}{
+			"phase":         batch.phase,
+			"current_nodes": strings.Join(names, ","),
+		}, log)
+		log.WithFields(map[string]interface{}{
+			"phase": batch.phase,
+			"nodes": names,
+		}).Info("Upgrading batch")
+
+		errs := make([]error, len(batch.nodes))
+		var wg sync.WaitGroup
+		for i := range batch.nodes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = s.upgradeNode(ctx, client, &batch.nodes[i], req)
+			}(i)
+		}
+		wg.Wait()
+
+		var failures []string
+		for i, err := range errs {
+			if err == nil {
+				upgrade.NodesUpgraded++
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("node %s: %v", names[i], err))
+		}
+		s.update(upgrade, map[string]interface{}{"nodes_upgraded": upgrade.NodesUpgraded}, log)
+
+		if ctx.Err() != nil {
+			s.finish(upgrade, models.UpgradeStatusInterrupted, "interrupted by controller shutdown", log)
+			return
+		}
+		if len(failures) > 0 {
+			s.finish(upgrade, models.UpgradeStatusFailed, strings.Join(failures, "; "), log)
+			return
+		}
+	}
+
+	if err := s.db.DB().Model(&models.Cluster{}).Where("id = ?", cluster.ID).Update("version", upgrade.ToVersion).Error; err != nil {
+		log.WithError(err).Error("Failed to record cluster version")
+	}
+	s.finish(upgrade, models.UpgradeStatusSucceeded, "", log)
+}
+
+// upgradeNode cordons and drains a node, installs the new version, waits for
+// it to report ready at that version and uncordons it. A node that fails is
+// left cordoned.
+func (s *UpgradeService) upgradeNode(ctx context.Context, client k8s.K8sClient, node *models.Node, req UpgradeRequest) error {
+	names := []string{upgradeStepCordon, upgradeStepDrain, upgradeStepInstall, upgradeStepWaitReady, upgradeStepUncordon}
+	job := s.jobs.start(node, models.JobTypeUpgrade, names, s.logger)
+
+	steps := []struct {
+		name    string
+		timeout time.Duration
+		run     func(ctx context.Context) error
+	}{
+		{upgradeStepCordon, kubeRequestTimeout, func(ctx context.Context) error {
+			return client.CordonNode(ctx, node.NodeName)
+		}},
+		{upgradeStepDrain, req.DrainTimeout + kubeRequestTimeout, func(ctx context.Context) error {
+			return client.DrainNode(ctx, node.NodeName, k8s.DrainOptions{Timeout: req.DrainTimeout})
+		}},
+		{upgradeStepInstall, 0, nil}, // output is recorded by the upgrader
+		{upgradeStepWaitReady, s.readyTimeout, func(ctx context.Context) error {
+			return s.waitReady(ctx, client, node, req.Version)
+		}},
+		{upgradeStepUncordon, kubeRequestTimeout, func(ctx context.Context) error {
+			return client.UncordonNode(ctx, node.NodeName)
+		}},
+	}
+
+	for i, st := range steps {
+		job.start(st.name)
+
+		var err error
+		if st.name == upgradeStepInstall {
+			var stdout, stderr string
+			stdout, stderr, err = s.upgrader.UpgradeNode(ctx, job.recorded(), st.name, node, req.Version)
+			job.finishWithOutput(st.name, err, stdout, stderr)
+		} else {
+			stepCtx, cancel := context.WithTimeout(ctx, st.timeout)
+			err = st.run(stepCtx)
+			cancel()
+			job.finish(st.name, err)
+		}
+
+		if err != nil {
+			for _, rest := range steps[i+1:] {
+				job.skip(rest.name)
+			}
+			status := models.JobStatusFailed
+			if ctx.Err() != nil {
+				status = models.JobStatusInterrupted
+			}
+			job.done(status, fmt.Sprintf("%s: %v", st.name, err))
+			return fmt.Errorf("%s: %w", st.name, err)
+		}
+	}
+
+	job.done(models.JobStatusSucceeded, "")
+	s.logger.WithFields(map[string]interface{}{
+		"node_id":   node.ID,
+		"node_name": node.NodeName,
+		"version":   req.Version,
+	}).Info("Node upgraded")
+	return nil
+}
+
+// waitReady polls the node until it reports ready at the version, then
+// records the version
+func (s *UpgradeService) waitReady(ctx context.Context, client k8s.K8sClient, node *models.Node, version string) error {
+	var last string
+	for {
+		info, err := client.GetNode(ctx, node.NodeName)
+		switch {
+		case err != nil:
+			last = err.Error()
+		case info.Ready && info.Version == version:
+			return s.db.DB().Model(node).Update("kube_version", version).Error
+		default:
+			last = fmt.Sprintf("ready=%t version=%s", info.Ready, info.Version)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node did not become ready at %s within %s (last seen %s)", version, s.readyTimeout, last)
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// update records the progress of an upgrade
+func (s *UpgradeService) update(upgrade *models.ClusterUpgrade, updates map[string]interface{}, log logger.Interface) {
+	if err := s.db.DB().Model(upgrade).Updates(updates).Error; err != nil {
+		log.WithError(err).Error("Failed to record upgrade progress")
+	}
+}
+
+// finish records the outcome of an upgrade
+func (s *UpgradeService) finish(upgrade *models.ClusterUpgrade, status models.UpgradeStatus, message string, log logger.Interface) {
+	s.update(upgrade, map[string]interface{}{
+		"status":        status,
+		"message":       message,
+		"current_nodes": "",
+		"finished_at":   time.Now(),
+	}, log)
+
+	log = log.WithFields(map[string]interface{}{
+		"status":         status,
+		"nodes_upgraded": upgrade.NodesUpgraded,
+		"nodes_total":    upgrade.NodesTotal,
+	})
+	if status == models.UpgradeStatusSucceeded {
+		log.Info("Cluster upgrade succeeded")
+	} else {
+		log.WithField("reason", message).Error("Cluster upgrade halted")
+	}
+}
+
+func (s *UpgradeService) setClusterStatus(clusterID uint, status models.ClusterStatus, log logger.Interface) {
+	if err := s.db.DB().Model(&models.Cluster{}).Where("id = ?", clusterID).Update("status", status).Error; err != nil {
+		log.WithError(err).Error("Failed to update cluster status")
+	}
+}
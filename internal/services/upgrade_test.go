@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+)
+
+const (
+	upgradeFromVersion = "v1.30.2+k3s1"
+	upgradeToVersion   = "v1.30.4+k3s1"
+)
+
+// fakeUpgrader bumps the kubelet version of upgraded nodes in a fake
+// clientset and records the order nodes were upgraded in
+type fakeUpgrader struct {
+	clientset *fake.Clientset
+
+	mu       sync.Mutex
+	upgraded []string
+	fail     map[string]bool // node names whose upgrade fails
+}
+
+func (u *fakeUpgrader) UpgradeNode(ctx context.Context, job *models.ProvisioningJob, step string, node *models.Node, version string) (string, string, error) {
+	u.mu.Lock()
+	u.upgraded = append(u.upgraded, node.NodeName)
+	fail := u.fail[node.NodeName]
+	u.mu.Unlock()
+
+	if fail {
+		return "", "curl: (22) The requested URL returned error: 404\n", fmt.Errorf("command exited with status 22")
+	}
+
+	kubeNode, err := u.clientset.CoreV1().Nodes().Get(ctx, node.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	kubeNode.Status.NodeInfo.KubeletVersion = version
+	_, err = u.clientset.CoreV1().Nodes().Update(ctx, kubeNode, metav1.UpdateOptions{})
+	return "k3s upgraded\n", "", err
+}
+
+func (u *fakeUpgrader) order() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]string(nil), u.upgraded...)
+}
+
+type upgradeEnv struct {
+	db        *storage.Database
+	upgrades  *UpgradeService
+	clusters  *ClusterService
+	jobs      *JobService
+	upgrader  *fakeUpgrader
+	clientset *fake.Clientset
+	cluster   *models.Cluster
+	nodes     map[string]*models.Node // by node name
+}
+
+// setupUpgrade creates a cluster with a master and three workers joined and
+// ready at upgradeFromVersion
+func setupUpgrade(t *testing.T) *upgradeEnv {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	clusters := NewClusterService(db, logger.Default())
+	cluster, err := clusters.Create(CreateClusterRequest{Name: "home", Version: upgradeFromVersion})
+	require.NoError(t, err)
+
+	jobs := NewJobService(db, logger.Default())
+	nodeService := NewNodeService(db, logger.Default())
+	clientset := fake.NewSimpleClientset()
+	nodes := make(map[string]*models.Node)
+
+	specs := []struct {
+		name string
+		role models.NodeRole
+	}{
+		{"pi-worker-1", models.NodeRoleWorker},
+		{"pi-master", models.NodeRoleMaster},
+		{"pi-worker-2", models.NodeRoleWorker},
+		{"pi-worker-3", models.NodeRoleWorker},
+	}
+	for i, spec := range specs {
+		node, err := nodeService.Create(CreateNodeRequest{
+			Name:       spec.name,
+			IPAddress:  fmt.Sprintf("192.168.1.%d", 10+i),
+			MACAddress: fmt.Sprintf("aa:bb:cc:dd:ee:%02x", i),
+			Role:       spec.role,
+			ClusterID:  &cluster.ID,
+			CPUCores:   4,
+			Memory:     4096,
+		})
+		require.NoError(t, err)
+		nodeName, ready, version := spec.name, models.NodeStatusReady, upgradeFromVersion
+		node, err = nodeService.Update(node.ID, UpdateNodeRequest{NodeName: &nodeName, Status: &ready, KubeVersion: &version})
+		require.NoError(t, err)
+		nodes[spec.name] = node
+
+		_, err = clientset.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: spec.name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: upgradeFromVersion},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	upgrader := &fakeUpgrader{clientset: clientset, fail: make(map[string]bool)}
+	upgrades := NewUpgradeService(db, &fakeKubeClients{clientset: clientset}, logger.Default())
+	upgrades.SetNodeUpgrader(upgrader)
+	upgrades.SetJobService(jobs)
+	upgrades.readyTimeout = 2 * time.Second
+	upgrades.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		upgrades.Wait()
+	})
+	require.NoError(t, upgrades.Start(ctx))
+
+	return &upgradeEnv{
+		db:        db,
+		upgrades:  upgrades,
+		clusters:  clusters,
+		jobs:      jobs,
+		upgrader:  upgrader,
+		clientset: clientset,
+		cluster:   cluster,
+		nodes:     nodes,
+	}
+}
+
+func (env *upgradeEnv) unschedulable(t *testing.T, nodeName string) bool {
+	node, err := env.clientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return node.Spec.Unschedulable
+}
+
+// setReady sets the Ready condition of a Kubernetes node
+func (env *upgradeEnv) setReady(t *testing.T, nodeName string, ready bool) {
+	node, err := env.clientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Status.Conditions[0].Status = corev1.ConditionFalse
+	if ready {
+		node.Status.Conditions[0].Status = corev1.ConditionTrue
+	}
+	_, err = env.clientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestUpgradeService_Upgrade(t *testing.T) {
+	t.Run("upgrades masters first then workers in batches", func(t *testing.T) {
+		env := setupUpgrade(t)
+
+		upgrade, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion, BatchSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, models.UpgradeStatusRunning, upgrade.Status)
+		assert.Equal(t, upgradeFromVersion, upgrade.FromVersion)
+		assert.Equal(t, 4, upgrade.NodesTotal)
+		env.upgrades.Wait()
+
+		order := env.upgrader.order()
+		require.Len(t, order, 4)
+		assert.Equal(t, "pi-master", order[0])
+		assert.ElementsMatch(t, []string{"pi-worker-1", "pi-worker-2"}, order[1:3], "the first batch of workers")
+		assert.Equal(t, "pi-worker-3", order[3])
+
+		report, err := env.clusters.StatusReport(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, upgradeToVersion, report.Version)
+		assert.Equal(t, models.ClusterStatusActive, report.Status, "the cluster's status is restored")
+		require.NotNil(t, report.Upgrade)
+		assert.Equal(t, upgrade.ID, report.Upgrade.ID)
+		assert.Equal(t, models.UpgradeStatusSucceeded, report.Upgrade.Status)
+		assert.Equal(t, models.UpgradePhaseWorkers, report.Upgrade.Phase)
+		assert.Equal(t, 4, report.Upgrade.NodesUpgraded)
+		assert.Empty(t, report.Upgrade.CurrentNodes)
+		assert.NotNil(t, report.Upgrade.FinishedAt)
+
+		for name, node := range env.nodes {
+			assert.False(t, env.unschedulable(t, name), "%s is uncordoned", name)
+
+			var stored models.Node
+			require.NoError(t, env.db.DB().First(&stored, node.ID).Error)
+			assert.Equal(t, upgradeToVersion, stored.KubeVersion)
+
+			job, err := env.jobs.Latest(node.ID, models.JobTypeUpgrade)
+			require.NoError(t, err)
+			assert.Equal(t, models.JobStatusSucceeded, job.Status)
+			require.Len(t, job.Steps, 5)
+			for _, step := range job.Steps {
+				assert.Equal(t, models.StepStatusSucceeded, step.Status, "%s step %s", name, step.Name)
+			}
+			assert.Equal(t, upgradeStepInstall, job.Steps[2].Name)
+			assert.Contains(t, job.Steps[2].Stdout, "k3s upgraded")
+		}
+	})
+
+	t.Run("halts at a failed node and leaves it cordoned", func(t *testing.T) {
+		env := setupUpgrade(t)
+		env.upgrader.fail["pi-worker-1"] = true
+
+		_, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		require.NoError(t, err)
+		env.upgrades.Wait()
+
+		assert.Equal(t, []string{"pi-master", "pi-worker-1"}, env.upgrader.order(), "later batches are not started")
+		assert.True(t, env.unschedulable(t, "pi-worker-1"))
+		assert.False(t, env.unschedulable(t, "pi-worker-2"))
+
+		upgrade, err := env.upgrades.Latest(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UpgradeStatusFailed, upgrade.Status)
+		assert.Equal(t, 1, upgrade.NodesUpgraded)
+		assert.Contains(t, upgrade.Message, "node pi-worker-1: upgrade-k3s")
+
+		job, err := env.jobs.Latest(env.nodes["pi-worker-1"].ID, models.JobTypeUpgrade)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusFailed, job.Status)
+		assert.Equal(t, models.StepStatusFailed, job.Steps[2].Status)
+		assert.Contains(t, job.Steps[2].Stderr, "404")
+		assert.Equal(t, models.StepStatusSkipped, job.Steps[3].Status)
+		assert.Equal(t, models.StepStatusSkipped, job.Steps[4].Status)
+
+		cluster, err := env.clusters.GetByID(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, upgradeFromVersion, cluster.Version, "the cluster version is unchanged")
+
+		// Upgrading again resumes with the nodes not yet upgraded
+		delete(env.upgrader.fail, "pi-worker-1")
+		resumed, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		require.NoError(t, err)
+		assert.Equal(t, 3, resumed.NodesTotal)
+		env.upgrades.Wait()
+
+		resumed, err = env.upgrades.Latest(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UpgradeStatusSucceeded, resumed.Status)
+		assert.False(t, env.unschedulable(t, "pi-worker-1"))
+	})
+
+	t.Run("fails a node that does not become ready at the version", func(t *testing.T) {
+		env := setupUpgrade(t)
+		env.upgrades.readyTimeout = 100 * time.Millisecond
+
+		env.setReady(t, "pi-master", false)
+
+		_, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		require.NoError(t, err)
+		env.upgrades.Wait()
+
+		assert.Equal(t, []string{"pi-master"}, env.upgrader.order())
+		upgrade, err := env.upgrades.Latest(env.cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UpgradeStatusFailed, upgrade.Status)
+		assert.Equal(t, models.UpgradePhaseMasters, upgrade.Phase)
+		assert.Contains(t, upgrade.Message, "wait-ready")
+		assert.True(t, env.unschedulable(t, "pi-master"))
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		env := setupUpgrade(t)
+
+		_, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: "1.30"})
+		assert.True(t, IsValidationFailed(err))
+		_, err = env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion, BatchSize: -1})
+		assert.True(t, IsValidationFailed(err))
+		_, err = env.upgrades.Upgrade(999, UpgradeRequest{Version: upgradeToVersion})
+		assert.True(t, IsNotFound(err))
+
+		notReady := models.NodeStatusNotReady
+		_, err = NewNodeService(env.db, logger.Default()).Update(env.nodes["pi-worker-2"].ID, UpdateNodeRequest{Status: &notReady})
+		require.NoError(t, err)
+		_, err = env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		assert.True(t, IsConflict(err), "every node must be ready")
+		assert.Empty(t, env.upgrader.order())
+	})
+
+	t.Run("rejects a second upgrade while one runs", func(t *testing.T) {
+		env := setupUpgrade(t)
+		env.upgrades.readyTimeout = 200 * time.Millisecond
+
+		// Hold the master short of ready so the first upgrade keeps running
+		env.setReady(t, "pi-master", false)
+
+		_, err := env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		require.NoError(t, err)
+		_, err = env.upgrades.Upgrade(env.cluster.ID, UpgradeRequest{Version: upgradeToVersion})
+		assert.True(t, IsConflict(err))
+		env.upgrades.Wait()
+	})
+}
+
+func TestUpgradeService_StartMarksInterrupted(t *testing.T) {
+	env := setupUpgrade(t)
+	stale := &models.ClusterUpgrade{ClusterID: env.cluster.ID, ToVersion: upgradeToVersion, Status: models.UpgradeStatusRunning, StartedAt: time.Now()}
+	require.NoError(t, env.db.DB().Create(stale).Error)
+
+	require.NoError(t, env.upgrades.Start(context.Background()))
+
+	upgrade, err := env.upgrades.Latest(env.cluster.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UpgradeStatusInterrupted, upgrade.Status)
+	assert.NotNil(t, upgrade.FinishedAt)
+}
+
+func TestUpgradeBatches(t *testing.T) {
+	nodes := func(names ...string) []models.Node {
+		out := make([]models.Node, len(names))
+		for i, name := range names {
+			out[i].Name = name
+		}
+		return out
+	}
+
+	batches := upgradeBatches(nodes("m1", "m2"), nodes("w1", "w2", "w3", "w4", "w5"), 2)
+	var got [][]string
+	for _, batch := range batches {
+		var names []string
+		for _, node := range batch.nodes {
+			names = append(names, string(batch.phase)+":"+node.Name)
+		}
+		got = append(got, names)
+	}
+	assert.Equal(t, [][]string{
+		{"masters:m1"},
+		{"masters:m2"},
+		{"workers:w1", "workers:w2"},
+		{"workers:w3", "workers:w4"},
+		{"workers:w5"},
+	}, got)
+}
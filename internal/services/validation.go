@@ -0,0 +1,195 @@
+package services
+
+import (
+	"net"
+
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/models"
+)
+
+const (
+	maxNameLength        = 100
+	maxDescriptionLength = 500
+
+	// Highest BCM GPIO number exposed on the Raspberry Pi header
+	maxGPIOPin = 40
+
+	// Longest debounce window the agent accepts for edge detection
+	maxDebounceMS = 10000
+)
+
+// validateCreateNodeRequest checks a node creation request before it reaches the database
+func validateCreateNodeRequest(req CreateNodeRequest) error {
+	if err := validateName(req.Name); err != nil {
+		return err
+	}
+	if net.ParseIP(req.IPAddress) == nil {
+		return errors.Wrapf(ErrValidationFailed, "invalid IP address %q", req.IPAddress)
+	}
+	if _, err := net.ParseMAC(req.MACAddress); err != nil {
+		return errors.Wrapf(ErrValidationFailed, "invalid MAC address %q", req.MACAddress)
+	}
+	if !validNodeRole(req.Role) {
+		return errors.Wrapf(ErrValidationFailed, "invalid role %q", req.Role)
+	}
+	if req.CPUCores < 0 || req.Memory < 0 {
+		return errors.Wrapf(ErrValidationFailed, "cpu_cores and memory must not be negative")
+	}
+	return nil
+}
+
+// validateUpdateNodeRequest checks the fields set on a node update request
+func validateUpdateNodeRequest(req UpdateNodeRequest) error {
+	if req.Name != nil {
+		if err := validateName(*req.Name); err != nil {
+			return err
+		}
+	}
+	if req.IPAddress != nil && net.ParseIP(*req.IPAddress) == nil {
+		return errors.Wrapf(ErrValidationFailed, "invalid IP address %q", *req.IPAddress)
+	}
+	if req.MACAddress != nil {
+		if _, err := net.ParseMAC(*req.MACAddress); err != nil {
+			return errors.Wrapf(ErrValidationFailed, "invalid MAC address %q", *req.MACAddress)
+		}
+	}
+	if req.Role != nil && !validNodeRole(*req.Role) {
+		return errors.Wrapf(ErrValidationFailed, "invalid role %q", *req.Role)
+	}
+	if req.Status != nil && !validNodeStatus(*req.Status) {
+		return errors.Wrapf(ErrValidationFailed, "invalid status %q", *req.Status)
+	}
+	if (req.CPUCores != nil && *req.CPUCores < 1) || (req.Memory != nil && *req.Memory < 1) {
+		return errors.Wrapf(ErrValidationFailed, "cpu_cores and memory must be at least 1")
+	}
+	return nil
+}
+
+// validateCreateGPIODeviceRequest checks a GPIO device creation request before it reaches the database
+func validateCreateGPIODeviceRequest(req CreateGPIODeviceRequest) error {
+	if err := validateName(req.Name); err != nil {
+		return err
+	}
+	if len(req.Description) > maxDescriptionLength {
+		return errors.Wrapf(ErrValidationFailed, "description exceeds %d characters", maxDescriptionLength)
+	}
+	if req.NodeID == 0 {
+		return errors.Wrapf(ErrValidationFailed, "node_id is required")
+	}
+	if req.PinNumber < 0 || req.PinNumber > maxGPIOPin {
+		return errors.Wrapf(ErrValidationFailed, "pin_number must be between 0 and %d", maxGPIOPin)
+	}
+	if !validGPIODirection(req.Direction) {
+		return errors.Wrapf(ErrValidationFailed, "invalid direction %q", req.Direction)
+	}
+	if req.PullMode != "" && !validGPIOPullMode(req.PullMode) {
+		return errors.Wrapf(ErrValidationFailed, "invalid pull_mode %q", req.PullMode)
+	}
+	if req.DeviceType != "" && !validGPIODeviceType(req.DeviceType) {
+		return errors.Wrapf(ErrValidationFailed, "invalid device_type %q", req.DeviceType)
+	}
+	if req.Config.Edge != "" && req.Direction != models.GPIODirectionInput {
+		return errors.Wrapf(ErrValidationFailed, "edge detection requires an input device")
+	}
+	return validateGPIOConfig(req.Config)
+}
+
+// validateUpdateGPIODeviceRequest checks the fields set on a GPIO device update request
+func validateUpdateGPIODeviceRequest(req UpdateGPIODeviceRequest) error {
+	if req.Name != nil {
+		if err := validateName(*req.Name); err != nil {
+			return err
+		}
+	}
+	if req.Description != nil && len(*req.Description) > maxDescriptionLength {
+		return errors.Wrapf(ErrValidationFailed, "description exceeds %d characters", maxDescriptionLength)
+	}
+	if req.Direction != nil && !validGPIODirection(*req.Direction) {
+		return errors.Wrapf(ErrValidationFailed, "invalid direction %q", *req.Direction)
+	}
+	if req.PullMode != nil && !validGPIOPullMode(*req.PullMode) {
+		return errors.Wrapf(ErrValidationFailed, "invalid pull_mode %q", *req.PullMode)
+	}
+	if req.DeviceType != nil && !validGPIODeviceType(*req.DeviceType) {
+		return errors.Wrapf(ErrValidationFailed, "invalid device_type %q", *req.DeviceType)
+	}
+	if req.Status != nil && !validGPIOStatus(*req.Status) {
+		return errors.Wrapf(ErrValidationFailed, "invalid status %q", *req.Status)
+	}
+	if req.Config != nil {
+		return validateGPIOConfig(*req.Config)
+	}
+	return nil
+}
+
+// validateGPIOConfig checks the device configuration fields the controller acts on
+func validateGPIOConfig(config models.GPIOConfig) error {
+	if config.Edge != "" && !validGPIOEdge(config.Edge) {
+		return errors.Wrapf(ErrValidationFailed, "invalid edge %q", config.Edge)
+	}
+	if config.DebounceMS < 0 || config.DebounceMS > maxDebounceMS {
+		return errors.Wrapf(ErrValidationFailed, "debounce_ms must be between 0 and %d", maxDebounceMS)
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.Wrapf(ErrValidationFailed, "name is required")
+	}
+	if len(name) > maxNameLength {
+		return errors.Wrapf(ErrValidationFailed, "name exceeds %d characters", maxNameLength)
+	}
+	return nil
+}
+
+func validNodeRole(role models.NodeRole) bool {
+	return role == models.NodeRoleMaster || role == models.NodeRoleWorker
+}
+
+func validNodeStatus(status models.NodeStatus) bool {
+	switch status {
+	case models.NodeStatusDiscovered, models.NodeStatusProvisioning, models.NodeStatusReady,
+		models.NodeStatusNotReady, models.NodeStatusMaintenance, models.NodeStatusFailed,
+		models.NodeStatusUnknown:
+		return true
+	}
+	return false
+}
+
+func validGPIODirection(direction models.GPIODirection) bool {
+	return direction == models.GPIODirectionInput || direction == models.GPIODirectionOutput
+}
+
+func validGPIOPullMode(pullMode models.GPIOPullMode) bool {
+	switch pullMode {
+	case models.GPIOPullNone, models.GPIOPullUp, models.GPIOPullDown:
+		return true
+	}
+	return false
+}
+
+func validGPIODeviceType(deviceType models.GPIODeviceType) bool {
+	switch deviceType {
+	case models.GPIODeviceTypeDigital, models.GPIODeviceTypeAnalog, models.GPIODeviceTypePWM,
+		models.GPIODeviceTypeSPI, models.GPIODeviceTypeI2C:
+		return true
+	}
+	return false
+}
+
+func validGPIOEdge(edge models.GPIOEdge) bool {
+	switch edge {
+	case models.GPIOEdgeRising, models.GPIOEdgeFalling, models.GPIOEdgeBoth:
+		return true
+	}
+	return false
+}
+
+func validGPIOStatus(status models.GPIOStatus) bool {
+	switch status {
+	case models.GPIOStatusActive, models.GPIOStatusInactive, models.GPIOStatusError:
+		return true
+	}
+	return false
+}
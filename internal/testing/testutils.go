@@ -54,6 +54,14 @@ func SetupTestDBFile(t *testing.T) (*gorm.DB, func()) {
 		&models.Node{},
 		&models.GPIODevice{},
 		&models.GPIOReading{},
+		&models.ProvisioningJob{},
+		&models.ProvisioningStep{},
+		&models.ClusterBackup{},
+		&models.ClusterBackupLock{},
+		&models.ClusterUpgrade{},
+		&models.NodeRegistration{},
+		&models.BootstrapToken{},
+		&models.NodeCertificate{},
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,249 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+)
+
+// authTimeout bounds how long a connection that did not present a token on
+// upgrade may stay open before sending an auth message
+const authTimeout = 10 * time.Second
+
+// errTopicForbidden is returned when a client's role does not cover a topic
+var errTopicForbidden = errors.New("insufficient permissions for topic")
+
+// defaultTopicRoles mirror the REST API, where reading any resource requires
+// the viewer role. Topics no rule covers require admin.
+var defaultTopicRoles = map[string]string{
+	"gpio.#":    middleware.RoleViewer,
+	"node.#":    middleware.RoleViewer,
+	"cluster.#": middleware.RoleViewer,
+	"system.#":  middleware.RoleViewer,
+}
+
+// roleRank orders roles from least to most privileged
+var roleRank = map[string]int{
+	middleware.RoleViewer:   1,
+	middleware.RoleOperator: 2,
+	middleware.RoleAdmin:    3,
+}
+
+// AuthMessage carries a token, either as the first message of a connection
+// or later to refresh an expiring token
+type AuthMessage struct {
+	Token string `json:"token"`
+}
+
+// AuthenticatedMessage acknowledges an accepted token
+type AuthenticatedMessage struct {
+	UserID    string     `json:"user_id"`
+	Role      string     `json:"role"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// topicRule is the minimum role for topics matching a pattern
+type topicRule struct {
+	pattern topicPattern
+	role    string
+}
+
+// newTopicRules merges configured overrides onto the default topic roles
+func newTopicRules(overrides map[string]string, log logger.Interface) []topicRule {
+	roles := make(map[string]string, len(defaultTopicRoles)+len(overrides))
+	for pattern, role := range defaultTopicRoles {
+		roles[pattern] = role
+	}
+	for pattern, role := range overrides {
+		roles[pattern] = role
+	}
+
+	rules := make([]topicRule, 0, len(roles))
+	for raw, role := range roles {
+		pattern, err := parseTopicPattern(raw)
+		if err != nil || roleRank[role] == 0 {
+			log.WithFields(map[string]interface{}{
+				"topic": raw,
+				"role":  role,
+			}).Warn("Ignoring invalid WebSocket topic role")
+			continue
+		}
+		rules = append(rules, topicRule{pattern: pattern, role: role})
+	}
+	return rules
+}
+
+// requiredRole returns the most privileged role among the rules that could
+// match a topic the pattern subscribes to
+func (s *Server) requiredRole(pattern topicPattern) string {
+	required := ""
+	for _, rule := range s.topicRules {
+		if rule.pattern.overlaps(pattern) && roleRank[rule.role] > roleRank[required] {
+			required = rule.role
+		}
+	}
+	if required == "" {
+		return middleware.RoleAdmin
+	}
+	return required
+}
+
+// overlaps reports whether some concrete topic matches both patterns
+func (p topicPattern) overlaps(other topicPattern) bool {
+	for i := 0; ; i++ {
+		if i == len(p) || i == len(other) {
+			return len(p) == len(other)
+		}
+		if p[i] == topicMultiWildcard || other[i] == topicMultiWildcard {
+			return true
+		}
+		if p[i] != topicWildcard && other[i] != topicWildcard && p[i] != other[i] {
+			return false
+		}
+	}
+}
+
+// SetAuthManager requires connections to authenticate with tokens issued by
+// the given manager. Without one, connections are accepted unauthenticated,
+// matching the REST API with auth disabled.
+func (s *Server) SetAuthManager(authManager *middleware.AuthManager) {
+	s.authManager = authManager
+}
+
+// tokenFromRequest extracts a bearer token from the Authorization header or
+// the token query parameter, which browsers must use since they cannot set
+// headers on WebSocket requests
+func tokenFromRequest(r *http.Request) string {
+	if header := r.Header.Get(middleware.AuthorizationHeader); header != "" {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authenticate validates a token and attaches its claims to the client. A
+// refresh must be for the same user.
+func (c *Client) authenticate(token string) (*middleware.JWTClaims, error) {
+	claims, err := c.server.authManager.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if c.claims != nil && c.claims.UserID != claims.UserID {
+		return nil, fmt.Errorf("token belongs to a different user")
+	}
+
+	c.setClaims(claims)
+	return claims, nil
+}
+
+// setClaims replaces the client's identity, drops subscriptions its role no
+// longer covers and arms the disconnect for when the token expires
+func (c *Client) setClaims(claims *middleware.JWTClaims) {
+	c.claims = claims
+	c.pruneSubscriptions()
+
+	if claims.ExpiresAt == nil {
+		c.stopAuthTimer()
+		return
+	}
+	c.armAuthTimer(time.Until(claims.ExpiresAt.Time), "token expired")
+}
+
+// handleAuth processes an auth message, closing unauthenticated connections
+// whose token is rejected
+func (c *Client) handleAuth(msg Message) {
+	if c.server.authManager == nil {
+		c.sendError(400, "Authentication is not enabled")
+		return
+	}
+
+	var auth AuthMessage
+	if err := json.Unmarshal(msg.Payload, &auth); err != nil || auth.Token == "" {
+		c.sendError(400, "Invalid auth message")
+		return
+	}
+
+	claims, err := c.authenticate(auth.Token)
+	if err != nil {
+		c.server.logger.WithFields(map[string]interface{}{
+			"client_id":   c.id,
+			"remote_addr": c.conn.RemoteAddr().String(),
+		}).WithError(err).Warn("WebSocket authentication failed")
+
+		if c.claims == nil {
+			c.closeWithReason(websocket.ClosePolicyViolation, "authentication failed")
+			return
+		}
+		c.sendError(401, "Invalid or expired token")
+		return
+	}
+
+	ack := AuthenticatedMessage{UserID: claims.UserID, Role: claims.Role}
+	if claims.ExpiresAt != nil {
+		ack.ExpiresAt = &claims.ExpiresAt.Time
+	}
+	payload, _ := json.Marshal(ack)
+	c.server.sendToClient(c, Message{
+		Type:      MessageTypeAuthenticated,
+		Payload:   payload,
+		Timestamp: time.Now(),
+		RequestID: msg.RequestID,
+	})
+}
+
+// authorizeTopic checks the client's role covers every topic a pattern can
+// match and returns the role that was required
+func (c *Client) authorizeTopic(pattern topicPattern) (string, error) {
+	if c.server.authManager == nil {
+		return "", nil
+	}
+
+	required := c.server.requiredRole(pattern)
+	if c.claims == nil || roleRank[c.claims.Role] < roleRank[required] {
+		return required, fmt.Errorf("%w: requires %s role", errTopicForbidden, required)
+	}
+	return required, nil
+}
+
+// pruneSubscriptions drops subscriptions the client's current role no longer covers
+func (c *Client) pruneSubscriptions() {
+	c.subMux.Lock()
+	defer c.subMux.Unlock()
+	for topic, sub := range c.subscriptions {
+		if roleRank[c.claims.Role] < roleRank[sub.role] {
+			delete(c.subscriptions, topic)
+		}
+	}
+}
+
+// armAuthTimer closes the connection after d unless re-armed or stopped
+func (c *Client) armAuthTimer(d time.Duration, reason string) {
+	c.stopAuthTimer()
+	c.authTimer = time.AfterFunc(d, func() {
+		c.server.logger.WithFields(map[string]interface{}{
+			"client_id": c.id,
+			"reason":    reason,
+		}).Info("Closing WebSocket connection without a valid token")
+		c.closeWithReason(websocket.ClosePolicyViolation, reason)
+	})
+}
+
+func (c *Client) stopAuthTimer() {
+	if c.authTimer != nil {
+		c.authTimer.Stop()
+	}
+}
+
+// closeWithReason sends a close frame and closes the connection, which ends
+// the read pump and unregisters the client
+func (c *Client) closeWithReason(code int, reason string) {
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	c.conn.Close()
+}
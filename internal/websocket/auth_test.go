@@ -0,0 +1,175 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+)
+
+// startAuthTestServer runs a server that requires tokens from the returned manager
+func startAuthTestServer(t *testing.T, tokenExpiry time.Duration, setup ...func(*Server)) (*Server, string, *middleware.AuthManager) {
+	authManager, err := middleware.NewAuthManager(&middleware.AuthConfig{
+		JWTSecret:         []byte("websocket-test-secret-websocket-test-secret"),
+		AccessTokenExpiry: tokenExpiry,
+	}, logger.Default())
+	require.NoError(t, err)
+
+	server := New(&config.WebSocketConfig{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		TopicRoles:      map[string]string{"system.#": middleware.RoleOperator},
+	}, logger.Default(), nil)
+	server.SetAuthManager(authManager)
+	for _, fn := range setup {
+		fn(server)
+	}
+	go server.run()
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	t.Cleanup(httpServer.Close)
+
+	return server, "ws" + strings.TrimPrefix(httpServer.URL, "http"), authManager
+}
+
+func token(t *testing.T, authManager *middleware.AuthManager, userID, role string) string {
+	token, err := authManager.GenerateToken(userID, role, middleware.TokenTypeAccess)
+	require.NoError(t, err)
+	return token
+}
+
+// expectClosed reads until the server closes the connection with a policy violation
+func expectClosed(t *testing.T, conn *websocket.Conn, timeout time.Duration) {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(timeout)))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error: %v", err)
+			return
+		}
+	}
+}
+
+func TestAuth_TokenOnUpgrade(t *testing.T) {
+	_, url, authManager := startAuthTestServer(t, time.Hour)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?token=not-a-jwt", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token(t, authManager, "alice", middleware.RoleViewer))
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	readMessage(t, conn)
+	subscribe(t, conn, "gpio.node.1.device.*")
+
+	conn = dial(t, url+"?token="+token(t, authManager, "bob", middleware.RoleViewer))
+	subscribe(t, conn, "node.*.status")
+}
+
+func TestAuth_FirstMessage(t *testing.T) {
+	_, url, authManager := startAuthTestServer(t, time.Hour)
+
+	// Anything other than auth on an unauthenticated connection closes it
+	conn := dial(t, url)
+	payload, _ := json.Marshal(SubscribeMessage{Topic: "gpio"})
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeSubscribe, Payload: payload}))
+	expectClosed(t, conn, 2*time.Second)
+
+	conn = dial(t, url)
+	payload, _ = json.Marshal(AuthMessage{Token: "not-a-jwt"})
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeAuth, Payload: payload}))
+	expectClosed(t, conn, 2*time.Second)
+
+	conn = dial(t, url)
+	payload, _ = json.Marshal(AuthMessage{Token: token(t, authManager, "alice", middleware.RoleOperator)})
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeAuth, Payload: payload, RequestID: "auth"}))
+	msg := readMessage(t, conn)
+	require.Equal(t, MessageTypeAuthenticated, msg.Type)
+	assert.Equal(t, "auth", msg.RequestID)
+	var ack AuthenticatedMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &ack))
+	assert.Equal(t, "alice", ack.UserID)
+	assert.NotNil(t, ack.ExpiresAt)
+
+	subscribe(t, conn, "system.metrics")
+
+	// A refresh for another user is refused without dropping the connection
+	payload, _ = json.Marshal(AuthMessage{Token: token(t, authManager, "mallory", middleware.RoleAdmin)})
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeAuth, Payload: payload}))
+	assert.Equal(t, MessageTypeError, readMessage(t, conn).Type)
+	subscribe(t, conn, "gpio")
+}
+
+func TestAuth_TopicRoles(t *testing.T) {
+	server, url, authManager := startAuthTestServer(t, time.Hour)
+	conn := dial(t, url+"?token="+token(t, authManager, "alice", middleware.RoleViewer))
+
+	forbidden := func(topic string) {
+		payload, _ := json.Marshal(SubscribeMessage{Topic: topic})
+		require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeSubscribe, Payload: payload}))
+		msg := readMessage(t, conn)
+		require.Equal(t, MessageTypeError, msg.Type, topic)
+		var errMsg ErrorMessage
+		require.NoError(t, json.Unmarshal(msg.Payload, &errMsg))
+		assert.Equal(t, 403, errMsg.Code, topic)
+	}
+
+	// system.# is raised to operator, and patterns that could reach it or
+	// topics no rule covers are refused
+	forbidden("system.metrics")
+	forbidden("*.metrics")
+	forbidden("*.1.status")
+	forbidden("secrets.#")
+	subscribe(t, conn, "gpio.node.1.#")
+	subscribe(t, conn, "node.1.status")
+
+	server.BroadcastSystemMetrics(SystemMetricsMessage{})
+	server.BroadcastNodeStatus(NodeStatusMessage{NodeID: 1, Status: "ready"})
+	assert.Equal(t, MessageTypeNodeStatus, readMessage(t, conn).Type)
+}
+
+func TestRequiredRole(t *testing.T) {
+	server := New(&config.WebSocketConfig{TopicRoles: map[string]string{
+		"gpio.node.*.device.*.config": middleware.RoleOperator,
+		"bad..pattern":                middleware.RoleViewer,
+		"node.*.secret":               "superuser",
+	}}, logger.Default(), nil)
+
+	tests := map[string]string{
+		"gpio.node.1.device.2":        middleware.RoleViewer,
+		"gpio.node.1.device.2.config": middleware.RoleOperator,
+		"gpio.node.1.#":               middleware.RoleOperator,
+		"node.*.status":               middleware.RoleViewer,
+		"*.*.status":                  middleware.RoleViewer,
+		"node.1.secret":               middleware.RoleViewer,
+		"unknown":                     middleware.RoleAdmin,
+	}
+	for topic, want := range tests {
+		pattern, err := parseTopicPattern(topic)
+		require.NoError(t, err)
+		assert.Equal(t, want, server.requiredRole(pattern), topic)
+	}
+}
+
+func TestAuth_DisconnectOnExpiry(t *testing.T) {
+	_, url, authManager := startAuthTestServer(t, 2*time.Second)
+	conn := dial(t, url+"?token="+token(t, authManager, "alice", middleware.RoleViewer))
+	subscribe(t, conn, "gpio")
+
+	expectClosed(t, conn, 4*time.Second)
+}
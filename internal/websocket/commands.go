@@ -0,0 +1,248 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/errors"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+)
+
+// maxQueuedCommands bounds the GPIO commands a client may have waiting behind
+// the one running
+const maxQueuedCommands = 16
+
+// commandRoles are the minimum roles for each command, matching the REST
+// GPIO endpoints
+var commandRoles = map[MessageType]string{
+	MessageTypeGPIORead:  middleware.RoleViewer,
+	MessageTypeGPIOWrite: middleware.RoleOperator,
+	MessageTypePWMSet:    middleware.RoleOperator,
+}
+
+// GPIOReadCommand requests the current value of a GPIO device
+type GPIOReadCommand struct {
+	DeviceID uint `json:"device_id"`
+}
+
+// GPIOWriteCommand sets the value of an output GPIO device
+type GPIOWriteCommand struct {
+	DeviceID uint `json:"device_id"`
+	Value    *int `json:"value"`
+}
+
+// PWMSetCommand changes the frequency and duty cycle of a PWM device
+type PWMSetCommand struct {
+	DeviceID  uint `json:"device_id"`
+	Frequency int  `json:"frequency"`
+	DutyCycle *int `json:"duty_cycle"`
+}
+
+// GPIOResultMessage is the response to a GPIO command
+type GPIOResultMessage struct {
+	DeviceID  uint      `json:"device_id"`
+	NodeID    uint      `json:"node_id"`
+	Pin       int       `json:"pin"`
+	Value     int       `json:"value"`
+	Frequency int       `json:"frequency,omitempty"`
+	DutyCycle int       `json:"duty_cycle,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// queuedCommand is a command accepted by the read pump, with the claims it was
+// authorized under
+type queuedCommand struct {
+	msg    Message
+	claims *middleware.JWTClaims
+}
+
+// SetGPIOService enables GPIO commands. Without it, commands are rejected.
+func (s *Server) SetGPIOService(gpioService *services.GPIOService) {
+	s.gpioService = gpioService
+}
+
+// handleCommand authorizes a GPIO command and queues it for the client's
+// command worker, replying with an error carrying the command's request ID if
+// it is refused or too many commands are waiting
+func (c *Client) handleCommand(msg Message) {
+	if msg.RequestID == "" {
+		c.sendError(400, "Commands require a request_id")
+		return
+	}
+	if c.server.gpioService == nil {
+		c.sendCommandError(msg.RequestID, http.StatusServiceUnavailable, "GPIO commands are not available")
+		return
+	}
+
+	if c.server.authManager != nil {
+		required := commandRoles[msg.Type]
+		if roleRank[c.claims.Role] < roleRank[required] {
+			c.audit(msg, c.claims, "denied", nil, nil)
+			c.sendCommandError(msg.RequestID, http.StatusForbidden, "Requires "+required+" role")
+			return
+		}
+	}
+
+	select {
+	case c.commands <- queuedCommand{msg: msg, claims: c.claims}:
+	default:
+		c.audit(msg, c.claims, "throttled", nil, nil)
+		c.sendCommandError(msg.RequestID, http.StatusTooManyRequests, "Too many GPIO commands in progress")
+	}
+}
+
+// commandWorker runs the client's queued commands until it disconnects. They
+// run one at a time in the order they were sent, so writes to a pin are
+// applied in order, and off the read pump, so a slow agent does not stop the
+// client's other messages being handled.
+func (c *Client) commandWorker() {
+	for {
+		select {
+		case cmd := <-c.commands:
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			c.runCommand(cmd)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// runCommand executes a queued command, replying with a result or an error
+// carrying the command's request ID
+func (c *Client) runCommand(cmd queuedCommand) {
+	msg := cmd.msg
+	device, err := c.executeCommand(msg)
+	if err != nil {
+		c.audit(msg, cmd.claims, "failed", nil, err)
+		code, message := commandErrorCode(err)
+		c.sendCommandError(msg.RequestID, code, message)
+		return
+	}
+	c.audit(msg, cmd.claims, "succeeded", device, nil)
+
+	payload, _ := json.Marshal(GPIOResultMessage{
+		DeviceID:  device.ID,
+		NodeID:    device.NodeID,
+		Pin:       device.PinNumber,
+		Value:     device.Value,
+		Frequency: device.Config.Frequency,
+		DutyCycle: device.Config.DutyCycle,
+		Timestamp: device.UpdatedAt,
+	})
+	c.server.sendToClient(c, Message{
+		Type:      MessageTypeGPIOResult,
+		Payload:   payload,
+		Timestamp: time.Now(),
+		RequestID: msg.RequestID,
+	})
+}
+
+// executeCommand decodes a command and runs it through the GPIO service
+func (c *Client) executeCommand(msg Message) (*models.GPIODevice, error) {
+	gpio := c.server.gpioService
+	invalid := errors.Wrapf(services.ErrInvalidInput, "invalid %s command", msg.Type)
+
+	switch msg.Type {
+	case MessageTypeGPIORead:
+		var cmd GPIOReadCommand
+		if err := json.Unmarshal(msg.Payload, &cmd); err != nil || cmd.DeviceID == 0 {
+			return nil, invalid
+		}
+		return gpio.Read(cmd.DeviceID)
+
+	case MessageTypeGPIOWrite:
+		var cmd GPIOWriteCommand
+		if err := json.Unmarshal(msg.Payload, &cmd); err != nil || cmd.DeviceID == 0 || cmd.Value == nil {
+			return nil, invalid
+		}
+		if err := gpio.Write(cmd.DeviceID, *cmd.Value); err != nil {
+			return nil, err
+		}
+		return gpio.GetByID(cmd.DeviceID)
+
+	case MessageTypePWMSet:
+		var cmd PWMSetCommand
+		if err := json.Unmarshal(msg.Payload, &cmd); err != nil || cmd.DeviceID == 0 || cmd.DutyCycle == nil {
+			return nil, invalid
+		}
+		return gpio.SetPWM(cmd.DeviceID, cmd.Frequency, *cmd.DutyCycle)
+	}
+
+	return nil, invalid
+}
+
+// audit records a command with the same event types as the REST and gRPC GPIO
+// operations
+func (c *Client) audit(msg Message, claims *middleware.JWTClaims, outcome string, device *models.GPIODevice, err error) {
+	fields := map[string]interface{}{
+		"event_type": auditEventTypes[msg.Type],
+		"channel":    "websocket",
+		"client_id":  c.id,
+		"request_id": msg.RequestID,
+		"outcome":    outcome,
+	}
+	if claims != nil {
+		fields["user_id"] = claims.UserID
+	}
+	if device != nil {
+		fields["device_id"] = device.ID
+		fields["pin"] = device.PinNumber
+		fields["value"] = device.Value
+	} else {
+		var target GPIOReadCommand
+		if json.Unmarshal(msg.Payload, &target) == nil {
+			fields["device_id"] = target.DeviceID
+		}
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	log := c.server.logger.WithFields(fields)
+	if outcome == "succeeded" {
+		log.Info("GPIO command performed")
+		return
+	}
+	log.Warn("GPIO command rejected")
+}
+
+var auditEventTypes = map[MessageType]string{
+	MessageTypeGPIORead:  "gpio_read",
+	MessageTypeGPIOWrite: "gpio_write",
+	MessageTypePWMSet:    "gpio_pwm",
+}
+
+// commandErrorCode maps service errors to the status codes the REST API uses
+func commandErrorCode(err error) (int, string) {
+	switch {
+	case services.IsNotFound(err):
+		return http.StatusNotFound, "GPIO device not found"
+	case services.IsValidationFailed(err), services.IsInvalidInput(err):
+		return http.StatusBadRequest, err.Error()
+	case services.IsConflict(err):
+		return http.StatusConflict, err.Error()
+	case services.IsAgentUnreachable(err):
+		return http.StatusServiceUnavailable, err.Error()
+	case services.IsGPIOError(err):
+		return http.StatusBadGateway, err.Error()
+	}
+	return http.StatusInternalServerError, "GPIO command failed"
+}
+
+// sendCommandError sends an error correlated with a command's request ID
+func (c *Client) sendCommandError(requestID string, code int, message string) {
+	payload, _ := json.Marshal(ErrorMessage{Code: code, Message: message})
+	c.server.sendToClient(c, Message{
+		Type:      MessageTypeError,
+		Payload:   payload,
+		Timestamp: time.Now(),
+		RequestID: requestID,
+	})
+}
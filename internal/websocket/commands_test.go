@@ -0,0 +1,262 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	"github.com/dsyorkd/pi-controller/internal/storage"
+	testutils "github.com/dsyorkd/pi-controller/internal/testing"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// fakeAgent is an in-process PiAgentService that keeps pin values in memory
+type fakeAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu         sync.Mutex
+	configured map[int32]*pb.ConfigureGPIOPinRequest
+	values     map[int32]int32
+	hold       chan struct{} // if set, reads wait until it is closed
+}
+
+func newFakeAgent() *fakeAgent {
+	return &fakeAgent{
+		configured: make(map[int32]*pb.ConfigureGPIOPinRequest),
+		values:     make(map[int32]int32),
+	}
+}
+
+func (a *fakeAgent) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPIOPinRequest) (*pb.ConfigureGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Like the agent, applying a changed configuration drives outputs low
+	if req.Direction == pb.AgentGPIODirection_AGENT_GPIO_DIRECTION_OUTPUT && !proto.Equal(a.configured[req.Pin], req) {
+		a.values[req.Pin] = 0
+	}
+	a.configured[req.Pin] = req
+	return &pb.ConfigureGPIOPinResponse{Success: true}, nil
+}
+
+func (a *fakeAgent) ReadGPIOPin(ctx context.Context, req *pb.ReadGPIOPinRequest) (*pb.ReadGPIOPinResponse, error) {
+	if a.hold != nil {
+		select {
+		case <-a.hold:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &pb.ReadGPIOPinResponse{Pin: req.Pin, Value: a.values[req.Pin]}, nil
+}
+
+func (a *fakeAgent) WriteGPIOPin(ctx context.Context, req *pb.WriteGPIOPinRequest) (*pb.WriteGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[req.Pin] = req.Value
+	return &pb.WriteGPIOPinResponse{Pin: req.Pin, Value: req.Value}, nil
+}
+
+func (a *fakeAgent) SetGPIOPWM(ctx context.Context, req *pb.SetGPIOPWMRequest) (*pb.SetGPIOPWMResponse, error) {
+	return &pb.SetGPIOPWMResponse{Success: true, Pin: req.Pin, Frequency: req.Frequency, DutyCycle: req.DutyCycle}, nil
+}
+
+// agentConnector routes every node to one in-process agent
+type agentConnector struct {
+	conn *grpc.ClientConn
+}
+
+func (c *agentConnector) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	return pb.NewPiAgentServiceClient(c.conn), nil
+}
+
+// setupGPIOService returns a GPIO service backed by the agent with an output
+// LED and a PWM fan on one node
+func setupGPIOService(t *testing.T, agent *fakeAgent) (*services.GPIOService, *models.GPIODevice, *models.GPIODevice) {
+	gormDB, cleanup := testutils.SetupTestDBFile(t)
+	t.Cleanup(cleanup)
+	db := storage.NewForTestWithDB(gormDB, logger.Default())
+
+	listener := bufconn.Listen(1024 * 1024)
+	agentServer := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(agentServer, agent)
+	go agentServer.Serve(listener)
+	t.Cleanup(agentServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	node, err := services.NewNodeService(db, logger.Default()).Create(services.CreateNodeRequest{
+		Name:       "pi-1",
+		IPAddress:  "127.0.0.1",
+		MACAddress: "aa:bb:cc:dd:ee:01",
+		Role:       models.NodeRoleWorker,
+		CPUCores:   4,
+		Memory:     1024,
+	})
+	require.NoError(t, err)
+
+	gpio := services.NewGPIOService(db, logger.Default())
+	gpio.SetAgentConnector(&agentConnector{conn: conn})
+
+	led, err := gpio.Create(services.CreateGPIODeviceRequest{
+		Name:       "led",
+		NodeID:     node.ID,
+		PinNumber:  18,
+		Direction:  models.GPIODirectionOutput,
+		DeviceType: models.GPIODeviceTypeDigital,
+	})
+	require.NoError(t, err)
+	fan, err := gpio.Create(services.CreateGPIODeviceRequest{
+		Name:       "fan",
+		NodeID:     node.ID,
+		PinNumber:  12,
+		Direction:  models.GPIODirectionOutput,
+		DeviceType: models.GPIODeviceTypePWM,
+	})
+	require.NoError(t, err)
+
+	return gpio, led, fan
+}
+
+// command sends a command and returns the response correlated with it
+func command(t *testing.T, conn *websocket.Conn, msgType MessageType, requestID string, payload interface{}) Message {
+	t.Helper()
+	data, _ := json.Marshal(payload)
+	require.NoError(t, conn.WriteJSON(Message{Type: msgType, Payload: data, RequestID: requestID, Timestamp: time.Now()}))
+
+	msg := readMessage(t, conn)
+	require.Equal(t, requestID, msg.RequestID)
+	return msg
+}
+
+func errorCode(t *testing.T, msg Message) int {
+	t.Helper()
+	require.Equal(t, MessageTypeError, msg.Type)
+	var errMsg ErrorMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &errMsg))
+	return errMsg.Code
+}
+
+func TestCommands_GPIO(t *testing.T) {
+	gpio, led, fan := setupGPIOService(t, newFakeAgent())
+	_, url := startTestServer(t, func(s *Server) { s.SetGPIOService(gpio) })
+	conn := dial(t, url)
+
+	one := 1
+	msg := command(t, conn, MessageTypeGPIOWrite, "w1", GPIOWriteCommand{DeviceID: led.ID, Value: &one})
+	require.Equal(t, MessageTypeGPIOResult, msg.Type)
+	var result GPIOResultMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &result))
+	assert.Equal(t, led.ID, result.DeviceID)
+	assert.Equal(t, 18, result.Pin)
+	assert.Equal(t, 1, result.Value)
+
+	msg = command(t, conn, MessageTypeGPIORead, "r1", GPIOReadCommand{DeviceID: led.ID})
+	require.Equal(t, MessageTypeGPIOResult, msg.Type)
+	require.NoError(t, json.Unmarshal(msg.Payload, &result))
+	assert.Equal(t, 1, result.Value, "reading an output does not reset it")
+
+	duty := 30
+	msg = command(t, conn, MessageTypePWMSet, "p1", PWMSetCommand{DeviceID: fan.ID, Frequency: 500, DutyCycle: &duty})
+	require.Equal(t, MessageTypeGPIOResult, msg.Type)
+	require.NoError(t, json.Unmarshal(msg.Payload, &result))
+	assert.Equal(t, 500, result.Frequency)
+	assert.Equal(t, 30, result.DutyCycle)
+
+	two := 2
+	assert.Equal(t, 400, errorCode(t, command(t, conn, MessageTypeGPIOWrite, "w2", GPIOWriteCommand{DeviceID: led.ID, Value: &two})))
+	assert.Equal(t, 400, errorCode(t, command(t, conn, MessageTypeGPIOWrite, "w3", GPIOWriteCommand{DeviceID: led.ID})))
+	assert.Equal(t, 404, errorCode(t, command(t, conn, MessageTypeGPIORead, "r2", GPIOReadCommand{DeviceID: 999})))
+	assert.Equal(t, 400, errorCode(t, command(t, conn, MessageTypePWMSet, "p2", PWMSetCommand{DeviceID: led.ID, Frequency: 500, DutyCycle: &duty})))
+
+	// Commands must be correlatable
+	assert.Equal(t, 400, errorCode(t, command(t, conn, MessageTypeGPIORead, "", GPIOReadCommand{DeviceID: led.ID})))
+}
+
+func TestCommands_RoleEnforcement(t *testing.T) {
+	gpio, led, _ := setupGPIOService(t, newFakeAgent())
+	_, url, authManager := startAuthTestServer(t, time.Hour, func(s *Server) { s.SetGPIOService(gpio) })
+
+	one := 1
+	viewer := dial(t, url+"?token="+token(t, authManager, "alice", middleware.RoleViewer))
+	assert.Equal(t, MessageTypeGPIOResult, command(t, viewer, MessageTypeGPIORead, "r1", GPIOReadCommand{DeviceID: led.ID}).Type)
+	assert.Equal(t, 403, errorCode(t, command(t, viewer, MessageTypeGPIOWrite, "w1", GPIOWriteCommand{DeviceID: led.ID, Value: &one})))
+
+	operator := dial(t, url+"?token="+token(t, authManager, "bob", middleware.RoleOperator))
+	assert.Equal(t, MessageTypeGPIOResult, command(t, operator, MessageTypeGPIOWrite, "w1", GPIOWriteCommand{DeviceID: led.ID, Value: &one}).Type)
+}
+
+func TestCommands_SlowAgent(t *testing.T) {
+	agent := newFakeAgent()
+	agent.hold = make(chan struct{})
+	gpio, led, _ := setupGPIOService(t, agent)
+	_, url := startTestServer(t, func(s *Server) { s.SetGPIOService(gpio) })
+	conn := dial(t, url)
+
+	send := func(msgType MessageType, requestID string, payload interface{}) {
+		data, _ := json.Marshal(payload)
+		require.NoError(t, conn.WriteJSON(Message{Type: msgType, Payload: data, RequestID: requestID, Timestamp: time.Now()}))
+	}
+
+	// Fill the queue behind a read the agent is holding
+	for i := 0; i <= maxQueuedCommands+1; i++ {
+		send(MessageTypeGPIORead, fmt.Sprintf("r%d", i), GPIOReadCommand{DeviceID: led.ID})
+	}
+	send(MessageTypePing, "ping", nil)
+
+	// The client is still served while the commands wait
+	throttled := 0
+	for {
+		msg := readMessage(t, conn)
+		if msg.Type == MessageTypePong && msg.RequestID == "ping" {
+			break
+		}
+		assert.Equal(t, http.StatusTooManyRequests, errorCode(t, msg))
+		throttled++
+	}
+	assert.GreaterOrEqual(t, throttled, 1)
+
+	// Released, the queued commands complete in order
+	close(agent.hold)
+	previous := -1
+	for i := 0; i < maxQueuedCommands+2-throttled; i++ {
+		msg := readMessage(t, conn)
+		require.Equal(t, MessageTypeGPIOResult, msg.Type)
+		var n int
+		_, err := fmt.Sscanf(msg.RequestID, "r%d", &n)
+		require.NoError(t, err)
+		assert.Greater(t, n, previous)
+		previous = n
+	}
+}
+
+func TestCommands_Unavailable(t *testing.T) {
+	_, url := startTestServer(t)
+	conn := dial(t, url)
+	assert.Equal(t, 503, errorCode(t, command(t, conn, MessageTypeGPIORead, "r1", GPIOReadCommand{DeviceID: 1})))
+}
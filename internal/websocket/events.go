@@ -0,0 +1,81 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/dsyorkd/pi-controller/internal/events"
+)
+
+// ConsumeEvents forwards events published on the bus to subscribed clients
+// until the server is stopped
+func (s *Server) ConsumeEvents(bus *events.Bus) {
+	sub := bus.Subscribe(nil, 0)
+
+	go func() {
+		defer sub.Close()
+
+		var reportedDrops uint64
+		for {
+			select {
+			case <-s.shutdown:
+				return
+			case event, ok := <-sub.C():
+				if !ok {
+					return
+				}
+				s.forwardEvent(event)
+
+				if dropped := sub.Dropped(); dropped > reportedDrops {
+					s.logger.WithField("dropped", dropped-reportedDrops).Warn("WebSocket event forwarding is falling behind")
+					reportedDrops = dropped
+				}
+			}
+		}
+	}()
+}
+
+// forwardEvent converts a bus event into the matching WebSocket broadcast
+func (s *Server) forwardEvent(event events.Event) {
+	switch payload := event.Payload.(type) {
+	case events.GPIOReading:
+		s.BroadcastGPIOReading(GPIOReadingMessage{
+			DeviceID:  payload.DeviceID,
+			NodeID:    payload.NodeID,
+			Pin:       payload.Pin,
+			Value:     payload.Value,
+			Timestamp: payload.Timestamp,
+		})
+
+	case events.GPIODevice:
+		data, _ := json.Marshal(payload)
+		s.BroadcastToTopic(gpioDeviceTopic(payload.NodeID, payload.DeviceID), Message{
+			Type:      MessageTypeGPIODevice,
+			Payload:   data,
+			Timestamp: event.Timestamp,
+		})
+
+	case events.Node:
+		s.BroadcastNodeStatus(NodeStatusMessage{
+			Action:    string(payload.Action),
+			NodeID:    payload.NodeID,
+			Name:      payload.Name,
+			Status:    payload.Status,
+			IPAddress: payload.IPAddress,
+			Timestamp: event.Timestamp,
+		})
+
+	case events.Cluster:
+		s.BroadcastClusterStatus(ClusterStatusMessage{
+			Action:     string(payload.Action),
+			ClusterID:  payload.ClusterID,
+			Name:       payload.Name,
+			Status:     payload.Status,
+			NodesReady: payload.NodesReady,
+			NodesTotal: payload.NodesTotal,
+			Timestamp:  event.Timestamp,
+		})
+
+	default:
+		s.logger.WithField("event_type", event.Type).Debug("Ignoring event with no WebSocket mapping")
+	}
+}
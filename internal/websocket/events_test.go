@@ -0,0 +1,107 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/events"
+	"github.com/dsyorkd/pi-controller/internal/logger"
+)
+
+// startTestServer runs the hub and serves upgrades from an httptest server,
+// applying any setup before the first connection
+func startTestServer(t *testing.T, setup ...func(*Server)) (*Server, string) {
+	server := New(&config.WebSocketConfig{ReadBufferSize: 1024, WriteBufferSize: 1024, ReplayBufferSize: 8}, logger.Default(), nil)
+	for _, fn := range setup {
+		fn(server)
+	}
+	go server.run()
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	t.Cleanup(httpServer.Close)
+
+	return server, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+// dial connects a client and consumes the welcome message
+func dial(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	readMessage(t, conn)
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) Message {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func subscribe(t *testing.T, conn *websocket.Conn, topic string) {
+	subscribeWith(t, conn, SubscribeMessage{Topic: topic})
+}
+
+// subscribeWith sends a subscription and returns its acknowledgement
+func subscribeWith(t *testing.T, conn *websocket.Conn, req SubscribeMessage) SubscribedMessage {
+	t.Helper()
+	payload, _ := json.Marshal(req)
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeSubscribe, Payload: payload, RequestID: "sub"}))
+
+	msg := readMessage(t, conn)
+	require.Equal(t, MessageTypeSubscribed, msg.Type)
+	require.Equal(t, "sub", msg.RequestID)
+	var ack SubscribedMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &ack))
+	return ack
+}
+
+func TestConsumeEvents_ForwardsToSubscribedTopics(t *testing.T) {
+	server, url := startTestServer(t)
+	bus := events.NewBus()
+	server.ConsumeEvents(bus)
+
+	nodes := dial(t, url)
+	subscribe(t, nodes, "nodes")
+	gpio := dial(t, url)
+	subscribe(t, gpio, "gpio")
+
+	bus.Publish(events.Node{Action: events.ActionUpdated, NodeID: 3, Name: "pi-3", Status: "ready"})
+	bus.Publish(events.GPIOReading{DeviceID: 7, NodeID: 3, Pin: 18, Value: 1})
+
+	msg := readMessage(t, nodes)
+	assert.Equal(t, MessageTypeNodeStatus, msg.Type)
+	var status NodeStatusMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &status))
+	assert.Equal(t, uint(3), status.NodeID)
+	assert.Equal(t, "ready", status.Status)
+	assert.Equal(t, "updated", status.Action)
+
+	msg = readMessage(t, gpio)
+	assert.Equal(t, MessageTypeGPIOReading, msg.Type)
+	var reading GPIOReadingMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &reading))
+	assert.Equal(t, uint(7), reading.DeviceID)
+	assert.Equal(t, 18, reading.Pin)
+
+	// The node client is not subscribed to gpio, so the next thing it sees is
+	// the cluster update on a topic it subscribes to afterwards
+	subscribe(t, nodes, "clusters")
+	bus.Publish(events.Cluster{Action: events.ActionCreated, ClusterID: 1, Name: "lab", NodesTotal: 2})
+	msg = readMessage(t, nodes)
+	assert.Equal(t, MessageTypeClusterStatus, msg.Type)
+}
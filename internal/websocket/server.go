@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
@@ -10,7 +11,9 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/dsyorkd/pi-controller/internal/logger"
 
+	"github.com/dsyorkd/pi-controller/internal/api/middleware"
 	"github.com/dsyorkd/pi-controller/internal/config"
+	"github.com/dsyorkd/pi-controller/internal/services"
 	"github.com/dsyorkd/pi-controller/internal/storage"
 )
 
@@ -20,6 +23,13 @@ type Server struct {
 	logger   logger.Interface
 	database *storage.Database
 	upgrader websocket.Upgrader
+
+	// Authentication; nil authManager accepts unauthenticated connections
+	authManager *middleware.AuthManager
+	topicRules  []topicRule
+
+	// GPIO command execution; nil rejects commands
+	gpioService *services.GPIOService
 	
 	// Client management
 	clients    map[*Client]bool
@@ -29,6 +39,12 @@ type Server struct {
 	broadcast  chan []byte
 	register   chan *Client
 	unregister chan *Client
+
+	// Topic sequencing and replay; topicMu orders publishing against
+	// subscriptions so a resuming client sees neither gaps nor duplicates
+	topicMu sync.Mutex
+	seq     uint64
+	replay  *replayBuffer
 	
 	// Shutdown
 	shutdown chan struct{}
@@ -41,9 +57,18 @@ type Client struct {
 	send   chan []byte
 	id     string
 	
-	// Subscription management
-	subscriptions map[string]bool
+	// Subscription management, keyed by the pattern the client subscribed with
+	subscriptions map[string]*subscription
 	subMux        sync.RWMutex
+
+	// Authentication state, owned by the read pump
+	claims    *middleware.JWTClaims
+	authTimer *time.Timer
+
+	// GPIO commands waiting for the command worker, which stops once done is
+	// closed by the read pump
+	commands chan queuedCommand
+	done     chan struct{}
 }
 
 // Message types for WebSocket communication
@@ -52,26 +77,51 @@ type MessageType string
 const (
 	MessageTypeSubscribe     MessageType = "subscribe"
 	MessageTypeUnsubscribe   MessageType = "unsubscribe"
+	MessageTypeSubscribed    MessageType = "subscribed"
+	MessageTypeAuth          MessageType = "auth"
+	MessageTypeAuthenticated MessageType = "authenticated"
 	MessageTypeGPIOReading   MessageType = "gpio_reading"
+	MessageTypeGPIODevice    MessageType = "gpio_device"
 	MessageTypeNodeStatus    MessageType = "node_status"
 	MessageTypeClusterStatus MessageType = "cluster_status"
 	MessageTypeSystemMetrics MessageType = "system_metrics"
 	MessageTypeError         MessageType = "error"
 	MessageTypePing          MessageType = "ping"
 	MessageTypePong          MessageType = "pong"
+
+	// Commands, answered with a result or error carrying the same RequestID
+	MessageTypeGPIORead   MessageType = "gpio_read"
+	MessageTypeGPIOWrite  MessageType = "gpio_write"
+	MessageTypePWMSet     MessageType = "pwm_set"
+	MessageTypeGPIOResult MessageType = "gpio_result"
 )
 
-// Message represents a WebSocket message
+// Message represents a WebSocket message. Topic messages carry the concrete
+// topic and a server-wide sequence number clients can resume from.
 type Message struct {
 	Type      MessageType     `json:"type"`
+	Topic     string          `json:"topic,omitempty"`
+	Seq       uint64          `json:"seq,omitempty"`
 	Payload   json.RawMessage `json:"payload,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
 	RequestID string          `json:"request_id,omitempty"`
 }
 
-// SubscribeMessage represents a subscription request
+// SubscribeMessage represents a subscription request. SinceSeq replays
+// buffered messages published after that sequence number before live delivery.
 type SubscribeMessage struct {
-	Topic string `json:"topic"`
+	Topic    string              `json:"topic"`
+	Filter   *SubscriptionFilter `json:"filter,omitempty"`
+	SinceSeq *uint64             `json:"since_seq,omitempty"`
+}
+
+// SubscribedMessage acknowledges a subscription. Complete is false when some
+// messages after the requested sequence number were no longer buffered.
+type SubscribedMessage struct {
+	Topic    string `json:"topic"`
+	Seq      uint64 `json:"seq"`
+	Replayed int    `json:"replayed"`
+	Complete bool   `json:"complete"`
 }
 
 // GPIOReadingMessage represents a GPIO reading event
@@ -85,6 +135,7 @@ type GPIOReadingMessage struct {
 
 // NodeStatusMessage represents a node status update
 type NodeStatusMessage struct {
+	Action    string    `json:"action,omitempty"`
 	NodeID    uint      `json:"node_id"`
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
@@ -94,6 +145,7 @@ type NodeStatusMessage struct {
 
 // ClusterStatusMessage represents a cluster status update
 type ClusterStatusMessage struct {
+	Action    string    `json:"action,omitempty"`
 	ClusterID uint      `json:"cluster_id"`
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
@@ -135,6 +187,8 @@ func New(cfg *config.WebSocketConfig, logger logger.Interface, db *storage.Datab
 		broadcast:  make(chan []byte, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		replay:     newReplayBuffer(max(cfg.ReplayBufferSize, 0)),
+		topicRules: newTopicRules(cfg.TopicRoles, logger),
 		shutdown:   make(chan struct{}),
 	}
 }
@@ -208,16 +262,17 @@ func (s *Server) run() {
 			s.logger.WithField("client_id", client.id).Debug("Client disconnected")
 
 		case message := <-s.broadcast:
+			var slow []*Client
 			s.clientsMux.RLock()
 			for client := range s.clients {
 				select {
 				case client.send <- message:
 				default:
-					delete(s.clients, client)
-					close(client.send)
+					slow = append(slow, client)
 				}
 			}
 			s.clientsMux.RUnlock()
+			s.removeClients(slow)
 
 		case <-ticker.C:
 			// Send ping to all clients
@@ -233,8 +288,23 @@ func (s *Server) run() {
 	}
 }
 
-// handleWebSocket handles WebSocket upgrade requests
+// handleWebSocket handles WebSocket upgrade requests. With authentication
+// enabled, a token on the request is validated before upgrading; otherwise the
+// client must authenticate with its first message.
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	var claims *middleware.JWTClaims
+	token := tokenFromRequest(r)
+	if s.authManager != nil && token != "" {
+		var err error
+		if claims, err = s.authManager.ValidateToken(token); err != nil {
+			s.logger.WithFields(map[string]interface{}{
+				"remote_addr": r.RemoteAddr,
+			}).WithError(err).Warn("WebSocket authentication failed")
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+	}
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to upgrade WebSocket connection")
@@ -246,14 +316,25 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		conn:          conn,
 		send:          make(chan []byte, 256),
 		id:            generateClientID(),
-		subscriptions: make(map[string]bool),
+		subscriptions: make(map[string]*subscription),
+		commands:      make(chan queuedCommand, maxQueuedCommands),
+		done:          make(chan struct{}),
+	}
+
+	if s.authManager != nil {
+		if claims != nil {
+			client.setClaims(claims)
+		} else {
+			client.armAuthTimer(authTimeout, "authentication timeout")
+		}
 	}
 
 	s.register <- client
 
-	// Start goroutines for reading and writing
+	// Start goroutines for reading, writing and running commands
 	go client.writePump()
 	go client.readPump()
+	go client.commandWorker()
 }
 
 // BroadcastMessage broadcasts a message to all connected clients
@@ -267,26 +348,57 @@ func (s *Server) BroadcastMessage(msg Message) {
 	s.broadcast <- data
 }
 
-// BroadcastToTopic broadcasts a message to clients subscribed to a specific topic
+// BroadcastToTopic assigns the next sequence number to a message, records it
+// for replay and sends it to clients with a matching subscription
 func (s *Server) BroadcastToTopic(topic string, msg Message) {
+	fields := extractFilterFields(msg.Payload)
+
+	s.topicMu.Lock()
+	s.seq++
+	msg.Topic = topic
+	msg.Seq = s.seq
 	data, err := json.Marshal(msg)
 	if err != nil {
+		s.seq--
+		s.topicMu.Unlock()
 		s.logger.WithError(err).Error("Failed to marshal topic message")
 		return
 	}
+	s.replay.add(sequencedMessage{seq: msg.Seq, topic: topic, fields: fields, data: data})
 
+	var slow []*Client
 	s.clientsMux.RLock()
 	for client := range s.clients {
-		if client.isSubscribedTo(topic) {
+		if client.wants(topic, fields) {
 			select {
 			case client.send <- data:
 			default:
-				delete(s.clients, client)
-				close(client.send)
+				slow = append(slow, client)
 			}
 		}
 	}
 	s.clientsMux.RUnlock()
+	s.topicMu.Unlock()
+
+	s.removeClients(slow)
+}
+
+// removeClients disconnects clients whose send buffer is full. Removal
+// happens under the write lock and only once per client so the send channel
+// is never closed twice.
+func (s *Server) removeClients(clients []*Client) {
+	if len(clients) == 0 {
+		return
+	}
+
+	s.clientsMux.Lock()
+	defer s.clientsMux.Unlock()
+	for _, client := range clients {
+		if _, ok := s.clients[client]; ok {
+			delete(s.clients, client)
+			close(client.send)
+		}
+	}
 }
 
 // sendToClient sends a message to a specific client
@@ -297,13 +409,17 @@ func (s *Server) sendToClient(client *Client, msg Message) {
 		return
 	}
 
+	s.clientsMux.RLock()
+	if _, ok := s.clients[client]; !ok {
+		s.clientsMux.RUnlock()
+		return
+	}
 	select {
 	case client.send <- data:
+		s.clientsMux.RUnlock()
 	default:
-		s.clientsMux.Lock()
-		delete(s.clients, client)
-		close(client.send)
-		s.clientsMux.Unlock()
+		s.clientsMux.RUnlock()
+		s.removeClients([]*Client{client})
 	}
 }
 
@@ -316,7 +432,7 @@ func (s *Server) BroadcastGPIOReading(reading GPIOReadingMessage) {
 		Timestamp: time.Now(),
 	}
 	
-	s.BroadcastToTopic("gpio", msg)
+	s.BroadcastToTopic(gpioReadingTopic(reading.NodeID, reading.DeviceID), msg)
 }
 
 // BroadcastNodeStatus broadcasts a node status update to subscribed clients
@@ -328,7 +444,7 @@ func (s *Server) BroadcastNodeStatus(status NodeStatusMessage) {
 		Timestamp: time.Now(),
 	}
 	
-	s.BroadcastToTopic("nodes", msg)
+	s.BroadcastToTopic(nodeStatusTopic(status.NodeID), msg)
 }
 
 // BroadcastClusterStatus broadcasts a cluster status update to subscribed clients
@@ -340,7 +456,7 @@ func (s *Server) BroadcastClusterStatus(status ClusterStatusMessage) {
 		Timestamp: time.Now(),
 	}
 	
-	s.BroadcastToTopic("clusters", msg)
+	s.BroadcastToTopic(clusterStatusTopic(status.ClusterID), msg)
 }
 
 // BroadcastSystemMetrics broadcasts system metrics to subscribed clients
@@ -352,7 +468,7 @@ func (s *Server) BroadcastSystemMetrics(metrics SystemMetricsMessage) {
 		Timestamp: time.Now(),
 	}
 	
-	s.BroadcastToTopic("system", msg)
+	s.BroadcastToTopic(systemMetricsTopic, msg)
 }
 
 // Client methods
@@ -360,6 +476,8 @@ func (s *Server) BroadcastSystemMetrics(metrics SystemMetricsMessage) {
 // readPump handles reading messages from the WebSocket connection
 func (c *Client) readPump() {
 	defer func() {
+		c.stopAuthTimer()
+		close(c.done)
 		c.server.unregister <- c
 		c.conn.Close()
 	}()
@@ -422,6 +540,15 @@ func (c *Client) writePump() {
 
 // handleMessage processes incoming messages from clients
 func (c *Client) handleMessage(msg Message) {
+	if msg.Type == MessageTypeAuth {
+		c.handleAuth(msg)
+		return
+	}
+	if c.server.authManager != nil && c.claims == nil {
+		c.closeWithReason(websocket.ClosePolicyViolation, "authentication required")
+		return
+	}
+
 	switch msg.Type {
 	case MessageTypeSubscribe:
 		var sub SubscribeMessage
@@ -429,7 +556,13 @@ func (c *Client) handleMessage(msg Message) {
 			c.sendError(400, "Invalid subscribe message")
 			return
 		}
-		c.subscribe(sub.Topic)
+		if err := c.subscribe(sub, msg.RequestID); err != nil {
+			code := 400
+			if errors.Is(err, errTopicForbidden) {
+				code = 403
+			}
+			c.sendError(code, err.Error())
+		}
 
 	case MessageTypeUnsubscribe:
 		var unsub SubscribeMessage
@@ -448,21 +581,87 @@ func (c *Client) handleMessage(msg Message) {
 		}
 		c.server.sendToClient(c, pong)
 
+	case MessageTypeGPIORead, MessageTypeGPIOWrite, MessageTypePWMSet:
+		c.handleCommand(msg)
+
 	default:
 		c.sendError(400, "Unknown message type")
 	}
 }
 
-// subscribe adds a topic subscription for the client
-func (c *Client) subscribe(topic string) {
+// subscribe adds or replaces a topic subscription for the client. When a
+// resume point is given, buffered messages after it are replayed ahead of any
+// live message.
+func (c *Client) subscribe(req SubscribeMessage, requestID string) error {
+	pattern, err := parseTopicPattern(req.Topic)
+	if err != nil {
+		return err
+	}
+	role, err := c.authorizeTopic(pattern)
+	if err != nil {
+		c.server.logger.WithFields(map[string]interface{}{
+			"client_id": c.id,
+			"user_id":   c.claims.UserID,
+			"topic":     req.Topic,
+		}).Warn("WebSocket subscription denied")
+		return err
+	}
+	sub := &subscription{pattern: pattern, filter: req.Filter, role: role}
+
+	s := c.server
+	s.topicMu.Lock()
+	defer s.topicMu.Unlock()
+
 	c.subMux.Lock()
-	c.subscriptions[topic] = true
+	c.subscriptions[req.Topic] = sub
 	c.subMux.Unlock()
-	
-	c.server.logger.WithFields(map[string]interface{}{
+
+	ack := SubscribedMessage{Topic: req.Topic, Seq: s.seq, Complete: true}
+	var replay [][]byte
+	if req.SinceSeq != nil {
+		buffered, complete := s.replay.since(*req.SinceSeq, s.seq)
+		ack.Complete = complete
+		for _, msg := range buffered {
+			if sub.pattern.matches(msg.topic) && sub.filter.matches(msg.fields) {
+				replay = append(replay, msg.data)
+			}
+		}
+
+		// Replay only what fits in the send buffer alongside the ack; the
+		// client can resume again from the last sequence number it received
+		if room := cap(c.send) - len(c.send) - 1; len(replay) > room {
+			replay = replay[:max(room, 0)]
+			ack.Complete = false
+		}
+		ack.Replayed = len(replay)
+	}
+
+	payload, _ := json.Marshal(ack)
+	data, _ := json.Marshal(Message{
+		Type:      MessageTypeSubscribed,
+		Payload:   payload,
+		Timestamp: time.Now(),
+		RequestID: requestID,
+	})
+
+	s.clientsMux.RLock()
+	if _, ok := s.clients[c]; ok {
+		for _, msg := range append([][]byte{data}, replay...) {
+			select {
+			case c.send <- msg:
+			default:
+			}
+		}
+	}
+	s.clientsMux.RUnlock()
+
+	s.logger.WithFields(map[string]interface{}{
 		"client_id": c.id,
-		"topic":     topic,
+		"topic":     req.Topic,
+		"replayed":  ack.Replayed,
 	}).Debug("Client subscribed to topic")
+
+	return nil
 }
 
 // unsubscribe removes a topic subscription for the client
@@ -477,11 +676,16 @@ func (c *Client) unsubscribe(topic string) {
 	}).Debug("Client unsubscribed from topic")
 }
 
-// isSubscribedTo checks if the client is subscribed to a topic
-func (c *Client) isSubscribedTo(topic string) bool {
+// wants checks if any of the client's subscriptions matches a topic message
+func (c *Client) wants(topic string, fields filterFields) bool {
 	c.subMux.RLock()
 	defer c.subMux.RUnlock()
-	return c.subscriptions[topic]
+	for _, sub := range c.subscriptions {
+		if sub.pattern.matches(topic) && sub.filter.matches(fields) {
+			return true
+		}
+	}
+	return false
 }
 
 // sendError sends an error message to the client
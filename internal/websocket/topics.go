@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Topics are dot-separated hierarchies such as gpio.node.3.device.7 or
+// node.3.status. In subscription patterns "*" matches exactly one segment and
+// a trailing "#" matches all remaining segments.
+const (
+	topicSeparator     = "."
+	topicWildcard      = "*"
+	topicMultiWildcard = "#"
+
+	maxTopicLength = 256
+)
+
+// legacyTopics maps the flat topic names used by earlier clients onto patterns
+var legacyTopics = map[string]string{
+	"gpio":     "gpio.#",
+	"nodes":    "node.#",
+	"clusters": "cluster.#",
+	"system":   "system.#",
+}
+
+// Topic builders for the messages the server publishes
+
+func gpioReadingTopic(nodeID, deviceID uint) string {
+	return fmt.Sprintf("gpio.node.%d.device.%d", nodeID, deviceID)
+}
+
+func gpioDeviceTopic(nodeID, deviceID uint) string {
+	return fmt.Sprintf("gpio.node.%d.device.%d.config", nodeID, deviceID)
+}
+
+func nodeStatusTopic(nodeID uint) string {
+	return fmt.Sprintf("node.%d.status", nodeID)
+}
+
+func clusterStatusTopic(clusterID uint) string {
+	return fmt.Sprintf("cluster.%d.status", clusterID)
+}
+
+const systemMetricsTopic = "system.metrics"
+
+// topicPattern is a parsed subscription pattern
+type topicPattern []string
+
+// parseTopicPattern validates a subscription pattern, expanding legacy names
+func parseTopicPattern(pattern string) (topicPattern, error) {
+	if expanded, ok := legacyTopics[pattern]; ok {
+		pattern = expanded
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if len(pattern) > maxTopicLength {
+		return nil, fmt.Errorf("topic exceeds %d characters", maxTopicLength)
+	}
+
+	segments := strings.Split(pattern, topicSeparator)
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("topic %q has an empty segment", pattern)
+		case segment == topicMultiWildcard && i != len(segments)-1:
+			return nil, fmt.Errorf("%q is only allowed as the last segment", topicMultiWildcard)
+		case segment != topicWildcard && segment != topicMultiWildcard && strings.ContainsAny(segment, "*#"):
+			return nil, fmt.Errorf("wildcards must be whole segments in %q", pattern)
+		}
+	}
+	return segments, nil
+}
+
+// matches reports whether a concrete topic matches the pattern
+func (p topicPattern) matches(topic string) bool {
+	segments := strings.Split(topic, topicSeparator)
+	for i, want := range p {
+		if want == topicMultiWildcard {
+			return len(segments) > i
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if want != topicWildcard && want != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(p)
+}
+
+// SubscriptionFilter narrows a subscription by message content. Messages
+// without the filtered field never match.
+type SubscriptionFilter struct {
+	ValueAbove *float64 `json:"value_above,omitempty"`
+	ValueBelow *float64 `json:"value_below,omitempty"`
+	Status     string   `json:"status,omitempty"`
+}
+
+// filterFields are the payload fields subscription filters can inspect
+type filterFields struct {
+	Value  *float64 `json:"value"`
+	Status *string  `json:"status"`
+}
+
+func extractFilterFields(payload json.RawMessage) filterFields {
+	var fields filterFields
+	if len(payload) > 0 {
+		_ = json.Unmarshal(payload, &fields)
+	}
+	return fields
+}
+
+func (f *SubscriptionFilter) matches(fields filterFields) bool {
+	if f == nil {
+		return true
+	}
+	if f.ValueAbove != nil && (fields.Value == nil || *fields.Value <= *f.ValueAbove) {
+		return false
+	}
+	if f.ValueBelow != nil && (fields.Value == nil || *fields.Value >= *f.ValueBelow) {
+		return false
+	}
+	if f.Status != "" && (fields.Status == nil || *fields.Status != f.Status) {
+		return false
+	}
+	return true
+}
+
+// subscription is one topic pattern a client listens on
+type subscription struct {
+	pattern topicPattern
+	filter  *SubscriptionFilter
+	role    string // minimum role the pattern required when subscribed
+}
+
+// sequencedMessage is an encoded topic message kept for replay
+type sequencedMessage struct {
+	seq    uint64
+	topic  string
+	fields filterFields
+	data   []byte
+}
+
+// replayBuffer is a fixed-size ring of the most recent topic messages
+type replayBuffer struct {
+	entries []sequencedMessage
+	next    int
+	full    bool
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{entries: make([]sequencedMessage, size)}
+}
+
+func (b *replayBuffer) add(msg sequencedMessage) {
+	if len(b.entries) == 0 {
+		return
+	}
+	b.entries[b.next] = msg
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// since returns buffered messages with a sequence number greater than seq,
+// oldest first. complete is false when messages after seq were already evicted.
+func (b *replayBuffer) since(seq, head uint64) (messages []sequencedMessage, complete bool) {
+	count := b.next
+	start := 0
+	if b.full {
+		count = len(b.entries)
+		start = b.next
+	}
+
+	for i := 0; i < count; i++ {
+		msg := b.entries[(start+i)%len(b.entries)]
+		if msg.seq > seq {
+			messages = append(messages, msg)
+		}
+	}
+
+	oldest := head + 1
+	if count > 0 {
+		oldest = b.entries[start].seq
+	}
+	return messages, seq+1 >= oldest || seq >= head
+}
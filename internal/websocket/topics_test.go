@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicPattern_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"gpio.node.3.device.7", "gpio.node.3.device.7", true},
+		{"gpio.node.3.device.*", "gpio.node.3.device.7", true},
+		{"gpio.node.3.device.*", "gpio.node.4.device.7", false},
+		{"gpio.node.3.device.*", "gpio.node.3.device.7.config", false},
+		{"node.*.status", "node.12.status", true},
+		{"node.*.status", "node.12", false},
+		{"gpio.node.3.#", "gpio.node.3.device.7.config", true},
+		{"gpio.node.3.#", "gpio.node.3", false},
+		{"gpio", "gpio.node.3.device.7", true},
+		{"nodes", "node.1.status", true},
+		{"clusters", "node.1.status", false},
+		{"system", "system.metrics", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.topic, func(t *testing.T) {
+			pattern, err := parseTopicPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pattern.matches(tt.topic))
+		})
+	}
+}
+
+func TestParseTopicPattern_Invalid(t *testing.T) {
+	for _, pattern := range []string{"", "gpio..node", "gpio.#.device", "gpio.node*", "node.3#"} {
+		_, err := parseTopicPattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestSubscriptionFilter_Matches(t *testing.T) {
+	threshold := 0.5
+	reading := extractFilterFields(json.RawMessage(`{"device_id":7,"value":0.8}`))
+	low := extractFilterFields(json.RawMessage(`{"device_id":7,"value":0.2}`))
+	status := extractFilterFields(json.RawMessage(`{"node_id":1,"status":"ready"}`))
+
+	var none *SubscriptionFilter
+	assert.True(t, none.matches(low))
+
+	above := &SubscriptionFilter{ValueAbove: &threshold}
+	assert.True(t, above.matches(reading))
+	assert.False(t, above.matches(low))
+	assert.False(t, above.matches(status), "messages without a value never pass a value filter")
+
+	below := &SubscriptionFilter{ValueBelow: &threshold}
+	assert.True(t, below.matches(low))
+	assert.False(t, below.matches(reading))
+
+	ready := &SubscriptionFilter{Status: "ready"}
+	assert.True(t, ready.matches(status))
+	assert.False(t, ready.matches(reading))
+}
+
+func TestReplayBuffer_Since(t *testing.T) {
+	buffer := newReplayBuffer(3)
+	messages, complete := buffer.since(0, 0)
+	assert.Empty(t, messages)
+	assert.True(t, complete)
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		buffer.add(sequencedMessage{seq: seq, topic: fmt.Sprintf("node.%d.status", seq)})
+	}
+
+	// Sequence numbers 3-5 are still buffered
+	messages, complete = buffer.since(2, 5)
+	assert.True(t, complete)
+	require.Len(t, messages, 3)
+	assert.Equal(t, uint64(3), messages[0].seq)
+	assert.Equal(t, uint64(5), messages[2].seq)
+
+	messages, complete = buffer.since(4, 5)
+	assert.True(t, complete)
+	require.Len(t, messages, 1)
+
+	messages, complete = buffer.since(5, 5)
+	assert.True(t, complete)
+	assert.Empty(t, messages)
+
+	// 2 was evicted, so resuming after 1 leaves a gap
+	messages, complete = buffer.since(1, 5)
+	assert.False(t, complete)
+	assert.Len(t, messages, 3)
+
+	// A disabled buffer can only resume a client that missed nothing
+	disabled := newReplayBuffer(0)
+	disabled.add(sequencedMessage{seq: 1})
+	_, complete = disabled.since(0, 1)
+	assert.False(t, complete)
+	_, complete = disabled.since(1, 1)
+	assert.True(t, complete)
+}
+
+func TestServer_HierarchicalTopicsAndResume(t *testing.T) {
+	server, url := startTestServer(t)
+
+	conn := dial(t, url)
+	subscribe(t, conn, "gpio.node.3.device.*")
+
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 4, DeviceID: 1, Value: 1})
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 3, DeviceID: 7, Value: 1})
+
+	msg := readMessage(t, conn)
+	assert.Equal(t, "gpio.node.3.device.7", msg.Topic)
+	assert.Equal(t, uint64(2), msg.Seq)
+	conn.Close()
+
+	// Events published while the client is away are replayed on resume,
+	// followed by live messages
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 3, DeviceID: 8, Value: 0})
+	server.BroadcastNodeStatus(NodeStatusMessage{NodeID: 3, Status: "ready"})
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 3, DeviceID: 7, Value: 1})
+
+	conn = dial(t, url)
+	since := msg.Seq
+	ack := subscribeWith(t, conn, SubscribeMessage{Topic: "gpio.node.3.device.*", SinceSeq: &since})
+	assert.Equal(t, uint64(5), ack.Seq)
+	assert.Equal(t, 2, ack.Replayed)
+	assert.True(t, ack.Complete)
+
+	assert.Equal(t, uint64(3), readMessage(t, conn).Seq)
+	assert.Equal(t, uint64(5), readMessage(t, conn).Seq)
+
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 3, DeviceID: 7, Value: 0})
+	assert.Equal(t, uint64(6), readMessage(t, conn).Seq)
+
+	// Resuming from before the buffered window reports the gap
+	for i := 0; i < 8; i++ {
+		server.BroadcastSystemMetrics(SystemMetricsMessage{})
+	}
+	var zero uint64
+	ack = subscribeWith(t, conn, SubscribeMessage{Topic: "system.metrics", SinceSeq: &zero})
+	assert.False(t, ack.Complete)
+	assert.Equal(t, 8, ack.Replayed)
+}
+
+func TestServer_SubscriptionFilter(t *testing.T) {
+	server, url := startTestServer(t)
+
+	conn := dial(t, url)
+	threshold := 0.5
+	subscribeWith(t, conn, SubscribeMessage{Topic: "gpio.#", Filter: &SubscriptionFilter{ValueAbove: &threshold}})
+
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 1, DeviceID: 1, Value: 0.2})
+	server.BroadcastGPIOReading(GPIOReadingMessage{NodeID: 1, DeviceID: 2, Value: 0.9})
+
+	msg := readMessage(t, conn)
+	var reading GPIOReadingMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &reading))
+	assert.Equal(t, uint(2), reading.DeviceID)
+
+	// An invalid pattern is rejected without dropping the connection
+	payload, _ := json.Marshal(SubscribeMessage{Topic: "gpio.#.device"})
+	require.NoError(t, conn.WriteJSON(Message{Type: MessageTypeSubscribe, Payload: payload}))
+	assert.Equal(t, MessageTypeError, readMessage(t, conn).Type)
+}
@@ -0,0 +1,360 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/ipv4"
+)
+
+// TXT record keys published by agents and parsed by performMDNSDiscovery
+const (
+	TXTVersion      = "version"
+	TXTModel        = "model"
+	TXTSerial       = "serial"
+	TXTMACAddress   = "mac"
+	TXTCapabilities = "capabilities"
+	TXTGRPCPort     = "grpc_port"
+)
+
+const (
+	// mdnsTTL is the TTL of records in multicast responses
+	mdnsTTL = 120
+	// legacyUnicastTTL caps the TTL of responses to one-shot queries sent from
+	// a port other than 5353 (RFC 6762 section 6.7)
+	legacyUnicastTTL = 10
+	// cacheFlush marks records this host is authoritative for
+	cacheFlush = 1 << 15
+)
+
+// mdnsGroup is the IPv4 mDNS multicast group
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Advertisement describes an agent as it is published over mDNS
+type Advertisement struct {
+	Instance     string // service instance name, usually the node name
+	Hostname     string // host name without .local, defaults to the system hostname
+	IPAddress    string // IPv4 address answered for the host name
+	Port         int    // agent gRPC port
+	Version      string
+	Model        string
+	SerialNumber string
+	MACAddress   string
+	Capabilities []string
+}
+
+// txt returns the TXT record strings for the advertisement
+func (a Advertisement) txt() []string {
+	pairs := []struct{ key, value string }{
+		{TXTVersion, a.Version},
+		{TXTModel, a.Model},
+		{TXTSerial, a.SerialNumber},
+		{TXTMACAddress, a.MACAddress},
+		{TXTCapabilities, strings.Join(a.Capabilities, ",")},
+		{TXTGRPCPort, strconv.Itoa(a.Port)},
+	}
+
+	txt := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair.value != "" {
+			txt = append(txt, pair.key+"="+pair.value)
+		}
+	}
+	return txt
+}
+
+// Advertiser answers mDNS queries for the agent's service instance so the
+// controller's discovery service finds it without static configuration
+type Advertiser struct {
+	ad     Advertisement
+	ip     [4]byte
+	iface  *net.Interface
+	logger *logrus.Entry
+
+	service  dnsmessage.Name // _pi-controller._tcp.local.
+	instance dnsmessage.Name // <instance>._pi-controller._tcp.local.
+	host     dnsmessage.Name // <hostname>.local.
+
+	mu    sync.Mutex
+	conn  *net.UDPConn
+	group *net.UDPAddr
+	done  chan struct{}
+}
+
+// NewAdvertiser creates an advertiser for the service type (for example
+// _pi-controller._tcp) on the named interface, or on the system default
+// multicast interface when interfaceName is empty
+func NewAdvertiser(serviceType, interfaceName string, ad Advertisement, logger *logrus.Logger) (*Advertiser, error) {
+	if serviceType == "" {
+		serviceType = DefaultConfig().ServiceType
+	}
+	if ad.Instance == "" {
+		return nil, fmt.Errorf("instance name is required")
+	}
+	if ad.Port <= 0 || ad.Port > 65535 {
+		return nil, fmt.Errorf("invalid port: %d", ad.Port)
+	}
+	ip := net.ParseIP(ad.IPAddress).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %q", ad.IPAddress)
+	}
+	if ad.Hostname == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		ad.Hostname = hostname
+	}
+
+	var iface *net.Interface
+	if interfaceName != "" {
+		var err error
+		if iface, err = net.InterfaceByName(interfaceName); err != nil {
+			return nil, fmt.Errorf("invalid interface %q: %w", interfaceName, err)
+		}
+	}
+
+	service, err := dnsmessage.NewName(strings.TrimSuffix(serviceType, ".") + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid service type %q: %w", serviceType, err)
+	}
+	instance, err := dnsmessage.NewName(dnsLabel(ad.Instance) + "." + service.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance name %q: %w", ad.Instance, err)
+	}
+	host, err := dnsmessage.NewName(dnsLabel(strings.TrimSuffix(ad.Hostname, ".local")) + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid hostname %q: %w", ad.Hostname, err)
+	}
+
+	a := &Advertiser{
+		ad:       ad,
+		iface:    iface,
+		logger:   logger.WithField("component", "mdns-advertiser"),
+		service:  service,
+		instance: instance,
+		host:     host,
+	}
+	copy(a.ip[:], ip)
+	return a, nil
+}
+
+// Start joins the mDNS group, announces the service and answers queries for
+// it until the context is cancelled or Stop is called
+func (a *Advertiser) Start(ctx context.Context) error {
+	conn, err := net.ListenMulticastUDP("udp4", a.iface, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to join mDNS group: %w", err)
+	}
+	if a.iface != nil {
+		if err := ipv4.NewPacketConn(conn).SetMulticastInterface(a.iface); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to set multicast interface: %w", err)
+		}
+	}
+
+	a.run(ctx, conn, mdnsGroup)
+
+	a.logger.WithFields(logrus.Fields{
+		"instance": a.instance.String(),
+		"host":     a.host.String(),
+		"port":     a.ad.Port,
+	}).Info("Advertising agent over mDNS")
+	return nil
+}
+
+// run serves queries on conn and sends announcements to group
+func (a *Advertiser) run(ctx context.Context, conn *net.UDPConn, group *net.UDPAddr) {
+	a.mu.Lock()
+	a.conn = conn
+	a.group = group
+	a.done = make(chan struct{})
+	a.mu.Unlock()
+
+	go a.serve(conn)
+	go a.announce(ctx, a.done)
+}
+
+// Stop sends a goodbye for the service and stops answering queries
+func (a *Advertiser) Stop() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil {
+		return nil
+	}
+
+	close(a.done)
+	if goodbye, err := a.response(0, nil, 0, false); err == nil {
+		if _, err := a.conn.WriteToUDP(goodbye, a.group); err != nil {
+			a.logger.WithError(err).Debug("Failed to send mDNS goodbye")
+		}
+	}
+
+	err := a.conn.Close()
+	a.conn = nil
+	return err
+}
+
+// announce sends unsolicited responses when the advertiser starts, as RFC
+// 6762 section 8.3 asks, so browsers pick the agent up without waiting for
+// their next query
+func (a *Advertiser) announce(ctx context.Context, done <-chan struct{}) {
+	delay := time.Second
+	for i := 0; i < 3; i++ {
+		a.send(nil, 0, nil, mdnsTTL, false)
+
+		select {
+		case <-ctx.Done():
+			a.Stop()
+			return
+		case <-done:
+			return
+		case <-time.After(delay):
+			delay *= 2
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		a.Stop()
+	case <-done:
+	}
+}
+
+// serve answers queries until the connection is closed
+func (a *Advertiser) serve(conn *net.UDPConn) {
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		var query dnsmessage.Message
+		if err := query.Unpack(buf[:n]); err != nil || query.Response || !a.matches(query.Questions) {
+			continue
+		}
+
+		// One-shot queries from other ports get a unicast reply that echoes
+		// the question, everyone else the multicast group
+		if from.Port != mdnsGroup.Port {
+			a.send(from, query.ID, query.Questions, legacyUnicastTTL, true)
+		} else {
+			a.send(nil, 0, nil, mdnsTTL, false)
+		}
+	}
+}
+
+// send writes a response to to, or to the multicast group when to is nil
+func (a *Advertiser) send(to *net.UDPAddr, id uint16, questions []dnsmessage.Question, ttl uint32, unicast bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn == nil {
+		return
+	}
+	if to == nil {
+		to = a.group
+	}
+
+	msg, err := a.response(id, questions, ttl, unicast)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build mDNS response")
+		return
+	}
+	if _, err := a.conn.WriteToUDP(msg, to); err != nil {
+		a.logger.WithError(err).WithField("to", to.String()).Debug("Failed to send mDNS response")
+	}
+}
+
+// matches reports whether any question asks for the service, the instance or
+// the host
+func (a *Advertiser) matches(questions []dnsmessage.Question) bool {
+	for _, q := range questions {
+		name := q.Name.String()
+		switch {
+		case strings.EqualFold(name, a.service.String()):
+			if q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL {
+				return true
+			}
+		case strings.EqualFold(name, a.instance.String()):
+			if q.Type == dnsmessage.TypeSRV || q.Type == dnsmessage.TypeTXT || q.Type == dnsmessage.TypeALL {
+				return true
+			}
+		case strings.EqualFold(name, a.host.String()):
+			if q.Type == dnsmessage.TypeA || q.Type == dnsmessage.TypeALL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// response builds the PTR answer with the SRV, TXT and A records as
+// additionals. Multicast responses set the cache-flush bit on the records
+// this host owns; legacy unicast responses must not.
+func (a *Advertiser) response(id uint16, questions []dnsmessage.Question, ttl uint32, unicast bool) ([]byte, error) {
+	unique := dnsmessage.ClassINET
+	if !unicast {
+		unique |= cacheFlush
+	}
+	header := func(name dnsmessage.Name, typ dnsmessage.Type, class dnsmessage.Class) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Type: typ, Class: class, TTL: ttl}
+	}
+
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, Response: true, Authoritative: true},
+		Questions: questions,
+		Answers: []dnsmessage.Resource{{
+			Header: header(a.service, dnsmessage.TypePTR, dnsmessage.ClassINET),
+			Body:   &dnsmessage.PTRResource{PTR: a.instance},
+		}},
+		Additionals: []dnsmessage.Resource{
+			{
+				Header: header(a.instance, dnsmessage.TypeSRV, unique),
+				Body:   &dnsmessage.SRVResource{Port: uint16(a.ad.Port), Target: a.host},
+			},
+			{
+				Header: header(a.instance, dnsmessage.TypeTXT, unique),
+				Body:   &dnsmessage.TXTResource{TXT: a.txt()},
+			},
+			{
+				Header: header(a.host, dnsmessage.TypeA, unique),
+				Body:   &dnsmessage.AResource{A: a.ip},
+			},
+		},
+	}
+	return msg.Pack()
+}
+
+// txt returns the TXT strings, or the single empty string RFC 6763 asks for
+// when there is nothing to publish
+func (a *Advertiser) txt() []string {
+	if txt := a.ad.txt(); len(txt) > 0 {
+		return txt
+	}
+	return []string{""}
+}
+
+// dnsLabel turns a name into a single DNS label
+func dnsLabel(name string) string {
+	label := strings.Map(func(r rune) rune {
+		if r == '.' || r == ' ' || r == '_' {
+			return '-'
+		}
+		return r
+	}, name)
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	return label
+}
@@ -0,0 +1,186 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func testAdvertisement() Advertisement {
+	return Advertisement{
+		Instance:     "pi-kitchen",
+		Hostname:     "raspberrypi",
+		IPAddress:    "192.168.1.42",
+		Port:         9091,
+		Version:      "1.2.0",
+		Model:        "Raspberry Pi 4 Model B Rev 1.4",
+		SerialNumber: "10000000abcdef01",
+		MACAddress:   "dc:a6:32:12:34:56",
+		Capabilities: []string{"gpio", "pwm", "interrupt"},
+	}
+}
+
+func listenLoopback(t *testing.T) *net.UDPConn {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// startAdvertiser serves the advertisement on a loopback socket and returns
+// it with a socket standing in for the multicast group
+func startAdvertiser(t *testing.T, ad Advertisement) (*Advertiser, *net.UDPConn, *net.UDPConn) {
+	advertiser, err := NewAdvertiser("_pi-controller._tcp", "", ad, logrus.New())
+	require.NoError(t, err)
+
+	conn := listenLoopback(t)
+	group := listenLoopback(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	advertiser.run(ctx, conn, group.LocalAddr().(*net.UDPAddr))
+	t.Cleanup(func() { advertiser.Stop() })
+
+	return advertiser, conn, group
+}
+
+// readResponse reads the next response sent to the group
+func readResponse(t *testing.T, group *net.UDPConn) *dnsmessage.Message {
+	buf := make([]byte, 9000)
+	require.NoError(t, group.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := group.ReadFromUDP(buf)
+	require.NoError(t, err)
+
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(buf[:n]))
+	return &msg
+}
+
+func TestNewAdvertiser(t *testing.T) {
+	ad := testAdvertisement()
+	ad.Instance = ""
+	_, err := NewAdvertiser("_pi-controller._tcp", "", ad, logrus.New())
+	assert.Error(t, err)
+
+	ad = testAdvertisement()
+	ad.IPAddress = "fe80::1"
+	_, err = NewAdvertiser("_pi-controller._tcp", "", ad, logrus.New())
+	assert.Error(t, err, "only IPv4 is advertised")
+
+	ad = testAdvertisement()
+	ad.Port = 0
+	_, err = NewAdvertiser("_pi-controller._tcp", "", ad, logrus.New())
+	assert.Error(t, err)
+
+	ad = testAdvertisement()
+	ad.Instance = "Raspberry Pi 4.kitchen"
+	advertiser, err := NewAdvertiser("", "", ad, logrus.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Raspberry-Pi-4-kitchen._pi-controller._tcp.local.", advertiser.instance.String())
+	assert.Equal(t, "raspberrypi.local.", advertiser.host.String())
+}
+
+func TestAdvertiser_Browse(t *testing.T) {
+	_, conn, group := startAdvertiser(t, testAdvertisement())
+
+	// The announcement goes to the group with the cache-flush bit set
+	announcement := readResponse(t, group)
+	require.Len(t, announcement.Answers, 1)
+	assert.Equal(t, dnsmessage.TypePTR, announcement.Answers[0].Header.Type)
+	require.Len(t, announcement.Additionals, 3)
+	assert.Equal(t, dnsmessage.ClassINET|cacheFlush, announcement.Additionals[0].Header.Class)
+	assert.Equal(t, uint32(mdnsTTL), announcement.Additionals[0].Header.TTL)
+
+	browser := listenLoopback(t)
+	nodes, err := browse(context.Background(), browser, conn.LocalAddr().(*net.UDPAddr), "_pi-controller._tcp", 500*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+
+	node := nodes[0]
+	assert.Equal(t, "mdns-pi-kitchen", node.ID)
+	assert.Equal(t, "raspberrypi.local.", node.Name)
+	assert.Equal(t, "192.168.1.42", node.IPAddress)
+	assert.Equal(t, 9091, node.Port)
+	assert.Equal(t, "_pi-controller._tcp", node.ServiceType)
+	assert.Equal(t, []string{"gpio", "pwm", "interrupt"}, node.Capabilities)
+	assert.Equal(t, "dc:a6:32:12:34:56", node.MACAddress)
+	assert.Equal(t, "10000000abcdef01", node.SerialNumber)
+	assert.Equal(t, "1.2.0", node.TXTRecords[TXTVersion])
+	assert.Equal(t, "Raspberry Pi 4 Model B Rev 1.4", node.TXTRecords[TXTModel])
+	assert.Equal(t, "9091", node.TXTRecords[TXTGRPCPort])
+	assert.False(t, node.LastSeen.IsZero())
+
+	// Browsing another service type finds nothing
+	nodes, err = browse(context.Background(), browser, conn.LocalAddr().(*net.UDPAddr), "_other._tcp", 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+}
+
+func TestAdvertiser_Goodbye(t *testing.T) {
+	advertiser, _, group := startAdvertiser(t, testAdvertisement())
+	readResponse(t, group)
+
+	require.NoError(t, advertiser.Stop())
+	goodbye := readResponse(t, group)
+	for _, res := range append(goodbye.Answers, goodbye.Additionals...) {
+		assert.Zero(t, res.Header.TTL)
+	}
+
+	records := newServiceRecords("_pi-controller._tcp", "_pi-controller._tcp.local.")
+	records.add(goodbye, net.IPv4(192, 168, 1, 42))
+	assert.Empty(t, records.nodes(time.Now()), "nodes saying goodbye are not reported")
+}
+
+func TestServiceRecords(t *testing.T) {
+	service := dnsmessage.MustNewName("_pi-controller._tcp.local.")
+	response := func(instance, host string, ip [4]byte, txt ...string) *dnsmessage.Message {
+		instanceName := dnsmessage.MustNewName(instance + "._pi-controller._tcp.local.")
+		hostName := dnsmessage.MustNewName(host)
+		header := func(name dnsmessage.Name, typ dnsmessage.Type) dnsmessage.ResourceHeader {
+			return dnsmessage.ResourceHeader{Name: name, Type: typ, Class: dnsmessage.ClassINET, TTL: 120}
+		}
+		msg := &dnsmessage.Message{
+			Header:  dnsmessage.Header{Response: true},
+			Answers: []dnsmessage.Resource{{Header: header(service, dnsmessage.TypePTR), Body: &dnsmessage.PTRResource{PTR: instanceName}}},
+			Additionals: []dnsmessage.Resource{
+				{Header: header(instanceName, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Port: 9091, Target: hostName}},
+				{Header: header(instanceName, dnsmessage.TypeTXT), Body: &dnsmessage.TXTResource{TXT: txt}},
+			},
+		}
+		if ip != [4]byte{} {
+			msg.Additionals = append(msg.Additionals, dnsmessage.Resource{Header: header(hostName, dnsmessage.TypeA), Body: &dnsmessage.AResource{A: ip}})
+		}
+		return msg
+	}
+
+	records := newServiceRecords("_pi-controller._tcp", service.String())
+	// Two Pis that kept the default hostname
+	records.add(response("pi-1", "raspberrypi.local.", [4]byte{192, 168, 1, 10}, "MAC=dc:a6:32:00:00:01", "capabilities=gpio, pwm,", "grpc_port=9191"), net.IPv4(192, 168, 1, 10))
+	records.add(response("pi-2", "raspberrypi.local.", [4]byte{192, 168, 1, 11}, ""), net.IPv4(192, 168, 1, 11))
+	// A responder that leaves the address out
+	records.add(response("pi-3", "pi-3.local.", [4]byte{}, "debug"), net.IPv4(192, 168, 1, 12))
+
+	nodes := make(map[string]Node)
+	for _, node := range records.nodes(time.Now()) {
+		nodes[node.ID] = node
+	}
+	require.Len(t, nodes, 3)
+
+	assert.Equal(t, "192.168.1.10", nodes["mdns-pi-1"].IPAddress)
+	assert.Equal(t, "dc:a6:32:00:00:01", nodes["mdns-pi-1"].MACAddress, "TXT keys are case-insensitive")
+	assert.Equal(t, []string{"gpio", "pwm"}, nodes["mdns-pi-1"].Capabilities)
+	assert.Equal(t, 9191, nodes["mdns-pi-1"].Port, "the advertised gRPC port wins")
+
+	assert.Equal(t, "192.168.1.11", nodes["mdns-pi-2"].IPAddress)
+	assert.Equal(t, 9091, nodes["mdns-pi-2"].Port)
+	assert.Empty(t, nodes["mdns-pi-2"].Capabilities)
+	assert.Empty(t, nodes["mdns-pi-2"].TXTRecords)
+
+	assert.Equal(t, "192.168.1.12", nodes["mdns-pi-3"].IPAddress, "falls back to the response's source address")
+	assert.Contains(t, nodes["mdns-pi-3"].TXTRecords, "debug")
+}
@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arpDiscoverer reports the Pis in the controller's ARP table
+type arpDiscoverer struct {
+	table         string
+	interfaceName string
+	ouis          ouiFilter
+}
+
+// NewARPDiscoverer creates a backend reading a Linux ARP table such as
+// /proc/net/arp. Only entries on the named interface, or any interface when
+// it is empty, whose MAC address has one of the OUIs, Raspberry Pi's when
+// empty, are reported.
+func NewARPDiscoverer(table, interfaceName string, ouis []string) (Discoverer, error) {
+	filter, err := newOUIFilter(ouis)
+	if err != nil {
+		return nil, err
+	}
+	return &arpDiscoverer{table: table, interfaceName: interfaceName, ouis: filter}, nil
+}
+
+func (d *arpDiscoverer) Name() string {
+	return MethodARP
+}
+
+// Discover parses the table:
+//
+//	IP address       HW type     Flags       HW address            Mask     Device
+//	192.168.1.10     0x1         0x2         dc:a6:32:12:34:56     *        eth0
+//
+// Entries without the completed flag (0x2) are still being resolved.
+func (d *arpDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	file, err := os.Open(d.table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ARP table: %w", err)
+	}
+	defer file.Close()
+
+	now := time.Now()
+	var nodes []Node
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[2], 0, 32)
+		if err != nil || flags&0x2 == 0 {
+			continue
+		}
+		if d.interfaceName != "" && fields[5] != d.interfaceName {
+			continue
+		}
+		mac := normalizeMAC(fields[3])
+		if mac == "" || !d.ouis.match(mac) {
+			continue
+		}
+
+		nodes = append(nodes, Node{
+			ID:          "arp-" + mac,
+			IPAddress:   fields[0],
+			ServiceType: MethodARP,
+			TXTRecords:  map[string]string{},
+			LastSeen:    now,
+			MACAddress:  mac,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ARP table: %w", err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,240 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/ipv4"
+)
+
+// Browse sends an mDNS query for the service type and returns the instances
+// that answered within the timeout. Responders reply to the query's source
+// port directly, so no socket needs to be bound to port 5353.
+func Browse(ctx context.Context, serviceType string, iface *net.Interface, timeout time.Duration) ([]Node, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mDNS socket: %w", err)
+	}
+	defer conn.Close()
+
+	if iface != nil {
+		if err := ipv4.NewPacketConn(conn).SetMulticastInterface(iface); err != nil {
+			return nil, fmt.Errorf("failed to set multicast interface: %w", err)
+		}
+	}
+
+	return browse(ctx, conn, mdnsGroup, serviceType, timeout)
+}
+
+// browse queries to and collects responses on conn until the timeout
+func browse(ctx context.Context, conn *net.UDPConn, to *net.UDPAddr, serviceType string, timeout time.Duration) ([]Node, error) {
+	service, err := dnsmessage.NewName(strings.TrimSuffix(serviceType, ".") + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid service type %q: %w", serviceType, err)
+	}
+
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(rand.Intn(1<<16-1) + 1)},
+		Questions: []dnsmessage.Question{{
+			Name:  service,
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build mDNS query: %w", err)
+	}
+	if _, err := conn.WriteToUDP(packed, to); err != nil {
+		return nil, fmt.Errorf("failed to send mDNS query: %w", err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	records := newServiceRecords(serviceType, service.String())
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, fmt.Errorf("failed to read mDNS response: %w", err)
+		}
+
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || !msg.Response {
+			continue
+		}
+		records.add(&msg, from.IP)
+	}
+
+	return records.nodes(time.Now()), nil
+}
+
+// serviceRecords collects the records of a service's instances across the
+// responses of every responder
+type serviceRecords struct {
+	serviceType string
+	service     string // service name with the .local. domain
+	instances   map[string]*instanceRecords
+	hosts       map[string]net.IP
+}
+
+// instanceRecords is what is known about one service instance
+type instanceRecords struct {
+	name   string
+	target string
+	port   int
+	txt    []string
+	ip     net.IP // from an A record in the same response
+	source net.IP // address the SRV record came from
+	gone   bool
+}
+
+func newServiceRecords(serviceType, service string) *serviceRecords {
+	return &serviceRecords{
+		serviceType: serviceType,
+		service:     strings.ToLower(service),
+		instances:   make(map[string]*instanceRecords),
+		hosts:       make(map[string]net.IP),
+	}
+}
+
+// instance returns the records for name, or nil if name is not an instance
+// of the service
+func (r *serviceRecords) instance(name string) *instanceRecords {
+	key := strings.ToLower(name)
+	if !strings.HasSuffix(key, "."+r.service) {
+		return nil
+	}
+	inst, ok := r.instances[key]
+	if !ok {
+		inst = &instanceRecords{name: name}
+		r.instances[key] = inst
+	}
+	return inst
+}
+
+// add records a response received from source. Addresses are resolved from
+// the same response first, since several Pis may share a default hostname.
+func (r *serviceRecords) add(msg *dnsmessage.Message, source net.IP) {
+	resources := append(append([]dnsmessage.Resource{}, msg.Answers...), msg.Additionals...)
+
+	local := make(map[string]net.IP)
+	for _, res := range resources {
+		if a, ok := res.Body.(*dnsmessage.AResource); ok && res.Header.TTL > 0 {
+			ip := net.IP(a.A[:])
+			local[strings.ToLower(res.Header.Name.String())] = ip
+			r.hosts[strings.ToLower(res.Header.Name.String())] = ip
+		}
+	}
+
+	for _, res := range resources {
+		switch body := res.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if !strings.EqualFold(res.Header.Name.String(), r.service) {
+				continue
+			}
+			if inst := r.instance(body.PTR.String()); inst != nil && res.Header.TTL == 0 {
+				inst.gone = true
+			}
+		case *dnsmessage.SRVResource:
+			inst := r.instance(res.Header.Name.String())
+			if inst == nil {
+				continue
+			}
+			inst.gone = res.Header.TTL == 0
+			inst.target = body.Target.String()
+			inst.port = int(body.Port)
+			inst.ip = local[strings.ToLower(inst.target)]
+			inst.source = source
+		case *dnsmessage.TXTResource:
+			if inst := r.instance(res.Header.Name.String()); inst != nil {
+				inst.txt = body.TXT
+			}
+		}
+	}
+}
+
+// nodes returns a node for every instance that published its SRV record
+func (r *serviceRecords) nodes(now time.Time) []Node {
+	nodes := make([]Node, 0, len(r.instances))
+	for _, inst := range r.instances {
+		if inst.gone || inst.target == "" {
+			continue
+		}
+
+		ip := inst.ip
+		if ip == nil {
+			ip = r.hosts[strings.ToLower(inst.target)]
+		}
+		if ip == nil {
+			ip = inst.source
+		}
+		if ip == nil {
+			continue
+		}
+
+		txt := parseTXT(inst.txt)
+		port := inst.port
+		if grpcPort, err := strconv.Atoi(txt[TXTGRPCPort]); err == nil && grpcPort > 0 {
+			port = grpcPort
+		}
+
+		label := inst.name[:len(inst.name)-len(r.service)-1]
+		nodes = append(nodes, Node{
+			ID:           "mdns-" + label,
+			Name:         inst.target,
+			IPAddress:    ip.String(),
+			Port:         port,
+			ServiceType:  r.serviceType,
+			TXTRecords:   txt,
+			LastSeen:     now,
+			Capabilities: parseCapabilities(txt[TXTCapabilities]),
+			MACAddress:   txt[TXTMACAddress],
+			SerialNumber: txt[TXTSerial],
+		})
+	}
+	return nodes
+}
+
+// parseTXT parses key=value TXT strings. Keys are case-insensitive and a key
+// without a value maps to the empty string.
+func parseTXT(txt []string) map[string]string {
+	records := make(map[string]string, len(txt))
+	for _, entry := range txt {
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "=")
+		key = strings.ToLower(key)
+		if _, exists := records[key]; !exists {
+			records[key] = value
+		}
+	}
+	return records
+}
+
+// parseCapabilities splits a comma-separated capabilities value
+func parseCapabilities(value string) []string {
+	capabilities := []string{}
+	for _, capability := range strings.Split(value, ",") {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	return capabilities
+}
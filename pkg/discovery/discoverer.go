@@ -0,0 +1,253 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Discoverer is a discovery backend. Discover returns the nodes the backend
+// sees right now; the service runs every backend each interval and merges
+// their results by node identity.
+type Discoverer interface {
+	// Name identifies the backend in logs and in Node.Sources
+	Name() string
+	Discover(ctx context.Context) ([]Node, error)
+}
+
+// Runner is implemented by backends that serve in the background, such as
+// the phone-home endpoint. Run blocks until the context is cancelled.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// Identity returns the key nodes are merged by: the MAC address, else the
+// serial number, else the backend's own ID
+func Identity(node Node) string {
+	if mac := normalizeMAC(node.MACAddress); mac != "" {
+		return "mac-" + mac
+	}
+	if node.SerialNumber != "" {
+		return "serial-" + strings.ToLower(node.SerialNumber)
+	}
+	return node.ID
+}
+
+// MergeNodes merges the results of several backends, given in priority
+// order. Nodes with the same MAC address or serial number are one node, as
+// are nodes at the same IP address when one of them lacks the hardware
+// identity the other has. Fields of higher priority results win; missing
+// fields are filled in from the others.
+func MergeNodes(results ...[]Node) []Node {
+	var merged []*Node
+	byMAC := make(map[string]*Node)
+	bySerial := make(map[string]*Node)
+	byIP := make(map[string]*Node)
+
+	for _, nodes := range results {
+		for _, node := range nodes {
+			mac := normalizeMAC(node.MACAddress)
+			serial := strings.ToLower(node.SerialNumber)
+
+			var target *Node
+			for _, candidate := range []*Node{byMAC[mac], bySerial[serial], byIP[node.IPAddress]} {
+				if candidate != nil && sameNode(candidate, &node) {
+					target = candidate
+					break
+				}
+			}
+
+			if target == nil {
+				copied := node
+				copied.Sources = append([]string(nil), node.Sources...)
+				copied.Capabilities = append([]string(nil), node.Capabilities...)
+				copied.TXTRecords = make(map[string]string, len(node.TXTRecords))
+				for key, value := range node.TXTRecords {
+					copied.TXTRecords[key] = value
+				}
+				target = &copied
+				merged = append(merged, target)
+			} else {
+				mergeNode(target, node)
+			}
+
+			if mac := normalizeMAC(target.MACAddress); mac != "" {
+				byMAC[mac] = target
+			}
+			if target.SerialNumber != "" {
+				bySerial[strings.ToLower(target.SerialNumber)] = target
+			}
+			if target.IPAddress != "" {
+				byIP[target.IPAddress] = target
+			}
+		}
+	}
+
+	nodes := make([]Node, 0, len(merged))
+	for _, node := range merged {
+		if mac := normalizeMAC(node.MACAddress); mac != "" {
+			node.MACAddress = mac
+		}
+		node.ID = Identity(*node)
+		nodes = append(nodes, *node)
+	}
+	return nodes
+}
+
+// sameNode reports whether two reports can be the same node: their MAC
+// addresses and serial numbers do not contradict each other
+func sameNode(a, b *Node) bool {
+	macA, macB := normalizeMAC(a.MACAddress), normalizeMAC(b.MACAddress)
+	if macA != "" && macB != "" && macA != macB {
+		// A Pi's Ethernet and wireless interfaces share its serial number
+		return a.SerialNumber != "" && strings.EqualFold(a.SerialNumber, b.SerialNumber)
+	}
+	if a.SerialNumber != "" && b.SerialNumber != "" && !strings.EqualFold(a.SerialNumber, b.SerialNumber) {
+		return false
+	}
+	return macA != "" && macA == macB ||
+		a.SerialNumber != "" && strings.EqualFold(a.SerialNumber, b.SerialNumber) ||
+		a.IPAddress != "" && a.IPAddress == b.IPAddress
+}
+
+// mergeNode fills the fields into is missing from a lower priority report
+func mergeNode(into *Node, from Node) {
+	if into.Name == "" {
+		into.Name = from.Name
+	}
+	if into.IPAddress == "" {
+		into.IPAddress = from.IPAddress
+	}
+	if into.Port == 0 {
+		into.Port = from.Port
+	}
+	if into.ServiceType == "" {
+		into.ServiceType = from.ServiceType
+	}
+	if into.MACAddress == "" {
+		into.MACAddress = from.MACAddress
+	}
+	if into.SerialNumber == "" {
+		into.SerialNumber = from.SerialNumber
+	}
+	if from.LastSeen.After(into.LastSeen) {
+		into.LastSeen = from.LastSeen
+	}
+	for key, value := range from.TXTRecords {
+		if _, exists := into.TXTRecords[key]; !exists {
+			into.TXTRecords[key] = value
+		}
+	}
+	into.Capabilities = appendMissing(into.Capabilities, from.Capabilities...)
+	into.Sources = appendMissing(into.Sources, from.Sources...)
+}
+
+// appendMissing appends the values not already in list
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// normalizeMAC returns the MAC address in lower-case colon form, or "" if it
+// is not one
+func normalizeMAC(mac string) string {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return ""
+	}
+	return hw.String()
+}
+
+// mdnsDiscoverer browses for agents advertising the service type
+type mdnsDiscoverer struct {
+	serviceType   string
+	interfaceName string
+	timeout       time.Duration
+}
+
+// NewMDNSDiscoverer creates a backend browsing for the service type on the
+// named interface, or the system default when interfaceName is empty. The
+// agents' TXT records carry their version, model, serial number, MAC
+// address, capabilities and gRPC port.
+func NewMDNSDiscoverer(serviceType, interfaceName string, timeout time.Duration) Discoverer {
+	return &mdnsDiscoverer{serviceType: serviceType, interfaceName: interfaceName, timeout: timeout}
+}
+
+func (d *mdnsDiscoverer) Name() string {
+	return MethodMDNS
+}
+
+func (d *mdnsDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	var iface *net.Interface
+	if d.interfaceName != "" {
+		var err error
+		if iface, err = net.InterfaceByName(d.interfaceName); err != nil {
+			return nil, err
+		}
+	}
+	return Browse(ctx, d.serviceType, iface, d.timeout)
+}
+
+// scanDiscoverer scans the local networks for the agent port
+type scanDiscoverer struct {
+	logger *logrus.Entry
+}
+
+func (d *scanDiscoverer) Name() string {
+	return MethodScan
+}
+
+// Discover performs a network scan for discovery
+func (d *scanDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	// TODO: Implement actual network scanning
+	// This would typically scan common subnets for the service port
+	// For now, this is a placeholder
+
+	// Get local network interfaces
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+				d.logger.WithFields(logrus.Fields{
+					"interface": iface.Name,
+					"network":   ipnet.String(),
+				}).Debug("Scanning network")
+
+				// TODO: Scan the network for nodes
+				// This would involve:
+				// 1. Generating IP ranges from the network
+				// 2. Port scanning for the service port
+				// 3. Attempting to connect and identify pi-controller agents
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiscoverer reports fixed nodes
+type fakeDiscoverer struct {
+	name  string
+	nodes []Node
+}
+
+func (d *fakeDiscoverer) Name() string { return d.name }
+
+func (d *fakeDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	return d.nodes, nil
+}
+
+func TestMergeNodes(t *testing.T) {
+	now := time.Now()
+	mdns := []Node{{
+		ID:           "mdns-pi-kitchen",
+		Name:         "pi-kitchen.local.",
+		IPAddress:    "192.168.1.10",
+		Port:         9091,
+		ServiceType:  "_pi-controller._tcp",
+		TXTRecords:   map[string]string{TXTModel: "Raspberry Pi 4 Model B"},
+		LastSeen:     now.Add(-time.Second),
+		Capabilities: []string{"gpio"},
+		SerialNumber: "10000000abcdef01",
+		Sources:      []string{MethodMDNS},
+	}}
+	dhcp := []Node{
+		{ID: "dhcp-dc:a6:32:00:00:01", Name: "pi-kitchen", IPAddress: "192.168.1.10", ServiceType: MethodDHCP, MACAddress: "DC:A6:32:00:00:01", LastSeen: now, Sources: []string{MethodDHCP}},
+		{ID: "dhcp-dc:a6:32:00:00:02", Name: "pi-garage", IPAddress: "192.168.1.11", ServiceType: MethodDHCP, MACAddress: "dc:a6:32:00:00:02", LastSeen: now, Sources: []string{MethodDHCP}},
+	}
+	arp := []Node{
+		{ID: "arp-dc:a6:32:00:00:01", IPAddress: "192.168.1.10", ServiceType: MethodARP, MACAddress: "dc:a6:32:00:00:01", Sources: []string{MethodARP}},
+		// A different board that took over the garage Pi's address
+		{ID: "arp-dc:a6:32:00:00:03", IPAddress: "192.168.1.11", ServiceType: MethodARP, MACAddress: "dc:a6:32:00:00:03", Sources: []string{MethodARP}},
+	}
+	phoneHome := []Node{
+		// The kitchen Pi's wireless interface, identified by its serial number
+		{ID: "phone-home-e4:5f:01:00:00:01", IPAddress: "192.168.1.20", ServiceType: MethodPhoneHome, MACAddress: "e4:5f:01:00:00:01", SerialNumber: "10000000ABCDEF01", Capabilities: []string{"gpio", "pwm"}, Sources: []string{MethodPhoneHome}},
+	}
+
+	merged := make(map[string]Node)
+	for _, node := range MergeNodes(mdns, dhcp, arp, phoneHome) {
+		merged[node.ID] = node
+	}
+	require.Len(t, merged, 3)
+
+	kitchen, ok := merged["mac-dc:a6:32:00:00:01"]
+	require.True(t, ok, "identified by MAC address once a backend reports it")
+	assert.Equal(t, "pi-kitchen.local.", kitchen.Name, "higher priority results win")
+	assert.Equal(t, "192.168.1.10", kitchen.IPAddress)
+	assert.Equal(t, 9091, kitchen.Port)
+	assert.Equal(t, "_pi-controller._tcp", kitchen.ServiceType)
+	assert.Equal(t, "dc:a6:32:00:00:01", kitchen.MACAddress, "filled in from the DHCP lease at the same address")
+	assert.Equal(t, []string{MethodMDNS, MethodDHCP, MethodARP, MethodPhoneHome}, kitchen.Sources)
+	assert.Equal(t, []string{"gpio", "pwm"}, kitchen.Capabilities)
+	assert.Equal(t, now, kitchen.LastSeen)
+	assert.Equal(t, "Raspberry Pi 4 Model B", kitchen.TXTRecords[TXTModel])
+
+	garage := merged["mac-dc:a6:32:00:00:02"]
+	assert.Equal(t, "pi-garage", garage.Name)
+	assert.Equal(t, []string{MethodDHCP}, garage.Sources)
+
+	other := merged["mac-dc:a6:32:00:00:03"]
+	assert.Equal(t, "192.168.1.11", other.IPAddress, "a different MAC address at the same IP is a different node")
+
+	// Merging does not modify the backends' results
+	assert.Equal(t, []string{MethodMDNS}, mdns[0].Sources)
+	assert.Equal(t, []string{"gpio"}, mdns[0].Capabilities)
+}
+
+func TestService_MergesBackends(t *testing.T) {
+	config := DefaultConfig()
+	config.Method = MethodStatic
+	config.Interval = "1h"
+	service, err := NewService(config, logrus.New())
+	require.NoError(t, err)
+
+	service.AddDiscoverer(&fakeDiscoverer{name: "first", nodes: []Node{{ID: "a", Name: "pi", MACAddress: "dc:a6:32:00:00:01", TXTRecords: map[string]string{}}}})
+	service.AddDiscoverer(&fakeDiscoverer{name: "second", nodes: []Node{{ID: "b", IPAddress: "192.168.1.10", MACAddress: "dc:a6:32:00:00:01"}}})
+
+	var mu sync.Mutex
+	var events []NodeEvent
+	service.AddEventHandler(func(event NodeEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, service.Start(ctx))
+	defer service.Stop()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, NodeDiscovered, events[0].Type)
+	node, ok := service.GetNode("mac-dc:a6:32:00:00:01")
+	require.True(t, ok)
+	assert.Equal(t, "pi", node.Name)
+	assert.Equal(t, "192.168.1.10", node.IPAddress)
+	assert.Equal(t, []string{"first", "second"}, node.Sources)
+}
+
+func TestNewService_Methods(t *testing.T) {
+	config := DefaultConfig()
+	config.Methods = []string{MethodMDNS, MethodARP, MethodPhoneHome}
+	service, err := NewService(config, logrus.New())
+	require.NoError(t, err)
+	require.Len(t, service.discoverers, 3)
+	assert.Equal(t, MethodPhoneHome, service.discoverers[2].Name())
+
+	config.Methods = []string{MethodDHCP}
+	_, err = NewService(config, logrus.New())
+	assert.Error(t, err, "the dhcp method needs lease files")
+
+	config.Methods = []string{"carrier-pigeon"}
+	_, err = NewService(config, logrus.New())
+	assert.Error(t, err)
+
+	config.Methods = []string{MethodARP}
+	config.OUIs = []string{"dc:a6"}
+	_, err = NewService(config, logrus.New())
+	assert.Error(t, err, "an OUI has at least three octets")
+}
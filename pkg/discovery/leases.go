@@ -0,0 +1,252 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RaspberryPiOUIs are the MAC address prefixes assigned to Raspberry Pi
+// boards
+var RaspberryPiOUIs = []string{"28:cd:c1", "2c:cf:67", "b8:27:eb", "d8:3a:dd", "dc:a6:32", "e4:5f:01"}
+
+// ouiFilter matches MAC addresses against vendor prefixes
+type ouiFilter []string
+
+// newOUIFilter normalizes the prefixes, or uses RaspberryPiOUIs when there
+// are none
+func newOUIFilter(prefixes []string) (ouiFilter, error) {
+	if len(prefixes) == 0 {
+		prefixes = RaspberryPiOUIs
+	}
+
+	filter := make(ouiFilter, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		normalized := strings.ToLower(strings.ReplaceAll(prefix, "-", ":"))
+		octets := strings.Split(normalized, ":")
+		if len(octets) < 3 || len(octets) > 6 {
+			return nil, fmt.Errorf("invalid OUI %q", prefix)
+		}
+		for _, octet := range octets {
+			if _, err := strconv.ParseUint(octet, 16, 8); err != nil || len(octet) != 2 {
+				return nil, fmt.Errorf("invalid OUI %q", prefix)
+			}
+		}
+		filter = append(filter, normalized)
+	}
+	return filter, nil
+}
+
+// match reports whether the normalized MAC address has one of the prefixes
+func (f ouiFilter) match(mac string) bool {
+	for _, prefix := range f {
+		if strings.HasPrefix(mac, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dhcpLeaseDiscoverer reports the Pis holding active DHCP leases
+type dhcpLeaseDiscoverer struct {
+	files []string
+	ouis  ouiFilter
+	now   func() time.Time
+}
+
+// NewDHCPLeaseDiscoverer creates a backend reading dnsmasq or ISC dhcpd
+// lease files. Only leases of MAC addresses with one of the OUIs, Raspberry
+// Pi's when empty, are reported.
+func NewDHCPLeaseDiscoverer(files []string, ouis []string) (Discoverer, error) {
+	filter, err := newOUIFilter(ouis)
+	if err != nil {
+		return nil, err
+	}
+	return &dhcpLeaseDiscoverer{files: files, ouis: filter, now: time.Now}, nil
+}
+
+func (d *dhcpLeaseDiscoverer) Name() string {
+	return MethodDHCP
+}
+
+func (d *dhcpLeaseDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	now := d.now()
+
+	var nodes []Node
+	for _, file := range d.files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lease file: %w", err)
+		}
+
+		var leases []dhcpLease
+		if isISCLeaseFile(string(data)) {
+			leases = parseISCLeases(string(data))
+		} else {
+			leases = parseDnsmasqLeases(string(data))
+		}
+
+		for _, lease := range leases {
+			mac := normalizeMAC(lease.mac)
+			if mac == "" || !d.ouis.match(mac) || lease.expired(now) {
+				continue
+			}
+			nodes = append(nodes, Node{
+				ID:          "dhcp-" + mac,
+				Name:        lease.hostname,
+				IPAddress:   lease.ip,
+				ServiceType: MethodDHCP,
+				TXTRecords:  map[string]string{},
+				LastSeen:    now,
+				MACAddress:  mac,
+			})
+		}
+	}
+	return nodes, nil
+}
+
+// dhcpLease is a lease from either file format
+type dhcpLease struct {
+	ip       string
+	mac      string
+	hostname string
+	ends     time.Time // zero for leases that never expire
+	inactive bool
+}
+
+func (l dhcpLease) expired(now time.Time) bool {
+	return l.inactive || !l.ends.IsZero() && l.ends.Before(now)
+}
+
+// parseDnsmasqLeases parses dnsmasq.leases, one lease per line:
+//
+//	<expiry epoch> <mac> <ip> <hostname or *> <client id or *>
+//
+// An expiry of 0 is an infinite lease. DHCPv6 leases follow a "duid" line
+// and have no MAC address, so they are skipped.
+func parseDnsmasqLeases(data string) []dhcpLease {
+	var leases []dhcpLease
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[0] == "duid" {
+			continue
+		}
+		expiry, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		lease := dhcpLease{ip: fields[2], mac: fields[1]}
+		if fields[3] != "*" {
+			lease.hostname = fields[3]
+		}
+		if expiry != 0 {
+			lease.ends = time.Unix(expiry, 0)
+		}
+		leases = append(leases, lease)
+	}
+	return leases
+}
+
+// isISCLeaseFile reports whether data is in dhcpd.leases format
+func isISCLeaseFile(data string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "lease ") && strings.HasSuffix(line, "{") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseISCLeases parses dhcpd.leases blocks:
+//
+//	lease 192.168.1.10 {
+//	  ends 4 2026/10/15 22:00:00;
+//	  binding state active;
+//	  hardware ethernet dc:a6:32:12:34:56;
+//	  client-hostname "raspberrypi";
+//	}
+//
+// dhcpd appends a new block each time a lease changes, so the last block for
+// an address wins.
+func parseISCLeases(data string) []dhcpLease {
+	var (
+		order   []string
+		byIP    = make(map[string]dhcpLease)
+		current *dhcpLease
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if current == nil {
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[0] == "lease" && fields[2] == "{" {
+				current = &dhcpLease{ip: fields[1]}
+			}
+			continue
+		}
+
+		if line == "}" {
+			if _, seen := byIP[current.ip]; !seen {
+				order = append(order, current.ip)
+			}
+			byIP[current.ip] = *current
+			current = nil
+			continue
+		}
+
+		statement := strings.TrimSuffix(line, ";")
+		fields := strings.Fields(statement)
+		switch {
+		case len(fields) >= 3 && fields[0] == "hardware":
+			current.mac = fields[2]
+		case len(fields) >= 2 && fields[0] == "client-hostname":
+			current.hostname = strings.Trim(strings.TrimPrefix(statement, "client-hostname "), `"`)
+		case len(fields) >= 3 && fields[0] == "binding" && fields[1] == "state":
+			current.inactive = fields[2] != "active"
+		case len(fields) >= 2 && fields[0] == "ends":
+			current.ends = parseISCTime(fields[1:])
+		}
+	}
+
+	leases := make([]dhcpLease, 0, len(order))
+	for _, ip := range order {
+		leases = append(leases, byIP[ip])
+	}
+	return leases
+}
+
+// parseISCTime parses a dhcpd.leases time: "never", "epoch <seconds>" or
+// "<weekday> <yyyy/mm/dd> <hh:mm:ss>" in UTC. It returns the zero time for
+// leases that never end and for times it cannot parse.
+func parseISCTime(fields []string) time.Time {
+	switch {
+	case fields[0] == "never":
+		return time.Time{}
+	case fields[0] == "epoch" && len(fields) >= 2:
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		return time.Unix(seconds, 0)
+	case len(fields) >= 3:
+		t, err := time.Parse("2006/01/02 15:04:05", fields[1]+" "+fields[2])
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,137 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dnsmasqLeases = `1792152000 dc:a6:32:00:00:01 192.168.1.10 pi-kitchen 01:dc:a6:32:00:00:01
+0 b8:27:eb:00:00:02 192.168.1.11 * *
+1760000000 e4:5f:01:00:00:03 192.168.1.12 pi-expired *
+1792152000 3c:22:fb:00:00:04 192.168.1.13 laptop *
+duid 00:01:00:01:2c:5f:1a:2b:dc:a6:32:00:00:01
+1792152000 1234 fd00::10 pi-kitchen 00:01:00:01
+`
+
+const iscLeases = `# The format of this file is documented in the dhcpd.leases(5) manual page.
+authoring-byte-order little-endian;
+
+lease 192.168.1.10 {
+  starts 4 2026/10/15 10:00:00;
+  ends 4 2026/10/15 12:00:00;
+  binding state active;
+  hardware ethernet dc:a6:32:00:00:01;
+  client-hostname "pi-kitchen";
+}
+lease 192.168.1.11 {
+  starts 4 2026/10/15 10:00:00;
+  ends never;
+  binding state active;
+  hardware ethernet b8:27:eb:00:00:02;
+}
+lease 192.168.1.12 {
+  starts 4 2026/10/15 10:00:00;
+  ends epoch 1792152000; # Sun Oct 15 10:00:00 2026
+  binding state free;
+  hardware ethernet e4:5f:01:00:00:03;
+}
+lease 192.168.1.10 {
+  starts 4 2026/10/16 10:00:00;
+  ends 4 2026/10/16 12:00:00;
+  binding state active;
+  hardware ethernet dc:a6:32:00:00:01;
+  client-hostname "pi-kitchen";
+}
+`
+
+func writeFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	return path
+}
+
+func discoverByMAC(t *testing.T, discoverer Discoverer) map[string]Node {
+	nodes, err := discoverer.Discover(context.Background())
+	require.NoError(t, err)
+
+	byMAC := make(map[string]Node)
+	for _, node := range nodes {
+		byMAC[node.MACAddress] = node
+	}
+	return byMAC
+}
+
+func TestDHCPLeaseDiscoverer(t *testing.T) {
+	now := time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"dnsmasq", dnsmasqLeases},
+		{"isc", iscLeases},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discoverer, err := NewDHCPLeaseDiscoverer([]string{writeFile(t, "leases", tt.data)}, nil)
+			require.NoError(t, err)
+			discoverer.(*dhcpLeaseDiscoverer).now = func() time.Time { return now }
+
+			nodes := discoverByMAC(t, discoverer)
+			require.Len(t, nodes, 2, "expired, released and non-Pi leases are skipped")
+
+			kitchen := nodes["dc:a6:32:00:00:01"]
+			assert.Equal(t, "dhcp-dc:a6:32:00:00:01", kitchen.ID)
+			assert.Equal(t, "pi-kitchen", kitchen.Name)
+			assert.Equal(t, "192.168.1.10", kitchen.IPAddress)
+			assert.Equal(t, MethodDHCP, kitchen.ServiceType)
+			assert.Equal(t, now, kitchen.LastSeen)
+
+			unnamed := nodes["b8:27:eb:00:00:02"]
+			assert.Equal(t, "192.168.1.11", unnamed.IPAddress, "leases that never expire are active")
+			assert.Empty(t, unnamed.Name)
+		})
+	}
+
+	discoverer, err := NewDHCPLeaseDiscoverer([]string{writeFile(t, "leases", dnsmasqLeases)}, []string{"3C-22-FB"})
+	require.NoError(t, err)
+	discoverer.(*dhcpLeaseDiscoverer).now = func() time.Time { return now }
+	nodes := discoverByMAC(t, discoverer)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "laptop", nodes["3c:22:fb:00:00:04"].Name)
+
+	discoverer, err = NewDHCPLeaseDiscoverer([]string{filepath.Join(t.TempDir(), "missing")}, nil)
+	require.NoError(t, err)
+	_, err = discoverer.Discover(context.Background())
+	assert.Error(t, err)
+}
+
+func TestARPDiscoverer(t *testing.T) {
+	table := writeFile(t, "arp", `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.10     0x1         0x2         dc:a6:32:00:00:01     *        eth0
+192.168.1.11     0x1         0x0         00:00:00:00:00:00     *        eth0
+192.168.1.12     0x1         0x2         3c:22:fb:00:00:04     *        eth0
+192.168.2.13     0x1         0x2         e4:5f:01:00:00:03     *        wlan0
+`)
+
+	discoverer, err := NewARPDiscoverer(table, "", nil)
+	require.NoError(t, err)
+	nodes := discoverByMAC(t, discoverer)
+	require.Len(t, nodes, 2, "incomplete entries and other vendors are skipped")
+	assert.Equal(t, "192.168.1.10", nodes["dc:a6:32:00:00:01"].IPAddress)
+	assert.Equal(t, "arp-dc:a6:32:00:00:01", nodes["dc:a6:32:00:00:01"].ID)
+	assert.Equal(t, MethodARP, nodes["dc:a6:32:00:00:01"].ServiceType)
+	assert.Equal(t, "192.168.2.13", nodes["e4:5f:01:00:00:03"].IPAddress)
+
+	discoverer, err = NewARPDiscoverer(table, "wlan0", nil)
+	require.NoError(t, err)
+	nodes = discoverByMAC(t, discoverer)
+	require.Len(t, nodes, 1)
+	assert.Contains(t, nodes, "e4:5f:01:00:00:03")
+}
@@ -22,6 +22,16 @@ type Node struct {
 	TXTRecords  map[string]string `json:"txt_records"`
 	LastSeen    time.Time         `json:"last_seen"`
 	Capabilities []string         `json:"capabilities"`
+
+	// Hardware identity, when the discovery method reveals it. Discovered
+	// nodes are registered in the inventory by MAC address, or matched to an
+	// existing node by serial number.
+	MACAddress   string `json:"mac_address,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+
+	// Sources lists the discovery backends that reported the node, in
+	// priority order. ServiceType is that of the first.
+	Sources []string `json:"sources,omitempty"`
 }
 
 // NodeEventType represents the type of node discovery event
@@ -53,18 +63,48 @@ type Config struct {
 	StaticNodes     []string `yaml:"static_nodes" mapstructure:"static_nodes"`
 	ServiceName     string   `yaml:"service_name" mapstructure:"service_name"`
 	ServiceType     string   `yaml:"service_type" mapstructure:"service_type"`
+
+	// Methods runs several backends together, in priority order, instead of
+	// the single Method
+	Methods []string `yaml:"methods" mapstructure:"methods"`
+
+	// DHCP lease files (dnsmasq or ISC dhcpd format) read by the dhcp method
+	LeaseFiles []string `yaml:"lease_files" mapstructure:"lease_files"`
+
+	// ARP table read by the arp method
+	ARPTable string `yaml:"arp_table" mapstructure:"arp_table"`
+
+	// MAC address prefixes accepted from DHCP leases and the ARP table,
+	// Raspberry Pi's OUIs when empty
+	OUIs []string `yaml:"ouis" mapstructure:"ouis"`
+
+	// Listen address of the phone-home endpoint freshly imaged Pis POST
+	// their identity to
+	PhoneHomeAddress string `yaml:"phone_home_address" mapstructure:"phone_home_address"`
 }
 
+// Discovery methods
+const (
+	MethodMDNS      = "mdns"
+	MethodScan      = "scan"
+	MethodStatic    = "static"
+	MethodDHCP      = "dhcp"
+	MethodARP       = "arp"
+	MethodPhoneHome = "phone-home"
+)
+
 // DefaultConfig returns default discovery configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Enabled:     true,
-		Method:      "mdns",
-		Port:        9091,
-		Interval:    "30s",
-		Timeout:     "5s",
-		ServiceName: "pi-controller",
-		ServiceType: "_pi-controller._tcp",
+		Enabled:          true,
+		Method:           MethodMDNS,
+		Port:             9091,
+		Interval:         "30s",
+		Timeout:          "5s",
+		ServiceName:      "pi-controller",
+		ServiceType:      "_pi-controller._tcp",
+		ARPTable:         "/proc/net/arp",
+		PhoneHomeAddress: ":8082",
 	}
 }
 
@@ -75,6 +115,7 @@ type Service struct {
 	mu            sync.RWMutex
 	nodes         map[string]*Node
 	eventHandlers []NodeEventHandler
+	discoverers   []Discoverer
 	running       bool
 	stopChan      chan struct{}
 	interval      time.Duration
@@ -106,9 +147,64 @@ func NewService(config *Config, logger *logrus.Logger) (*Service, error) {
 		stopChan: make(chan struct{}),
 	}
 
+	methods := config.Methods
+	if len(methods) == 0 && config.Method != "" {
+		methods = []string{config.Method}
+	}
+	for _, method := range methods {
+		discoverer, err := service.newDiscoverer(method)
+		if err != nil {
+			return nil, err
+		}
+		if discoverer != nil {
+			service.discoverers = append(service.discoverers, discoverer)
+		}
+	}
+
 	return service, nil
 }
 
+// newDiscoverer creates the backend for a discovery method. The static
+// method has none; static nodes are loaded when the service starts.
+func (s *Service) newDiscoverer(method string) (Discoverer, error) {
+	switch method {
+	case MethodMDNS:
+		return NewMDNSDiscoverer(s.config.ServiceType, s.config.Interface, s.timeout), nil
+	case MethodScan:
+		return &scanDiscoverer{logger: s.logger}, nil
+	case MethodStatic:
+		return nil, nil
+	case MethodDHCP:
+		if len(s.config.LeaseFiles) == 0 {
+			return nil, fmt.Errorf("the dhcp discovery method needs lease files")
+		}
+		return NewDHCPLeaseDiscoverer(s.config.LeaseFiles, s.config.OUIs)
+	case MethodARP:
+		table := s.config.ARPTable
+		if table == "" {
+			table = DefaultConfig().ARPTable
+		}
+		return NewARPDiscoverer(table, s.config.Interface, s.config.OUIs)
+	case MethodPhoneHome:
+		address := s.config.PhoneHomeAddress
+		if address == "" {
+			address = DefaultConfig().PhoneHomeAddress
+		}
+		return NewPhoneHome(address, s.logger.Logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported discovery method: %s", method)
+	}
+}
+
+// AddDiscoverer adds a discovery backend. Call it before Start; backends
+// added later run after the configured ones and have lower priority when
+// results are merged.
+func (s *Service) AddDiscoverer(discoverer Discoverer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discoverers = append(s.discoverers, discoverer)
+}
+
 // Start starts the discovery service
 func (s *Service) Start(ctx context.Context) error {
 	if !s.config.Enabled {
@@ -124,8 +220,16 @@ func (s *Service) Start(ctx context.Context) error {
 	s.running = true
 	s.mu.Unlock()
 
+	s.mu.RLock()
+	discoverers := append([]Discoverer(nil), s.discoverers...)
+	s.mu.RUnlock()
+
+	names := make([]string, 0, len(discoverers))
+	for _, discoverer := range discoverers {
+		names = append(names, discoverer.Name())
+	}
 	s.logger.WithFields(logrus.Fields{
-		"method":   s.config.Method,
+		"methods":  names,
 		"interval": s.config.Interval,
 	}).Info("Starting discovery service")
 
@@ -134,16 +238,30 @@ func (s *Service) Start(ctx context.Context) error {
 		s.loadStaticNodes()
 	}
 
-	// Start discovery based on method
-	switch s.config.Method {
-	case "mdns":
-		go s.runMDNSDiscovery(ctx)
-	case "scan":
-		go s.runNetworkScan(ctx)
-	case "static":
+	// Background backends such as the phone-home endpoint stop with the
+	// service
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		select {
+		case <-runCtx.Done():
+		case <-s.stopChan:
+		}
+	}()
+	for _, discoverer := range discoverers {
+		if r, ok := discoverer.(Runner); ok {
+			go func(name string) {
+				if err := r.Run(runCtx); err != nil {
+					s.logger.WithError(err).WithField("method", name).Error("Discovery backend stopped")
+				}
+			}(discoverer.Name())
+		}
+	}
+
+	if len(discoverers) > 0 {
+		go s.runDiscovery(runCtx, discoverers)
+	} else {
 		s.logger.Info("Using static node discovery only")
-	default:
-		return fmt.Errorf("unsupported discovery method: %s", s.config.Method)
 	}
 
 	// Start cleanup routine
@@ -207,6 +325,9 @@ func (s *Service) GetNode(id string) (*Node, bool) {
 		TXTRecords:   node.TXTRecords,
 		LastSeen:     node.LastSeen,
 		Capabilities: node.Capabilities,
+		MACAddress:   node.MACAddress,
+		SerialNumber: node.SerialNumber,
+		Sources:      node.Sources,
 	}, true
 }
 
@@ -256,12 +377,12 @@ func (s *Service) loadStaticNodes() {
 	}
 }
 
-// runMDNSDiscovery runs mDNS-based discovery
-func (s *Service) runMDNSDiscovery(ctx context.Context) {
-	s.logger.Info("Starting mDNS discovery")
+// runDiscovery runs every backend each interval
+func (s *Service) runDiscovery(ctx context.Context, discoverers []Discoverer) {
 	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
+	s.performDiscovery(ctx, discoverers)
 	for {
 		select {
 		case <-ctx.Done():
@@ -269,136 +390,66 @@ func (s *Service) runMDNSDiscovery(ctx context.Context) {
 		case <-s.stopChan:
 			return
 		case <-ticker.C:
-			s.performMDNSDiscovery()
+			s.performDiscovery(ctx, discoverers)
 		}
 	}
 }
 
-// performMDNSDiscovery performs a single mDNS discovery cycle
-func (s *Service) performMDNSDiscovery() {
-	// TODO: Implement actual mDNS discovery
-	// This would typically use libraries like github.com/hashicorp/mdns
-	// For now, this is a mock implementation
-
-	s.logger.Debug("Performing mDNS discovery scan")
-
-	// Mock discovery: simulate finding nodes
-	mockNodes := []Node{
-		{
-			ID:        "mdns-pi-001",
-			Name:      "raspberrypi-001",
-			IPAddress: "192.168.1.101",
-			Port:      s.config.Port,
-			ServiceType: s.config.ServiceType,
-			TXTRecords: map[string]string{
-				"version":      "1.0.0",
-				"capabilities": "gpio,monitoring",
-				"model":        "Raspberry Pi 4",
-			},
-			LastSeen:    time.Now(),
-			Capabilities: []string{"gpio", "monitoring"},
-		},
-		{
-			ID:        "mdns-pi-002",
-			Name:      "raspberrypi-002",
-			IPAddress: "192.168.1.102",
-			Port:      s.config.Port,
-			ServiceType: s.config.ServiceType,
-			TXTRecords: map[string]string{
-				"version":      "1.0.0",
-				"capabilities": "gpio,monitoring",
-				"model":        "Raspberry Pi 3",
-			},
-			LastSeen:    time.Now(),
-			Capabilities: []string{"gpio", "monitoring"},
-		},
+// performDiscovery performs a single discovery cycle. The backends run
+// concurrently and their results are merged by node identity, so a Pi seen
+// over mDNS and in the ARP table is one node.
+func (s *Service) performDiscovery(ctx context.Context, discoverers []Discoverer) {
+	s.logger.Debug("Performing discovery scan")
+
+	results := make([][]Node, len(discoverers))
+	var wg sync.WaitGroup
+	for i, discoverer := range discoverers {
+		wg.Add(1)
+		go func(i int, discoverer Discoverer) {
+			defer wg.Done()
+			nodes, err := discoverer.Discover(ctx)
+			if err != nil {
+				s.logger.WithError(err).WithField("method", discoverer.Name()).Warn("Discovery failed")
+				return
+			}
+			for j := range nodes {
+				if len(nodes[j].Sources) == 0 {
+					nodes[j].Sources = []string{discoverer.Name()}
+				}
+			}
+			results[i] = nodes
+		}(i, discoverer)
 	}
+	wg.Wait()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, node := range mockNodes {
+	for _, node := range MergeNodes(results...) {
 		existingNode, exists := s.nodes[node.ID]
 		if exists {
-			// Update existing node
-			existingNode.LastSeen = node.LastSeen
+			// Update existing node, which may have moved or been upgraded
+			*existingNode = node
 			s.emitEvent(NodeEvent{
 				Type: NodeUpdated,
 				Node: node,
 			})
 		} else {
 			// New node discovered
-			s.nodes[node.ID] = &node
+			discovered := node
+			s.nodes[node.ID] = &discovered
 			s.emitEvent(NodeEvent{
 				Type: NodeDiscovered,
 				Node: node,
 			})
 
 			s.logger.WithFields(logrus.Fields{
-				"id":         node.ID,
-				"name":       node.Name,
-				"ip_address": node.IPAddress,
-			}).Info("Discovered new node via mDNS")
-		}
-	}
-}
-
-// runNetworkScan runs network scan-based discovery
-func (s *Service) runNetworkScan(ctx context.Context) {
-	s.logger.Info("Starting network scan discovery")
-	ticker := time.NewTicker(s.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-s.stopChan:
-			return
-		case <-ticker.C:
-			s.performNetworkScan()
-		}
-	}
-}
-
-// performNetworkScan performs a network scan for discovery
-func (s *Service) performNetworkScan() {
-	s.logger.Debug("Performing network scan")
-
-	// TODO: Implement actual network scanning
-	// This would typically scan common subnets for the service port
-	// For now, this is a placeholder
-
-	// Get local network interfaces
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to get network interfaces")
-		return
-	}
-
-	for _, iface := range interfaces {
-		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
-			continue
-		}
-
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
-
-		for _, addr := range addrs {
-			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
-				s.logger.WithFields(logrus.Fields{
-					"interface": iface.Name,
-					"network":   ipnet.String(),
-				}).Debug("Scanning network")
-
-				// TODO: Scan the network for nodes
-				// This would involve:
-				// 1. Generating IP ranges from the network
-				// 2. Port scanning for the service port
-				// 3. Attempting to connect and identify pi-controller agents
-			}
+				"id":           node.ID,
+				"name":         node.Name,
+				"ip_address":   node.IPAddress,
+				"sources":      node.Sources,
+				"capabilities": node.Capabilities,
+			}).Info("Discovered new node")
 		}
 	}
 }
@@ -0,0 +1,243 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxPhoneHomeBody limits the size of a phone-home request
+const maxPhoneHomeBody = 64 << 10
+
+// maxPhoneHomePending limits how many identities are held between Discover
+// calls, so anonymous posts cannot exhaust the controller's memory
+const maxPhoneHomePending = 1024
+
+// PhoneHome is the endpoint freshly imaged Pis POST their identity to, from
+// cloud-init's phone_home module or a first-boot script. Each identity is
+// reported by the next Discover call; after that the Pi is kept alive by the
+// other backends seeing it.
+//
+// The endpoint is unauthenticated. Posted nodes only enter the inventory
+// through the registration policy, and reports of nodes already in the
+// inventory never change their address or identity.
+type PhoneHome struct {
+	address string
+	logger  *logrus.Entry
+	handler http.Handler
+
+	mu      sync.Mutex
+	pending map[string]Node
+}
+
+// phoneHomeRequest is the JSON body of a phone-home request. Form bodies
+// use the same field names, which include those cloud-init posts.
+type phoneHomeRequest struct {
+	InstanceID   string   `json:"instance_id"`
+	Hostname     string   `json:"hostname"`
+	FQDN         string   `json:"fqdn"`
+	IPAddress    string   `json:"ip_address"`
+	MACAddress   string   `json:"mac_address"`
+	SerialNumber string   `json:"serial_number"`
+	Model        string   `json:"model"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// NewPhoneHome creates the phone-home endpoint listening on address. It
+// accepts POST /phone-home and POST /phone-home/{instance_id}, so cloud-init
+// can pass $INSTANCE_ID in the URL.
+func NewPhoneHome(address string, logger *logrus.Logger) *PhoneHome {
+	p := &PhoneHome{
+		address: address,
+		logger:  logger.WithField("component", "phone-home"),
+		pending: make(map[string]Node),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /phone-home", p.handle)
+	mux.HandleFunc("POST /phone-home/{instance_id}", p.handle)
+	p.handler = mux
+	return p
+}
+
+func (p *PhoneHome) Name() string {
+	return MethodPhoneHome
+}
+
+// Discover returns the nodes that phoned home since the last call
+func (p *PhoneHome) Discover(ctx context.Context) ([]Node, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nodes := make([]Node, 0, len(p.pending))
+	for _, node := range p.pending {
+		nodes = append(nodes, node)
+	}
+	p.pending = make(map[string]Node)
+	return nodes, nil
+}
+
+// Run serves the endpoint until the context is cancelled
+func (p *PhoneHome) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              p.address,
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	p.logger.WithField("address", p.address).Info("Phone-home endpoint listening")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("phone-home endpoint failed: %w", err)
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (p *PhoneHome) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.handler.ServeHTTP(w, r)
+}
+
+func (p *PhoneHome) handle(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhoneHomeBody)
+
+	req, err := parsePhoneHomeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node, err := req.node(r.RemoteAddr, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	identity := Identity(node)
+	if _, exists := p.pending[identity]; !exists && len(p.pending) >= maxPhoneHomePending {
+		p.mu.Unlock()
+		p.logger.WithField("id", node.ID).Warn("Dropping phone-home report, too many are pending")
+		http.Error(w, "too many pending phone-home reports", http.StatusServiceUnavailable)
+		return
+	}
+	p.pending[identity] = node
+	p.mu.Unlock()
+
+	p.logger.WithFields(logrus.Fields{
+		"id":         node.ID,
+		"name":       node.Name,
+		"ip_address": node.IPAddress,
+	}).Info("Node phoned home")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"id": node.ID})
+}
+
+// parsePhoneHomeRequest reads a JSON or form body
+func parsePhoneHomeRequest(r *http.Request) (*phoneHomeRequest, error) {
+	req := &phoneHomeRequest{}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("invalid form body: %w", err)
+		}
+		req.InstanceID = r.PostForm.Get("instance_id")
+		req.Hostname = r.PostForm.Get("hostname")
+		req.FQDN = r.PostForm.Get("fqdn")
+		req.IPAddress = r.PostForm.Get("ip_address")
+		req.MACAddress = r.PostForm.Get("mac_address")
+		req.SerialNumber = r.PostForm.Get("serial_number")
+		req.Model = r.PostForm.Get("model")
+		req.Capabilities = parseCapabilities(r.PostForm.Get("capabilities"))
+	}
+
+	if req.InstanceID == "" {
+		req.InstanceID = r.PathValue("instance_id")
+	}
+	return req, nil
+}
+
+// node converts the request into a node. The MAC address, serial number or
+// instance ID identifies it; the address defaults to the request's source.
+func (req *phoneHomeRequest) node(remoteAddr string, now time.Time) (Node, error) {
+	mac := ""
+	if req.MACAddress != "" {
+		if mac = normalizeMAC(req.MACAddress); mac == "" {
+			return Node{}, fmt.Errorf("invalid MAC address %q", req.MACAddress)
+		}
+	}
+
+	id := mac
+	if id == "" {
+		id = req.SerialNumber
+	}
+	if id == "" {
+		id = req.InstanceID
+	}
+	if id == "" {
+		return Node{}, fmt.Errorf("one of mac_address, serial_number or instance_id is required")
+	}
+
+	ip := req.IPAddress
+	if ip != "" && net.ParseIP(ip) == nil {
+		return Node{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	if ip == "" {
+		host, _, err := net.SplitHostPort(remoteAddr)
+		if err != nil {
+			host = remoteAddr
+		}
+		ip = host
+	}
+
+	name := req.Hostname
+	if name == "" {
+		name, _, _ = strings.Cut(req.FQDN, ".")
+	}
+
+	txt := make(map[string]string)
+	for key, value := range map[string]string{"instance_id": req.InstanceID, "fqdn": req.FQDN, TXTModel: req.Model} {
+		if value != "" {
+			txt[key] = value
+		}
+	}
+
+	capabilities := req.Capabilities
+	if capabilities == nil {
+		capabilities = []string{}
+	}
+
+	return Node{
+		ID:           "phone-home-" + id,
+		Name:         name,
+		IPAddress:    ip,
+		ServiceType:  MethodPhoneHome,
+		TXTRecords:   txt,
+		LastSeen:     now,
+		Capabilities: capabilities,
+		MACAddress:   mac,
+		SerialNumber: req.SerialNumber,
+	}, nil
+}
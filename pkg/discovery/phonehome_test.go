@@ -0,0 +1,117 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneHome(t *testing.T) {
+	phoneHome := NewPhoneHome(":0", logrus.New())
+	server := httptest.NewServer(phoneHome)
+	defer server.Close()
+
+	// A first-boot script posting JSON
+	resp, err := http.Post(server.URL+"/phone-home", "application/json", strings.NewReader(`{
+		"hostname": "raspberrypi",
+		"mac_address": "DC:A6:32:00:00:01",
+		"serial_number": "10000000abcdef01",
+		"ip_address": "192.168.1.10",
+		"model": "Raspberry Pi 5 Model B Rev 1.0",
+		"capabilities": ["gpio"]
+	}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	// cloud-init's phone_home module posting a form with $INSTANCE_ID in the URL
+	resp, err = http.PostForm(server.URL+"/phone-home/iid-pi-0042", url.Values{
+		"hostname":    {"pi-0042"},
+		"fqdn":        {"pi-0042.lab.example.com"},
+		"pub_key_rsa": {"ssh-rsa AAAA"},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	nodes, err := phoneHome.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	byID := map[string]Node{nodes[0].ID: nodes[0], nodes[1].ID: nodes[1]}
+
+	pi := byID["phone-home-dc:a6:32:00:00:01"]
+	assert.Equal(t, "raspberrypi", pi.Name)
+	assert.Equal(t, "192.168.1.10", pi.IPAddress)
+	assert.Equal(t, "dc:a6:32:00:00:01", pi.MACAddress)
+	assert.Equal(t, "10000000abcdef01", pi.SerialNumber)
+	assert.Equal(t, "Raspberry Pi 5 Model B Rev 1.0", pi.TXTRecords[TXTModel])
+	assert.Equal(t, []string{"gpio"}, pi.Capabilities)
+	assert.Equal(t, MethodPhoneHome, pi.ServiceType)
+
+	cloudInit := byID["phone-home-iid-pi-0042"]
+	assert.Equal(t, "pi-0042", cloudInit.Name)
+	assert.Equal(t, "127.0.0.1", cloudInit.IPAddress, "the address defaults to the request's source")
+	assert.Equal(t, "iid-pi-0042", cloudInit.TXTRecords["instance_id"])
+	assert.Equal(t, "pi-0042.lab.example.com", cloudInit.TXTRecords["fqdn"])
+
+	nodes, err = phoneHome.Discover(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, nodes, "each identity is reported once")
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+	}{
+		{"no identity", "/phone-home", "application/json", `{"hostname": "raspberrypi"}`},
+		{"invalid MAC address", "/phone-home", "application/x-www-form-urlencoded", "mac_address=dc:a6"},
+		{"invalid IP address", "/phone-home", "application/json", `{"serial_number": "1", "ip_address": "pi"}`},
+		{"invalid JSON", "/phone-home", "application/json", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+tt.path, tt.contentType, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+
+	resp, err = http.Get(server.URL + "/phone-home")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestPhoneHome_PendingLimit(t *testing.T) {
+	phoneHome := NewPhoneHome(":0", logrus.New())
+	server := httptest.NewServer(phoneHome)
+	defer server.Close()
+
+	post := func(serial string) int {
+		resp, err := http.PostForm(server.URL+"/phone-home", url.Values{"serial_number": {serial}})
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for i := 0; i < maxPhoneHomePending; i++ {
+		require.Equal(t, http.StatusAccepted, post(fmt.Sprint(i)))
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, post("one-too-many"))
+	assert.Equal(t, http.StatusAccepted, post("0"), "pending nodes may report again")
+
+	nodes, err := phoneHome.Discover(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, nodes, maxPhoneHomePending)
+	assert.Equal(t, http.StatusAccepted, post("one-too-many"), "discovery drains the pending reports")
+}
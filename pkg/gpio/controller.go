@@ -58,6 +58,7 @@ type Controller struct {
 	logger         *logrus.Entry
 	available      bool
 	activePins     map[int]*PinState
+	configs        map[int]PinConfig // configuration applied to each pin
 	activeOps      int
 	mutex          sync.RWMutex
 	opMutex        sync.Mutex
@@ -77,6 +78,7 @@ func NewController(config *Config, securityConfig *SecurityConfig, logger *logru
 		securityConfig: securityConfig,
 		logger:         logger.WithField("component", "gpio"),
 		activePins:     make(map[int]*PinState),
+		configs:        make(map[int]PinConfig),
 	}
 
 	// Initialize with enhanced security defaults
@@ -292,6 +294,19 @@ func (c *Controller) ConfigurePin(config PinConfig, userID string) error {
 		}
 	}
 
+	// Reconfiguring resets outputs low and stops edge detection, so a pin
+	// already configured the same way is left alone
+	c.mutex.RLock()
+	current, configured := c.configs[config.Pin]
+	c.mutex.RUnlock()
+	if configured && current == config {
+		c.logger.WithFields(logrus.Fields{
+			"pin":     config.Pin,
+			"user_id": userID,
+		}).Debug("GPIO pin already configured")
+		return nil
+	}
+
 	if err := c.impl.ConfigurePin(config); err != nil {
 		c.logger.WithFields(logrus.Fields{
 			"pin":       config.Pin,
@@ -305,6 +320,7 @@ func (c *Controller) ConfigurePin(config PinConfig, userID string) error {
 
 	// Track active pin
 	c.mutex.Lock()
+	c.configs[config.Pin] = config
 	c.activePins[config.Pin] = &PinState{
 		Pin:       config.Pin,
 		Direction: config.Direction,
@@ -453,6 +469,16 @@ func (c *Controller) SetPWM(pin int, frequency int, dutyCycle int, userID string
 		return fmt.Errorf("failed to set PWM on pin %d: %w", pin, err)
 	}
 
+	// Keep the recorded configuration in step, so configuring the pin with
+	// its new PWM settings does not reconfigure it
+	c.mutex.Lock()
+	if config, exists := c.configs[pin]; exists {
+		config.PWMFrequency = frequency
+		config.PWMDutyCycle = dutyCycle
+		c.configs[pin] = config
+	}
+	c.mutex.Unlock()
+
 	c.logger.WithFields(logrus.Fields{
 		"pin":        pin,
 		"frequency":  frequency,
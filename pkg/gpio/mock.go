@@ -78,6 +78,10 @@ func (m *MockGPIO) ConfigurePin(config PinConfig) error {
 		timestamp: time.Now(),
 	}
 	
+	// Like the hardware, a reconfigured pin no longer detects edges
+	delete(m.eventHandlers, config.Pin)
+	delete(m.eventTypes, config.Pin)
+	
 	return nil
 }
 
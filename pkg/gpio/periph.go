@@ -31,6 +31,10 @@ type pinState struct {
 	config    PinConfig
 	lastRead  time.Time
 	lastValue PinValue
+
+	// interruptGen changes whenever interrupts are enabled or disabled so a
+	// superseded monitor goroutine knows to exit
+	interruptGen uint64
 }
 
 // pwmState tracks PWM configuration
@@ -488,8 +492,9 @@ func (p *PeriphGPIO) EnableInterrupt(pin int, eventType EventType, handler Event
 		return fmt.Errorf("failed to configure interrupt on pin %d: %w", pin, err)
 	}
 
-	// Start monitoring in a goroutine
-	go p.monitorPin(pin, eventType, handler)
+	// Start monitoring in a goroutine, replacing any previous monitor
+	state.interruptGen++
+	go p.monitorPin(pin, state.interruptGen, eventType, handler)
 
 	p.logger.WithFields(logrus.Fields{
 		"pin":        pin,
@@ -509,6 +514,7 @@ func (p *PeriphGPIO) DisableInterrupt(pin int) error {
 	}
 
 	// Disable edge detection
+	state.interruptGen++
 	if err := state.pin.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
 		return fmt.Errorf("failed to disable interrupt on pin %d: %w", pin, err)
 	}
@@ -517,16 +523,19 @@ func (p *PeriphGPIO) DisableInterrupt(pin int) error {
 	return nil
 }
 
-// monitorPin monitors a pin for events
-func (p *PeriphGPIO) monitorPin(pinNum int, eventType EventType, handler EventHandler) {
+// monitorPin monitors a pin for events until the pin is released or its
+// interrupt is disabled or re-enabled
+func (p *PeriphGPIO) monitorPin(pinNum int, gen uint64, eventType EventType, handler EventHandler) {
+	p.mutex.RLock()
 	state := p.pins[pinNum]
+	p.mutex.RUnlock()
 	if state == nil {
 		return
 	}
 
 	for {
-		// Wait for edge
-		if state.pin.WaitForEdge(-1) {
+		// Wait for edge, waking periodically to notice the interrupt was disabled
+		if state.pin.WaitForEdge(time.Second) {
 			value := Low
 			if state.pin.Read() == gpio.High {
 				value = High
@@ -545,10 +554,11 @@ func (p *PeriphGPIO) monitorPin(pinNum int, eventType EventType, handler EventHa
 
 		// Check if we should continue monitoring
 		p.mutex.RLock()
-		_, exists := p.pins[pinNum]
+		current, exists := p.pins[pinNum]
+		active := exists && current == state && state.interruptGen == gen
 		p.mutex.RUnlock()
 
-		if !exists {
+		if !active {
 			break
 		}
 	}
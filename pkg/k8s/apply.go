@@ -0,0 +1,358 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// DefaultFieldManager is the field manager server-side apply records for
+// fields set by the controller
+const DefaultFieldManager = "pi-controller"
+
+// ErrInvalidManifest is returned when a manifest cannot be decoded into
+// Kubernetes objects
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// ApplyAction is what applying a manifest did to an object
+type ApplyAction string
+
+const (
+	ApplyCreated    ApplyAction = "created"
+	ApplyConfigured ApplyAction = "configured"
+	ApplyUnchanged  ApplyAction = "unchanged"
+	ApplyPruned     ApplyAction = "pruned"
+	ApplyFailed     ApplyAction = "failed"
+)
+
+// ApplyOptions controls how manifests are applied
+type ApplyOptions struct {
+	// FieldManager owns the applied fields; defaults to DefaultFieldManager
+	FieldManager string
+	// Force takes ownership of fields other managers have set instead of
+	// failing with a conflict
+	Force bool
+	// DryRun has the API server validate and admit every change without
+	// persisting it
+	DryRun bool
+	// PruneSelector, if set, deletes the objects matching this label selector
+	// that are of a kind and in a namespace the manifests apply to but are
+	// not in the manifests themselves
+	PruneSelector string
+}
+
+// ApplyResult reports what happened to one object
+type ApplyResult struct {
+	APIVersion string      `json:"api_version"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	Action     ApplyAction `json:"action"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// SetDynamicClient sets the dynamic client and REST mapper used to apply
+// manifests, such as fakes in tests
+func (c *Client) SetDynamicClient(dynamicClient dynamic.Interface, mapper meta.RESTMapper) {
+	c.dynamic = dynamicClient
+	c.mapper = mapper
+}
+
+// initDynamicClient builds the dynamic client and a discovery-backed REST
+// mapper from the client's REST config
+func (c *Client) initDynamicClient(restConfig *rest.Config, clientset kubernetes.Interface) error {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+	c.SetDynamicClient(dynamicClient, mapper)
+	return nil
+}
+
+// Apply server-side applies every object in a stream of YAML or JSON
+// documents, in order, and reports what happened to each. Objects without a
+// namespace go to the client's namespace. An object that fails to apply does
+// not stop the rest, but it does skip pruning, so that a failed replacement
+// never leaves its predecessor deleted.
+func (c *Client) Apply(ctx context.Context, manifests []byte, opts ApplyOptions) ([]ApplyResult, error) {
+	if c.dynamic == nil || c.mapper == nil {
+		return nil, fmt.Errorf("client has no dynamic client configured")
+	}
+
+	var pruneSelector string
+	if opts.PruneSelector != "" {
+		selector, err := metav1.ParseToLabelSelector(opts.PruneSelector)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid prune selector: %v", ErrInvalidManifest, err)
+		}
+		if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+			return nil, fmt.Errorf("%w: prune selector must not be empty", ErrInvalidManifest)
+		}
+		pruneSelector = opts.PruneSelector
+	}
+
+	objects, err := decodeManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.FieldManager == "" {
+		opts.FieldManager = DefaultFieldManager
+	}
+	var dryRun []string
+	if opts.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	results := make([]ApplyResult, 0, len(objects))
+	applied := make(map[pruneScope]map[string]bool)
+	var scopes []pruneScope
+	failed := false
+
+	for _, obj := range objects {
+		result, scope, err := c.applyObject(ctx, obj, opts, dryRun)
+		if err != nil {
+			result.Action = ApplyFailed
+			result.Error = err.Error()
+			failed = true
+		} else {
+			if applied[scope] == nil {
+				applied[scope] = make(map[string]bool)
+				scopes = append(scopes, scope)
+			}
+			applied[scope][result.Name] = true
+		}
+		results = append(results, result)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"objects": len(objects),
+		"dry_run": opts.DryRun,
+	}).Info("Applied manifests")
+
+	if pruneSelector == "" {
+		return results, nil
+	}
+	if failed {
+		c.logger.Warn("Skipping prune because some objects failed to apply")
+		return results, nil
+	}
+
+	for _, scope := range scopes {
+		pruned, err := c.prune(ctx, scope, applied[scope], pruneSelector, dryRun)
+		results = append(results, pruned...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// pruneScope is a resource in one namespace, or cluster-wide if the
+// namespace is empty
+type pruneScope struct {
+	resource  schema.GroupVersionResource
+	kind      schema.GroupVersionKind
+	namespace string
+}
+
+// applyObject applies one object, returning its result and the scope it was
+// applied in
+func (c *Client) applyObject(ctx context.Context, obj *unstructured.Unstructured, opts ApplyOptions, dryRun []string) (ApplyResult, pruneScope, error) {
+	gvk := obj.GroupVersionKind()
+	result := ApplyResult{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+
+	mapping, err := c.restMapping(gvk)
+	if err != nil {
+		return result, pruneScope{}, err
+	}
+
+	scope := pruneScope{resource: mapping.Resource, kind: gvk}
+	var resource dynamic.ResourceInterface = c.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(c.namespace)
+		}
+		scope.namespace = obj.GetNamespace()
+		resource = c.dynamic.Resource(mapping.Resource).Namespace(scope.namespace)
+	} else {
+		obj.SetNamespace("")
+	}
+	result.Namespace = scope.namespace
+
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return result, scope, err
+	}
+
+	appliedObj, err := resource.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: opts.FieldManager,
+		Force:        opts.Force,
+		DryRun:       dryRun,
+	})
+	if err != nil {
+		return result, scope, err
+	}
+
+	switch {
+	case existing == nil:
+		result.Action = ApplyCreated
+	case sameObject(existing, appliedObj):
+		result.Action = ApplyUnchanged
+	default:
+		result.Action = ApplyConfigured
+	}
+	return result, scope, nil
+}
+
+// restMapping resolves the resource of a kind. Kinds the mapper does not know
+// may belong to a CustomResourceDefinition applied earlier in the same
+// manifests, so the mapper is refreshed once before giving up.
+func (c *Client) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil && meta.IsNoMatchError(err) {
+		if resettable, ok := c.mapper.(meta.ResettableRESTMapper); ok {
+			resettable.Reset()
+			mapping, err = c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	return mapping, err
+}
+
+// prune deletes the objects in a scope that match the selector but were not
+// applied
+func (c *Client) prune(ctx context.Context, scope pruneScope, applied map[string]bool, selector string, dryRun []string) ([]ApplyResult, error) {
+	var resource dynamic.ResourceInterface = c.dynamic.Resource(scope.resource)
+	if scope.namespace != "" {
+		resource = c.dynamic.Resource(scope.resource).Namespace(scope.namespace)
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s for pruning: %w", scope.resource.Resource, err)
+	}
+
+	var results []ApplyResult
+	propagation := metav1.DeletePropagationBackground
+	for _, item := range list.Items {
+		if applied[item.GetName()] || item.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		result := ApplyResult{
+			APIVersion: scope.kind.GroupVersion().String(),
+			Kind:       scope.kind.Kind,
+			Namespace:  scope.namespace,
+			Name:       item.GetName(),
+			Action:     ApplyPruned,
+		}
+		err := resource.Delete(ctx, item.GetName(), metav1.DeleteOptions{
+			DryRun:            dryRun,
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			result.Action = ApplyFailed
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// decodeManifests splits a stream of YAML or JSON documents into objects,
+// expanding List kinds and skipping empty documents
+func decodeManifests(manifests []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+
+	var objects []*unstructured.Unstructured
+	for document := 1; ; document++ {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidManifest, document, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidManifest, document, err)
+			}
+			for i := range list.Items {
+				if err := validateObject(&list.Items[i]); err != nil {
+					return nil, fmt.Errorf("%w: document %d item %d: %v", ErrInvalidManifest, document, i+1, err)
+				}
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+
+		if err := validateObject(obj); err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidManifest, document, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("%w: no objects found", ErrInvalidManifest)
+	}
+	return objects, nil
+}
+
+func validateObject(obj *unstructured.Unstructured) error {
+	switch {
+	case obj.GetAPIVersion() == "":
+		return fmt.Errorf("apiVersion is required")
+	case obj.GetKind() == "":
+		return fmt.Errorf("kind is required")
+	case obj.GetName() == "":
+		return fmt.Errorf("%s has no metadata.name", obj.GetKind())
+	}
+	return nil
+}
+
+// sameObject reports whether applying changed an object, ignoring the
+// metadata the API server maintains and the status controllers update
+func sameObject(before, after *unstructured.Unstructured) bool {
+	strip := func(obj *unstructured.Unstructured) map[string]interface{} {
+		content := obj.DeepCopy().Object
+		delete(content, "status")
+		if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "resourceVersion")
+			delete(metadata, "generation")
+			delete(metadata, "managedFields")
+		}
+		return content
+	}
+	return equality.Semantic.DeepEqual(strip(before), strip(after))
+}
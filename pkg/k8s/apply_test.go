@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	configMapsResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	namespacesResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
+
+// applyReactor stands in for server-side apply, which the fake dynamic
+// client's tracker only supports for objects that already exist, by storing
+// the applied object as is
+func applyReactor(tracker k8stesting.ObjectTracker) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+
+		gvr, namespace := patch.GetResource(), patch.GetNamespace()
+		_, err := tracker.Get(gvr, namespace, patch.GetName())
+		switch {
+		case apierrors.IsNotFound(err):
+			err = tracker.Create(gvr, obj, namespace)
+		case err == nil:
+			err = tracker.Update(gvr, obj, namespace)
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		stored, err := tracker.Get(gvr, namespace, patch.GetName())
+		return true, stored, err
+	}
+}
+
+func testConfigMap(namespace, name, value string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"data":       map[string]interface{}{"value": value},
+	}}
+	obj.SetLabels(labels)
+	return obj
+}
+
+func setupApplyClient(objects ...runtime.Object) (*Client, *dynamicfake.FakeDynamicClient) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMapsResource: "ConfigMapList",
+		namespacesResource: "NamespaceList",
+	}, objects...)
+	dynamicClient.PrependReactor("patch", "*", applyReactor(dynamicClient.Tracker()))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	client := NewClientForClientset(fake.NewSimpleClientset(), logrus.New())
+	client.SetDynamicClient(dynamicClient, mapper)
+	return client, dynamicClient
+}
+
+const applyManifests = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  labels:
+    app: web
+data:
+  value: "2"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: theme
+  namespace: apps
+  labels:
+    app: web
+data:
+  value: dark
+`
+
+func TestApply(t *testing.T) {
+	web := map[string]string{"app": "web"}
+
+	t.Run("creates, configures and leaves objects unchanged", func(t *testing.T) {
+		client, dynamicClient := setupApplyClient(
+			testConfigMap("default", "settings", "1", web),
+			testConfigMap("apps", "theme", "dark", web),
+		)
+
+		results, err := client.Apply(context.Background(), []byte(applyManifests), ApplyOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []ApplyResult{
+			{APIVersion: "v1", Kind: "Namespace", Name: "apps", Action: ApplyCreated},
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings", Action: ApplyConfigured},
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "apps", Name: "theme", Action: ApplyUnchanged},
+		}, results)
+
+		settings, err := dynamicClient.Resource(configMapsResource).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+		require.NoError(t, err)
+		value, _, _ := unstructured.NestedString(settings.Object, "data", "value")
+		assert.Equal(t, "2", value)
+	})
+
+	t.Run("prunes labelled objects missing from the manifests", func(t *testing.T) {
+		client, dynamicClient := setupApplyClient(
+			testConfigMap("default", "legacy", "1", web),
+			testConfigMap("default", "unrelated", "1", map[string]string{"app": "db"}),
+			testConfigMap("other", "elsewhere", "1", web),
+		)
+
+		results, err := client.Apply(context.Background(), []byte(applyManifests), ApplyOptions{PruneSelector: "app=web"})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, ApplyResult{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "legacy", Action: ApplyPruned}, results[3])
+
+		configMaps := dynamicClient.Resource(configMapsResource)
+		_, err = configMaps.Namespace("default").Get(context.Background(), "legacy", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		_, err = configMaps.Namespace("default").Get(context.Background(), "unrelated", metav1.GetOptions{})
+		assert.NoError(t, err, "objects not matching the selector are kept")
+		_, err = configMaps.Namespace("other").Get(context.Background(), "elsewhere", metav1.GetOptions{})
+		assert.NoError(t, err, "namespaces the manifests do not touch are kept")
+	})
+
+	t.Run("passes dry run on to the API server", func(t *testing.T) {
+		client, dynamicClient := setupApplyClient(testConfigMap("default", "legacy", "1", web))
+		dynamicClient.PrependReactor("delete", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			options := action.(k8stesting.DeleteActionImpl).DeleteOptions
+			assert.Equal(t, []string{metav1.DryRunAll}, options.DryRun)
+			return true, nil, nil
+		})
+
+		results, err := client.Apply(context.Background(), []byte(applyManifests), ApplyOptions{DryRun: true, PruneSelector: "app=web"})
+		require.NoError(t, err)
+		assert.Equal(t, ApplyPruned, results[len(results)-1].Action)
+	})
+
+	t.Run("reports kinds the cluster does not serve and skips pruning", func(t *testing.T) {
+		client, dynamicClient := setupApplyClient(testConfigMap("default", "legacy", "1", web))
+		manifests := applyManifests + "---\napiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: gear\n"
+
+		results, err := client.Apply(context.Background(), []byte(manifests), ApplyOptions{PruneSelector: "app=web"})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, ApplyFailed, results[3].Action)
+		assert.NotEmpty(t, results[3].Error)
+
+		_, err = dynamicClient.Resource(configMapsResource).Namespace("default").Get(context.Background(), "legacy", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects invalid manifests", func(t *testing.T) {
+		client, _ := setupApplyClient()
+
+		for _, manifests := range []string{
+			"",
+			"---\n---\n",
+			"apiVersion: v1\nkind: ConfigMap\nmetadata: {}\n",
+			"kind: ConfigMap\nmetadata:\n  name: x\n",
+			"apiVersion: v1\nkind: [\n",
+		} {
+			_, err := client.Apply(context.Background(), []byte(manifests), ApplyOptions{})
+			assert.ErrorIs(t, err, ErrInvalidManifest, manifests)
+		}
+
+		_, err := client.Apply(context.Background(), []byte(applyManifests), ApplyOptions{PruneSelector: "app in ("})
+		assert.ErrorIs(t, err, ErrInvalidManifest)
+	})
+}
+
+func TestDecodeManifestsExpandsLists(t *testing.T) {
+	objects, err := decodeManifests([]byte(`{
+		"apiVersion": "v1",
+		"kind": "List",
+		"items": [
+			{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}},
+			{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "b"}}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "b", objects[1].GetName())
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -19,6 +21,8 @@ import (
 // Client wraps the Kubernetes client with additional functionality
 type Client struct {
 	clientset kubernetes.Interface
+	dynamic   dynamic.Interface
+	mapper    meta.RESTMapper
 	config    *rest.Config
 	logger    *logrus.Entry
 	namespace string
@@ -86,10 +90,44 @@ func NewClient(config *Config, logger *logrus.Logger) (*Client, error) {
 		logger:    logger.WithField("component", "k8s-client"),
 		namespace: config.Namespace,
 	}
+	if err := client.initDynamicClient(restConfig, clientset); err != nil {
+		return nil, err
+	}
 
 	return client, nil
 }
 
+// NewClientFromKubeConfig creates a client from the contents of a kubeconfig
+// file, using its current context
+func NewClientFromKubeConfig(kubeConfig []byte, logger *logrus.Logger) (*Client, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	client := NewClientForClientset(clientset, logger)
+	client.config = restConfig
+	if err := client.initDynamicClient(restConfig, clientset); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewClientForClientset creates a client around an existing clientset, such as
+// a fake one in tests
+func NewClientForClientset(clientset kubernetes.Interface, logger *logrus.Logger) *Client {
+	return &Client{
+		clientset: clientset,
+		logger:    logger.WithField("component", "k8s-client"),
+		namespace: DefaultConfig().Namespace,
+	}
+}
+
 // HealthCheck performs a basic health check against the Kubernetes API
 func (c *Client) HealthCheck(ctx context.Context) error {
 	_, err := c.clientset.Discovery().ServerVersion()
@@ -111,6 +149,7 @@ func (c *Client) GetServerVersion(ctx context.Context) (string, error) {
 // NodeInfo represents information about a Kubernetes node
 type NodeInfo struct {
 	Name         string            `json:"name"`
+	InternalIP   string            `json:"internal_ip"`
 	Ready        bool              `json:"ready"`
 	Version      string            `json:"version"`
 	OS           string            `json:"os"`
@@ -183,6 +222,14 @@ func (c *Client) convertNodeToInfo(node corev1.Node) NodeInfo {
 		}
 	}
 
+	internalIP := ""
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			internalIP = address.Address
+			break
+		}
+	}
+
 	// Extract roles from labels
 	roles := []string{}
 	if _, exists := node.Labels["node-role.kubernetes.io/master"]; exists {
@@ -209,6 +256,7 @@ func (c *Client) convertNodeToInfo(node corev1.Node) NodeInfo {
 
 	return NodeInfo{
 		Name:         node.Name,
+		InternalIP:   internalIP,
 		Ready:        ready,
 		Version:      node.Status.NodeInfo.KubeletVersion,
 		OS:           node.Status.NodeInfo.OperatingSystem,
@@ -329,24 +377,6 @@ func (c *Client) ListPodsOnNode(ctx context.Context, nodeName string) ([]PodInfo
 	return podInfos, nil
 }
 
-// DrainNode cordons and drains a node for maintenance
-func (c *Client) DrainNode(ctx context.Context, nodeName string) error {
-	// First, cordon the node
-	if err := c.CordonNode(ctx, nodeName); err != nil {
-		return fmt.Errorf("failed to cordon node: %w", err)
-	}
-
-	// TODO: Implement pod eviction logic
-	// This would typically involve:
-	// 1. Getting all pods on the node
-	// 2. Evicting pods that can be safely evicted
-	// 3. Waiting for pods to be terminated
-	// 4. Handling pods that cannot be evicted
-
-	c.logger.WithField("node_name", nodeName).Info("Node drained successfully")
-	return nil
-}
-
 // CordonNode marks a node as unschedulable
 func (c *Client) CordonNode(ctx context.Context, nodeName string) error {
 	node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
@@ -0,0 +1,199 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// mirrorPodAnnotation marks static pods the kubelet mirrors into the API server
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// DrainOptions controls how pods are removed from a node
+type DrainOptions struct {
+	// Timeout bounds the whole drain, including waiting for evicted pods to
+	// terminate. Zero uses DefaultDrainTimeout.
+	Timeout time.Duration
+	// GracePeriodSeconds overrides each pod's termination grace period when set
+	GracePeriodSeconds *int64
+	// Force deletes pods instead of evicting them, bypassing
+	// PodDisruptionBudgets
+	Force bool
+	// PollInterval is how often blocked evictions are retried and terminating
+	// pods are checked. Zero uses DefaultDrainPollInterval.
+	PollInterval time.Duration
+}
+
+const (
+	DefaultDrainTimeout      = 5 * time.Minute
+	DefaultDrainPollInterval = 5 * time.Second
+)
+
+// DrainNode cordons a node and evicts its pods, retrying evictions that a
+// PodDisruptionBudget blocks until the timeout. DaemonSet pods, mirror pods
+// and pods that have already completed are left in place.
+func (c *Client) DrainNode(ctx context.Context, nodeName string, opts DrainOptions) error {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDrainTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultDrainPollInterval
+	}
+
+	if err := c.CordonNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed to cordon node: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	pods, err := c.podsToDrain(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	log := c.logger.WithFields(logrus.Fields{
+		"node_name": nodeName,
+		"pods":      len(pods),
+		"force":     opts.Force,
+	})
+	log.Info("Draining node")
+
+	for _, pod := range pods {
+		if err := c.removePod(ctx, pod, opts); err != nil {
+			return fmt.Errorf("failed to drain node %s: %w", nodeName, err)
+		}
+	}
+	for _, pod := range pods {
+		if err := c.waitForPodDeletion(ctx, pod, opts.PollInterval); err != nil {
+			return fmt.Errorf("failed to drain node %s: %w", nodeName, err)
+		}
+	}
+
+	log.Info("Node drained successfully")
+	return nil
+}
+
+// DeleteNode removes a node from the API server. A node that does not exist
+// is not an error.
+func (c *Client) DeleteNode(ctx context.Context, nodeName string) error {
+	err := c.clientset.CoreV1().Nodes().Delete(ctx, nodeName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		c.logger.WithFields(logrus.Fields{
+			"node_name": nodeName,
+			"error":     err,
+		}).Error("Failed to delete node")
+		return fmt.Errorf("failed to delete node: %w", err)
+	}
+
+	c.logger.WithField("node_name", nodeName).Info("Node deleted successfully")
+	return nil
+}
+
+// podsToDrain returns the pods on a node that must be removed before it can
+// be taken out of the cluster
+func (c *Client) podsToDrain(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	list, err := c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node: %w", err)
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range list.Items {
+		switch {
+		case pod.Spec.NodeName != nodeName:
+		case pod.DeletionTimestamp != nil:
+			// Already terminating; waited for below like any evicted pod
+			pods = append(pods, pod)
+		case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed:
+		case pod.Annotations[mirrorPodAnnotation] != "":
+		case isDaemonSetPod(pod):
+		default:
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func isDaemonSetPod(pod corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// removePod evicts or, when forced, deletes a pod. Evictions refused because
+// of a PodDisruptionBudget are retried until ctx expires.
+func (c *Client) removePod(ctx context.Context, pod corev1.Pod, opts DrainOptions) error {
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+
+	deleteOptions := metav1.DeleteOptions{
+		GracePeriodSeconds: opts.GracePeriodSeconds,
+		Preconditions:      &metav1.Preconditions{UID: &pod.UID},
+	}
+
+	for {
+		var err error
+		if opts.Force {
+			err = c.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOptions)
+		} else {
+			err = c.clientset.CoreV1().Pods(pod.Namespace).EvictV1(ctx, &policyv1.Eviction{
+				ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+				DeleteOptions: &deleteOptions,
+			})
+		}
+
+		switch {
+		case err == nil, apierrors.IsNotFound(err), apierrors.IsConflict(err):
+			// A conflict means the UID precondition failed: the pod was
+			// already replaced
+			return nil
+		case apierrors.IsTooManyRequests(err):
+			c.logger.WithFields(logrus.Fields{
+				"namespace": pod.Namespace,
+				"pod":       pod.Name,
+			}).Debug("Eviction blocked by a disruption budget, retrying")
+		default:
+			return fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out evicting pod %s/%s: a disruption budget does not allow it", pod.Namespace, pod.Name)
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
+
+// waitForPodDeletion waits until a pod no longer exists or has been replaced
+// by one with a different UID
+func (c *Client) waitForPodDeletion(ctx context.Context, pod corev1.Pod, interval time.Duration) error {
+	for {
+		current, err := c.clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to get pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pod %s/%s to terminate", pod.Namespace, pod.Name)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// evictions makes a fake clientset honour evictions, refusing the given
+// number of attempts per pod as a PodDisruptionBudget would
+type evictions struct {
+	mu      sync.Mutex
+	blocked map[string]int
+	evicted []string
+}
+
+func (e *evictions) install(clientset *fake.Clientset) {
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.blocked[eviction.Name] != 0 {
+			e.blocked[eviction.Name]--
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 1)
+		}
+		e.evicted = append(e.evicted, eviction.Name)
+		err := clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+		return true, nil, err
+	})
+}
+
+func drainTestPod(name, nodeName string, mutate func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func setupDrainTest(t *testing.T, blocked map[string]int) (*Client, *fake.Clientset, *evictions) {
+	controller := true
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pi-worker"}},
+		drainTestPod("web", "pi-worker", nil),
+		drainTestPod("db", "pi-worker", nil),
+		drainTestPod("elsewhere", "pi-other", nil),
+		drainTestPod("flannel", "pi-worker", func(pod *corev1.Pod) {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "flannel", Controller: &controller}}
+		}),
+		drainTestPod("kube-vip", "pi-worker", func(pod *corev1.Pod) {
+			pod.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+		}),
+		drainTestPod("job", "pi-worker", func(pod *corev1.Pod) {
+			pod.Status.Phase = corev1.PodSucceeded
+		}),
+	)
+	ev := &evictions{blocked: blocked}
+	ev.install(clientset)
+	return NewClientForClientset(clientset, logrus.New()), clientset, ev
+}
+
+func TestDrainNode(t *testing.T) {
+	t.Run("evicts workload pods and cordons the node", func(t *testing.T) {
+		client, clientset, ev := setupDrainTest(t, nil)
+
+		err := client.DrainNode(context.Background(), "pi-worker", DrainOptions{PollInterval: time.Millisecond})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"web", "db"}, ev.evicted)
+		node, err := clientset.CoreV1().Nodes().Get(context.Background(), "pi-worker", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.True(t, node.Spec.Unschedulable)
+
+		pods, err := clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, pods.Items, 4, "DaemonSet, mirror, completed and other nodes' pods are kept")
+	})
+
+	t.Run("retries evictions blocked by a disruption budget", func(t *testing.T) {
+		client, _, ev := setupDrainTest(t, map[string]int{"db": 3})
+
+		err := client.DrainNode(context.Background(), "pi-worker", DrainOptions{PollInterval: time.Millisecond})
+		require.NoError(t, err)
+		assert.Contains(t, ev.evicted, "db")
+	})
+
+	t.Run("times out when a disruption budget never allows eviction", func(t *testing.T) {
+		client, _, _ := setupDrainTest(t, map[string]int{"db": -1})
+
+		err := client.DrainNode(context.Background(), "pi-worker", DrainOptions{
+			Timeout:      50 * time.Millisecond,
+			PollInterval: time.Millisecond,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "default/db")
+	})
+
+	t.Run("force deletes pods without evicting them", func(t *testing.T) {
+		client, clientset, ev := setupDrainTest(t, map[string]int{"db": -1})
+
+		err := client.DrainNode(context.Background(), "pi-worker", DrainOptions{Force: true, PollInterval: time.Millisecond})
+		require.NoError(t, err)
+		assert.Empty(t, ev.evicted)
+
+		_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "db", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestDeleteNode(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pi-worker"}})
+	client := NewClientForClientset(clientset, logrus.New())
+
+	require.NoError(t, client.DeleteNode(context.Background(), "pi-worker"))
+	_, err := clientset.CoreV1().Nodes().Get(context.Background(), "pi-worker", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	assert.NoError(t, client.DeleteNode(context.Background(), "pi-worker"), "deleting a missing node succeeds")
+}
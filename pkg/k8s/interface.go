@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -12,4 +13,42 @@ type K8sClient interface {
 	GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error)
 	UpdatePod(ctx context.Context, namespace string, pod *corev1.Pod) (*corev1.Pod, error)
 	DeletePod(ctx context.Context, namespace, name string) error
+
+	CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error)
+	GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error)
+	ListDeployments(ctx context.Context, namespace string) ([]appsv1.Deployment, error)
+	UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error)
+	DeleteDeployment(ctx context.Context, namespace, name string) error
+	GetRolloutStatus(ctx context.Context, namespace, name string) (*RolloutStatus, error)
+	RollbackDeployment(ctx context.Context, namespace, name string, revision int64) (*appsv1.Deployment, error)
+
+	CreateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error)
+	GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error)
+	ListDaemonSets(ctx context.Context, namespace string) ([]appsv1.DaemonSet, error)
+	UpdateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error)
+	DeleteDaemonSet(ctx context.Context, namespace, name string) error
+
+	CreateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error)
+	GetService(ctx context.Context, namespace, name string) (*corev1.Service, error)
+	ListServices(ctx context.Context, namespace string) ([]corev1.Service, error)
+	UpdateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error)
+	DeleteService(ctx context.Context, namespace, name string) error
+
+	CreateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+	ListConfigMaps(ctx context.Context, namespace string) ([]corev1.ConfigMap, error)
+	UpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	DeleteConfigMap(ctx context.Context, namespace, name string) error
+
+	Apply(ctx context.Context, manifests []byte, opts ApplyOptions) ([]ApplyResult, error)
+
+	GetClusterInfo(ctx context.Context) (*ClusterInfo, error)
+	GetNode(ctx context.Context, nodeName string) (*NodeInfo, error)
+	CordonNode(ctx context.Context, nodeName string) error
+	UncordonNode(ctx context.Context, nodeName string) error
+	DrainNode(ctx context.Context, nodeName string, opts DrainOptions) error
+	DeleteNode(ctx context.Context, nodeName string) error
+	SyncNodeLabels(ctx context.Context, nodeName, prefix string, labels map[string]string) (bool, error)
 }
+
+var _ K8sClient = (*Client)(nil)
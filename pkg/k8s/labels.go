@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SyncNodeLabels makes the labels of a node that start with prefix exactly
+// match labels, adding, changing and removing them with one merge patch.
+// Labels without the prefix are left alone. It reports whether the node
+// was patched.
+func (c *Client) SyncNodeLabels(ctx context.Context, nodeName, prefix string, labels map[string]string) (bool, error) {
+	for key := range labels {
+		if !strings.HasPrefix(key, prefix) {
+			return false, fmt.Errorf("label %s does not start with %s", key, prefix)
+		}
+	}
+
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	// A nil value removes a label in a merge patch
+	changes := make(map[string]*string)
+	for key := range node.Labels {
+		if _, keep := labels[key]; strings.HasPrefix(key, prefix) && !keep {
+			changes[key] = nil
+		}
+	}
+	for key, value := range labels {
+		if current, exists := node.Labels[key]; !exists || current != value {
+			value := value
+			changes[key] = &value
+		}
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": changes},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to encode label patch: %w", err)
+	}
+
+	if _, err := c.clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return false, fmt.Errorf("failed to patch node labels: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"node_name": nodeName,
+		"prefix":    prefix,
+		"changes":   len(changes),
+	}).Info("Node labels synced")
+	return true, nil
+}
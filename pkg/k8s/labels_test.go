@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSyncNodeLabels(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "pi-1",
+		Labels: map[string]string{
+			"kubernetes.io/hostname": "pi-1",
+			"example.io/gpio-4":      "digital",
+			"example.io/gpio-17":     "digital",
+			"example.io/gpio-18":     "digital",
+		},
+	}})
+	client := NewClientForClientset(clientset, logrus.New())
+	ctx := context.Background()
+
+	desired := map[string]string{
+		"example.io/gpio-18": "pwm",
+		"example.io/gpio-17": "digital",
+		"example.io/gpio-23": "digital",
+	}
+	patched, err := client.SyncNodeLabels(ctx, "pi-1", "example.io/gpio-", desired)
+	require.NoError(t, err)
+	assert.True(t, patched)
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, "pi-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/hostname": "pi-1",
+		"example.io/gpio-17":     "digital",
+		"example.io/gpio-18":     "pwm",
+		"example.io/gpio-23":     "digital",
+	}, node.Labels)
+
+	patched, err = client.SyncNodeLabels(ctx, "pi-1", "example.io/gpio-", desired)
+	require.NoError(t, err)
+	assert.False(t, patched, "labels already match")
+
+	_, err = client.SyncNodeLabels(ctx, "pi-1", "example.io/gpio-", map[string]string{"other.io/x": "y"})
+	assert.Error(t, err, "labels outside the prefix are refused")
+}
@@ -0,0 +1,294 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// revisionAnnotation records the rollout revision of a Deployment and of the
+// ReplicaSets it creates
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// ErrRevisionNotFound is returned when rolling back to a revision a deployment
+// does not have
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// CreateDeployment creates a new deployment in the specified namespace
+func (c *Client) CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	return c.clientset.AppsV1().Deployments(c.namespaceOr(namespace)).Create(ctx, deployment, metav1.CreateOptions{})
+}
+
+// GetDeployment retrieves a deployment by name from the specified namespace
+func (c *Client) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	return c.clientset.AppsV1().Deployments(c.namespaceOr(namespace)).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListDeployments returns the deployments in a namespace, or in all
+// namespaces if it is empty
+func (c *Client) ListDeployments(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
+	list, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// UpdateDeployment updates a deployment in the specified namespace
+func (c *Client) UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	return c.clientset.AppsV1().Deployments(c.namespaceOr(namespace)).Update(ctx, deployment, metav1.UpdateOptions{})
+}
+
+// DeleteDeployment deletes a deployment and the pods it manages
+func (c *Client) DeleteDeployment(ctx context.Context, namespace, name string) error {
+	propagation := metav1.DeletePropagationForeground
+	return c.clientset.AppsV1().Deployments(c.namespaceOr(namespace)).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
+// RolloutState summarises the progress of a deployment's latest rollout
+type RolloutState string
+
+const (
+	RolloutProgressing RolloutState = "progressing"
+	RolloutComplete    RolloutState = "complete"
+	RolloutFailed      RolloutState = "failed"
+)
+
+// RolloutStatus describes the progress of a deployment's latest rollout
+type RolloutStatus struct {
+	State             RolloutState `json:"state"`
+	Message           string       `json:"message"`
+	Revision          int64        `json:"revision"`
+	Replicas          int32        `json:"replicas"`
+	UpdatedReplicas   int32        `json:"updated_replicas"`
+	ReadyReplicas     int32        `json:"ready_replicas"`
+	AvailableReplicas int32        `json:"available_replicas"`
+}
+
+// GetRolloutStatus reports whether a deployment's latest rollout has
+// completed, using the same rules as kubectl rollout status
+func (c *Client) GetRolloutStatus(ctx context.Context, namespace, name string) (*RolloutStatus, error) {
+	deployment, err := c.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return rolloutStatus(deployment), nil
+}
+
+func rolloutStatus(deployment *appsv1.Deployment) *RolloutStatus {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	status := &RolloutStatus{
+		State:             RolloutProgressing,
+		Revision:          revisionOf(deployment.ObjectMeta),
+		Replicas:          desired,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			status.State = RolloutFailed
+			status.Message = fmt.Sprintf("deployment %q exceeded its progress deadline", deployment.Name)
+			return status
+		}
+	}
+
+	switch {
+	case deployment.Generation > deployment.Status.ObservedGeneration:
+		status.Message = "waiting for the deployment spec update to be observed"
+	case deployment.Status.UpdatedReplicas < desired:
+		status.Message = fmt.Sprintf("%d of %d new replicas have been updated", deployment.Status.UpdatedReplicas, desired)
+	case deployment.Status.Replicas > deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d old replicas are pending termination", deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
+	case deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d of %d updated replicas are available", deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
+	default:
+		status.State = RolloutComplete
+		status.Message = fmt.Sprintf("deployment %q successfully rolled out", deployment.Name)
+	}
+	return status
+}
+
+// RollbackDeployment restores the pod template of an earlier revision of a
+// deployment, which starts a new rollout. Revision 0 rolls back to the
+// revision before the current one.
+func (c *Client) RollbackDeployment(ctx context.Context, namespace, name string, revision int64) (*appsv1.Deployment, error) {
+	namespace = c.namespaceOr(namespace)
+	deployment, err := c.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of deployment %s: %w", name, err)
+	}
+	list, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets of deployment %s: %w", name, err)
+	}
+
+	// Revisions of the ReplicaSets this deployment controls, newest first
+	var history []appsv1.ReplicaSet
+	for _, rs := range list.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil && owner.UID == deployment.UID {
+			history = append(history, rs)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return revisionOf(history[i].ObjectMeta) > revisionOf(history[j].ObjectMeta)
+	})
+
+	current := revisionOf(deployment.ObjectMeta)
+	var target *appsv1.ReplicaSet
+	for i := range history {
+		rev := revisionOf(history[i].ObjectMeta)
+		if (revision == 0 && rev < current) || (revision != 0 && rev == revision) {
+			target = &history[i]
+			break
+		}
+	}
+	if target == nil {
+		if revision == 0 {
+			return nil, fmt.Errorf("%w: deployment %s has no previous revision", ErrRevisionNotFound, name)
+		}
+		return nil, fmt.Errorf("%w: deployment %s has no revision %d", ErrRevisionNotFound, name, revision)
+	}
+
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deployment.Spec.Template = *template
+
+	updated, err := c.UpdateDeployment(ctx, namespace, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"namespace":  namespace,
+		"deployment": name,
+		"revision":   revisionOf(target.ObjectMeta),
+	}).Info("Deployment rolled back")
+	return updated, nil
+}
+
+func revisionOf(meta metav1.ObjectMeta) int64 {
+	revision, _ := strconv.ParseInt(meta.Annotations[revisionAnnotation], 10, 64)
+	return revision
+}
+
+// CreateDaemonSet creates a new daemon set in the specified namespace
+func (c *Client) CreateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	return c.clientset.AppsV1().DaemonSets(c.namespaceOr(namespace)).Create(ctx, daemonSet, metav1.CreateOptions{})
+}
+
+// GetDaemonSet retrieves a daemon set by name from the specified namespace
+func (c *Client) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	return c.clientset.AppsV1().DaemonSets(c.namespaceOr(namespace)).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListDaemonSets returns the daemon sets in a namespace, or in all namespaces
+// if it is empty
+func (c *Client) ListDaemonSets(ctx context.Context, namespace string) ([]appsv1.DaemonSet, error) {
+	list, err := c.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// UpdateDaemonSet updates a daemon set in the specified namespace
+func (c *Client) UpdateDaemonSet(ctx context.Context, namespace string, daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	return c.clientset.AppsV1().DaemonSets(c.namespaceOr(namespace)).Update(ctx, daemonSet, metav1.UpdateOptions{})
+}
+
+// DeleteDaemonSet deletes a daemon set and the pods it manages
+func (c *Client) DeleteDaemonSet(ctx context.Context, namespace, name string) error {
+	propagation := metav1.DeletePropagationForeground
+	return c.clientset.AppsV1().DaemonSets(c.namespaceOr(namespace)).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+}
+
+// CreateService creates a new service in the specified namespace
+func (c *Client) CreateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(c.namespaceOr(namespace)).Create(ctx, service, metav1.CreateOptions{})
+}
+
+// GetService retrieves a service by name from the specified namespace
+func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(c.namespaceOr(namespace)).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListServices returns the services in a namespace, or in all namespaces if
+// it is empty
+func (c *Client) ListServices(ctx context.Context, namespace string) ([]corev1.Service, error) {
+	list, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// UpdateService updates a service in the specified namespace
+func (c *Client) UpdateService(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(c.namespaceOr(namespace)).Update(ctx, service, metav1.UpdateOptions{})
+}
+
+// DeleteService deletes a service by name from the specified namespace
+func (c *Client) DeleteService(ctx context.Context, namespace, name string) error {
+	return c.clientset.CoreV1().Services(c.namespaceOr(namespace)).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// CreateConfigMap creates a new config map in the specified namespace
+func (c *Client) CreateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(c.namespaceOr(namespace)).Create(ctx, configMap, metav1.CreateOptions{})
+}
+
+// GetConfigMap retrieves a config map by name from the specified namespace
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(c.namespaceOr(namespace)).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListConfigMaps returns the config maps in a namespace, or in all
+// namespaces if it is empty
+func (c *Client) ListConfigMaps(ctx context.Context, namespace string) ([]corev1.ConfigMap, error) {
+	list, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// UpdateConfigMap updates a config map in the specified namespace
+func (c *Client) UpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(c.namespaceOr(namespace)).Update(ctx, configMap, metav1.UpdateOptions{})
+}
+
+// DeleteConfigMap deletes a config map by name from the specified namespace
+func (c *Client) DeleteConfigMap(ctx context.Context, namespace, name string) error {
+	return c.clientset.CoreV1().ConfigMaps(c.namespaceOr(namespace)).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// namespaceOr returns namespace, or the client's default namespace if it is
+// empty
+func (c *Client) namespaceOr(namespace string) string {
+	if namespace == "" {
+		return c.namespace
+	}
+	return namespace
+}
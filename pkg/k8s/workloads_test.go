@@ -0,0 +1,172 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func testDeployment(revision string) *appsv1.Deployment {
+	labels := map[string]string{"app": "web"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			UID:         "web-uid",
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(2),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}}},
+			},
+		},
+	}
+}
+
+// testReplicaSet returns a ReplicaSet the web deployment created for a revision
+func testReplicaSet(revision, image string) *appsv1.ReplicaSet {
+	controller := true
+	labels := map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: "hash-" + revision}
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-" + revision,
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: map[string]string{revisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "web",
+				UID:        types.UID("web-uid"),
+				Controller: &controller,
+			}},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+			},
+		},
+	}
+}
+
+func TestRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*appsv1.Deployment)
+		state  RolloutState
+	}{
+		{
+			name: "complete",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2}
+			},
+			state: RolloutComplete,
+		},
+		{
+			name: "spec change not yet observed",
+			mutate: func(d *appsv1.Deployment) {
+				d.Generation = 2
+				d.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+			},
+			state: RolloutProgressing,
+		},
+		{
+			name: "old replicas still running",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status = appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}
+			},
+			state: RolloutProgressing,
+		},
+		{
+			name: "updated replicas not yet available",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1}
+			},
+			state: RolloutProgressing,
+		},
+		{
+			name: "progress deadline exceeded",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status = appsv1.DeploymentStatus{
+					Replicas:        2,
+					UpdatedReplicas: 1,
+					Conditions: []appsv1.DeploymentCondition{{
+						Type:   appsv1.DeploymentProgressing,
+						Status: corev1.ConditionFalse,
+						Reason: "ProgressDeadlineExceeded",
+					}},
+				}
+			},
+			state: RolloutFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := testDeployment("3")
+			tt.mutate(deployment)
+			client := NewClientForClientset(fake.NewSimpleClientset(deployment), logrus.New())
+
+			status, err := client.GetRolloutStatus(context.Background(), "default", "web")
+			require.NoError(t, err)
+			assert.Equal(t, tt.state, status.State, status.Message)
+			assert.Equal(t, int64(3), status.Revision)
+		})
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	setup := func() (*Client, *fake.Clientset) {
+		other := testReplicaSet("9", "other:1")
+		other.OwnerReferences[0].UID = "other-uid"
+		clientset := fake.NewSimpleClientset(
+			testDeployment("3"),
+			testReplicaSet("1", "nginx:1.25"),
+			testReplicaSet("2", "nginx:1.26"),
+			testReplicaSet("3", "nginx:1.27"),
+			other,
+		)
+		return NewClientForClientset(clientset, logrus.New()), clientset
+	}
+
+	t.Run("rolls back to the previous revision", func(t *testing.T) {
+		client, _ := setup()
+
+		deployment, err := client.RollbackDeployment(context.Background(), "default", "web", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "nginx:1.26", deployment.Spec.Template.Spec.Containers[0].Image)
+		assert.NotContains(t, deployment.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	})
+
+	t.Run("rolls back to a given revision", func(t *testing.T) {
+		client, clientset := setup()
+
+		_, err := client.RollbackDeployment(context.Background(), "default", "web", 1)
+		require.NoError(t, err)
+
+		deployment, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "nginx:1.25", deployment.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("ignores replica sets of other deployments", func(t *testing.T) {
+		client, _ := setup()
+
+		_, err := client.RollbackDeployment(context.Background(), "default", "web", 9)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+}
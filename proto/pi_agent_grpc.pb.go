@@ -24,10 +24,13 @@ const (
 	PiAgentService_WriteGPIOPin_FullMethodName        = "/pi_agent.PiAgentService/WriteGPIOPin"
 	PiAgentService_SetGPIOPWM_FullMethodName          = "/pi_agent.PiAgentService/SetGPIOPWM"
 	PiAgentService_ListConfiguredPins_FullMethodName  = "/pi_agent.PiAgentService/ListConfiguredPins"
+	PiAgentService_WatchGPIOPin_FullMethodName        = "/pi_agent.PiAgentService/WatchGPIOPin"
 	PiAgentService_AgentHealth_FullMethodName         = "/pi_agent.PiAgentService/AgentHealth"
 	PiAgentService_GetSystemInfo_FullMethodName       = "/pi_agent.PiAgentService/GetSystemInfo"
 	PiAgentService_GetSystemMetrics_FullMethodName    = "/pi_agent.PiAgentService/GetSystemMetrics"
 	PiAgentService_StreamSystemMetrics_FullMethodName = "/pi_agent.PiAgentService/StreamSystemMetrics"
+	PiAgentService_SnapshotDatastore_FullMethodName   = "/pi_agent.PiAgentService/SnapshotDatastore"
+	PiAgentService_RestoreDatastore_FullMethodName    = "/pi_agent.PiAgentService/RestoreDatastore"
 )
 
 // PiAgentServiceClient is the client API for PiAgentService service.
@@ -42,12 +45,16 @@ type PiAgentServiceClient interface {
 	WriteGPIOPin(ctx context.Context, in *WriteGPIOPinRequest, opts ...grpc.CallOption) (*WriteGPIOPinResponse, error)
 	SetGPIOPWM(ctx context.Context, in *SetGPIOPWMRequest, opts ...grpc.CallOption) (*SetGPIOPWMResponse, error)
 	ListConfiguredPins(ctx context.Context, in *ListConfiguredPinsRequest, opts ...grpc.CallOption) (*ListConfiguredPinsResponse, error)
+	WatchGPIOPin(ctx context.Context, in *WatchGPIOPinRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GPIOPinEvent], error)
 	// Health and status
 	AgentHealth(ctx context.Context, in *AgentHealthRequest, opts ...grpc.CallOption) (*AgentHealthResponse, error)
 	GetSystemInfo(ctx context.Context, in *GetSystemInfoRequest, opts ...grpc.CallOption) (*GetSystemInfoResponse, error)
 	// System metrics
 	GetSystemMetrics(ctx context.Context, in *GetSystemMetricsRequest, opts ...grpc.CallOption) (*GetSystemMetricsResponse, error)
 	StreamSystemMetrics(ctx context.Context, in *StreamSystemMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SystemMetricsResponse], error)
+	// k3s datastore backup and restore on server nodes
+	SnapshotDatastore(ctx context.Context, in *SnapshotDatastoreRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DatastoreChunk], error)
+	RestoreDatastore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[RestoreDatastoreRequest, RestoreDatastoreResponse], error)
 }
 
 type piAgentServiceClient struct {
@@ -108,6 +115,25 @@ func (c *piAgentServiceClient) ListConfiguredPins(ctx context.Context, in *ListC
 	return out, nil
 }
 
+func (c *piAgentServiceClient) WatchGPIOPin(ctx context.Context, in *WatchGPIOPinRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GPIOPinEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PiAgentService_ServiceDesc.Streams[0], PiAgentService_WatchGPIOPin_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchGPIOPinRequest, GPIOPinEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_WatchGPIOPinClient = grpc.ServerStreamingClient[GPIOPinEvent]
+
 func (c *piAgentServiceClient) AgentHealth(ctx context.Context, in *AgentHealthRequest, opts ...grpc.CallOption) (*AgentHealthResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AgentHealthResponse)
@@ -140,7 +166,7 @@ func (c *piAgentServiceClient) GetSystemMetrics(ctx context.Context, in *GetSyst
 
 func (c *piAgentServiceClient) StreamSystemMetrics(ctx context.Context, in *StreamSystemMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SystemMetricsResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &PiAgentService_ServiceDesc.Streams[0], PiAgentService_StreamSystemMetrics_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &PiAgentService_ServiceDesc.Streams[1], PiAgentService_StreamSystemMetrics_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +183,38 @@ func (c *piAgentServiceClient) StreamSystemMetrics(ctx context.Context, in *Stre
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PiAgentService_StreamSystemMetricsClient = grpc.ServerStreamingClient[SystemMetricsResponse]
 
+func (c *piAgentServiceClient) SnapshotDatastore(ctx context.Context, in *SnapshotDatastoreRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DatastoreChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PiAgentService_ServiceDesc.Streams[2], PiAgentService_SnapshotDatastore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SnapshotDatastoreRequest, DatastoreChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_SnapshotDatastoreClient = grpc.ServerStreamingClient[DatastoreChunk]
+
+func (c *piAgentServiceClient) RestoreDatastore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[RestoreDatastoreRequest, RestoreDatastoreResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PiAgentService_ServiceDesc.Streams[3], PiAgentService_RestoreDatastore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RestoreDatastoreRequest, RestoreDatastoreResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_RestoreDatastoreClient = grpc.ClientStreamingClient[RestoreDatastoreRequest, RestoreDatastoreResponse]
+
 // PiAgentServiceServer is the server API for PiAgentService service.
 // All implementations must embed UnimplementedPiAgentServiceServer
 // for forward compatibility.
@@ -169,12 +227,16 @@ type PiAgentServiceServer interface {
 	WriteGPIOPin(context.Context, *WriteGPIOPinRequest) (*WriteGPIOPinResponse, error)
 	SetGPIOPWM(context.Context, *SetGPIOPWMRequest) (*SetGPIOPWMResponse, error)
 	ListConfiguredPins(context.Context, *ListConfiguredPinsRequest) (*ListConfiguredPinsResponse, error)
+	WatchGPIOPin(*WatchGPIOPinRequest, grpc.ServerStreamingServer[GPIOPinEvent]) error
 	// Health and status
 	AgentHealth(context.Context, *AgentHealthRequest) (*AgentHealthResponse, error)
 	GetSystemInfo(context.Context, *GetSystemInfoRequest) (*GetSystemInfoResponse, error)
 	// System metrics
 	GetSystemMetrics(context.Context, *GetSystemMetricsRequest) (*GetSystemMetricsResponse, error)
 	StreamSystemMetrics(*StreamSystemMetricsRequest, grpc.ServerStreamingServer[SystemMetricsResponse]) error
+	// k3s datastore backup and restore on server nodes
+	SnapshotDatastore(*SnapshotDatastoreRequest, grpc.ServerStreamingServer[DatastoreChunk]) error
+	RestoreDatastore(grpc.ClientStreamingServer[RestoreDatastoreRequest, RestoreDatastoreResponse]) error
 	mustEmbedUnimplementedPiAgentServiceServer()
 }
 
@@ -200,6 +262,9 @@ func (UnimplementedPiAgentServiceServer) SetGPIOPWM(context.Context, *SetGPIOPWM
 func (UnimplementedPiAgentServiceServer) ListConfiguredPins(context.Context, *ListConfiguredPinsRequest) (*ListConfiguredPinsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListConfiguredPins not implemented")
 }
+func (UnimplementedPiAgentServiceServer) WatchGPIOPin(*WatchGPIOPinRequest, grpc.ServerStreamingServer[GPIOPinEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGPIOPin not implemented")
+}
 func (UnimplementedPiAgentServiceServer) AgentHealth(context.Context, *AgentHealthRequest) (*AgentHealthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AgentHealth not implemented")
 }
@@ -212,6 +277,12 @@ func (UnimplementedPiAgentServiceServer) GetSystemMetrics(context.Context, *GetS
 func (UnimplementedPiAgentServiceServer) StreamSystemMetrics(*StreamSystemMetricsRequest, grpc.ServerStreamingServer[SystemMetricsResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamSystemMetrics not implemented")
 }
+func (UnimplementedPiAgentServiceServer) SnapshotDatastore(*SnapshotDatastoreRequest, grpc.ServerStreamingServer[DatastoreChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method SnapshotDatastore not implemented")
+}
+func (UnimplementedPiAgentServiceServer) RestoreDatastore(grpc.ClientStreamingServer[RestoreDatastoreRequest, RestoreDatastoreResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreDatastore not implemented")
+}
 func (UnimplementedPiAgentServiceServer) mustEmbedUnimplementedPiAgentServiceServer() {}
 func (UnimplementedPiAgentServiceServer) testEmbeddedByValue()                        {}
 
@@ -323,6 +394,17 @@ func _PiAgentService_ListConfiguredPins_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PiAgentService_WatchGPIOPin_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGPIOPinRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PiAgentServiceServer).WatchGPIOPin(m, &grpc.GenericServerStream[WatchGPIOPinRequest, GPIOPinEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_WatchGPIOPinServer = grpc.ServerStreamingServer[GPIOPinEvent]
+
 func _PiAgentService_AgentHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AgentHealthRequest)
 	if err := dec(in); err != nil {
@@ -388,6 +470,24 @@ func _PiAgentService_StreamSystemMetrics_Handler(srv interface{}, stream grpc.Se
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PiAgentService_StreamSystemMetricsServer = grpc.ServerStreamingServer[SystemMetricsResponse]
 
+func _PiAgentService_SnapshotDatastore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotDatastoreRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PiAgentServiceServer).SnapshotDatastore(m, &grpc.GenericServerStream[SnapshotDatastoreRequest, DatastoreChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_SnapshotDatastoreServer = grpc.ServerStreamingServer[DatastoreChunk]
+
+func _PiAgentService_RestoreDatastore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PiAgentServiceServer).RestoreDatastore(&grpc.GenericServerStream[RestoreDatastoreRequest, RestoreDatastoreResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PiAgentService_RestoreDatastoreServer = grpc.ClientStreamingServer[RestoreDatastoreRequest, RestoreDatastoreResponse]
+
 // PiAgentService_ServiceDesc is the grpc.ServiceDesc for PiAgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -429,11 +529,26 @@ var PiAgentService_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGPIOPin",
+			Handler:       _PiAgentService_WatchGPIOPin_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamSystemMetrics",
 			Handler:       _PiAgentService_StreamSystemMetrics_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SnapshotDatastore",
+			Handler:       _PiAgentService_SnapshotDatastore_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RestoreDatastore",
+			Handler:       _PiAgentService_RestoreDatastore_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/pi_agent.proto",
 }
@@ -19,28 +19,31 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PiControllerService_CreateCluster_FullMethodName      = "/pi_controller.PiControllerService/CreateCluster"
-	PiControllerService_GetCluster_FullMethodName         = "/pi_controller.PiControllerService/GetCluster"
-	PiControllerService_ListClusters_FullMethodName       = "/pi_controller.PiControllerService/ListClusters"
-	PiControllerService_UpdateCluster_FullMethodName      = "/pi_controller.PiControllerService/UpdateCluster"
-	PiControllerService_DeleteCluster_FullMethodName      = "/pi_controller.PiControllerService/DeleteCluster"
-	PiControllerService_CreateNode_FullMethodName         = "/pi_controller.PiControllerService/CreateNode"
-	PiControllerService_GetNode_FullMethodName            = "/pi_controller.PiControllerService/GetNode"
-	PiControllerService_ListNodes_FullMethodName          = "/pi_controller.PiControllerService/ListNodes"
-	PiControllerService_UpdateNode_FullMethodName         = "/pi_controller.PiControllerService/UpdateNode"
-	PiControllerService_DeleteNode_FullMethodName         = "/pi_controller.PiControllerService/DeleteNode"
-	PiControllerService_ProvisionNode_FullMethodName      = "/pi_controller.PiControllerService/ProvisionNode"
-	PiControllerService_DeprovisionNode_FullMethodName    = "/pi_controller.PiControllerService/DeprovisionNode"
-	PiControllerService_CreateGPIODevice_FullMethodName   = "/pi_controller.PiControllerService/CreateGPIODevice"
-	PiControllerService_GetGPIODevice_FullMethodName      = "/pi_controller.PiControllerService/GetGPIODevice"
-	PiControllerService_ListGPIODevices_FullMethodName    = "/pi_controller.PiControllerService/ListGPIODevices"
-	PiControllerService_UpdateGPIODevice_FullMethodName   = "/pi_controller.PiControllerService/UpdateGPIODevice"
-	PiControllerService_DeleteGPIODevice_FullMethodName   = "/pi_controller.PiControllerService/DeleteGPIODevice"
-	PiControllerService_ReadGPIO_FullMethodName           = "/pi_controller.PiControllerService/ReadGPIO"
-	PiControllerService_WriteGPIO_FullMethodName          = "/pi_controller.PiControllerService/WriteGPIO"
-	PiControllerService_StreamGPIOReadings_FullMethodName = "/pi_controller.PiControllerService/StreamGPIOReadings"
-	PiControllerService_Health_FullMethodName             = "/pi_controller.PiControllerService/Health"
-	PiControllerService_GetSystemInfo_FullMethodName      = "/pi_controller.PiControllerService/GetSystemInfo"
+	PiControllerService_CreateCluster_FullMethodName         = "/pi_controller.PiControllerService/CreateCluster"
+	PiControllerService_GetCluster_FullMethodName            = "/pi_controller.PiControllerService/GetCluster"
+	PiControllerService_ListClusters_FullMethodName          = "/pi_controller.PiControllerService/ListClusters"
+	PiControllerService_UpdateCluster_FullMethodName         = "/pi_controller.PiControllerService/UpdateCluster"
+	PiControllerService_DeleteCluster_FullMethodName         = "/pi_controller.PiControllerService/DeleteCluster"
+	PiControllerService_CreateNode_FullMethodName            = "/pi_controller.PiControllerService/CreateNode"
+	PiControllerService_GetNode_FullMethodName               = "/pi_controller.PiControllerService/GetNode"
+	PiControllerService_ListNodes_FullMethodName             = "/pi_controller.PiControllerService/ListNodes"
+	PiControllerService_UpdateNode_FullMethodName            = "/pi_controller.PiControllerService/UpdateNode"
+	PiControllerService_DeleteNode_FullMethodName            = "/pi_controller.PiControllerService/DeleteNode"
+	PiControllerService_ProvisionNode_FullMethodName         = "/pi_controller.PiControllerService/ProvisionNode"
+	PiControllerService_DeprovisionNode_FullMethodName       = "/pi_controller.PiControllerService/DeprovisionNode"
+	PiControllerService_CreateGPIODevice_FullMethodName      = "/pi_controller.PiControllerService/CreateGPIODevice"
+	PiControllerService_GetGPIODevice_FullMethodName         = "/pi_controller.PiControllerService/GetGPIODevice"
+	PiControllerService_ListGPIODevices_FullMethodName       = "/pi_controller.PiControllerService/ListGPIODevices"
+	PiControllerService_UpdateGPIODevice_FullMethodName      = "/pi_controller.PiControllerService/UpdateGPIODevice"
+	PiControllerService_DeleteGPIODevice_FullMethodName      = "/pi_controller.PiControllerService/DeleteGPIODevice"
+	PiControllerService_ReadGPIO_FullMethodName              = "/pi_controller.PiControllerService/ReadGPIO"
+	PiControllerService_WriteGPIO_FullMethodName             = "/pi_controller.PiControllerService/WriteGPIO"
+	PiControllerService_StreamGPIOReadings_FullMethodName    = "/pi_controller.PiControllerService/StreamGPIOReadings"
+	PiControllerService_ReportGPIOAllocations_FullMethodName = "/pi_controller.PiControllerService/ReportGPIOAllocations"
+	PiControllerService_EnrollAgent_FullMethodName           = "/pi_controller.PiControllerService/EnrollAgent"
+	PiControllerService_RenewAgentCertificate_FullMethodName = "/pi_controller.PiControllerService/RenewAgentCertificate"
+	PiControllerService_Health_FullMethodName                = "/pi_controller.PiControllerService/Health"
+	PiControllerService_GetSystemInfo_FullMethodName         = "/pi_controller.PiControllerService/GetSystemInfo"
 )
 
 // PiControllerServiceClient is the client API for PiControllerService service.
@@ -73,6 +76,12 @@ type PiControllerServiceClient interface {
 	WriteGPIO(ctx context.Context, in *WriteGPIORequest, opts ...grpc.CallOption) (*WriteGPIOResponse, error)
 	// Real-time GPIO streaming
 	StreamGPIOReadings(ctx context.Context, in *StreamGPIOReadingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GPIOReading], error)
+	// Kubernetes device plugin allocations, reported by each node's agent
+	ReportGPIOAllocations(ctx context.Context, in *ReportGPIOAllocationsRequest, opts ...grpc.CallOption) (*ReportGPIOAllocationsResponse, error)
+	// Agent enrollment with the controller's certificate authority. Enrolling
+	// takes a bootstrap token; renewing takes the agent's current certificate.
+	EnrollAgent(ctx context.Context, in *EnrollAgentRequest, opts ...grpc.CallOption) (*EnrollAgentResponse, error)
+	RenewAgentCertificate(ctx context.Context, in *RenewAgentCertificateRequest, opts ...grpc.CallOption) (*EnrollAgentResponse, error)
 	// Health and status
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	GetSystemInfo(ctx context.Context, in *SystemInfoRequest, opts ...grpc.CallOption) (*SystemInfoResponse, error)
@@ -295,6 +304,36 @@ func (c *piControllerServiceClient) StreamGPIOReadings(ctx context.Context, in *
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PiControllerService_StreamGPIOReadingsClient = grpc.ServerStreamingClient[GPIOReading]
 
+func (c *piControllerServiceClient) ReportGPIOAllocations(ctx context.Context, in *ReportGPIOAllocationsRequest, opts ...grpc.CallOption) (*ReportGPIOAllocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportGPIOAllocationsResponse)
+	err := c.cc.Invoke(ctx, PiControllerService_ReportGPIOAllocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *piControllerServiceClient) EnrollAgent(ctx context.Context, in *EnrollAgentRequest, opts ...grpc.CallOption) (*EnrollAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollAgentResponse)
+	err := c.cc.Invoke(ctx, PiControllerService_EnrollAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *piControllerServiceClient) RenewAgentCertificate(ctx context.Context, in *RenewAgentCertificateRequest, opts ...grpc.CallOption) (*EnrollAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollAgentResponse)
+	err := c.cc.Invoke(ctx, PiControllerService_RenewAgentCertificate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *piControllerServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthResponse)
@@ -345,6 +384,12 @@ type PiControllerServiceServer interface {
 	WriteGPIO(context.Context, *WriteGPIORequest) (*WriteGPIOResponse, error)
 	// Real-time GPIO streaming
 	StreamGPIOReadings(*StreamGPIOReadingsRequest, grpc.ServerStreamingServer[GPIOReading]) error
+	// Kubernetes device plugin allocations, reported by each node's agent
+	ReportGPIOAllocations(context.Context, *ReportGPIOAllocationsRequest) (*ReportGPIOAllocationsResponse, error)
+	// Agent enrollment with the controller's certificate authority. Enrolling
+	// takes a bootstrap token; renewing takes the agent's current certificate.
+	EnrollAgent(context.Context, *EnrollAgentRequest) (*EnrollAgentResponse, error)
+	RenewAgentCertificate(context.Context, *RenewAgentCertificateRequest) (*EnrollAgentResponse, error)
 	// Health and status
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	GetSystemInfo(context.Context, *SystemInfoRequest) (*SystemInfoResponse, error)
@@ -418,6 +463,15 @@ func (UnimplementedPiControllerServiceServer) WriteGPIO(context.Context, *WriteG
 func (UnimplementedPiControllerServiceServer) StreamGPIOReadings(*StreamGPIOReadingsRequest, grpc.ServerStreamingServer[GPIOReading]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamGPIOReadings not implemented")
 }
+func (UnimplementedPiControllerServiceServer) ReportGPIOAllocations(context.Context, *ReportGPIOAllocationsRequest) (*ReportGPIOAllocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportGPIOAllocations not implemented")
+}
+func (UnimplementedPiControllerServiceServer) EnrollAgent(context.Context, *EnrollAgentRequest) (*EnrollAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollAgent not implemented")
+}
+func (UnimplementedPiControllerServiceServer) RenewAgentCertificate(context.Context, *RenewAgentCertificateRequest) (*EnrollAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewAgentCertificate not implemented")
+}
 func (UnimplementedPiControllerServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
 }
@@ -798,6 +852,60 @@ func _PiControllerService_StreamGPIOReadings_Handler(srv interface{}, stream grp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PiControllerService_StreamGPIOReadingsServer = grpc.ServerStreamingServer[GPIOReading]
 
+func _PiControllerService_ReportGPIOAllocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportGPIOAllocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PiControllerServiceServer).ReportGPIOAllocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PiControllerService_ReportGPIOAllocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PiControllerServiceServer).ReportGPIOAllocations(ctx, req.(*ReportGPIOAllocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PiControllerService_EnrollAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PiControllerServiceServer).EnrollAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PiControllerService_EnrollAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PiControllerServiceServer).EnrollAgent(ctx, req.(*EnrollAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PiControllerService_RenewAgentCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewAgentCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PiControllerServiceServer).RenewAgentCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PiControllerService_RenewAgentCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PiControllerServiceServer).RenewAgentCertificate(ctx, req.(*RenewAgentCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PiControllerService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthRequest)
 	if err := dec(in); err != nil {
@@ -917,6 +1025,18 @@ var PiControllerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WriteGPIO",
 			Handler:    _PiControllerService_WriteGPIO_Handler,
 		},
+		{
+			MethodName: "ReportGPIOAllocations",
+			Handler:    _PiControllerService_ReportGPIOAllocations_Handler,
+		},
+		{
+			MethodName: "EnrollAgent",
+			Handler:    _PiControllerService_EnrollAgent_Handler,
+		},
+		{
+			MethodName: "RenewAgentCertificate",
+			Handler:    _PiControllerService_RenewAgentCertificate_Handler,
+		},
 		{
 			MethodName: "Health",
 			Handler:    _PiControllerService_Health_Handler,
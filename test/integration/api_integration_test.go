@@ -33,10 +33,11 @@ type APIIntegrationTestSuite struct {
 	clusterService *services.ClusterService
 	nodeService    *services.NodeService
 	gpioService    *services.GPIOService
+	agent          *fakeAgent
 }
 
-// SetupSuite sets up the test suite
-func (suite *APIIntegrationTestSuite) SetupSuite() {
+// SetupTest gives every test a fresh database so fixtures with fixed IDs do not collide
+func (suite *APIIntegrationTestSuite) SetupTest() {
 	db, cleanup := testutils.SetupTestDBFile(suite.T())
 	suite.db = storage.NewForTestWithDB(db, logger.Default())
 	suite.cleanup = cleanup
@@ -48,6 +49,10 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 	suite.nodeService = services.NewNodeService(suite.db, testLogger)
 	suite.gpioService = services.NewGPIOService(suite.db, testLogger)
 
+	// Route GPIO reads and writes to an in-process agent
+	suite.agent = newFakeAgent()
+	suite.gpioService.SetAgentConnector(startFakeAgent(suite.T(), suite.agent))
+
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(suite.db)
 	clusterHandler := handlers.NewClusterHandler(suite.clusterService, testLogger)
@@ -84,6 +89,9 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 			nodes.GET("/:id", nodeHandler.Get)
 			nodes.PUT("/:id", nodeHandler.Update)
 			nodes.DELETE("/:id", nodeHandler.Delete)
+			nodes.GET("/:id/gpio", nodeHandler.ListGPIO)
+			nodes.POST("/:id/provision", nodeHandler.Provision)
+			nodes.POST("/:id/deprovision", nodeHandler.Deprovision)
 		}
 
 		// GPIO routes
@@ -94,15 +102,15 @@ func (suite *APIIntegrationTestSuite) SetupSuite() {
 			gpio.GET("/:id", gpioHandler.Get)
 			gpio.PUT("/:id", gpioHandler.Update)
 			gpio.DELETE("/:id", gpioHandler.Delete)
-			gpio.GET("/:id/read", gpioHandler.Read)
+			gpio.POST("/:id/read", gpioHandler.Read)
 			gpio.POST("/:id/write", gpioHandler.Write)
 			gpio.GET("/:id/readings", gpioHandler.GetReadings)
 		}
 	}
 }
 
-// TearDownSuite cleans up after the test suite
-func (suite *APIIntegrationTestSuite) TearDownSuite() {
+// TearDownTest cleans up after each test
+func (suite *APIIntegrationTestSuite) TearDownTest() {
 	if suite.cleanup != nil {
 		suite.cleanup()
 	}
@@ -284,17 +292,15 @@ func (suite *APIIntegrationTestSuite) TestAPIIntegration_NodeWorkflow() {
 
 	assert.Equal(suite.T(), http.StatusCreated, w.Code)
 
-	var createResponse struct {
-		Data models.Node `json:"data"`
-	}
+	var createResponse models.Node
 	err = json.Unmarshal(w.Body.Bytes(), &createResponse)
 	require.NoError(suite.T(), err)
 	
-	nodeID := createResponse.Data.ID
+	nodeID := createResponse.ID
 	assert.NotZero(suite.T(), nodeID)
-	assert.Equal(suite.T(), createReq.Name, createResponse.Data.Name)
-	assert.Equal(suite.T(), createReq.IPAddress, createResponse.Data.IPAddress)
-	assert.Equal(suite.T(), models.NodeStatusDiscovered, createResponse.Data.Status)
+	assert.Equal(suite.T(), createReq.Name, createResponse.Name)
+	assert.Equal(suite.T(), createReq.IPAddress, createResponse.IPAddress)
+	assert.Equal(suite.T(), models.NodeStatusDiscovered, createResponse.Status)
 
 	// 2. Get the node by ID
 	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/nodes/%d", nodeID), nil)
@@ -332,12 +338,13 @@ func (suite *APIIntegrationTestSuite) TestAPIIntegration_NodeWorkflow() {
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 	
 	var listResponse struct {
-		Data  []models.Node `json:"data"`
+		Nodes []models.Node `json:"nodes"`
 		Total int64         `json:"total"`
 	}
 	err = json.Unmarshal(w.Body.Bytes(), &listResponse)
 	require.NoError(suite.T(), err)
-	assert.Len(suite.T(), listResponse.Data, 1)
+	assert.Len(suite.T(), listResponse.Nodes, 1)
+	assert.Equal(suite.T(), "updated-integration-test-node", listResponse.Nodes[0].Name)
 
 	// 5. Delete the node
 	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/nodes/%d", nodeID), nil)
@@ -381,16 +388,14 @@ func (suite *APIIntegrationTestSuite) TestAPIIntegration_GPIOWorkflow() {
 
 	assert.Equal(suite.T(), http.StatusCreated, w.Code)
 
-	var createResponse struct {
-		Data models.GPIODevice `json:"data"`
-	}
+	var createResponse models.GPIODevice
 	err = json.Unmarshal(w.Body.Bytes(), &createResponse)
 	require.NoError(suite.T(), err)
 	
-	deviceID := createResponse.Data.ID
+	deviceID := createResponse.ID
 	assert.NotZero(suite.T(), deviceID)
-	assert.Equal(suite.T(), createReq.Name, createResponse.Data.Name)
-	assert.Equal(suite.T(), createReq.PinNumber, createResponse.Data.PinNumber)
+	assert.Equal(suite.T(), createReq.Name, createResponse.Name)
+	assert.Equal(suite.T(), createReq.PinNumber, createResponse.PinNumber)
 
 	// 2. Write to GPIO device
 	writeReq := struct {
@@ -410,7 +415,7 @@ func (suite *APIIntegrationTestSuite) TestAPIIntegration_GPIOWorkflow() {
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 
 	// 3. Read from GPIO device
-	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/gpio/%d/read", deviceID), nil)
+	req, err = http.NewRequest("POST", fmt.Sprintf("/api/v1/gpio/%d/read", deviceID), nil)
 	require.NoError(suite.T(), err)
 
 	w = httptest.NewRecorder()
@@ -428,12 +433,13 @@ func (suite *APIIntegrationTestSuite) TestAPIIntegration_GPIOWorkflow() {
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 
 	var readingsResponse struct {
-		Data  []models.GPIOReading `json:"data"`
-		Total int64                `json:"total"`
+		Readings []models.GPIOReading `json:"readings"`
+		Total    int64                `json:"total"`
 	}
 	err = json.Unmarshal(w.Body.Bytes(), &readingsResponse)
 	require.NoError(suite.T(), err)
-	assert.GreaterOrEqual(suite.T(), len(readingsResponse.Data), 1) // Should have at least the write + read
+	assert.Len(suite.T(), readingsResponse.Readings, 2) // One for the write and one for the read
+	assert.Equal(suite.T(), int64(2), readingsResponse.Total)
 
 	// 5. List GPIO devices
 	req, err = http.NewRequest("GET", "/api/v1/gpio", nil)
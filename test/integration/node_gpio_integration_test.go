@@ -0,0 +1,359 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/dsyorkd/pi-controller/internal/models"
+	"github.com/dsyorkd/pi-controller/internal/services"
+	pb "github.com/dsyorkd/pi-controller/proto"
+)
+
+// fakeAgent is an in-process PiAgentService that keeps pin values in memory
+type fakeAgent struct {
+	pb.UnimplementedPiAgentServiceServer
+
+	mu     sync.Mutex
+	values map[int32]int32
+	down   bool
+}
+
+func newFakeAgent() *fakeAgent {
+	return &fakeAgent{values: make(map[int32]int32)}
+}
+
+func (a *fakeAgent) ConfigureGPIOPin(ctx context.Context, req *pb.ConfigureGPIOPinRequest) (*pb.ConfigureGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.down {
+		return nil, status.Error(codes.Unavailable, "agent down")
+	}
+	return &pb.ConfigureGPIOPinResponse{Success: true, ConfiguredAt: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) ReadGPIOPin(ctx context.Context, req *pb.ReadGPIOPinRequest) (*pb.ReadGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &pb.ReadGPIOPinResponse{Pin: req.Pin, Value: a.values[req.Pin], Timestamp: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) WriteGPIOPin(ctx context.Context, req *pb.WriteGPIOPinRequest) (*pb.WriteGPIOPinResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[req.Pin] = req.Value
+	return &pb.WriteGPIOPinResponse{Pin: req.Pin, Value: req.Value, Timestamp: timestamppb.Now()}, nil
+}
+
+func (a *fakeAgent) setDown(down bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.down = down
+}
+
+// bufconnConnector is an AgentConnector that routes every node to one in-process agent
+type bufconnConnector struct {
+	conn *grpc.ClientConn
+}
+
+func (c *bufconnConnector) Client(ctx context.Context, node *models.Node) (pb.PiAgentServiceClient, error) {
+	return pb.NewPiAgentServiceClient(c.conn), nil
+}
+
+func startFakeAgent(t *testing.T, agent *fakeAgent) *bufconnConnector {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPiAgentServiceServer(server, agent)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &bufconnConnector{conn: conn}
+}
+
+// request sends a JSON request through the suite router
+func (suite *APIIntegrationTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload *bytes.Buffer
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(suite.T(), err)
+		payload = bytes.NewBuffer(data)
+	} else {
+		payload = &bytes.Buffer{}
+	}
+
+	req, err := http.NewRequest(method, path, payload)
+	require.NoError(suite.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// createNode creates a node through the API and returns it
+func (suite *APIIntegrationTestSuite) createNode(i int, clusterID *uint, role models.NodeRole) models.Node {
+	w := suite.request("POST", "/api/v1/nodes", services.CreateNodeRequest{
+		Name:       fmt.Sprintf("node-%d", i),
+		IPAddress:  fmt.Sprintf("10.0.0.%d", i),
+		MACAddress: fmt.Sprintf("02:00:00:00:00:%02x", i),
+		Role:       role,
+		ClusterID:  clusterID,
+	})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	var node models.Node
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &node))
+	return node
+}
+
+// createDevice creates a GPIO device through the API and returns it
+func (suite *APIIntegrationTestSuite) createDevice(nodeID uint, pin int, direction models.GPIODirection) models.GPIODevice {
+	w := suite.request("POST", "/api/v1/gpio", services.CreateGPIODeviceRequest{
+		Name:      fmt.Sprintf("pin-%d", pin),
+		NodeID:    nodeID,
+		PinNumber: pin,
+		Direction: direction,
+	})
+	require.Equal(suite.T(), http.StatusCreated, w.Code, w.Body.String())
+
+	var device models.GPIODevice
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &device))
+	return device
+}
+
+// TestAPIIntegration_NodePaginationAndFilters tests node list pagination and filtering
+func (suite *APIIntegrationTestSuite) TestAPIIntegration_NodePaginationAndFilters() {
+	cluster := &models.Cluster{Name: "paging-cluster", Status: models.ClusterStatusActive}
+	require.NoError(suite.T(), suite.db.DB().Create(cluster).Error)
+
+	for i := 1; i <= 5; i++ {
+		var clusterID *uint
+		role := models.NodeRoleWorker
+		if i <= 3 {
+			clusterID = &cluster.ID
+		}
+		if i == 1 {
+			role = models.NodeRoleMaster
+		}
+		suite.createNode(i, clusterID, role)
+	}
+
+	type listResponse struct {
+		Nodes  []models.Node `json:"nodes"`
+		Count  int           `json:"count"`
+		Total  int64         `json:"total"`
+		Limit  int           `json:"limit"`
+		Offset int           `json:"offset"`
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCount int
+		expectedTotal int64
+	}{
+		{"Default page", "", 5, 5},
+		{"First page", "?limit=2", 2, 5},
+		{"Last page", "?limit=2&offset=4", 1, 5},
+		{"Past the end", "?offset=10", 0, 5},
+		{"By cluster", fmt.Sprintf("?cluster_id=%d", cluster.ID), 3, 3},
+		{"By role", "?role=master", 1, 1},
+		{"By status", "?status=discovered&limit=3", 3, 5},
+		{"Combined filters", fmt.Sprintf("?cluster_id=%d&role=worker", cluster.ID), 2, 2},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			w := suite.request("GET", "/api/v1/nodes"+tt.query, nil)
+			require.Equal(suite.T(), http.StatusOK, w.Code)
+
+			var resp listResponse
+			require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Len(suite.T(), resp.Nodes, tt.expectedCount)
+			assert.Equal(suite.T(), tt.expectedCount, resp.Count)
+			assert.Equal(suite.T(), tt.expectedTotal, resp.Total)
+		})
+	}
+}
+
+// TestAPIIntegration_GPIOPaginationAndFilters tests GPIO list pagination and filtering
+func (suite *APIIntegrationTestSuite) TestAPIIntegration_GPIOPaginationAndFilters() {
+	first := suite.createNode(1, nil, models.NodeRoleWorker)
+	second := suite.createNode(2, nil, models.NodeRoleWorker)
+
+	suite.createDevice(first.ID, 17, models.GPIODirectionOutput)
+	suite.createDevice(first.ID, 18, models.GPIODirectionOutput)
+	suite.createDevice(first.ID, 22, models.GPIODirectionInput)
+	suite.createDevice(second.ID, 17, models.GPIODirectionInput)
+
+	type listResponse struct {
+		Devices []models.GPIODevice `json:"devices"`
+		Total   int64               `json:"total"`
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCount int
+		expectedTotal int64
+	}{
+		{"All devices", "", 4, 4},
+		{"Paged", "?limit=3&offset=2", 2, 4},
+		{"By node", fmt.Sprintf("?node_id=%d", first.ID), 3, 3},
+		{"By direction", "?direction=input", 2, 2},
+		{"By node and direction", fmt.Sprintf("?node_id=%d&direction=output", first.ID), 2, 2},
+		{"By device type", "?device_type=pwm", 0, 0},
+		{"By status", "?status=active", 4, 4},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			w := suite.request("GET", "/api/v1/gpio"+tt.query, nil)
+			require.Equal(suite.T(), http.StatusOK, w.Code)
+
+			var resp listResponse
+			require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Len(suite.T(), resp.Devices, tt.expectedCount)
+			assert.Equal(suite.T(), tt.expectedTotal, resp.Total)
+		})
+	}
+
+	// Devices are also reachable through their node
+	w := suite.request("GET", fmt.Sprintf("/api/v1/nodes/%d/gpio", second.ID), nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var nodeDevices struct {
+		Devices []models.GPIODevice `json:"devices"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &nodeDevices))
+	assert.Len(suite.T(), nodeDevices.Devices, 1)
+}
+
+// TestAPIIntegration_NodeProvisioning tests moving a node in and out of a cluster
+func (suite *APIIntegrationTestSuite) TestAPIIntegration_NodeProvisioning() {
+	cluster := &models.Cluster{Name: "provision-cluster", Status: models.ClusterStatusActive}
+	require.NoError(suite.T(), suite.db.DB().Create(cluster).Error)
+	node := suite.createNode(1, nil, models.NodeRoleWorker)
+
+	w := suite.request("POST", fmt.Sprintf("/api/v1/nodes/%d/provision", node.ID), map[string]uint{"cluster_id": cluster.ID})
+	require.Equal(suite.T(), http.StatusAccepted, w.Code, w.Body.String())
+
+	var provisioned models.Node
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &provisioned))
+	assert.Equal(suite.T(), models.NodeStatusProvisioning, provisioned.Status)
+	require.NotNil(suite.T(), provisioned.ClusterID)
+	assert.Equal(suite.T(), cluster.ID, *provisioned.ClusterID)
+
+	w = suite.request("POST", fmt.Sprintf("/api/v1/nodes/%d/provision", node.ID), map[string]uint{"cluster_id": 9999})
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	w = suite.request("POST", fmt.Sprintf("/api/v1/nodes/%d/deprovision", node.ID), nil)
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+
+	var deprovisioned models.Node
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &deprovisioned))
+	assert.Equal(suite.T(), models.NodeStatusDiscovered, deprovisioned.Status)
+	assert.Nil(suite.T(), deprovisioned.ClusterID)
+}
+
+// TestAPIIntegration_NodeGPIOErrorMapping tests how service errors map to HTTP status codes
+func (suite *APIIntegrationTestSuite) TestAPIIntegration_NodeGPIOErrorMapping() {
+	node := suite.createNode(1, nil, models.NodeRoleWorker)
+	output := suite.createDevice(node.ID, 18, models.GPIODirectionOutput)
+	input := suite.createDevice(node.ID, 23, models.GPIODirectionInput)
+
+	tests := []struct {
+		name           string
+		method         string
+		endpoint       string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"Node invalid ID", "GET", "/api/v1/nodes/abc", nil, http.StatusBadRequest},
+		{"Node not found", "GET", "/api/v1/nodes/9999", nil, http.StatusNotFound},
+		{"Node invalid limit", "GET", "/api/v1/nodes?limit=abc", nil, http.StatusBadRequest},
+		{"Node negative offset", "GET", "/api/v1/nodes?offset=-1", nil, http.StatusBadRequest},
+		{"Node invalid cluster filter", "GET", "/api/v1/nodes?cluster_id=abc", nil, http.StatusBadRequest},
+		{"Node missing name", "POST", "/api/v1/nodes", services.CreateNodeRequest{IPAddress: "10.0.1.1", MACAddress: "02:00:00:00:01:01", Role: models.NodeRoleWorker}, http.StatusBadRequest},
+		{"Node invalid IP", "POST", "/api/v1/nodes", services.CreateNodeRequest{Name: "bad-ip", IPAddress: "not-an-ip", MACAddress: "02:00:00:00:01:01", Role: models.NodeRoleWorker}, http.StatusBadRequest},
+		{"Node invalid role", "POST", "/api/v1/nodes", services.CreateNodeRequest{Name: "bad-role", IPAddress: "10.0.1.1", MACAddress: "02:00:00:00:01:01", Role: "leader"}, http.StatusBadRequest},
+		{"Node duplicate name", "POST", "/api/v1/nodes", services.CreateNodeRequest{Name: node.Name, IPAddress: "10.0.1.1", MACAddress: "02:00:00:00:01:01", Role: models.NodeRoleWorker}, http.StatusConflict},
+		{"Node unknown cluster", "POST", "/api/v1/nodes", services.CreateNodeRequest{Name: "orphan", IPAddress: "10.0.1.2", MACAddress: "02:00:00:00:01:02", Role: models.NodeRoleWorker, ClusterID: uintPtr(9999)}, http.StatusNotFound},
+		{"Node invalid status update", "PUT", fmt.Sprintf("/api/v1/nodes/%d", node.ID), map[string]string{"status": "sleeping"}, http.StatusBadRequest},
+		{"Node delete with devices", "DELETE", fmt.Sprintf("/api/v1/nodes/%d", node.ID), nil, http.StatusConflict},
+		{"Provision without cluster", "POST", fmt.Sprintf("/api/v1/nodes/%d/provision", node.ID), map[string]string{}, http.StatusBadRequest},
+		{"GPIO invalid ID", "GET", "/api/v1/gpio/abc", nil, http.StatusBadRequest},
+		{"GPIO not found", "GET", "/api/v1/gpio/9999", nil, http.StatusNotFound},
+		{"GPIO invalid node filter", "GET", "/api/v1/gpio?node_id=-1", nil, http.StatusBadRequest},
+		{"GPIO pin out of range", "POST", "/api/v1/gpio", services.CreateGPIODeviceRequest{Name: "too-high", NodeID: node.ID, PinNumber: 99, Direction: models.GPIODirectionOutput}, http.StatusBadRequest},
+		{"GPIO invalid direction", "POST", "/api/v1/gpio", services.CreateGPIODeviceRequest{Name: "sideways", NodeID: node.ID, PinNumber: 5, Direction: "sideways"}, http.StatusBadRequest},
+		{"GPIO unknown node", "POST", "/api/v1/gpio", services.CreateGPIODeviceRequest{Name: "orphan", NodeID: 9999, PinNumber: 5, Direction: models.GPIODirectionOutput}, http.StatusNotFound},
+		{"GPIO pin in use", "POST", "/api/v1/gpio", services.CreateGPIODeviceRequest{Name: "dup", NodeID: node.ID, PinNumber: 18, Direction: models.GPIODirectionOutput}, http.StatusConflict},
+		{"GPIO write missing value", "POST", fmt.Sprintf("/api/v1/gpio/%d/write", output.ID), map[string]int{}, http.StatusBadRequest},
+		{"GPIO write to input", "POST", fmt.Sprintf("/api/v1/gpio/%d/write", input.ID), map[string]int{"value": 1}, http.StatusBadRequest},
+		{"GPIO digital write out of range", "POST", fmt.Sprintf("/api/v1/gpio/%d/write", output.ID), map[string]int{"value": 7}, http.StatusBadRequest},
+		{"GPIO readings invalid time", "GET", fmt.Sprintf("/api/v1/gpio/%d/readings?start_time=yesterday", output.ID), nil, http.StatusBadRequest},
+		{"GPIO readings unknown device", "GET", "/api/v1/gpio/9999/readings", nil, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			w := suite.request(tt.method, tt.endpoint, tt.body)
+			assert.Equal(suite.T(), tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+// TestAPIIntegration_GPIOAgentUnavailable tests that agent failures surface as 503
+func (suite *APIIntegrationTestSuite) TestAPIIntegration_GPIOAgentUnavailable() {
+	node := suite.createNode(1, nil, models.NodeRoleWorker)
+	output := suite.createDevice(node.ID, 18, models.GPIODirectionOutput)
+
+	suite.agent.setDown(true)
+
+	w := suite.request("POST", fmt.Sprintf("/api/v1/gpio/%d/write", output.ID), map[string]int{"value": 1})
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, w.Code)
+
+	w = suite.request("POST", fmt.Sprintf("/api/v1/gpio/%d/read", output.ID), nil)
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, w.Code)
+
+	suite.agent.setDown(false)
+
+	w = suite.request("POST", fmt.Sprintf("/api/v1/gpio/%d/write", output.ID), map[string]int{"value": 1})
+	require.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+
+	var written struct {
+		Value int `json:"value"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &written))
+	assert.Equal(suite.T(), 1, written.Value)
+}
+
+// Helper function for uint pointers
+func uintPtr(u uint) *uint {
+	return &u
+}